@@ -0,0 +1,69 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrMultiValidation is matched by a MultiValidationError with more than one error
+var ErrMultiValidation = errors.New("multiple validation errors")
+
+// MultiValidationError holds every error found by ValidateAll. errors.Is and errors.As
+// match each of the errors.
+type MultiValidationError struct {
+	errs []error
+}
+
+// Error returns the messages of all errors separated by semicolons
+func (e *MultiValidationError) Error() string {
+	messages := make([]string, 0, len(e.errs))
+	for _, err := range e.errs {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// Errors returns the errors in the order of the validators which produced them
+func (e *MultiValidationError) Errors() []error {
+	return append([]error(nil), e.errs...)
+}
+
+// Unwrap returns the errors, so errors.Is and errors.As inspect each of them
+func (e *MultiValidationError) Unwrap() []error {
+	return e.errs
+}
+
+// Is reports whether the error aggregates more than one error when target is ErrMultiValidation
+func (e *MultiValidationError) Is(target error) bool {
+	return target == ErrMultiValidation && len(e.errs) > 1
+}
+
+// ValidateAll runs all validators, instead of stopping at the first failure, and returns
+// a MultiValidationError with the errors they returned, or nil when all of them passed
+func ValidateAll(validators ...func() error) error {
+	var errs []error
+	for _, validate := range validators {
+		if err := validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return &MultiValidationError{errs: errs}
+}
+
+// WithPrefix returns a validator which prefixes the error of validate, e.g. with the field name
+func WithPrefix(prefix string, validate func() error) func() error {
+	return func() error {
+		if err := validate(); err != nil {
+			return fmt.Errorf("%s: %w", prefix, err)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,63 @@
+package validation_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+)
+
+var errCustom = errors.New("custom")
+
+func TestValidateAll(t *testing.T) {
+	t.Parallel()
+
+	// given
+	var calls int
+	failing := func(err error) func() error {
+		return func() error {
+			calls++
+
+			return err
+		}
+	}
+
+	// when
+	err := validation.ValidateAll(
+		failing(validation.NameIsValid("")),
+		failing(nil),
+		validation.WithPrefix("limit", failing(validation.IntIsPositive(0, "max"))),
+		failing(errCustom),
+	)
+
+	// then
+	assert.Equal(t, 4, calls)
+	require.ErrorIs(t, err, validation.ErrMultiValidation)
+	require.ErrorIs(t, err, validation.ErrValidationFailed)
+	require.ErrorIs(t, err, errCustom)
+
+	var multiErr *validation.MultiValidationError
+	require.ErrorAs(t, err, &multiErr)
+	require.Len(t, multiErr.Errors(), 3)
+	assert.Contains(t, multiErr.Errors()[1].Error(), "limit: ")
+	assert.Equal(t, multiErr.Errors()[0].Error()+"; "+multiErr.Errors()[1].Error()+"; custom", err.Error())
+}
+
+func TestValidateAll_SingleError(t *testing.T) {
+	t.Parallel()
+
+	err := validation.ValidateAll(func() error { return nil }, func() error { return errCustom })
+
+	require.ErrorIs(t, err, errCustom)
+	assert.NotErrorIs(t, err, validation.ErrMultiValidation)
+	assert.Equal(t, "custom", err.Error())
+}
+
+func TestValidateAll_NoErrors(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, validation.ValidateAll(func() error { return nil }))
+	require.NoError(t, validation.ValidateAll())
+}
@@ -108,8 +108,14 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 
+	jsonpatch "github.com/evanphx/json-patch/v5"
 	"github.com/vitalii-honchar/go-agent/internal/validation"
 	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
 	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmfactory"
@@ -135,6 +141,8 @@ var (
 	ErrEmptySystemPrompt = errors.New("system prompt cannot be empty")
 	// ErrAccessDenied is returned when RBAC middleware denies access
 	ErrAccessDenied = errors.New("access denied: user not authorized to use tool")
+	// ErrDuplicateToolName is returned when several tools are registered with the same name
+	ErrDuplicateToolName = errors.New("duplicate tool name")
 )
 
 var systemPromptTemplate = NewPrompt(`You are an agent that implements the ReAct ` +
@@ -153,7 +161,10 @@ Always maintain explicit reasoning chains. Your thoughts should be visible and l
 
 TOOLS AVAILABLE TO USE:
 {{.tools}}
-
+{{if .unavailable_tools}}
+TOOLS UNAVAILABLE IN THIS RUN (do not call them):
+{{.unavailable_tools}}
+{{end}}
 CURRENT TOOLS USAGE:
 {{.tools_usage}}
 
@@ -183,11 +194,106 @@ type Agent[T any] struct {
 	llmConfig        llm.LLMConfig
 	tools            map[string]llm.LLMTool
 	limits           map[string]int
+	priorities       map[string]int
+	toolTimeouts     map[string]time.Duration
 	defaultToolLimit int
 	outputSchema     *T
+	outputSchemaMap  map[string]any
+	schemaRegistry   *SchemaRegistry
+	schemaName       string
 	systemPrompt     Prompt
 	behavior         string
 	middlewares      []AgentMiddleware
+	multimodal       bool
+	history          *conversationHistory
+	warmUp           warmUpConfig
+	strictFunctions  bool
+	retrySuffix      string
+	toolCallLog      *toolCallLog
+	toolFilters      []func(tool llm.LLMTool) bool
+	toolConditions   map[string]ToolCondition
+	unavailableTools []string
+	observability    *observer
+	observabilityErr error
+	secretScrubber   *secretScrubber
+	onRunStart       []RunStartHook
+	onRunEnd         []RunEndHook[T]
+
+	inputMarshaler    InputMarshaler
+	outputUnmarshaler OutputUnmarshaler
+	outputPatch       jsonpatch.Patch
+
+	outputValidators    []Validator[T]
+	validatorMaxRetries int
+
+	toolCallTransformers map[string][]ToolCallTransformer
+	panicRecovery        bool
+
+	parallelToolCalls   bool
+	concurrentToolLimit int
+
+	promptInjection    *promptInjectionDetector
+	promptInjectionErr error
+	fallback           *Agent[T]
+	requestLogging     bool
+	requestLogger      *slog.Logger
+	outputCache        *outputCacheConfig[T]
+	overrideLLMs       *overrideLLMCache
+	partialResultSlots *partialResultSlots
+
+	outputLength *outputLengthLimit
+
+	duplicateTools []string
+
+	globalToolLimit *int
+	runTimeout      time.Duration
+
+	toolDependencies map[string][]string
+
+	injectedToolResults map[string][]llm.LLMToolResult
+
+	budgetUSD  *float64
+	modelPrice *llm.ModelPrice
+
+	toolResultTransformers map[string][]ToolResultTransformer
+
+	messageInterceptors []MessageInterceptor
+
+	routingFactory *llmfactory.RoutingFactory
+
+	maxBehaviorLength int
+	minBehaviorLength int
+
+	systemPromptErr error
+
+	idempotencyKey ToolCallIdempotencyKey
+
+	toolSchemaOverrides map[string]map[string]any
+
+	promptCaching bool
+
+	speculative *speculativeExecution[T]
+
+	output *outputWriter
+
+	schemaMaxRetries int
+
+	promptBundle PromptBundle
+	localeFn     func(ctx context.Context) string
+
+	retryBackoff retryBackoff
+
+	maxInputBytes *int
+
+	namespace    string
+	namespaceErr error
+
+	sampleRate *float64
+
+	version    string
+	versionErr error
+
+	toolCallBatch *toolCallBatch
 }
 
 // AgentOption is a function that configures an Agent
@@ -228,43 +334,133 @@ type AgentMiddleware func(context.Context, *AgentState, llm.LLMMessage) (llm.LLM
 // is invalid (e.g., empty behavior, missing LLM config).
 func NewAgent[T any](options ...AgentOption[T]) (*Agent[T], error) {
 	agent := &Agent[T]{
-		tools:            make(map[string]llm.LLMTool),
-		limits:           make(map[string]int),
-		defaultToolLimit: 3,
-		systemPrompt:     systemPromptTemplate,
+		tools:               make(map[string]llm.LLMTool),
+		limits:              make(map[string]int),
+		priorities:          make(map[string]int),
+		toolTimeouts:        make(map[string]time.Duration),
+		partialResultSlots:  newPartialResultSlots(),
+		defaultToolLimit:    3,
+		concurrentToolLimit: defaultConcurrentToolLimit,
+		validatorMaxRetries: defaultValidatorMaxRetries,
+		systemPrompt:        systemPromptTemplate,
+		overrideLLMs:        newOverrideLLMCache(),
+		maxBehaviorLength:   defaultMaxBehaviorLength,
+		retryBackoff:        retryBackoff{maxDelay: defaultMaxRetryDelay},
+		warmUp:              warmUpConfig{timeout: DefaultWarmUpTimeout},
 	}
 	for _, opt := range options {
 		opt(agent)
 	}
 
+	agent.applyToolFilters()
+	agent.applyToolSchemaOverrides()
+
 	err := agent.validate()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create agent: %w", err)
 	}
 
-	agentLLM, err := llmfactory.CreateLLM(agent.llmConfig, agent.tools)
+	if agent.strictFunctions {
+		agent.llmConfig.StrictFunctionCalling = true
+	}
+
+	agentLLM, err := llmfactory.CreateLLM(agent.llmConfig, agent.llmTools())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create LLM: %w", err)
 	}
 
-	agent.llm = agentLLM
+	agent.llm = agent.withRequestLogging(agentLLM)
 	agent.outputSchema = new(T)
 
+	if agent.warmUp.onCreate {
+		ctx, cancel := context.WithTimeout(context.Background(), agent.warmUp.timeout)
+		err := WarmUp(ctx, agent)
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create agent: %w", err)
+		}
+	}
+
 	return agent, nil
 }
 
+// validate checks the configuration of the agent and reports all problems at once
 func (a *Agent[T]) validate() error {
-	if err := validation.NameIsValid(a.name); err != nil {
-		return fmt.Errorf("name: %w", err)
-	}
-	if err := validation.StringIsNotEmpty(a.behavior); err != nil {
-		return fmt.Errorf("behavior: %w", err)
+	validators := []func() error{
+		validation.WithPrefix("name", func() error {
+			return validation.NameIsValid(a.name)
+		}),
+		validation.WithPrefix("behavior", func() error {
+			return validation.StringIsNotEmpty(a.behavior)
+		}),
+		validation.WithPrefix("behavior", a.validateBehaviorLength),
+		validation.WithPrefix("llm config", a.validateLLMConfig),
+		validation.WithPrefix("output schema", a.resolveRegistrySchema),
+		validation.WithPrefix("multimodal", a.validateMultimodal),
+		func() error {
+			if len(a.duplicateTools) > 0 {
+				return fmt.Errorf("%w: %s", ErrDuplicateToolName, strings.Join(a.duplicateTools, ", "))
+			}
+
+			return nil
+		},
+		validation.WithPrefix("tool limits", func() error {
+			return validation.IntIsPositive(a.defaultToolLimit, "default tool limit")
+		}),
 	}
-	if err := a.llmConfig.Validate(); err != nil {
-		return fmt.Errorf("llm config: %w", err)
+	for _, name := range slices.Sorted(maps.Keys(a.limits)) {
+		validators = append(validators, validation.WithPrefix("tool limits", func() error {
+			return validation.IntIsPositive(a.limits[name], "limit of tool "+name)
+		}))
 	}
+	validators = append(validators,
+		validation.WithPrefix("tool limits", a.validateGlobalToolLimit),
+		validation.WithPrefix("tool dependencies", a.validateToolDependencies),
+		validation.WithPrefix("tool schema overrides", a.validateToolSchemaOverrides),
+		validation.WithPrefix("speculative execution", a.validateSpeculativeExecution),
+		validation.WithPrefix("retry backoff", a.retryBackoff.validate),
+		validation.WithPrefix("warm-up", a.warmUp.validate),
+		validation.WithPrefix("schema retries", func() error {
+			return validation.IntIsNotNegative(a.schemaMaxRetries, "max retries")
+		}),
+		validation.WithPrefix("output", func() error {
+			if a.output == nil {
+				return nil
+			}
 
-	return nil
+			return a.output.validate()
+		}),
+		validation.WithPrefix("parallel tool calls", func() error {
+			return validation.IntIsPositive(a.concurrentToolLimit, "concurrent tool limit")
+		}),
+		validation.WithPrefix("input history", a.validateHistory),
+		validation.WithPrefix("output validation", func() error {
+			return validation.IntIsNotNegative(a.validatorMaxRetries, "validator max retries")
+		}),
+		validation.WithPrefix("output length", a.validateOutputLength),
+		validation.WithPrefix("budget", a.validateBudget),
+		validation.WithPrefix("observability", func() error {
+			return a.observabilityErr
+		}),
+		validation.WithPrefix("prompt injection protection", func() error {
+			return a.promptInjectionErr
+		}),
+		validation.WithPrefix("system prompt", func() error {
+			return a.systemPromptErr
+		}),
+		validation.WithPrefix("localized system prompt", a.validatePromptBundle),
+		validation.WithPrefix("input size limit", a.validateInputSizeLimit),
+		validation.WithPrefix("namespace", func() error {
+			return a.namespaceErr
+		}),
+		validation.WithPrefix("tool call sampler", a.validateToolCallSampler),
+		validation.WithPrefix("agent version", func() error {
+			return a.versionErr
+		}),
+		validation.WithPrefix("tool call batch", a.toolCallBatch.validate),
+	)
+
+	return validation.ValidateAll(validators...)
 }
 
 // WithName sets the agent's name
@@ -292,16 +488,34 @@ func WithBehavior[T any](behavior string) AgentOption[T] {
 func WithSystemPrompt[T any](prompt Prompt) AgentOption[T] {
 	return func(a *Agent[T]) {
 		a.systemPrompt = prompt
+		a.systemPromptErr = nil
 	}
 }
 
 // WithTool adds a tool to the agent
 func WithTool[T any](name string, tool llm.LLMTool) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.addTool(name, tool)
+	}
+}
+
+// WithToolOverwrite adds a tool to the agent, replacing a tool registered earlier with the
+// same name. Use it instead of WithTool to override a tool on purpose, e.g. a default tool
+// in options shared between agents.
+func WithToolOverwrite[T any](name string, tool llm.LLMTool) AgentOption[T] {
 	return func(a *Agent[T]) {
 		a.tools[name] = tool
 	}
 }
 
+// addTool registers the tool, remembering the name when a tool with it is already registered
+func (a *Agent[T]) addTool(name string, tool llm.LLMTool) {
+	if _, exists := a.tools[name]; exists && !slices.Contains(a.duplicateTools, name) {
+		a.duplicateTools = append(a.duplicateTools, name)
+	}
+	a.tools[name] = tool
+}
+
 // WithToolLimit sets a usage limit for a specific tool
 func WithToolLimit[T any](name string, limit int) AgentOption[T] {
 	return func(a *Agent[T]) {
@@ -316,52 +530,192 @@ func WithDefaultToolLimit[T any](limit int) AgentOption[T] {
 	}
 }
 
+// WithToolPriority sets the execution priority for a specific tool.
+// When the LLM requests several tool calls in one turn, calls with a lower
+// priority number are executed first and their results appear earlier in the
+// conversation. Tools without an explicit priority default to 0.
+func WithToolPriority[T any](name string, priority int) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.priorities[name] = priority
+	}
+}
+
+// WithStrictFunctionCalling enables strict function calling, which prevents the model from
+// hallucinating tool parameters. Disabled by default. In strict mode OpenAI requires every tool
+// parameter schema to have additionalProperties set to false and all properties required:
+// schemas generated from Go structs satisfy this when no field is tagged omitempty.
+func WithStrictFunctionCalling[T any](enabled bool) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.strictFunctions = enabled
+	}
+}
+
 func WithMiddleware[T any](middleware AgentMiddleware) AgentOption[T] {
 	return func(a *Agent[T]) {
 		a.middlewares = append(a.middlewares, middleware)
 	}
 }
 
+// UsingLLM returns a copy of the agent that sends its requests to the given LLM.
+// The copy shares tools, limits, history and request logging with the original agent.
+// It is mainly useful in tests, to run a configured agent against a mock LLM.
+func (a *Agent[T]) UsingLLM(agentLLM llm.LLM) *Agent[T] {
+	clone := *a
+	clone.llm = a.withRequestLogging(agentLLM)
+
+	return &clone
+}
+
 // AgentState represents the current state of agent execution
 type AgentState struct {
 	Messages []llm.LLMMessage
+	// StartTime is the time Run started, in UTC
+	StartTime time.Time
+	// EndTime is the time Run finished, in UTC. It is zero while the run is in progress.
+	EndTime time.Time
+	// TotalUsage is the number of tokens consumed by the LLM calls of the run so far
+	TotalUsage llm.TokenUsage
+
+	llmCallDurations  []time.Duration
+	toolCallHistory   []ToolCallRecord
+	scrubber          *secretScrubber
+	injectedResults   *injectedToolResults
+	budgetWarned      bool
+	interceptors      []MessageInterceptor
+	idempotentResults *idempotentResults
+	branchesUsage     *sharedUsage
+
+	// Sampled reports whether detailed traces are recorded for the run, see WithToolCallSampler
+	Sampled bool
 }
 
-// AddMessage adds a message to the agent's conversation history
+// AddMessage adds a message to the agent's conversation history.
+// Secrets configured with WithSecretScrubber are removed from the message first.
 func (a *AgentState) AddMessage(msg llm.LLMMessage) {
+	if a.scrubber != nil {
+		msg = a.scrubber.scrubMessage(msg)
+	}
+
 	a.Messages = append(a.Messages, msg)
 }
 
+// Duration returns how long the run took, or how long it has been running so far
+func (a *AgentState) Duration() time.Duration {
+	if a.EndTime.IsZero() {
+		return time.Since(a.StartTime)
+	}
+
+	return a.EndTime.Sub(a.StartTime)
+}
+
+// LLMCallDurations returns the latency of every LLM call made during the run, in order
+func (a *AgentState) LLMCallDurations() []time.Duration {
+	durations := make([]time.Duration, len(a.llmCallDurations))
+	copy(durations, a.llmCallDurations)
+
+	return durations
+}
+
+func (a *AgentState) recordLLMCall(start time.Time) {
+	a.llmCallDurations = append(a.llmCallDurations, time.Since(start))
+}
+
 // Run executes the agent with the given input and returns the result
 func (a *Agent[T]) Run(ctx context.Context, input any) (*AgentResult[T], error) {
-	state, err := a.createInitState(input)
+	ctx = a.withSamplingDecision(ctx)
+	ctx, finishObservation := a.observeRun(ctx)
+	a.notifyRunStart(ctx, input)
+
+	runCtx, cancel := a.withRunTimeout(ctx)
+	result, err := a.runCached(runCtx, input)
+	cancel()
+	err = classifyRunError(ctx, runCtx, err)
+
+	a.notifyRunEnd(ctx, result, err)
+	finishObservation(err)
+
+	return result, err
+}
+
+func (a *Agent[T]) run(ctx context.Context, input any) (*AgentResult[T], error) {
+	startTime := time.Now().UTC()
+
+	state, err := a.createInitState(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	state.StartTime = startTime
+	defer func() {
+		state.EndTime = time.Now().UTC()
+	}()
+
+	userMessage := state.Messages[len(state.Messages)-1]
+
+	var result *AgentResult[T]
+	if a.speculative != nil && a.speculative.branches > 1 {
+		result, err = a.runSpeculative(ctx, state)
+	} else {
+		result, err = a.runLoop(ctx, state)
+	}
+	a.tagVersion(result)
 	if err != nil {
+		return result, err
+	}
+
+	if err := a.recordHistory(userMessage, result); err != nil {
 		return nil, err
 	}
 
+	if err := a.output.writeResult(result.Data); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// runLoop calls the LLM and the tools it requests until the LLM ends the conversation
+func (a *Agent[T]) runLoop(ctx context.Context, state *AgentState) (*AgentResult[T], error) {
 	usage := make(map[string]int)
 
 	for {
-		llmMessage, err := a.llm.Call(ctx, state.Messages)
+		llmMessage, err := a.callLLM(ctx, state)
 		if err != nil {
 			return nil, fmt.Errorf("%w: %s", ErrLLMCall, err)
 		}
 
+		if err := a.checkBudget(ctx, state); err != nil {
+			llmMessage, addErr := state.addMessage(ctx, llmMessage)
+			if addErr != nil {
+				return nil, addErr
+			}
+			streamMessage(ctx, llmMessage)
+
+			return &AgentResult[T]{
+				Data:     nil,
+				Messages: state.Messages,
+			}, err
+		}
+
 		llmMessage, err = a.runMiddlewares(ctx, state, llmMessage)
 		if err != nil {
 			return nil, fmt.Errorf("%w: %w", ErrMiddlewareError, err)
 		}
 
 		if llmMessage.ToolCalls != nil {
-			results, err := a.callTools(llmMessage, usage)
+			results, err := a.callTools(ctx, state, llmMessage, usage)
 			if err != nil {
 				if errors.Is(err, ErrLimitReached) {
-					state.AddMessage(llmMessage)
+					llmMessage, addErr := state.addMessage(ctx, llmMessage)
+					if addErr != nil {
+						return nil, addErr
+					}
+					streamMessage(ctx, llmMessage)
 
 					return &AgentResult[T]{
 						Data:     nil,
 						Messages: state.Messages,
-					}, ErrLimitReached
+					}, err
 				}
 
 				return nil, err
@@ -370,21 +724,49 @@ func (a *Agent[T]) Run(ctx context.Context, input any) (*AgentResult[T], error)
 			llmMessage.ToolResults = results
 		}
 
-		state.AddMessage(llmMessage)
+		llmMessage, err = state.addMessage(ctx, llmMessage)
+		if err != nil {
+			return nil, err
+		}
+		streamMessage(ctx, llmMessage)
+
+		if err := a.output.writeStep(llmMessage); err != nil {
+			return nil, err
+		}
 
 		if llmMessage.End {
 			return a.createResult(ctx, state)
 		}
 
-		newSystemPrompt, err := a.createSystemPrompt(usage)
+		newSystemPrompt, err := a.createSystemPrompt(ctx, usage)
 		if err != nil {
 			return nil, fmt.Errorf("failed to update system prompt: %w", err)
 		}
 
-		state.Messages[0].Content = newSystemPrompt
+		if err := state.setSystemMessage(ctx, newSystemPrompt); err != nil {
+			return nil, err
+		}
 	}
 }
 
+func (a *Agent[T]) callLLM(ctx context.Context, state *AgentState) (llm.LLMMessage, error) {
+	start := time.Now()
+	llmMessage, err := a.llm.Call(ctx, state.Messages)
+	state.recordLLMCall(start)
+	if err != nil {
+		return llm.LLMMessage{}, err
+	}
+
+	state.TotalUsage = state.TotalUsage.Add(llmMessage.Usage)
+	state.branchesUsage.add(llmMessage.Usage)
+
+	if llmMessage.Timestamp.IsZero() {
+		llmMessage.Timestamp = time.Now().UTC()
+	}
+
+	return llmMessage, nil
+}
+
 func (a *Agent[T]) runMiddlewares(
 	ctx context.Context,
 	state *AgentState,
@@ -409,8 +791,19 @@ func (a *Agent[T]) getToolLimit(name string) int {
 	return a.defaultToolLimit
 }
 
-func (a *Agent[T]) createInitState(input any) (*AgentState, error) {
-	systemPrompt, err := a.createSystemPrompt(make(map[string]int))
+func (a *Agent[T]) sortToolCallsByPriority(toolCalls []llm.LLMToolCall) []llm.LLMToolCall {
+	sorted := make([]llm.LLMToolCall, len(toolCalls))
+	copy(sorted, toolCalls)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return a.priorities[sorted[i].ToolName] < a.priorities[sorted[j].ToolName]
+	})
+
+	return sorted
+}
+
+func (a *Agent[T]) createInitState(ctx context.Context, input any) (*AgentState, error) {
+	systemPrompt, err := a.createSystemPrompt(ctx, make(map[string]int))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create system prompt: %w", err)
 	}
@@ -419,47 +812,102 @@ func (a *Agent[T]) createInitState(input any) (*AgentState, error) {
 		return nil, ErrEmptySystemPrompt
 	}
 
-	inputJSON, err := json.Marshal(input)
+	userMessage, err := a.createUserMessage(input)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal input: %w", err)
+		return nil, err
+	}
+	if err := a.promptInjection.check(ctx, userMessage); err != nil {
+		return nil, err
 	}
 
-	return &AgentState{
-		Messages: []llm.LLMMessage{
-			llm.NewLLMMessage(llm.LLMMessageTypeSystem, systemPrompt),
-			llm.NewLLMMessage(llm.LLMMessageTypeUser, string(inputJSON)),
-		},
-	}, nil
+	state := &AgentState{
+		scrubber:        a.secretScrubber,
+		injectedResults: newInjectedToolResults(a.injectedToolResults),
+		interceptors:    a.messageInterceptors,
+		Sampled:         isSampled(ctx),
+	}
+	if a.idempotencyKey != nil {
+		state.idempotentResults = idempotentResultsFor(ctx)
+	}
+
+	messages := append([]llm.LLMMessage{a.newSystemMessage(systemPrompt)}, a.GetHistory()...)
+	for _, msg := range append(messages, userMessage) {
+		if _, err := state.addMessage(ctx, msg); err != nil {
+			return nil, err
+		}
+	}
+
+	return state, nil
+}
+
+func (a *Agent[T]) createUserMessage(input any) (llm.LLMMessage, error) {
+	if parts, ok := input.([]llm.MessagePart); ok {
+		return a.createMultimodalInput(parts)
+	}
+
+	inputJSON, err := a.marshalInput(input)
+	if err != nil {
+		return llm.LLMMessage{}, fmt.Errorf("failed to marshal input: %w", err)
+	}
+
+	if err := a.checkInputSize(inputJSON); err != nil {
+		return llm.LLMMessage{}, err
+	}
+
+	return llm.NewLLMMessage(llm.LLMMessageTypeUser, string(inputJSON)), nil
 }
 
-func (a *Agent[T]) createSystemPrompt(usage map[string]int) (string, error) {
-	tools, err := json.Marshal(a.tools)
+func (a *Agent[T]) createSystemPrompt(ctx context.Context, usage map[string]int) (string, error) {
+	tools, err := json.Marshal(a.llmTools())
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal tools: %w", err)
 	}
 
-	toolsUsage, err := json.Marshal(usage)
+	toolsUsage, err := json.Marshal(namespacedKeys(a, usage))
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal tools usage: %w", err)
 	}
 
-	callingLimits, err := json.Marshal(a.limits)
+	callingLimits, err := json.Marshal(namespacedKeys(a, a.limits))
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal calling limits: %w", err)
 	}
 
-	return a.systemPrompt.Render(map[string]any{
-		"tools":          string(tools),
-		"tools_usage":    string(toolsUsage),
-		"calling_limits": string(callingLimits),
-		"behavior":       a.behavior,
+	return a.systemPromptFor(ctx).Render(map[string]any{
+		"tools":             string(tools),
+		"tools_usage":       string(toolsUsage),
+		"calling_limits":    string(callingLimits),
+		"behavior":          a.behavior,
+		"unavailable_tools": strings.Join(a.namespacedNames(a.unavailableTools), ", "),
 	})
 }
 
-func (a *Agent[T]) callTools(llmMessage llm.LLMMessage, usage map[string]int) ([]llm.LLMToolResult, error) {
-	results := make([]llm.LLMToolResult, 0, len(llmMessage.ToolCalls))
+func (a *Agent[T]) callTools(
+	ctx context.Context,
+	state *AgentState,
+	llmMessage llm.LLMMessage,
+	usage map[string]int,
+) ([]llm.LLMToolResult, error) {
+	toolCalls := a.sortToolCallsByPriority(a.stripNamespace(llmMessage.ToolCalls))
+	if a.parallelToolCalls {
+		return a.callToolsParallel(ctx, state, toolCalls, usage, a.concurrentToolLimit)
+	}
+
+	results := make([]llm.LLMToolResult, 0, len(toolCalls))
 
-	for _, toolCall := range llmMessage.ToolCalls {
+	for _, group := range a.groupToolCallBatches(toolCalls) {
+		if len(group) > 1 {
+			// the calls of a batched tool are submitted together, so they share a batch
+			batchResults, err := a.callToolsParallel(ctx, state, group, usage, len(group))
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, batchResults...)
+
+			continue
+		}
+
+		toolCall := group[0]
 		tool, ok := a.tools[toolCall.ToolName]
 		if !ok {
 			results = append(
@@ -477,8 +925,20 @@ func (a *Agent[T]) callTools(llmMessage llm.LLMMessage, usage map[string]int) ([
 		if usage[toolCall.ToolName] >= limit {
 			return nil, ErrLimitReached
 		}
+		if a.globalToolLimitReached(usage, 0) {
+			return nil, ErrGlobalLimitReached
+		}
+		if err := a.checkToolDependencies(toolCall.ToolName, usage); err != nil {
+			results = append(results, a.createErrorToolResult(toolCall.ID, err))
+
+			continue
+		}
 
-		toolRes, err := tool.Call(toolCall.ID, toolCall.Args)
+		toolRes, record, err := a.runTool(ctx, state, tool, toolCall)
+		state.recordToolCall(record)
+		if errors.Is(err, ErrRateLimitExceeded) {
+			return nil, err
+		}
 		if err != nil {
 			results = append(results, a.createErrorToolResult(toolCall.ID, fmt.Errorf("%w: %s", ErrToolError, err)))
 
@@ -493,6 +953,14 @@ func (a *Agent[T]) callTools(llmMessage llm.LLMMessage, usage map[string]int) ([
 	return results, nil
 }
 
+func (a *Agent[T]) structuredOutputSchema() any {
+	if a.outputSchemaMap != nil {
+		return a.outputSchemaMap
+	}
+
+	return a.outputSchema
+}
+
 func (a *Agent[T]) createErrorToolResult(callID string, err error) llm.ErrorLLMToolResult {
 	return llm.ErrorLLMToolResult{
 		BaseLLMToolResult: llm.BaseLLMToolResult{ID: callID},
@@ -507,12 +975,23 @@ func (a *Agent[T]) createResult(ctx context.Context, state *AgentState) (*AgentR
 		return nil, fmt.Errorf("failed to render output prompt: %w", err)
 	}
 
-	state.Messages = append(state.Messages, llm.NewLLMMessage(llm.LLMMessageTypeUser, outputPrompt))
+	if _, err := state.addMessage(ctx, llm.NewLLMMessage(llm.LLMMessageTypeUser, outputPrompt)); err != nil {
+		return nil, err
+	}
 
 	// Call LLM with structured output
-	result, err := llm.CallWithStructuredOutput[T](ctx, a.llm, state.Messages)
+	result, err := a.callWithSchemaRetry(ctx, state)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrLLMCall, err)
+	}
+
+	result, err = a.validateOutput(ctx, state, result)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %s", ErrLLMCall, err)
+		return nil, err
+	}
+
+	if err := a.limitOutputLength(&result); err != nil {
+		return nil, err
 	}
 
 	return &AgentResult[T]{
@@ -0,0 +1,100 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+)
+
+var (
+	// ErrAllAgentsFailed is returned by AgentGroup.Run when no agent of the group succeeded
+	ErrAllAgentsFailed = errors.New("all agents in the group failed")
+	// ErrInvalidAgentGroup is returned by AgentGroup.Run when the group has no agents or no merger
+	ErrInvalidAgentGroup = errors.New("invalid agent group")
+)
+
+// ResultMerger combines the results of the agents of an AgentGroup into a single result
+type ResultMerger[T any] func(results []*AgentResult[T]) (*AgentResult[T], error)
+
+// AgentGroup runs several agents on the same input and merges their results, e.g. specialized
+// agents which analyze the same document independently
+type AgentGroup[T any] struct {
+	merger ResultMerger[T]
+	agents []*Agent[T]
+}
+
+// NewAgentGroup creates a group of agents whose results are combined by merger
+//
+// Example:
+//
+//	group := agent.NewAgentGroup(func(results []*agent.AgentResult[Review]) (*agent.AgentResult[Review], error) {
+//		merged := Review{}
+//		for _, result := range results {
+//			merged.Issues = append(merged.Issues, result.Data.Issues...)
+//		}
+//
+//		return &agent.AgentResult[Review]{Data: &merged}, nil
+//	}, securityAgent, styleAgent)
+//
+//	result, err := group.Run(ctx, document)
+func NewAgentGroup[T any](merger ResultMerger[T], agents ...*Agent[T]) *AgentGroup[T] {
+	return &AgentGroup[T]{
+		merger: merger,
+		agents: agents,
+	}
+}
+
+// Run dispatches input to all agents concurrently and waits for all of them to complete.
+// The agents share ctx, so its deadline applies to the whole group. A failed agent is logged
+// and left out of the results passed to the merger, in the order of the agents in the group.
+// Run fails with ErrAllAgentsFailed, joined with the errors of the agents, only when every
+// agent failed.
+func (g *AgentGroup[T]) Run(ctx context.Context, input any) (*AgentResult[T], error) {
+	if g.merger == nil {
+		return nil, fmt.Errorf("%w: merger cannot be nil", ErrInvalidAgentGroup)
+	}
+	if len(g.agents) == 0 {
+		return nil, fmt.Errorf("%w: no agents", ErrInvalidAgentGroup)
+	}
+
+	results := make([]*AgentResult[T], len(g.agents))
+	errs := make([]error, len(g.agents))
+
+	var wg sync.WaitGroup
+	for i, groupAgent := range g.agents {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			results[i], errs[i] = groupAgent.Run(ctx, input)
+		}()
+	}
+	wg.Wait()
+
+	succeeded := make([]*AgentResult[T], 0, len(g.agents))
+	for i, err := range errs {
+		if err != nil {
+			slog.WarnContext(ctx, "agent in group failed", "agent", g.agents[i].name, "error", err)
+
+			errs[i] = fmt.Errorf("agent %s: %w", g.agents[i].name, err)
+
+			continue
+		}
+
+		succeeded = append(succeeded, results[i])
+	}
+
+	if len(succeeded) == 0 {
+		return nil, errors.Join(append([]error{ErrAllAgentsFailed}, errs...)...)
+	}
+
+	merged, err := g.merger(succeeded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge agent results: %w", err)
+	}
+
+	return merged, nil
+}
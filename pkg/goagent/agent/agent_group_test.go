@@ -0,0 +1,84 @@
+package agent_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+func createGroupMember(t *testing.T, name string, responses ...llm.LLMMessage) *agent.Agent[AddNumbersResult] {
+	t.Helper()
+
+	member, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult](name),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You add numbers."),
+	)
+	require.NoError(t, err)
+
+	structured := `{"sum": ` + name[len(name)-1:] + `}`
+	agent.SetLLM(member, llmtest.NewMockLLM(structured, responses...))
+
+	return member
+}
+
+func sumMerger(results []*agent.AgentResult[AddNumbersResult]) (*agent.AgentResult[AddNumbersResult], error) {
+	merged := AddNumbersResult{}
+	for _, result := range results {
+		merged.Sum = merged.Sum*10 + result.Data.Sum
+	}
+
+	return &agent.AgentResult[AddNumbersResult]{Data: &merged}, nil
+}
+
+func TestAgentGroup_Run(t *testing.T) {
+	t.Parallel()
+
+	// given
+	group := agent.NewAgentGroup(sumMerger,
+		createGroupMember(t, "agent_1", endMessage("done")),
+		createGroupMember(t, "agent_2"),
+		createGroupMember(t, "agent_3", endMessage("done")),
+	)
+
+	// when
+	result, err := group.Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, 13, result.Data.Sum)
+}
+
+func TestAgentGroup_Run_AllAgentsFailed(t *testing.T) {
+	t.Parallel()
+
+	// given
+	group := agent.NewAgentGroup(sumMerger,
+		createGroupMember(t, "agent_1"),
+		createGroupMember(t, "agent_2"),
+	)
+
+	// when
+	_, err := group.Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+
+	// then
+	require.ErrorIs(t, err, agent.ErrAllAgentsFailed)
+	assert.ErrorIs(t, err, agent.ErrLLMCall)
+	assert.Contains(t, err.Error(), "agent agent_2")
+}
+
+func TestAgentGroup_Run_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := agent.NewAgentGroup[AddNumbersResult](nil, createGroupMember(t, "agent_1")).
+		Run(context.Background(), AddNumbers{})
+	require.ErrorIs(t, err, agent.ErrInvalidAgentGroup)
+
+	_, err = agent.NewAgentGroup(sumMerger).Run(context.Background(), AddNumbers{})
+	require.ErrorIs(t, err, agent.ErrInvalidAgentGroup)
+}
@@ -9,6 +9,8 @@ import (
 type AgentResult[T any] struct {
 	Data     *T               `json:"data"`
 	Messages []llm.LLMMessage `json:"messages"`
+	// Metadata holds additional information about the run, e.g. MetadataUsedFallback
+	Metadata map[string]any `json:"metadata,omitempty"`
 }
 
 // NewAgentResult creates a new AgentResult with the given data and messages
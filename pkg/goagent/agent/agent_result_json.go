@@ -0,0 +1,262 @@
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+var (
+	// ErrUnknownToolResultType is returned when a serialized tool result has a type which
+	// is not registered with RegisterToolResultType
+	ErrUnknownToolResultType = errors.New("unknown tool result type")
+	// ErrToolResultTypeAlreadyRegistered is returned when a tool result type name is already taken
+	ErrToolResultTypeAlreadyRegistered = errors.New("tool result type already registered")
+)
+
+const partialToolResultType = "partial"
+
+var toolResultTypes = newToolResultTypeRegistry()
+
+type toolResultTypeRegistry struct {
+	mu     sync.RWMutex
+	byName map[string]reflect.Type
+	byType map[reflect.Type]string
+}
+
+func newToolResultTypeRegistry() *toolResultTypeRegistry {
+	reg := &toolResultTypeRegistry{
+		byName: make(map[string]reflect.Type),
+		byType: make(map[reflect.Type]string),
+	}
+	reg.add("base", reflect.TypeFor[llm.BaseLLMToolResult]())
+	reg.add("error", reflect.TypeFor[llm.ErrorLLMToolResult]())
+	reg.add(partialToolResultType, reflect.TypeFor[llm.PartialToolResult]())
+
+	return reg
+}
+
+func (r *toolResultTypeRegistry) add(name string, typ reflect.Type) {
+	r.byName[name] = typ
+	r.byType[typ] = name
+}
+
+// RegisterToolResultType registers the tool result type R under name, so AgentResult
+// restores tool results of this type when it is deserialized. Register every tool result
+// type of the agent tools before calling AgentResultFromJSON. llm.BaseLLMToolResult,
+// llm.ErrorLLMToolResult and llm.PartialToolResult are registered by default.
+//
+// Example:
+//
+//	if err := agent.RegisterToolResultType[AddToolResult]("add_result"); err != nil {
+//		log.Fatal(err)
+//	}
+func RegisterToolResultType[R llm.LLMToolResult](name string) error {
+	if err := validation.StringIsNotEmpty(name); err != nil {
+		return fmt.Errorf("tool result type name: %w", err)
+	}
+
+	toolResultTypes.mu.Lock()
+	defer toolResultTypes.mu.Unlock()
+
+	if _, exists := toolResultTypes.byName[name]; exists {
+		return fmt.Errorf("%w: %s", ErrToolResultTypeAlreadyRegistered, name)
+	}
+	toolResultTypes.add(name, reflect.TypeFor[R]())
+
+	return nil
+}
+
+// typedToolResult is the serialized form of a tool result, the type discriminator is the name
+// the result type is registered with, or the Go type name for unregistered types
+type typedToolResult struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+type partialToolResultJSON struct {
+	llm.BaseLLMToolResult
+	Partial bool             `json:"partial"`
+	Result  *typedToolResult `json:"result"`
+}
+
+type messageJSON struct {
+	llm.LLMMessage
+	ToolResults []typedToolResult `json:"tool_result,omitempty"`
+}
+
+type agentResultJSON[T any] struct {
+	Data     *T             `json:"data"`
+	Messages []messageJSON  `json:"messages"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
+
+// ToJSON serializes the result, including the concrete types of the tool results,
+// see MarshalJSON
+func (r *AgentResult[T]) ToJSON() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+// AgentResultFromJSON deserializes a result created by AgentResult.ToJSON
+func AgentResultFromJSON[T any](data []byte) (*AgentResult[T], error) {
+	var result AgentResult[T]
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// MarshalJSON encodes every tool result in the messages together with its type, so that
+// UnmarshalJSON restores the concrete type instead of failing on the LLMToolResult interface
+func (r AgentResult[T]) MarshalJSON() ([]byte, error) {
+	messages := make([]messageJSON, len(r.Messages))
+	for i, msg := range r.Messages {
+		toolResults, err := encodeToolResults(msg.ToolResults)
+		if err != nil {
+			return nil, err
+		}
+
+		msg.ToolResults = nil
+		messages[i] = messageJSON{LLMMessage: msg, ToolResults: toolResults}
+	}
+
+	return json.Marshal(agentResultJSON[T]{Data: r.Data, Messages: messages, Metadata: r.Metadata})
+}
+
+// UnmarshalJSON decodes a result encoded by MarshalJSON. It fails with ErrUnknownToolResultType
+// when a tool result type is not registered with RegisterToolResultType.
+func (r *AgentResult[T]) UnmarshalJSON(data []byte) error {
+	var decoded agentResultJSON[T]
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	messages := make([]llm.LLMMessage, len(decoded.Messages))
+	for i, msg := range decoded.Messages {
+		toolResults, err := decodeToolResults(msg.ToolResults)
+		if err != nil {
+			return err
+		}
+
+		messages[i] = msg.LLMMessage
+		messages[i].ToolResults = toolResults
+	}
+
+	*r = AgentResult[T]{Data: decoded.Data, Messages: messages, Metadata: decoded.Metadata}
+
+	return nil
+}
+
+func encodeToolResults(results []llm.LLMToolResult) ([]typedToolResult, error) {
+	if results == nil {
+		return nil, nil
+	}
+
+	encoded := make([]typedToolResult, len(results))
+	for i, result := range results {
+		typed, err := encodeToolResult(result)
+		if err != nil {
+			return nil, err
+		}
+		encoded[i] = *typed
+	}
+
+	return encoded, nil
+}
+
+func encodeToolResult(result llm.LLMToolResult) (*typedToolResult, error) {
+	var value any = result
+
+	if partial, ok := result.(llm.PartialToolResult); ok {
+		partialJSON := partialToolResultJSON{BaseLLMToolResult: partial.BaseLLMToolResult, Partial: partial.Partial}
+		if partial.Result != nil {
+			nested, err := encodeToolResult(partial.Result)
+			if err != nil {
+				return nil, err
+			}
+			partialJSON.Result = nested
+		}
+		value = partialJSON
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal tool result %s: %w", result.GetID(), err)
+	}
+
+	return &typedToolResult{Type: toolResultTypeName(reflect.TypeOf(result)), Value: data}, nil
+}
+
+func toolResultTypeName(typ reflect.Type) string {
+	toolResultTypes.mu.RLock()
+	defer toolResultTypes.mu.RUnlock()
+
+	if name, ok := toolResultTypes.byType[typ]; ok {
+		return name
+	}
+
+	return typ.String()
+}
+
+func decodeToolResults(encoded []typedToolResult) ([]llm.LLMToolResult, error) {
+	if encoded == nil {
+		return nil, nil
+	}
+
+	results := make([]llm.LLMToolResult, len(encoded))
+	for i, typed := range encoded {
+		result, err := decodeToolResult(typed)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = result
+	}
+
+	return results, nil
+}
+
+func decodeToolResult(typed typedToolResult) (llm.LLMToolResult, error) {
+	if typed.Type == partialToolResultType {
+		var partialJSON partialToolResultJSON
+		if err := json.Unmarshal(typed.Value, &partialJSON); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal partial tool result: %w", err)
+		}
+
+		partial := llm.PartialToolResult{BaseLLMToolResult: partialJSON.BaseLLMToolResult, Partial: partialJSON.Partial}
+		if partialJSON.Result != nil {
+			nested, err := decodeToolResult(*partialJSON.Result)
+			if err != nil {
+				return nil, err
+			}
+			partial.Result = nested
+		}
+
+		return partial, nil
+	}
+
+	toolResultTypes.mu.RLock()
+	typ, ok := toolResultTypes.byName[typed.Type]
+	toolResultTypes.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownToolResultType, typed.Type)
+	}
+
+	value := reflect.New(typ)
+	if err := json.Unmarshal(typed.Value, value.Interface()); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tool result of type %s: %w", typed.Type, err)
+	}
+
+	result, ok := value.Elem().Interface().(llm.LLMToolResult)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s does not implement LLMToolResult", ErrUnknownToolResultType, typed.Type)
+	}
+
+	return result, nil
+}
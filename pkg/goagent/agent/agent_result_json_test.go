@@ -0,0 +1,124 @@
+package agent_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+type serializedSumResult struct {
+	llm.BaseLLMToolResult
+	Sum float64 `json:"sum"`
+}
+
+func TestAgentResult_ToJSON(t *testing.T) {
+	t.Parallel()
+
+	// given
+	require.NoError(t, agent.RegisterToolResultType[AddToolResult]("agent_result_json_add"))
+
+	addAgent, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("json_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You add numbers."),
+		agent.WithTool[AddNumbersResult]("add", createTestAddTool()),
+	)
+	require.NoError(t, err)
+
+	agent.SetLLM(addAgent, llmtest.NewMockLLM(`{"sum": 3}`,
+		toolCallMessage(
+			llm.LLMToolCall{ID: "call_1", ToolName: "add", Args: `{"num1": 1, "num2": 2}`},
+			llm.LLMToolCall{ID: "call_2", ToolName: "missing", Args: `{}`},
+		),
+		endMessage("done"),
+	))
+
+	result, err := addAgent.Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+	require.NoError(t, err)
+
+	// when
+	data, err := result.ToJSON()
+	require.NoError(t, err)
+
+	restored, err := agent.AgentResultFromJSON[AddNumbersResult](data)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, result.Data, restored.Data)
+	require.Len(t, restored.Messages, len(result.Messages))
+
+	toolResults := restored.Messages[2].ToolResults
+	require.Len(t, toolResults, 2)
+
+	addResult, isOK := toolResults[0].(AddToolResult)
+	require.True(t, isOK)
+	assert.Equal(t, "call_1", addResult.GetID())
+	assert.InDelta(t, 3.0, addResult.Sum, 0)
+
+	errorResult, isOK := toolResults[1].(llm.ErrorLLMToolResult)
+	require.True(t, isOK)
+	assert.Contains(t, errorResult.Error, agent.ErrToolNotFound.Error())
+}
+
+func TestAgentResult_ToJSON_PartialToolResult(t *testing.T) {
+	t.Parallel()
+
+	// given
+	require.NoError(t, agent.RegisterToolResultType[serializedSumResult]("agent_result_json_sum"))
+
+	partial := llm.PartialToolResult{
+		BaseLLMToolResult: llm.BaseLLMToolResult{ID: "call_1"},
+		Partial:           true,
+		Result:            serializedSumResult{BaseLLMToolResult: llm.BaseLLMToolResult{ID: "call_1"}, Sum: 1},
+	}
+	message := llm.LLMMessage{Type: llm.LLMMessageTypeAssistant, ToolResults: []llm.LLMToolResult{partial}}
+	result := &agent.AgentResult[AddNumbersResult]{Messages: []llm.LLMMessage{message}}
+
+	// when
+	data, err := result.ToJSON()
+	require.NoError(t, err)
+
+	restored, err := agent.AgentResultFromJSON[AddNumbersResult](data)
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, restored.Messages, 1)
+	assert.Equal(t, []llm.LLMToolResult{partial}, restored.Messages[0].ToolResults)
+}
+
+func TestAgentResultFromJSON_UnknownToolResultType(t *testing.T) {
+	t.Parallel()
+
+	// given
+	type unregisteredResult struct {
+		llm.BaseLLMToolResult
+	}
+
+	message := llm.LLMMessage{
+		Type:        llm.LLMMessageTypeAssistant,
+		ToolResults: []llm.LLMToolResult{unregisteredResult{BaseLLMToolResult: llm.BaseLLMToolResult{ID: "call_1"}}},
+	}
+	result := &agent.AgentResult[AddNumbersResult]{Messages: []llm.LLMMessage{message}}
+
+	data, err := result.ToJSON()
+	require.NoError(t, err)
+
+	// when
+	_, err = agent.AgentResultFromJSON[AddNumbersResult](data)
+
+	// then
+	require.ErrorIs(t, err, agent.ErrUnknownToolResultType)
+}
+
+func TestRegisterToolResultType_AlreadyRegistered(t *testing.T) {
+	t.Parallel()
+
+	err := agent.RegisterToolResultType[llm.ErrorLLMToolResult]("error")
+
+	require.ErrorIs(t, err, agent.ErrToolResultTypeAlreadyRegistered)
+}
@@ -0,0 +1,48 @@
+package agent_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+func TestAgentState_Timings(t *testing.T) {
+	t.Parallel()
+
+	var state *agent.AgentState
+
+	timedAgent, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("timed_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithMiddleware[AddNumbersResult](func(
+			_ context.Context, s *agent.AgentState, msg llm.LLMMessage,
+		) (llm.LLMMessage, error) {
+			state = s
+			assert.False(t, s.StartTime.IsZero())
+			assert.True(t, s.EndTime.IsZero())
+
+			return msg, nil
+		}),
+	)
+	require.NoError(t, err)
+
+	agent.SetLLM(timedAgent, llmtest.NewMockLLM(`{"sum": 3}`, endMessage("done")))
+
+	result, err := timedAgent.Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+	require.NoError(t, err)
+
+	require.NotNil(t, state)
+	assert.False(t, state.EndTime.Before(state.StartTime))
+	assert.Equal(t, state.EndTime.Sub(state.StartTime), state.Duration())
+	assert.Len(t, state.LLMCallDurations(), 2, "one tool loop call and one structured output call")
+
+	for _, msg := range result.Messages {
+		assert.False(t, msg.Timestamp.IsZero(), "message %s has no timestamp", msg.Type)
+	}
+}
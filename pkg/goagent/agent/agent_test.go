@@ -10,6 +10,7 @@ import (
 
 	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
 	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -907,3 +908,56 @@ func verifyMiddlewareChaining(
 	t.Logf("📝 Middleware execution order: %v", executionOrder)
 	t.Logf("✅ Final result: %d", result.Data.Sum)
 }
+
+func TestWithToolPriority(t *testing.T) {
+	t.Parallel()
+
+	var executionOrder []string
+	createRecordingTool := func(name string) llm.LLMTool {
+		tool, err := llm.NewLLMTool(
+			llm.WithLLMToolName(name),
+			llm.WithLLMToolDescription("Records its invocation"),
+			llm.WithLLMToolParametersSchema[AddToolParams](),
+			llm.WithLLMToolCall(func(callID string, params AddToolParams) (AddToolResult, error) {
+				executionOrder = append(executionOrder, name)
+
+				return AddToolResult{BaseLLMToolResult: llm.BaseLLMToolResult{ID: callID}}, nil
+			}),
+		)
+		require.NoError(t, err)
+
+		return tool
+	}
+
+	priorityAgent, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("priority_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithTool[AddNumbersResult]("expensive", createRecordingTool("expensive")),
+		agent.WithTool[AddNumbersResult]("sanity_check", createRecordingTool("sanity_check")),
+		agent.WithTool[AddNumbersResult]("regular", createRecordingTool("regular")),
+		agent.WithToolPriority[AddNumbersResult]("expensive", 10),
+		agent.WithToolPriority[AddNumbersResult]("sanity_check", -1),
+	)
+	require.NoError(t, err)
+
+	agent.SetLLM(priorityAgent, llmtest.NewMockLLM(`{"sum": 0}`,
+		toolCallMessage(
+			llm.LLMToolCall{ID: "1", ToolName: "expensive", Args: `{}`},
+			llm.LLMToolCall{ID: "2", ToolName: "regular", Args: `{}`},
+			llm.LLMToolCall{ID: "3", ToolName: "sanity_check", Args: `{}`},
+		),
+		endMessage("done"),
+	))
+
+	result, err := priorityAgent.Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sanity_check", "regular", "expensive"}, executionOrder)
+
+	toolResults := result.Messages[2].ToolResults
+	require.Len(t, toolResults, 3)
+	assert.Equal(t, "3", toolResults[0].GetID())
+	assert.Equal(t, "2", toolResults[1].GetID())
+	assert.Equal(t, "1", toolResults[2].GetID())
+}
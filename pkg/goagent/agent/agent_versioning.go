@@ -0,0 +1,36 @@
+package agent
+
+import (
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+)
+
+// MetadataAgentVersion is the AgentResult.Metadata key holding the version set by
+// WithAgentVersioning
+const MetadataAgentVersion = "agent_version"
+
+const agentVersionPattern = `^\d+\.\d+\.\d+$`
+
+// WithAgentVersioning tags the agent with a semantic version such as 1.4.0, which should
+// change whenever the behavior or the output schema changes, because results of the old and
+// the new version may differ in meaning. The version is stored in
+// AgentResult.Metadata[MetadataAgentVersion], in the agent_version column of WithToolCallLog
+// and in the agent.version attribute of the run span, so consumers can route or filter
+// results by version.
+func WithAgentVersioning[T any](version string) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.version = version
+		a.versionErr = validation.StringMatchesPattern(version, agentVersionPattern)
+	}
+}
+
+// tagVersion stores the version of the agent in the metadata of the result
+func (a *Agent[T]) tagVersion(result *AgentResult[T]) {
+	if a.version == "" || result == nil {
+		return
+	}
+
+	if result.Metadata == nil {
+		result.Metadata = make(map[string]any)
+	}
+	result.Metadata[MetadataAgentVersion] = a.version
+}
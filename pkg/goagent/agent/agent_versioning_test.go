@@ -0,0 +1,87 @@
+package agent_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func createVersionedAgent(options ...agent.AgentOption[AddNumbersResult]) (*agent.Agent[AddNumbersResult], error) {
+	return agent.NewAgent(append([]agent.AgentOption[AddNumbersResult]{
+		agent.WithName[AddNumbersResult]("versioned_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithTool[AddNumbersResult]("add", createTestAddTool()),
+	}, options...)...)
+}
+
+func TestWithAgentVersioning(t *testing.T) {
+	t.Parallel()
+
+	// given
+	var logBuffer bytes.Buffer
+	spans, tracerProvider := newSpanRecorder()
+	cfg := agent.NewDefaultObservabilityConfig("calculator_service")
+	cfg.TracerProvider = tracerProvider
+
+	versionedAgent, err := createVersionedAgent(
+		agent.WithAgentVersioning[AddNumbersResult]("1.4.0"),
+		agent.WithToolCallLog[AddNumbersResult](&logBuffer),
+		agent.WithObservabilityMiddleware[AddNumbersResult](cfg),
+	)
+	require.NoError(t, err)
+
+	mockLLM := llmtest.NewMockLLM(`{"sum": 3}`,
+		toolCallMessage(llm.LLMToolCall{ID: "call_1", ToolName: "add", Args: `{"num1": 1, "num2": 2}`}),
+		endMessage("done"),
+	)
+
+	// when
+	result, err := versionedAgent.UsingLLM(mockLLM).Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "1.4.0", result.Metadata[agent.MetadataAgentVersion])
+
+	rows, err := csv.NewReader(&logBuffer).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, "agent_version", rows[0][8])
+	assert.Equal(t, "1.4.0", rows[1][8])
+
+	require.Len(t, spans.Ended(), 1)
+	assert.Contains(t, spans.Ended()[0].Attributes(), attribute.String("agent.version", "1.4.0"))
+}
+
+func TestWithAgentVersioning_Disabled(t *testing.T) {
+	t.Parallel()
+
+	versionedAgent, err := createVersionedAgent()
+	require.NoError(t, err)
+
+	result, err := versionedAgent.UsingLLM(llmtest.NewMockLLM(`{"sum": 3}`, endMessage("done"))).
+		Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+
+	require.NoError(t, err)
+	assert.NotContains(t, result.Metadata, agent.MetadataAgentVersion)
+}
+
+func TestWithAgentVersioning_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, version := range []string{"", "1.4", "v1.4.0", "1.4.0-beta"} {
+		_, err := createVersionedAgent(agent.WithAgentVersioning[AddNumbersResult](version))
+
+		require.ErrorIs(t, err, validation.ErrValidationFailed, "version %q", version)
+		assert.Contains(t, err.Error(), "agent version")
+	}
+}
@@ -0,0 +1,56 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+)
+
+const defaultMaxBehaviorLength = 10_000
+
+var (
+	// ErrBehaviorTooLong is returned by NewAgent when the behavior exceeds the maximum length
+	ErrBehaviorTooLong = errors.New("behavior too long")
+	// ErrBehaviorTooShort is returned by NewAgent when the behavior is shorter than the minimum length
+	ErrBehaviorTooShort = errors.New("behavior too short")
+)
+
+// WithMaxBehaviorLength sets the maximum length of the behavior in characters, 10,000 by
+// default. Long behaviors bloat the system prompt of every LLM call, so NewAgent rejects them
+// with ErrBehaviorTooLong.
+func WithMaxBehaviorLength[T any](chars int) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.maxBehaviorLength = chars
+	}
+}
+
+// WithMinBehaviorLength sets the minimum length of the behavior in characters, for agents
+// which require specific instructions. NewAgent rejects shorter behaviors with ErrBehaviorTooShort.
+func WithMinBehaviorLength[T any](chars int) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.minBehaviorLength = chars
+	}
+}
+
+func (a *Agent[T]) validateBehaviorLength() error {
+	if err := validation.IntIsPositive(a.maxBehaviorLength, "max behavior length"); err != nil {
+		return err
+	}
+	if err := validation.IntIsInRange(a.minBehaviorLength, 0, a.maxBehaviorLength, "min behavior length"); err != nil {
+		return err
+	}
+
+	length := utf8.RuneCountInString(a.behavior)
+	if length > a.maxBehaviorLength {
+		return fmt.Errorf("%w: %w: %d characters, the maximum is %d",
+			validation.ErrValidationFailed, ErrBehaviorTooLong, length, a.maxBehaviorLength)
+	}
+	if length < a.minBehaviorLength {
+		return fmt.Errorf("%w: %w: %d characters, the minimum is %d",
+			validation.ErrValidationFailed, ErrBehaviorTooShort, length, a.minBehaviorLength)
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package agent_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+)
+
+func createBehaviorAgent(behavior string, opts ...agent.AgentOption[AddNumbersResult]) error {
+	opts = append([]agent.AgentOption[AddNumbersResult]{
+		agent.WithName[AddNumbersResult]("behavior_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult](behavior),
+	}, opts...)
+
+	_, err := agent.NewAgent(opts...)
+
+	return err
+}
+
+func TestWithMaxBehaviorLength(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		behavior string
+		opts     []agent.AgentOption[AddNumbersResult]
+		wantErr  error
+	}{
+		{
+			name:     "default maximum",
+			behavior: strings.Repeat("a", 10_000),
+		},
+		{
+			name:     "exceeds default maximum",
+			behavior: strings.Repeat("a", 10_001),
+			wantErr:  agent.ErrBehaviorTooLong,
+		},
+		{
+			name:     "counts characters rather than bytes",
+			behavior: strings.Repeat("ж", 10),
+			opts:     []agent.AgentOption[AddNumbersResult]{agent.WithMaxBehaviorLength[AddNumbersResult](10)},
+		},
+		{
+			name:     "exceeds custom maximum",
+			behavior: "You are a calculator.",
+			opts:     []agent.AgentOption[AddNumbersResult]{agent.WithMaxBehaviorLength[AddNumbersResult](10)},
+			wantErr:  agent.ErrBehaviorTooLong,
+		},
+		{
+			name:     "shorter than minimum",
+			behavior: "Add.",
+			opts:     []agent.AgentOption[AddNumbersResult]{agent.WithMinBehaviorLength[AddNumbersResult](10)},
+			wantErr:  agent.ErrBehaviorTooShort,
+		},
+		{
+			name:     "non-positive maximum",
+			behavior: "You are a calculator.",
+			opts:     []agent.AgentOption[AddNumbersResult]{agent.WithMaxBehaviorLength[AddNumbersResult](0)},
+			wantErr:  validation.ErrValidationFailed,
+		},
+		{
+			name:     "minimum above maximum",
+			behavior: "You are a calculator.",
+			opts: []agent.AgentOption[AddNumbersResult]{
+				agent.WithMinBehaviorLength[AddNumbersResult](100),
+				agent.WithMaxBehaviorLength[AddNumbersResult](50),
+			},
+			wantErr: validation.ErrValidationFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := createBehaviorAgent(tt.behavior, tt.opts...)
+
+			if tt.wantErr == nil {
+				require.NoError(t, err)
+
+				return
+			}
+			require.ErrorIs(t, err, tt.wantErr)
+			require.ErrorIs(t, err, validation.ErrValidationFailed)
+			assert.Contains(t, err.Error(), "behavior")
+		})
+	}
+}
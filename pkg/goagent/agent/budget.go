@@ -0,0 +1,96 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+// budgetWarningRatio is the share of the budget at which a warning is logged
+const budgetWarningRatio = 0.8
+
+var (
+	// ErrBudgetExceeded is returned when the estimated cost of a run exceeds the limit set by WithBudget
+	ErrBudgetExceeded = errors.New("budget exceeded")
+	// ErrUnknownModelPrice is returned when the budget is set for a model without a known price
+	ErrUnknownModelPrice = errors.New("unknown model price")
+)
+
+// WithBudget stops runs whose estimated cost exceeds maxUSD. The cost is estimated after
+// every LLM call from the token usage of the run, see AgentState.TotalUsage, and the price
+// of the model from llm.LookupModelPrice or WithModelPrice. When the budget is exceeded, Run
+// returns ErrBudgetExceeded together with the messages of the run. A warning is logged once
+// the cost reaches 80% of the budget. With WithSpeculativeExecution the budget applies to
+// all branches of a run together.
+//
+// The final call producing the structured output is not counted, because
+// llm.LLM.CallWithStructuredOutput does not report token usage.
+func WithBudget[T any](maxUSD float64) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.budgetUSD = &maxUSD
+	}
+}
+
+// WithModelPrice sets the price used by WithBudget, for models missing from the built-in
+// price table or with negotiated prices
+func WithModelPrice[T any](price llm.ModelPrice) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.modelPrice = &price
+	}
+}
+
+func (a *Agent[T]) validateBudget() error {
+	if a.budgetUSD == nil {
+		return nil
+	}
+	if *a.budgetUSD <= 0 {
+		return fmt.Errorf("%w: budget must be positive, got %v", validation.ErrValidationFailed, *a.budgetUSD)
+	}
+
+	_, err := a.resolveModelPrice()
+
+	return err
+}
+
+func (a *Agent[T]) resolveModelPrice() (llm.ModelPrice, error) {
+	if a.modelPrice != nil {
+		return *a.modelPrice, nil
+	}
+
+	price, ok := llm.LookupModelPrice(a.llmConfig.Model)
+	if !ok {
+		return llm.ModelPrice{}, fmt.Errorf("%w: %s, set it with WithModelPrice",
+			ErrUnknownModelPrice, a.llmConfig.Model)
+	}
+
+	return price, nil
+}
+
+// checkBudget returns ErrBudgetExceeded when the estimated cost of the run exceeds the budget
+func (a *Agent[T]) checkBudget(ctx context.Context, state *AgentState) error {
+	if a.budgetUSD == nil {
+		return nil
+	}
+
+	price, err := a.resolveModelPrice()
+	if err != nil {
+		return err
+	}
+
+	cost := price.Cost(state.budgetUsage())
+	if cost > *a.budgetUSD {
+		return fmt.Errorf("%w: estimated cost %.4f USD, budget %.4f USD", ErrBudgetExceeded, cost, *a.budgetUSD)
+	}
+
+	if !state.budgetWarned && cost >= *a.budgetUSD*budgetWarningRatio {
+		state.budgetWarned = true
+		slog.WarnContext(ctx, "agent run is close to its budget",
+			"agent", a.name, "cost_usd", cost, "budget_usd", *a.budgetUSD)
+	}
+
+	return nil
+}
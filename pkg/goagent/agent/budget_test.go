@@ -0,0 +1,125 @@
+package agent_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+// oneDollarPerMillion makes the cost of a run in USD its token count divided by a million
+var oneDollarPerMillion = llm.ModelPrice{InputPerMillion: 1, OutputPerMillion: 1}
+
+func withUsage(msg llm.LLMMessage, tokens int) llm.LLMMessage {
+	msg.Usage = llm.TokenUsage{InputTokens: tokens / 2, OutputTokens: tokens - tokens/2}
+
+	return msg
+}
+
+func createBudgetAgent(
+	t *testing.T, opts ...agent.AgentOption[AddNumbersResult],
+) (*agent.Agent[AddNumbersResult], error) {
+	t.Helper()
+
+	opts = append([]agent.AgentOption[AddNumbersResult]{
+		agent.WithName[AddNumbersResult]("budget_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithTool[AddNumbersResult]("add", createTestAddTool()),
+	}, opts...)
+
+	return agent.NewAgent(opts...)
+}
+
+func TestWithBudget(t *testing.T) {
+	t.Parallel()
+
+	// given
+	budgetAgent, err := createBudgetAgent(t,
+		agent.WithBudget[AddNumbersResult](0.001),
+		agent.WithModelPrice[AddNumbersResult](oneDollarPerMillion),
+	)
+	require.NoError(t, err)
+
+	agent.SetLLM(budgetAgent, llmtest.NewMockLLM(`{"sum": 3}`,
+		withUsage(toolCallMessage(addCall("call_1")), 600),
+		withUsage(toolCallMessage(addCall("call_2")), 600),
+		endMessage("done"),
+	))
+
+	// when
+	result, err := budgetAgent.Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+
+	// then
+	require.ErrorIs(t, err, agent.ErrBudgetExceeded)
+	require.NotNil(t, result)
+	assert.Nil(t, result.Data)
+
+	last := result.Messages[len(result.Messages)-1]
+	assert.Equal(t, "call_2", last.ToolCalls[0].ID)
+	assert.Empty(t, last.ToolResults)
+}
+
+func TestWithBudget_WithinBudget(t *testing.T) {
+	t.Parallel()
+
+	// given
+	budgetAgent, err := createBudgetAgent(t,
+		agent.WithBudget[AddNumbersResult](0.01),
+		agent.WithModelPrice[AddNumbersResult](oneDollarPerMillion),
+	)
+	require.NoError(t, err)
+
+	agent.SetLLM(budgetAgent, llmtest.NewMockLLM(`{"sum": 3}`,
+		withUsage(toolCallMessage(addCall("call_1")), 4000),
+		withUsage(endMessage("done"), 5000),
+	))
+
+	// when
+	result, err := budgetAgent.Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Data.Sum)
+}
+
+func TestWithBudget_Validation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		opts    []agent.AgentOption[AddNumbersResult]
+		wantErr error
+	}{
+		{
+			name:    "not positive",
+			opts:    []agent.AgentOption[AddNumbersResult]{agent.WithBudget[AddNumbersResult](0)},
+			wantErr: validation.ErrValidationFailed,
+		},
+		{
+			name: "unknown model",
+			opts: []agent.AgentOption[AddNumbersResult]{
+				agent.WithLLMConfig[AddNumbersResult](llm.LLMConfig{
+					Type: llm.LLMTypeOpenAI, APIKey: "test-key", Model: "custom-model",
+				}),
+				agent.WithBudget[AddNumbersResult](1),
+			},
+			wantErr: agent.ErrUnknownModelPrice,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := createBudgetAgent(t, tt.opts...)
+
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
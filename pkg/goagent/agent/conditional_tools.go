@@ -0,0 +1,61 @@
+package agent
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+// ToolCondition decides whether a conditional tool is available in a run
+type ToolCondition func(ctx context.Context) bool
+
+// WithConditionalTool adds a tool which is available only in runs where predicate returns
+// true for the run context, e.g. when the user stored in the context has the admin role.
+// The predicate is evaluated once at the start of every Run. When it returns false, the tool
+// is removed from the tools offered to the LLM, marked as unavailable in the system prompt,
+// and calls to it are rejected with ErrToolNotFound.
+//
+// Example:
+//
+//	agent.WithConditionalTool[Result]("delete_user", deleteUserTool, func(ctx context.Context) bool {
+//		return auth.UserFromContext(ctx).IsAdmin()
+//	})
+func WithConditionalTool[T any](name string, tool llm.LLMTool, predicate ToolCondition) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.addTool(name, tool)
+		if a.toolConditions == nil {
+			a.toolConditions = make(map[string]ToolCondition)
+		}
+		a.toolConditions[name] = predicate
+	}
+}
+
+// withConditionalTools returns the agent to use for a run with the given context. When a
+// conditional tool is unavailable, it is a copy of the agent without that tool.
+func (a *Agent[T]) withConditionalTools(ctx context.Context) *Agent[T] {
+	var unavailable []string
+	for _, name := range slices.Sorted(maps.Keys(a.toolConditions)) {
+		if _, registered := a.tools[name]; registered && !a.toolConditions[name](ctx) {
+			unavailable = append(unavailable, name)
+		}
+	}
+
+	if len(unavailable) == 0 {
+		return a
+	}
+
+	clone := *a
+	clone.tools = maps.Clone(a.tools)
+	for _, name := range unavailable {
+		delete(clone.tools, name)
+	}
+	clone.unavailableTools = unavailable
+
+	if restrictor, ok := a.llm.(llm.ToolRestrictor); ok {
+		clone.llm = restrictor.RestrictTools(slices.Collect(maps.Keys(clone.llmTools())))
+	}
+
+	return &clone
+}
@@ -0,0 +1,112 @@
+package agent_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+type roleKey struct{}
+
+func isAdmin(ctx context.Context) bool {
+	role, _ := ctx.Value(roleKey{}).(string)
+
+	return role == "admin"
+}
+
+func TestWithConditionalTool(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name              string
+		role              string
+		expectUnavailable bool
+	}{
+		{"predicate true", "admin", false},
+		{"predicate false", "viewer", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			conditionalAgent, err := agent.NewAgent(
+				agent.WithName[AddNumbersResult]("conditional_agent"),
+				agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+				agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+				agent.WithConditionalTool[AddNumbersResult]("add", createTestAddTool(), isAdmin),
+			)
+			require.NoError(t, err)
+
+			mockLLM := llmtest.NewMockLLM(`{"sum": 3}`,
+				toolCallMessage(llm.LLMToolCall{ID: "call_1", ToolName: "add", Args: `{"num1": 1, "num2": 2}`}),
+				endMessage("done"),
+			)
+			ctx := context.WithValue(context.Background(), roleKey{}, tt.role)
+
+			// when
+			result, err := conditionalAgent.UsingLLM(mockLLM).Run(ctx, AddNumbers{Num1: 1, Num2: 2})
+
+			// then
+			require.NoError(t, err)
+
+			systemPrompt := mockLLM.Calls()[0][0].Content
+			toolResult := result.Messages[2].ToolResults[0]
+			if tt.expectUnavailable {
+				assert.Contains(t, systemPrompt, "TOOLS UNAVAILABLE IN THIS RUN (do not call them):\nadd")
+				assert.NotContains(t, systemPrompt, "Adds two numbers")
+
+				errorResult, ok := toolResult.(llm.ErrorLLMToolResult)
+				require.True(t, ok)
+				assert.Contains(t, errorResult.Error, agent.ErrToolNotFound.Error())
+			} else {
+				assert.NotContains(t, systemPrompt, "TOOLS UNAVAILABLE")
+				assert.Contains(t, systemPrompt, "Adds two numbers")
+				addResult, ok := toolResult.(AddToolResult)
+				require.True(t, ok)
+				assert.InDelta(t, 3, addResult.Sum, 0)
+			}
+		})
+	}
+}
+
+func TestWithConditionalTool_EvaluatedPerRun(t *testing.T) {
+	t.Parallel()
+
+	// given
+	var evaluations int
+	conditionalAgent, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("conditional_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithConditionalTool[AddNumbersResult]("add", createTestAddTool(), func(ctx context.Context) bool {
+			evaluations++
+
+			return isAdmin(ctx)
+		}),
+	)
+	require.NoError(t, err)
+
+	adminCtx := context.WithValue(context.Background(), roleKey{}, "admin")
+	for range 2 {
+		mockLLM := llmtest.NewMockLLM(`{"sum": 3}`,
+			toolCallMessage(llm.LLMToolCall{ID: "call_1", ToolName: "add", Args: `{"num1": 1, "num2": 2}`}),
+			toolCallMessage(llm.LLMToolCall{ID: "call_2", ToolName: "add", Args: `{"num1": 3, "num2": 0}`}),
+			endMessage("done"),
+		)
+
+		// when
+		_, err := conditionalAgent.UsingLLM(mockLLM).Run(adminCtx, AddNumbers{Num1: 1, Num2: 2})
+
+		// then
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, 2, evaluations)
+}
@@ -0,0 +1,94 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+const redactedPlaceholder = "[REDACTED]"
+
+var (
+	// ErrContentPolicyViolation is returned when LLM output matches a content filter pattern
+	ErrContentPolicyViolation = errors.New("content policy violation")
+	// ErrInvalidContentFilterPattern is returned when a content filter pattern is not a valid regexp
+	ErrInvalidContentFilterPattern = errors.New("invalid content filter pattern")
+)
+
+type contentFilter struct {
+	patterns    []*regexp.Regexp
+	returnError bool
+}
+
+// ContentFilterOption configures a content filter middleware
+type ContentFilterOption func(*contentFilter)
+
+// WithContentFilterError makes the content filter return ErrContentPolicyViolation
+// instead of redacting matched content
+func WithContentFilterError() ContentFilterOption {
+	return func(f *contentFilter) {
+		f.returnError = true
+	}
+}
+
+// NewContentFilterMiddleware creates a middleware that scans LLM message content for
+// the given regular expressions. By default, every match is replaced with [REDACTED]
+// and a warning is logged. Use WithContentFilterError to fail the run instead.
+//
+// Example:
+//
+//	filter, err := agent.NewContentFilterMiddleware([]string{
+//		`\b\d{3}-\d{2}-\d{4}\b`, // SSN
+//		`sk-[A-Za-z0-9]{20,}`,   // secret tokens
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	myAgent, err := agent.NewAgent(
+//		// ... other options
+//		agent.WithMiddleware[MyResult](filter),
+//	)
+func NewContentFilterMiddleware(patterns []string, options ...ContentFilterOption) (AgentMiddleware, error) {
+	filter := &contentFilter{
+		patterns: make([]*regexp.Regexp, 0, len(patterns)),
+	}
+	for _, opt := range options {
+		opt(filter)
+	}
+
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidContentFilterPattern, pattern, err)
+		}
+		filter.patterns = append(filter.patterns, re)
+	}
+
+	return filter.apply, nil
+}
+
+func (f *contentFilter) apply(
+	ctx context.Context,
+	_ *AgentState,
+	llmMessage llm.LLMMessage,
+) (llm.LLMMessage, error) {
+	for _, re := range f.patterns {
+		if !re.MatchString(llmMessage.Content) {
+			continue
+		}
+
+		if f.returnError {
+			return llm.LLMMessage{}, fmt.Errorf("%w: content matches pattern %s", ErrContentPolicyViolation, re)
+		}
+
+		slog.WarnContext(ctx, "content filter redacted LLM message content", "pattern", re.String())
+		llmMessage.Content = re.ReplaceAllString(llmMessage.Content, redactedPlaceholder)
+	}
+
+	return llmMessage, nil
+}
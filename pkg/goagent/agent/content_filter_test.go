@@ -0,0 +1,63 @@
+package agent_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+const ssnPattern = `\b\d{3}-\d{2}-\d{4}\b`
+
+func TestContentFilterMiddleware_Redacts(t *testing.T) {
+	t.Parallel()
+
+	filter, err := agent.NewContentFilterMiddleware([]string{ssnPattern, `sk-[A-Za-z0-9]+`})
+	require.NoError(t, err)
+
+	msg := llm.NewLLMMessage(llm.LLMMessageTypeAssistant, "SSN is 123-45-6789 and key is sk-abc123")
+
+	result, err := filter(context.Background(), &agent.AgentState{}, msg)
+
+	require.NoError(t, err)
+	assert.Equal(t, "SSN is [REDACTED] and key is [REDACTED]", result.Content)
+}
+
+func TestContentFilterMiddleware_NoMatch(t *testing.T) {
+	t.Parallel()
+
+	filter, err := agent.NewContentFilterMiddleware([]string{ssnPattern})
+	require.NoError(t, err)
+
+	msg := llm.NewLLMMessage(llm.LLMMessageTypeAssistant, "Nothing sensitive here")
+
+	result, err := filter(context.Background(), &agent.AgentState{}, msg)
+
+	require.NoError(t, err)
+	assert.Equal(t, msg, result)
+}
+
+func TestContentFilterMiddleware_ErrorMode(t *testing.T) {
+	t.Parallel()
+
+	filter, err := agent.NewContentFilterMiddleware([]string{ssnPattern}, agent.WithContentFilterError())
+	require.NoError(t, err)
+
+	msg := llm.NewLLMMessage(llm.LLMMessageTypeAssistant, "SSN is 123-45-6789")
+
+	_, err = filter(context.Background(), &agent.AgentState{}, msg)
+
+	require.ErrorIs(t, err, agent.ErrContentPolicyViolation)
+}
+
+func TestContentFilterMiddleware_InvalidPattern(t *testing.T) {
+	t.Parallel()
+
+	filter, err := agent.NewContentFilterMiddleware([]string{"("})
+
+	require.ErrorIs(t, err, agent.ErrInvalidContentFilterPattern)
+	assert.Nil(t, filter)
+}
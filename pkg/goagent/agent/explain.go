@@ -0,0 +1,146 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmfactory"
+)
+
+// ErrExplanationUnavailable is returned when a result was produced without any tool calls
+var ErrExplanationUnavailable = errors.New("explanation unavailable: result was not derived from tool calls")
+
+var explainPromptTemplate = NewPrompt(`You explain how an AI agent reached its final result.
+
+You are given the final result and every tool call the agent made, with arguments and results.
+Write a concise prose explanation for a non-technical reader that:
+- Describes which tool calls contributed to each piece of the result
+- Points out any field of the result that is not supported by a tool result
+- Does not invent tool calls or data that is not listed below
+
+FINAL RESULT:
+{{.result}}
+
+TOOL CALLS:
+{{.tool_calls}}
+`)
+
+type explainer[T any] struct {
+	llmConfig llm.LLMConfig
+	llm       llm.LLM
+}
+
+// ExplainOption configures Explain
+type ExplainOption[T any] func(*explainer[T])
+
+// WithExplainerLLMConfig sets the LLM used to generate the explanation.
+// It can be a cheaper model than the one used by the agent itself.
+func WithExplainerLLMConfig[T any](cfg llm.LLMConfig) ExplainOption[T] {
+	return func(e *explainer[T]) {
+		e.llmConfig = cfg
+	}
+}
+
+type explainedToolCall struct {
+	ToolName string            `json:"tool_name"`
+	Args     string            `json:"args"`
+	Result   llm.LLMToolResult `json:"result,omitempty"`
+}
+
+// Explain generates a human-readable explanation of how result was reached, based on
+// the tool calls recorded in result.Messages. Returns ErrExplanationUnavailable when no
+// tool calls were made, since the result then comes from the LLM's own knowledge.
+//
+// Example:
+//
+//	explanation, err := agent.Explain(ctx, result,
+//		agent.WithExplainerLLMConfig[MyResult](llm.LLMConfig{
+//			Type:   llm.LLMTypeOpenAI,
+//			APIKey: apiKey,
+//			Model:  "gpt-4o-mini",
+//		}),
+//	)
+func Explain[T any](ctx context.Context, result *AgentResult[T], options ...ExplainOption[T]) (string, error) {
+	exp := &explainer[T]{}
+	for _, opt := range options {
+		opt(exp)
+	}
+
+	if result == nil {
+		return "", fmt.Errorf("result: %w: value cannot be nil", validation.ErrValidationFailed)
+	}
+
+	toolCalls := collectToolCalls(result.Messages)
+	if len(toolCalls) == 0 {
+		return "", ErrExplanationUnavailable
+	}
+
+	if exp.llm == nil {
+		if err := exp.llmConfig.Validate(); err != nil {
+			return "", fmt.Errorf("explainer llm config: %w", err)
+		}
+
+		explainerLLM, err := llmfactory.CreateLLM(exp.llmConfig, nil)
+		if err != nil {
+			return "", fmt.Errorf("failed to create explainer LLM: %w", err)
+		}
+		exp.llm = explainerLLM
+	}
+
+	prompt, err := createExplainPrompt(result.Data, toolCalls)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := exp.llm.Call(ctx, []llm.LLMMessage{
+		llm.NewLLMMessage(llm.LLMMessageTypeSystem, prompt),
+		llm.NewLLMMessage(llm.LLMMessageTypeUser, "Explain how the final result was reached."),
+	})
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrLLMCall, err)
+	}
+
+	return response.Content, nil
+}
+
+func collectToolCalls(messages []llm.LLMMessage) []explainedToolCall {
+	var toolCalls []explainedToolCall
+
+	for _, msg := range messages {
+		results := make(map[string]llm.LLMToolResult, len(msg.ToolResults))
+		for _, toolRes := range msg.ToolResults {
+			results[toolRes.GetID()] = toolRes
+		}
+
+		for _, toolCall := range msg.ToolCalls {
+			toolCalls = append(toolCalls, explainedToolCall{
+				ToolName: toolCall.ToolName,
+				Args:     toolCall.Args,
+				Result:   results[toolCall.ID],
+			})
+		}
+	}
+
+	return toolCalls
+}
+
+func createExplainPrompt(data any, toolCalls []explainedToolCall) (string, error) {
+	resultJSON, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	toolCallsJSON, err := json.MarshalIndent(toolCalls, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal tool calls: %w", err)
+	}
+
+	return explainPromptTemplate.Render(map[string]any{
+		"result":     string(resultJSON),
+		"tool_calls": string(toolCallsJSON),
+	})
+}
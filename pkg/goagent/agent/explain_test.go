@@ -0,0 +1,66 @@
+package agent_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+func TestExplain(t *testing.T) {
+	t.Parallel()
+
+	result := &agent.AgentResult[AddNumbersResult]{
+		Data: &AddNumbersResult{Sum: 8},
+		Messages: []llm.LLMMessage{
+			llm.NewLLMMessage(llm.LLMMessageTypeSystem, "system"),
+			{
+				Type:      llm.LLMMessageTypeAssistant,
+				ToolCalls: []llm.LLMToolCall{{ID: "call_1", ToolName: "add", Args: `{"num1":3,"num2":5}`}},
+				ToolResults: []llm.LLMToolResult{
+					AddToolResult{BaseLLMToolResult: llm.BaseLLMToolResult{ID: "call_1"}, Sum: 8},
+				},
+			},
+		},
+	}
+	explainerLLM := llmtest.NewMockLLM("", endMessage("The sum 8 comes from the add tool."))
+
+	explanation, err := agent.Explain(context.Background(), result,
+		agent.WithExplainerLLM[AddNumbersResult](explainerLLM))
+
+	require.NoError(t, err)
+	assert.Equal(t, "The sum 8 comes from the add tool.", explanation)
+
+	require.Equal(t, 1, len(explainerLLM.Calls()))
+	systemPrompt := explainerLLM.Calls()[0][0].Content
+	assert.Contains(t, systemPrompt, `"sum": 8`)
+	assert.Contains(t, systemPrompt, `"tool_name": "add"`)
+}
+
+func TestExplain_NoToolCalls(t *testing.T) {
+	t.Parallel()
+
+	result := &agent.AgentResult[AddNumbersResult]{
+		Data:     &AddNumbersResult{Sum: 8},
+		Messages: []llm.LLMMessage{llm.NewLLMMessage(llm.LLMMessageTypeAssistant, "8")},
+	}
+
+	_, err := agent.Explain(context.Background(), result,
+		agent.WithExplainerLLMConfig[AddNumbersResult](testLLMConfig()))
+
+	require.ErrorIs(t, err, agent.ErrExplanationUnavailable)
+}
+
+func TestExplain_NilResult(t *testing.T) {
+	t.Parallel()
+
+	_, err := agent.Explain[AddNumbersResult](context.Background(), nil,
+		agent.WithExplainerLLMConfig[AddNumbersResult](testLLMConfig()))
+
+	require.ErrorIs(t, err, validation.ErrValidationFailed)
+}
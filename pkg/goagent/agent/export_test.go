@@ -0,0 +1,48 @@
+package agent
+
+import (
+	"context"
+	"time"
+
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+// SetLLM replaces the agent's LLM so tests can run without a real provider
+func SetLLM[T any](a *Agent[T], agentLLM llm.LLM) {
+	a.llm = agentLLM
+}
+
+// WithExplainerLLM replaces the explainer LLM so tests can run without a real provider
+func WithExplainerLLM[T any](explainerLLM llm.LLM) ExplainOption[T] {
+	return func(e *explainer[T]) {
+		e.llm = explainerLLM
+	}
+}
+
+// RunLLM returns the LLM the agent would use for a run with the given context
+func RunLLM[T any](ctx context.Context, a *Agent[T]) (llm.LLM, error) {
+	runAgent, err := a.withLLMConfigOverride(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return runAgent.llm, nil
+}
+
+// SetUnionLLM replaces the LLM of a union agent so tests can run without a real provider
+func SetUnionLLM[A any, B any](u *UnionAgent[A, B], agentLLM llm.LLM) {
+	u.agent.llm = agentLLM
+}
+
+// CachedEntries returns how many entries, expired or not, the cache holds
+func CachedEntries[T any](c *TTLOutputCache[T]) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.entries)
+}
+
+// RetryDelay returns how long RunWithRetry waits before the retry with the given number
+func RetryDelay[T any](a *Agent[T], attempt int) time.Duration {
+	return a.retryBackoff.delay(attempt)
+}
@@ -0,0 +1,52 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// MetadataUsedFallback is the AgentResult.Metadata key set to true when the result
+// was produced by the fallback agent
+const MetadataUsedFallback = "used_fallback"
+
+// WithAgentFallback sets an agent, e.g. a cheaper one, which Run delegates to with the
+// same input when the primary agent fails with ErrLimitReached or ErrLLMCall. Other errors
+// and errors after ctx is cancelled are returned directly. The fallback result is marked
+// with MetadataUsedFallback.
+func WithAgentFallback[T any](fallbackAgent *Agent[T]) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.fallback = fallbackAgent
+	}
+}
+
+func (a *Agent[T]) runFallback(
+	ctx context.Context,
+	input any,
+	result *AgentResult[T],
+	err error,
+) (*AgentResult[T], error) {
+	if a.fallback == nil || !isFallbackError(err) || ctx.Err() != nil {
+		return result, err
+	}
+
+	slog.WarnContext(ctx, "agent run failed, using fallback agent",
+		"agent", a.name,
+		"fallback", a.fallback.name,
+		"error", err,
+	)
+
+	result, err = a.fallback.Run(ctx, input)
+	if result != nil {
+		if result.Metadata == nil {
+			result.Metadata = make(map[string]any)
+		}
+		result.Metadata[MetadataUsedFallback] = true
+	}
+
+	return result, err
+}
+
+func isFallbackError(err error) bool {
+	return errors.Is(err, ErrLimitReached) || errors.Is(err, ErrLLMCall)
+}
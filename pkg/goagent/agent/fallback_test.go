@@ -0,0 +1,131 @@
+package agent_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+func newFallbackTestAgent(
+	t *testing.T, name string, mockLLM llm.LLM, options ...agent.AgentOption[AddNumbersResult],
+) *agent.Agent[AddNumbersResult] {
+	t.Helper()
+
+	options = append([]agent.AgentOption[AddNumbersResult]{
+		agent.WithName[AddNumbersResult](name),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithTool[AddNumbersResult]("add", createTestAddTool()),
+	}, options...)
+
+	a, err := agent.NewAgent(options...)
+	require.NoError(t, err)
+
+	return a.UsingLLM(mockLLM)
+}
+
+func addCall(id string) llm.LLMToolCall {
+	return llm.LLMToolCall{ID: id, ToolName: "add", Args: `{"num1": 1, "num2": 2}`}
+}
+
+func TestWithAgentFallback_TriggeringErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		primaryLLM *llmtest.MockLLM
+		options    []agent.AgentOption[AddNumbersResult]
+	}{
+		{
+			name:       "LLM call error",
+			primaryLLM: llmtest.NewMockLLM(`{"sum": 0}`),
+		},
+		{
+			name:       "tool limit reached",
+			primaryLLM: llmtest.NewMockLLM(`{"sum": 0}`, toolCallMessage(addCall("call_1"), addCall("call_2"))),
+			options:    []agent.AgentOption[AddNumbersResult]{agent.WithToolLimit[AddNumbersResult]("add", 1)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			fallbackLLM := llmtest.NewMockLLM(`{"sum": 3}`, endMessage("done"))
+			fallbackAgent := newFallbackTestAgent(t, "fallback", fallbackLLM)
+			options := append(tt.options, agent.WithAgentFallback(fallbackAgent))
+			primaryAgent := newFallbackTestAgent(t, "primary", tt.primaryLLM, options...)
+
+			// when
+			result, err := primaryAgent.Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+
+			// then
+			require.NoError(t, err)
+			assert.InDelta(t, 3, result.Data.Sum, 0)
+			assert.Equal(t, true, result.Metadata[agent.MetadataUsedFallback])
+			assert.NotEmpty(t, fallbackLLM.Calls())
+		})
+	}
+}
+
+func TestWithAgentFallback_NotUsedOnSuccess(t *testing.T) {
+	t.Parallel()
+
+	// given
+	fallbackLLM := llmtest.NewMockLLM(`{"sum": 0}`, endMessage("done"))
+	primaryAgent := newFallbackTestAgent(t, "primary", llmtest.NewMockLLM(`{"sum": 3}`, endMessage("done")),
+		agent.WithAgentFallback(newFallbackTestAgent(t, "fallback", fallbackLLM)))
+
+	// when
+	result, err := primaryAgent.Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+
+	// then
+	require.NoError(t, err)
+	assert.InDelta(t, 3, result.Data.Sum, 0)
+	assert.NotContains(t, result.Metadata, agent.MetadataUsedFallback)
+	assert.Empty(t, fallbackLLM.Calls())
+}
+
+func TestWithAgentFallback_PropagatesOtherErrors(t *testing.T) {
+	t.Parallel()
+
+	// given
+	fallbackLLM := llmtest.NewMockLLM(`{"sum": 3}`, endMessage("done"))
+	denyAll := func(context.Context, *agent.AgentState, llm.LLMMessage) (llm.LLMMessage, error) {
+		return llm.LLMMessage{}, agent.ErrAccessDenied
+	}
+	primaryAgent := newFallbackTestAgent(t, "primary", llmtest.NewMockLLM(`{"sum": 0}`, endMessage("done")),
+		agent.WithMiddleware[AddNumbersResult](denyAll),
+		agent.WithAgentFallback(newFallbackTestAgent(t, "fallback", fallbackLLM)))
+
+	// when
+	_, err := primaryAgent.Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+
+	// then
+	require.ErrorIs(t, err, agent.ErrMiddlewareError)
+	assert.Empty(t, fallbackLLM.Calls())
+}
+
+func TestWithAgentFallback_NotUsedAfterCancellation(t *testing.T) {
+	t.Parallel()
+
+	// given
+	fallbackLLM := llmtest.NewMockLLM(`{"sum": 3}`, endMessage("done"))
+	primaryAgent := newFallbackTestAgent(t, "primary", llmtest.NewMockLLM(`{"sum": 0}`),
+		agent.WithAgentFallback(newFallbackTestAgent(t, "fallback", fallbackLLM)))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// when
+	_, err := primaryAgent.Run(ctx, AddNumbers{Num1: 1, Num2: 2})
+
+	// then
+	require.ErrorIs(t, err, agent.ErrLLMCall)
+	assert.Empty(t, fallbackLLM.Calls())
+}
@@ -0,0 +1,191 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+const fixtureFilePermissions = 0o600
+
+// Fixture is a recorded agent run used for golden-file testing
+type Fixture struct {
+	Input     json.RawMessage  `json:"input"`
+	Responses []llm.LLMMessage `json:"responses"`
+	Output    json.RawMessage  `json:"output"`
+	// ToolResults are the JSON encoded tool results of every turn with tool calls
+	ToolResults []json.RawMessage `json:"tool_results,omitempty"`
+	// MessageTypes are the types of all messages of the run, in order
+	MessageTypes []llm.LLMMessageType `json:"message_types,omitempty"`
+}
+
+// RecordFixture runs the agent against the real LLM and writes the input, the LLM
+// responses, the tool results, the message sequence and the final output to a JSON fixture
+// file at path. Tools are executed again when the fixture is replayed with RunWithFixture.
+func RecordFixture[T any](ctx context.Context, a *Agent[T], input any, path string) error {
+	result, err := a.Run(ctx, input)
+	if err != nil {
+		return fmt.Errorf("failed to run agent: %w", err)
+	}
+
+	inputJSON, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Errorf("failed to marshal input: %w", err)
+	}
+
+	outputJSON, err := json.Marshal(result.Data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	toolResults, err := collectToolResults(result.Messages)
+	if err != nil {
+		return err
+	}
+
+	fixture := Fixture{
+		Input:        inputJSON,
+		Responses:    collectLLMResponses(result.Messages),
+		Output:       outputJSON,
+		ToolResults:  toolResults,
+		MessageTypes: collectMessageTypes(result.Messages),
+	}
+
+	data, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal fixture: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, fixtureFilePermissions); err != nil {
+		return fmt.Errorf("failed to write fixture: %w", err)
+	}
+
+	return nil
+}
+
+// RunWithFixture replays a fixture recorded with RecordFixture through a mock LLM and
+// fails the test if the agent errors, if input differs from the recorded input, or if the
+// tool results, the message sequence or the final output of the replay differ from the
+// recorded ones. The LLM responses are replayed as recorded, so the output check catches
+// changes in the processing of the output, such as patches and validators.
+//
+// Example:
+//
+//	func TestCalculatorAgent(t *testing.T) {
+//		agent.RunWithFixture(t, calculatorAgent, input, "testdata/calculator.json")
+//	}
+func RunWithFixture[T any](t testing.TB, a *Agent[T], input any, path string) *AgentResult[T] {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read fixture %s: %v", path, err)
+	}
+
+	var fixture Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		t.Fatalf("failed to unmarshal fixture %s: %v", path, err)
+	}
+
+	assertGoldenJSON(t, "input", fixture.Input, input)
+
+	replay := a.UsingLLM(llmtest.NewMockLLM(string(fixture.Output), fixture.Responses...))
+
+	result, err := replay.Run(context.Background(), input)
+	if err != nil {
+		t.Fatalf("failed to replay fixture %s: %v", path, err)
+	}
+
+	toolResults, err := collectToolResults(result.Messages)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	assertGoldenJSON(t, "tool results", mustMarshal(t, fixture.ToolResults), toolResults)
+	assertGoldenJSON(t, "message types", mustMarshal(t, fixture.MessageTypes), collectMessageTypes(result.Messages))
+
+	var output T
+	if err := json.Unmarshal(fixture.Output, &output); err != nil {
+		t.Fatalf("failed to unmarshal golden output of fixture %s: %v", path, err)
+	}
+	assertGoldenJSON(t, "output", mustMarshal(t, output), result.Data)
+
+	return result
+}
+
+// assertGoldenJSON compares the JSON encoding of actual with the recorded JSON
+func assertGoldenJSON(t testing.TB, name string, expected json.RawMessage, actual any) {
+	t.Helper()
+
+	actualJSON := mustMarshal(t, actual)
+
+	var expectedValue, actualValue any
+	if err := json.Unmarshal(expected, &expectedValue); err != nil {
+		t.Fatalf("failed to unmarshal golden %s: %v", name, err)
+	}
+	if err := json.Unmarshal(actualJSON, &actualValue); err != nil {
+		t.Fatalf("failed to unmarshal %s: %v", name, err)
+	}
+
+	if !reflect.DeepEqual(expectedValue, actualValue) {
+		t.Errorf("%s mismatch with the fixture:\nexpected: %s\nactual:   %s", name, expected, actualJSON)
+	}
+}
+
+func mustMarshal(t testing.TB, value any) json.RawMessage {
+	t.Helper()
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("failed to marshal %T: %v", value, err)
+	}
+
+	return data
+}
+
+func collectToolResults(messages []llm.LLMMessage) ([]json.RawMessage, error) {
+	var results []json.RawMessage
+
+	for _, msg := range messages {
+		if len(msg.ToolResults) == 0 {
+			continue
+		}
+
+		data, err := json.Marshal(msg.ToolResults)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal tool results: %w", err)
+		}
+		results = append(results, data)
+	}
+
+	return results, nil
+}
+
+func collectMessageTypes(messages []llm.LLMMessage) []llm.LLMMessageType {
+	types := make([]llm.LLMMessageType, 0, len(messages))
+	for _, msg := range messages {
+		types = append(types, msg.Type)
+	}
+
+	return types
+}
+
+func collectLLMResponses(messages []llm.LLMMessage) []llm.LLMMessage {
+	var responses []llm.LLMMessage
+
+	for _, msg := range messages {
+		if msg.Type != llm.LLMMessageTypeAssistant {
+			continue
+		}
+
+		msg.ToolResults = nil
+		responses = append(responses, msg)
+	}
+
+	return responses
+}
@@ -0,0 +1,182 @@
+package agent_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+func TestRecordAndRunWithFixture(t *testing.T) {
+	t.Parallel()
+
+	toolCallCounter, addTool := createAddTool(t)
+	fixtureAgent, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("fixture_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithTool[AddNumbersResult]("add", addTool),
+	)
+	require.NoError(t, err)
+
+	agent.SetLLM(fixtureAgent, llmtest.NewMockLLM(`{"sum": 8}`,
+		toolCallMessage(llm.LLMToolCall{ID: "call_1", ToolName: "add", Args: `{"num1": 3, "num2": 5}`}),
+		endMessage("The sum is 8"),
+	))
+
+	input := AddNumbers{Num1: 3, Num2: 5}
+	path := filepath.Join(t.TempDir(), "fixture.json")
+
+	err = agent.RecordFixture(context.Background(), fixtureAgent, input, path)
+	require.NoError(t, err)
+
+	result := agent.RunWithFixture(t, fixtureAgent, input, path)
+
+	assert.Equal(t, 8, result.Data.Sum)
+	assert.Equal(t, int64(2), *toolCallCounter, "Tool should run once when recording and once when replaying")
+}
+
+// recordingTB records the failures of RunWithFixture instead of failing the test
+type recordingTB struct {
+	testing.TB
+
+	mu       sync.Mutex
+	failures []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingTB) Fatalf(format string, args ...any) {
+	r.Errorf(format, args...)
+	runtime.Goexit()
+}
+
+// replayFixture runs RunWithFixture in its own goroutine, so Fatalf can stop it
+func replayFixture(tb *recordingTB, a *agent.Agent[AddNumbersResult], input any, path string) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		agent.RunWithFixture(tb, a, input, path)
+	}()
+	<-done
+}
+
+func TestRunWithFixture_DetectsChanges(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		replayInput AddNumbers
+		offset      float64
+		wantFailure string
+	}{
+		{name: "same run", replayInput: AddNumbers{Num1: 3, Num2: 5}},
+		{name: "different input", replayInput: AddNumbers{Num1: 4, Num2: 5}, wantFailure: "input mismatch"},
+		{
+			name:        "different tool result",
+			replayInput: AddNumbers{Num1: 3, Num2: 5},
+			offset:      1,
+			wantFailure: "tool results mismatch",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			var offset float64
+			addTool := llm.MustNewLLMToolFromFunc("add", "Adds two numbers",
+				func(callID string, params AddToolParams) (AddToolResult, error) {
+					return AddToolResult{
+						BaseLLMToolResult: llm.BaseLLMToolResult{ID: callID},
+						Sum:               params.Num1 + params.Num2 + offset,
+					}, nil
+				},
+			)
+			fixtureAgent, err := agent.NewAgent(
+				agent.WithName[AddNumbersResult]("fixture_agent"),
+				agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+				agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+				agent.WithTool[AddNumbersResult]("add", addTool),
+			)
+			require.NoError(t, err)
+			agent.SetLLM(fixtureAgent, llmtest.NewMockLLM(`{"sum": 8}`,
+				toolCallMessage(llm.LLMToolCall{ID: "call_1", ToolName: "add", Args: `{"num1": 3, "num2": 5}`}),
+				endMessage("The sum is 8"),
+			))
+			path := filepath.Join(t.TempDir(), "fixture.json")
+			err = agent.RecordFixture(context.Background(), fixtureAgent, AddNumbers{Num1: 3, Num2: 5}, path)
+			require.NoError(t, err)
+
+			// when
+			offset = tt.offset
+			tb := &recordingTB{}
+			replayFixture(tb, fixtureAgent, tt.replayInput, path)
+
+			// then
+			if tt.wantFailure == "" {
+				assert.Empty(t, tb.failures)
+
+				return
+			}
+			require.Len(t, tb.failures, 1)
+			assert.Contains(t, tb.failures[0], tt.wantFailure)
+		})
+	}
+}
+
+func TestRunWithFixture_DetectsOutputChanges(t *testing.T) {
+	t.Parallel()
+
+	// given
+	_, addTool := createAddTool(t)
+	createFixtureAgent := func(options ...agent.AgentOption[AddNumbersResult]) *agent.Agent[AddNumbersResult] {
+		fixtureAgent, err := agent.NewAgent(append([]agent.AgentOption[AddNumbersResult]{
+			agent.WithName[AddNumbersResult]("fixture_agent"),
+			agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+			agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+			agent.WithTool[AddNumbersResult]("add", addTool),
+		}, options...)...)
+		require.NoError(t, err)
+
+		return fixtureAgent
+	}
+
+	recordAgent := createFixtureAgent()
+	agent.SetLLM(recordAgent, llmtest.NewMockLLM(`{"sum": 8}`,
+		toolCallMessage(llm.LLMToolCall{ID: "call_1", ToolName: "add", Args: `{"num1": 3, "num2": 5}`}),
+		endMessage("The sum is 8"),
+	))
+	input := AddNumbers{Num1: 3, Num2: 5}
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	require.NoError(t, agent.RecordFixture(context.Background(), recordAgent, input, path))
+
+	patch, err := jsonpatch.DecodePatch([]byte(`[{"op": "replace", "path": "/sum", "value": 9}]`))
+	require.NoError(t, err)
+	replayAgent := createFixtureAgent(agent.WithOutputPatch[AddNumbersResult](patch))
+
+	// when
+	tb := &recordingTB{}
+	replayFixture(tb, replayAgent, input, path)
+
+	// then
+	require.Len(t, tb.failures, 1)
+	assert.Contains(t, tb.failures[0], "output mismatch")
+}
@@ -0,0 +1,44 @@
+package agent
+
+import (
+	"fmt"
+
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+)
+
+// ErrGlobalLimitReached is returned when the total number of tool calls in a run exceeds
+// the limit set by WithGlobalToolLimit. It wraps ErrLimitReached.
+var ErrGlobalLimitReached = fmt.Errorf("global %w", ErrLimitReached)
+
+// WithGlobalToolLimit caps the number of tool calls in a single run across all tools combined,
+// in addition to the per-tool limits. It stops agents which call many different tools to stay
+// under the individual limits. When the cap is reached, Run returns ErrGlobalLimitReached
+// together with the messages of the run, like for per-tool limits.
+func WithGlobalToolLimit[T any](maxTotal int) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.globalToolLimit = &maxTotal
+	}
+}
+
+func (a *Agent[T]) validateGlobalToolLimit() error {
+	if a.globalToolLimit == nil {
+		return nil
+	}
+
+	return validation.IntIsPositive(*a.globalToolLimit, "global tool limit")
+}
+
+// globalToolLimitReached reports whether one more tool call would exceed the global limit,
+// given the calls made in the run and the calls already planned in the current turn
+func (a *Agent[T]) globalToolLimitReached(usage map[string]int, planned int) bool {
+	if a.globalToolLimit == nil {
+		return false
+	}
+
+	total := planned
+	for _, count := range usage {
+		total += count
+	}
+
+	return total >= *a.globalToolLimit
+}
@@ -0,0 +1,101 @@
+package agent_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+func TestWithGlobalToolLimit(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		parallel bool
+	}{
+		{name: "sequential", parallel: false},
+		{name: "parallel", parallel: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			limitedAgent, err := agent.NewAgent(
+				agent.WithName[AddNumbersResult]("global_limit_agent"),
+				agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+				agent.WithBehavior[AddNumbersResult]("You add numbers."),
+				agent.WithTool[AddNumbersResult]("add", createTestAddTool()),
+				agent.WithToolLimit[AddNumbersResult]("add", 10),
+				agent.WithGlobalToolLimit[AddNumbersResult](2),
+				agent.WithParallelToolCalls[AddNumbersResult](tt.parallel),
+			)
+			require.NoError(t, err)
+
+			addCall := func(id string) llm.LLMToolCall {
+				return llm.LLMToolCall{ID: id, ToolName: "add", Args: `{"num1": 1, "num2": 2}`}
+			}
+			agent.SetLLM(limitedAgent, llmtest.NewMockLLM(`{"sum": 3}`,
+				toolCallMessage(addCall("call_1"), addCall("call_2")),
+				toolCallMessage(addCall("call_3")),
+				endMessage("done"),
+			))
+
+			// when
+			result, err := limitedAgent.Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+
+			// then
+			require.ErrorIs(t, err, agent.ErrGlobalLimitReached)
+			require.ErrorIs(t, err, agent.ErrLimitReached)
+			require.NotNil(t, result)
+			assert.Nil(t, result.Data)
+		})
+	}
+}
+
+func TestWithGlobalToolLimit_NotReached(t *testing.T) {
+	t.Parallel()
+
+	// given
+	limitedAgent, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("global_limit_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You add numbers."),
+		agent.WithTool[AddNumbersResult]("add", createTestAddTool()),
+		agent.WithGlobalToolLimit[AddNumbersResult](1),
+	)
+	require.NoError(t, err)
+
+	agent.SetLLM(limitedAgent, llmtest.NewMockLLM(`{"sum": 3}`,
+		toolCallMessage(llm.LLMToolCall{ID: "call_1", ToolName: "add", Args: `{"num1": 1, "num2": 2}`}),
+		endMessage("done"),
+	))
+
+	// when
+	result, err := limitedAgent.Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Data.Sum)
+}
+
+func TestWithGlobalToolLimit_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("global_limit_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You add numbers."),
+		agent.WithGlobalToolLimit[AddNumbersResult](0),
+	)
+
+	require.ErrorIs(t, err, validation.ErrValidationFailed)
+	assert.Contains(t, err.Error(), "global tool limit must be positive")
+}
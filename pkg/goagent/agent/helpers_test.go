@@ -0,0 +1,29 @@
+package agent_test
+
+import (
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+func toolCallMessage(toolCalls ...llm.LLMToolCall) llm.LLMMessage {
+	return llm.LLMMessage{
+		Type:      llm.LLMMessageTypeAssistant,
+		ToolCalls: toolCalls,
+	}
+}
+
+func endMessage(content string) llm.LLMMessage {
+	return llm.LLMMessage{
+		Type:    llm.LLMMessageTypeAssistant,
+		Content: content,
+		End:     true,
+	}
+}
+
+func testLLMConfig() llm.LLMConfig {
+	return llm.LLMConfig{
+		Type:        llm.LLMTypeOpenAI,
+		APIKey:      "test-api-key",
+		Model:       "gpt-4",
+		Temperature: 0.0,
+	}
+}
@@ -0,0 +1,97 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+// conversationHistory keeps the last turns (user, assistant) message pairs of an agent.
+// It is stored by pointer so copies of the agent share the same history and lock.
+type conversationHistory struct {
+	mu       sync.RWMutex
+	turns    int
+	messages []llm.LLMMessage
+}
+
+// WithInputHistory makes the agent remember the last turns (user, assistant) message pairs
+// and prepend them to every new Run, so a conversation can continue across calls:
+//
+//	chatAgent, err := agent.NewAgent(
+//		// ... other options
+//		agent.WithInputHistory[Reply](10),
+//	)
+//
+//	_, _ = chatAgent.Run(ctx, "My name is Alice")
+//	result, _ := chatAgent.Run(ctx, "What is my name?")
+//
+// The assistant message of a turn contains the JSON encoded result of the run.
+func WithInputHistory[T any](turns int) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.history = &conversationHistory{turns: turns}
+	}
+}
+
+// GetHistory returns a copy of the remembered conversation messages, oldest first.
+// Returns nil when WithInputHistory is not enabled.
+func (a *Agent[T]) GetHistory() []llm.LLMMessage {
+	if a.history == nil {
+		return nil
+	}
+
+	a.history.mu.RLock()
+	defer a.history.mu.RUnlock()
+
+	messages := make([]llm.LLMMessage, len(a.history.messages))
+	copy(messages, a.history.messages)
+
+	return messages
+}
+
+// ClearHistory forgets all remembered conversation turns
+func (a *Agent[T]) ClearHistory() {
+	if a.history == nil {
+		return
+	}
+
+	a.history.mu.Lock()
+	defer a.history.mu.Unlock()
+
+	a.history.messages = nil
+}
+
+func (a *Agent[T]) validateHistory() error {
+	if a.history == nil {
+		return nil
+	}
+
+	return validation.IntIsPositive(a.history.turns, "turns")
+}
+
+func (a *Agent[T]) recordHistory(userMessage llm.LLMMessage, result *AgentResult[T]) error {
+	if a.history == nil {
+		return nil
+	}
+
+	output, err := json.Marshal(result.Data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal result for history: %w", err)
+	}
+
+	a.history.mu.Lock()
+	defer a.history.mu.Unlock()
+
+	a.history.messages = append(a.history.messages,
+		userMessage,
+		llm.NewLLMMessage(llm.LLMMessageTypeAssistant, string(output)),
+	)
+
+	if overflow := len(a.history.messages) - 2*a.history.turns; overflow > 0 {
+		a.history.messages = a.history.messages[overflow:]
+	}
+
+	return nil
+}
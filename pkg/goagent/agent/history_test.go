@@ -0,0 +1,69 @@
+package agent_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+func createHistoryAgent(t *testing.T, turns int) *agent.Agent[AddNumbersResult] {
+	t.Helper()
+
+	historyAgent, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("history_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithInputHistory[AddNumbersResult](turns),
+	)
+	require.NoError(t, err)
+
+	return historyAgent
+}
+
+func TestWithInputHistory(t *testing.T) {
+	t.Parallel()
+
+	historyAgent := createHistoryAgent(t, 1)
+	mockLLM := llmtest.NewMockLLM(`{"sum": 3}`, endMessage("first"), endMessage("second"), endMessage("third"))
+	agent.SetLLM(historyAgent, mockLLM)
+
+	for _, input := range []AddNumbers{{Num1: 1, Num2: 2}, {Num1: 3, Num2: 4}, {Num1: 5, Num2: 6}} {
+		_, err := historyAgent.Run(context.Background(), input)
+		require.NoError(t, err)
+	}
+
+	history := historyAgent.GetHistory()
+	require.Len(t, history, 2, "only the last turn is kept")
+	assert.Equal(t, llm.LLMMessageTypeUser, history[0].Type)
+	assert.JSONEq(t, `{"num1": 5, "num2": 6}`, history[0].Content)
+	assert.Equal(t, llm.LLMMessageTypeAssistant, history[1].Type)
+	assert.JSONEq(t, `{"sum": 3}`, history[1].Content)
+
+	// The third run sees the second turn between the system prompt and the new input
+	thirdRunMessages := mockLLM.Calls()[4]
+	require.Len(t, thirdRunMessages, 4)
+	assert.JSONEq(t, `{"num1": 3, "num2": 4}`, thirdRunMessages[1].Content)
+	assert.JSONEq(t, `{"num1": 5, "num2": 6}`, thirdRunMessages[3].Content)
+
+	historyAgent.ClearHistory()
+	assert.Empty(t, historyAgent.GetHistory())
+}
+
+func TestWithInputHistory_InvalidTurns(t *testing.T) {
+	t.Parallel()
+
+	_, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("history_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithInputHistory[AddNumbersResult](0),
+	)
+
+	require.ErrorIs(t, err, validation.ErrValidationFailed)
+}
@@ -0,0 +1,79 @@
+package agent
+
+import (
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+// WithInjectedToolResult makes the agent return result for the tool call with the given ID
+// instead of calling the tool, e.g. to replay a conversation from a specific tool turn or to
+// test how the LLM handles a tool error. Injecting several results for the same call ID
+// returns them in order, one per call. Each run starts with all injected results.
+//
+// The result is sent to the LLM as is, so its ID should be callID:
+//
+//	agent.WithInjectedToolResult[Result]("call_1", llm.ErrorLLMToolResult{
+//		BaseLLMToolResult: llm.BaseLLMToolResult{ID: "call_1"},
+//		Error:             "service unavailable",
+//	})
+func WithInjectedToolResult[T any](callID string, result llm.LLMToolResult) AgentOption[T] {
+	return func(a *Agent[T]) {
+		if a.injectedToolResults == nil {
+			a.injectedToolResults = make(map[string][]llm.LLMToolResult)
+		}
+		a.injectedToolResults[callID] = append(a.injectedToolResults[callID], result)
+	}
+}
+
+// injectedToolResults holds the injected results which are not consumed yet in a run.
+// It is safe for concurrent use by parallel tool calls.
+type injectedToolResults struct {
+	mu      sync.Mutex
+	results map[string][]llm.LLMToolResult
+}
+
+func newInjectedToolResults(results map[string][]llm.LLMToolResult) *injectedToolResults {
+	if len(results) == 0 {
+		return nil
+	}
+
+	pending := maps.Clone(results)
+	for callID, callResults := range pending {
+		pending[callID] = slices.Clone(callResults)
+	}
+
+	return &injectedToolResults{results: pending}
+}
+
+// take removes and returns the next injected result for the call ID
+func (r *injectedToolResults) take(callID string) (llm.LLMToolResult, bool) {
+	if r == nil {
+		return nil, false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pending := r.results[callID]
+	if len(pending) == 0 {
+		return nil, false
+	}
+	r.results[callID] = pending[1:]
+
+	return pending[0], true
+}
+
+// clone returns a copy with the results which were not taken yet, e.g. for a branch of the state
+func (r *injectedToolResults) clone() *injectedToolResults {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return newInjectedToolResults(r.results)
+}
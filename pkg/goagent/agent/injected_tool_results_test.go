@@ -0,0 +1,101 @@
+package agent_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+func injectedErrorResult(callID, message string) llm.ErrorLLMToolResult {
+	return llm.ErrorLLMToolResult{
+		BaseLLMToolResult: llm.BaseLLMToolResult{ID: callID},
+		Error:             message,
+	}
+}
+
+func TestWithInjectedToolResult(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		parallel bool
+	}{
+		{name: "sequential"},
+		{name: "parallel", parallel: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			injectAgent, err := agent.NewAgent(
+				agent.WithName[AddNumbersResult]("inject_agent"),
+				agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+				agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+				agent.WithTool[AddNumbersResult]("add", createTestAddTool()),
+				agent.WithToolLimit[AddNumbersResult]("add", 4),
+				agent.WithParallelToolCalls[AddNumbersResult](tt.parallel),
+				agent.WithInjectedToolResult[AddNumbersResult]("call_1", injectedErrorResult("call_1", "first")),
+				agent.WithInjectedToolResult[AddNumbersResult]("call_1", injectedErrorResult("call_1", "second")),
+			)
+			require.NoError(t, err)
+
+			agent.SetLLM(injectAgent, llmtest.NewMockLLM(`{"sum": 3}`,
+				toolCallMessage(addCall("call_1"), addCall("call_2")),
+				toolCallMessage(addCall("call_1")),
+				toolCallMessage(addCall("call_1")),
+				endMessage("done"),
+			))
+
+			// when
+			result, err := injectAgent.Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+
+			// then
+			require.NoError(t, err)
+
+			var toolResults []llm.LLMToolResult
+			for _, msg := range result.Messages {
+				toolResults = append(toolResults, msg.ToolResults...)
+			}
+			require.Len(t, toolResults, 4)
+			assert.Equal(t, injectedErrorResult("call_1", "first"), toolResults[0])
+			assert.Equal(t, "call_2", toolResults[1].GetID())
+			assert.Equal(t, injectedErrorResult("call_1", "second"), toolResults[2])
+			assert.IsType(t, AddToolResult{}, toolResults[3])
+		})
+	}
+}
+
+func TestWithInjectedToolResult_EveryRun(t *testing.T) {
+	t.Parallel()
+
+	// given
+	injectAgent, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("inject_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithTool[AddNumbersResult]("add", createTestAddTool()),
+		agent.WithInjectedToolResult[AddNumbersResult]("call_1", injectedErrorResult("call_1", "injected")),
+	)
+	require.NoError(t, err)
+
+	for range 2 {
+		agent.SetLLM(injectAgent, llmtest.NewMockLLM(`{"sum": 3}`,
+			toolCallMessage(addCall("call_1")),
+			endMessage("done"),
+		))
+
+		// when
+		result, err := injectAgent.Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, injectedErrorResult("call_1", "injected"), result.Messages[2].ToolResults[0])
+	}
+}
@@ -0,0 +1,40 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+)
+
+// ErrInputTooLarge is returned when the JSON of the input exceeds the limit set by
+// WithInputSizeLimit
+var ErrInputTooLarge = errors.New("input too large")
+
+// WithInputSizeLimit limits the size of the JSON encoded input to maxBytes. A run with a
+// larger input, e.g. a struct with the content of a whole file passed by accident, fails
+// with ErrInputTooLarge before the LLM is called, instead of wasting tokens or overflowing
+// the context window of the model. Multimodal input is limited by the JSON of its parts,
+// including the base64 data of the images.
+func WithInputSizeLimit[T any](maxBytes int) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.maxInputBytes = &maxBytes
+	}
+}
+
+func (a *Agent[T]) validateInputSizeLimit() error {
+	if a.maxInputBytes == nil {
+		return nil
+	}
+
+	return validation.IntIsPositive(*a.maxInputBytes, "max input bytes")
+}
+
+func (a *Agent[T]) checkInputSize(inputJSON []byte) error {
+	if a.maxInputBytes == nil || len(inputJSON) <= *a.maxInputBytes {
+		return nil
+	}
+
+	return fmt.Errorf("%w: input is %d bytes, the limit is %d bytes",
+		ErrInputTooLarge, len(inputJSON), *a.maxInputBytes)
+}
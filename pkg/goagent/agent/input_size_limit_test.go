@@ -0,0 +1,98 @@
+package agent_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+func TestWithInputSizeLimit(t *testing.T) {
+	t.Parallel()
+
+	input := AddNumbers{Num1: 1, Num2: 2}
+	inputJSON, err := json.Marshal(input)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		maxBytes int
+		wantErr  bool
+	}{
+		{name: "within limit", maxBytes: len(inputJSON) + 1, wantErr: false},
+		{name: "exactly at limit", maxBytes: len(inputJSON), wantErr: false},
+		{name: "over limit", maxBytes: len(inputJSON) - 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			limitedAgent, err := createPromptFileAgent(agent.WithInputSizeLimit[AddNumbersResult](tt.maxBytes))
+			require.NoError(t, err)
+			mockLLM := llmtest.NewMockLLM(`{"sum": 3}`, endMessage("done"))
+
+			// when
+			result, err := limitedAgent.UsingLLM(mockLLM).Run(context.Background(), input)
+
+			// then
+			if !tt.wantErr {
+				require.NoError(t, err)
+				assert.Equal(t, 3, result.Data.Sum)
+
+				return
+			}
+
+			require.ErrorIs(t, err, agent.ErrInputTooLarge)
+			assert.Contains(t, err.Error(), fmt.Sprintf("input is %d bytes", len(inputJSON)))
+			assert.Empty(t, mockLLM.Calls())
+		})
+	}
+}
+
+func TestWithInputSizeLimit_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := createPromptFileAgent(agent.WithInputSizeLimit[AddNumbersResult](0))
+
+	require.ErrorIs(t, err, validation.ErrValidationFailed)
+	assert.Contains(t, err.Error(), "input size limit")
+}
+
+func TestWithInputSizeLimit_MultimodalInput(t *testing.T) {
+	t.Parallel()
+
+	// given
+	config := testLLMConfig()
+	config.Model = "gpt-4o"
+	visionAgent, err := agent.NewAgent(
+		agent.WithName[HashResult]("vision_agent"),
+		agent.WithLLMConfig[HashResult](config),
+		agent.WithBehavior[HashResult]("Describe the image."),
+		agent.WithMultimodal[HashResult](true),
+		agent.WithInputSizeLimit[HashResult](1024),
+	)
+	require.NoError(t, err)
+	mockLLM := llmtest.NewMockLLM(`{"hash": "cat"}`, endMessage("a cat"))
+	image := base64.StdEncoding.EncodeToString([]byte(strings.Repeat("x", 2048)))
+
+	// when
+	_, err = visionAgent.UsingLLM(mockLLM).Run(context.Background(), []llm.MessagePart{
+		llm.NewTextPart("What is in this image?"),
+		llm.NewImagePart(llm.ImageContent{Base64: image}),
+	})
+
+	// then
+	require.ErrorIs(t, err, agent.ErrInputTooLarge)
+	assert.Empty(t, mockLLM.Calls())
+}
@@ -0,0 +1,43 @@
+package agent
+
+import "context"
+
+// RunStartHook is called at the start of Run, before any LLM call
+type RunStartHook func(ctx context.Context, input any)
+
+// RunEndHook is called when Run returns, with the final result (possibly nil) and error
+type RunEndHook[T any] func(ctx context.Context, result *AgentResult[T], err error)
+
+// WithOnRunStart registers a hook that is called synchronously at the start of every Run.
+// Hooks are called in the order they were registered.
+func WithOnRunStart[T any](fn func(ctx context.Context, input any)) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.onRunStart = append(a.onRunStart, fn)
+	}
+}
+
+// WithOnRunEnd registers a hook that is called synchronously at the end of every Run,
+// regardless of its outcome. Hooks are called in the order they were registered.
+//
+// Example:
+//
+//	agent.WithOnRunEnd[Result](func(ctx context.Context, result *agent.AgentResult[Result], err error) {
+//		metrics.RecordRun(err == nil)
+//	})
+func WithOnRunEnd[T any](fn func(ctx context.Context, result *AgentResult[T], err error)) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.onRunEnd = append(a.onRunEnd, fn)
+	}
+}
+
+func (a *Agent[T]) notifyRunStart(ctx context.Context, input any) {
+	for _, hook := range a.onRunStart {
+		hook(ctx, input)
+	}
+}
+
+func (a *Agent[T]) notifyRunEnd(ctx context.Context, result *AgentResult[T], err error) {
+	for _, hook := range a.onRunEnd {
+		hook(ctx, result, err)
+	}
+}
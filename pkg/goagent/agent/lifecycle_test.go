@@ -0,0 +1,52 @@
+package agent_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+func TestWithOnRunStartAndEnd(t *testing.T) {
+	t.Parallel()
+
+	var events []string
+	var endResult *agent.AgentResult[AddNumbersResult]
+	var endErr error
+
+	hookAgent, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("hook_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithOnRunStart[AddNumbersResult](func(_ context.Context, input any) {
+			assert.Equal(t, AddNumbers{Num1: 1, Num2: 2}, input)
+			events = append(events, "start")
+		}),
+		agent.WithOnRunEnd[AddNumbersResult](func(
+			_ context.Context, result *agent.AgentResult[AddNumbersResult], err error,
+		) {
+			events = append(events, "end")
+			endResult, endErr = result, err
+		}),
+	)
+	require.NoError(t, err)
+
+	agent.SetLLM(hookAgent, llmtest.NewMockLLM(`{"sum": 3}`, endMessage("done")))
+	result, err := hookAgent.Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"start", "end"}, events)
+	assert.Same(t, result, endResult)
+	require.NoError(t, endErr)
+
+	agent.SetLLM(hookAgent, llmtest.NewMockLLM(`{"sum": 3}`))
+	_, err = hookAgent.Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+
+	require.ErrorIs(t, err, agent.ErrLLMCall)
+	assert.Equal(t, []string{"start", "end", "start", "end"}, events)
+	assert.Nil(t, endResult)
+	require.ErrorIs(t, endErr, agent.ErrLLMCall)
+}
@@ -0,0 +1,119 @@
+package agent
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmfactory"
+)
+
+const defaultLLMOverrideCacheTTL = 10 * time.Minute
+
+type overrideLLMEntry struct {
+	llm       llm.LLM
+	expiresAt time.Time
+}
+
+// overrideLLMCache keeps the LLMs built for config overrides, so requests of the same
+// tenant don't rebuild the client. It is stored by pointer and shared by agent copies.
+type overrideLLMCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]overrideLLMEntry
+}
+
+// WithLLMOverrideCacheTTL sets how long an LLM built for a config override from the run
+// context is reused, see llm.WithLLMConfigOverride. Defaults to 10 minutes.
+func WithLLMOverrideCacheTTL[T any](ttl time.Duration) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.overrideLLMs.ttl = ttl
+	}
+}
+
+// withLLMConfigOverride returns the agent to use for a run with the given context. When the
+// context carries a config override which differs from the agent config, it is a copy of
+// the agent which uses an LLM built from the merged config.
+func (a *Agent[T]) withLLMConfigOverride(ctx context.Context) (*Agent[T], error) {
+	override, ok := llm.LLMConfigFromContext(ctx)
+	if !ok {
+		return a, nil
+	}
+
+	cfg := llm.MergeLLMConfig(a.llmConfig, override)
+	if reflect.DeepEqual(cfg, a.llmConfig) {
+		return a, nil
+	}
+
+	overrideLLM, err := a.overrideLLMs.get(cfg, func() (llm.LLM, error) {
+		if err := cfg.Validate(); err != nil {
+			return nil, fmt.Errorf("llm config override: %w", err)
+		}
+
+		return llmfactory.CreateLLM(cfg, a.llmTools())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	clone := *a
+	clone.llmConfig = cfg
+	clone.llm = a.withRequestLogging(overrideLLM)
+
+	return &clone, nil
+}
+
+func newOverrideLLMCache() *overrideLLMCache {
+	return &overrideLLMCache{
+		ttl:     defaultLLMOverrideCacheTTL,
+		entries: make(map[string]overrideLLMEntry),
+	}
+}
+
+func (c *overrideLLMCache) get(cfg llm.LLMConfig, create func() (llm.LLM, error)) (llm.LLM, error) {
+	key, err := overrideCacheKey(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if entry, ok := c.entries[key]; ok && now.Before(entry.expiresAt) {
+		return entry.llm, nil
+	}
+
+	overrideLLM, err := create()
+	if err != nil {
+		return nil, err
+	}
+
+	for cachedKey, entry := range c.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(c.entries, cachedKey)
+		}
+	}
+	c.entries[key] = overrideLLMEntry{llm: overrideLLM, expiresAt: now.Add(c.ttl)}
+
+	return overrideLLM, nil
+}
+
+// overrideCacheKey hashes the config, so API keys are not kept in plain text as map keys.
+// The HTTP client is not serialized, it is identified by its address.
+func overrideCacheKey(cfg llm.LLMConfig) (string, error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return "", fmt.Errorf("llm config override: %w", err)
+	}
+	data = fmt.Appendf(data, "%p", cfg.HTTPClient)
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,185 @@
+package agent_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+const sumCompletion = `{
+	"id": "chatcmpl-1",
+	"object": "chat.completion",
+	"created": 1700000000,
+	"model": "gpt-4o-mini",
+	"choices": [{"index": 0, "finish_reason": "stop", "message": {"role": "assistant", "content": "{\"sum\": 3}"}}]
+}`
+
+// tenantServer is a fake OpenAI API which records the API keys it receives
+type tenantServer struct {
+	mu          sync.Mutex
+	authHeaders []string
+}
+
+func (s *tenantServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.authHeaders = append(s.authHeaders, r.Header.Get("Authorization"))
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(sumCompletion))
+}
+
+// serverTransport sends every request to the target server
+type serverTransport struct {
+	target *url.URL
+}
+
+func (s serverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = s.target.Scheme
+	req.URL.Host = s.target.Host
+
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func newTenantClient(t *testing.T, handler http.Handler) *http.Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	target, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	return &http.Client{Transport: serverTransport{target: target}}
+}
+
+func newOverrideTestAgent(t *testing.T, options ...agent.AgentOption[AddNumbersResult]) *agent.Agent[AddNumbersResult] {
+	t.Helper()
+
+	options = append([]agent.AgentOption[AddNumbersResult]{
+		agent.WithName[AddNumbersResult]("tenant_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+	}, options...)
+
+	a, err := agent.NewAgent(options...)
+	require.NoError(t, err)
+
+	return a
+}
+
+func TestLLMConfigOverride_Run(t *testing.T) {
+	t.Parallel()
+
+	// given
+	server := &tenantServer{}
+	tenantAgent := newOverrideTestAgent(t)
+	ctx := llm.WithLLMConfigOverride(context.Background(), llm.LLMConfig{
+		APIKey:     "tenant-key",
+		HTTPClient: newTenantClient(t, server),
+	})
+
+	// when
+	result, err := tenantAgent.Run(ctx, AddNumbers{Num1: 1, Num2: 2})
+
+	// then
+	require.NoError(t, err)
+	assert.InDelta(t, 3, result.Data.Sum, 0)
+	assert.Equal(t, []string{"Bearer tenant-key", "Bearer tenant-key"}, server.authHeaders)
+}
+
+func TestLLMConfigOverride_ReusesLLM(t *testing.T) {
+	t.Parallel()
+
+	// given
+	tenantAgent := newOverrideTestAgent(t)
+	baseLLM, err := agent.RunLLM(context.Background(), tenantAgent)
+	require.NoError(t, err)
+
+	tenantCtx := llm.WithLLMConfigOverride(context.Background(), llm.LLMConfig{APIKey: "tenant-key"})
+	sameAsBaseCtx := llm.WithLLMConfigOverride(context.Background(), llm.LLMConfig{Model: testLLMConfig().Model})
+
+	// when
+	first, err := agent.RunLLM(tenantCtx, tenantAgent)
+	require.NoError(t, err)
+	second, err := agent.RunLLM(tenantCtx, tenantAgent)
+	require.NoError(t, err)
+	unchanged, err := agent.RunLLM(sameAsBaseCtx, tenantAgent)
+	require.NoError(t, err)
+
+	// then
+	assert.NotSame(t, baseLLM, first)
+	assert.Same(t, first, second)
+	assert.Same(t, baseLLM, unchanged)
+}
+
+func TestLLMConfigOverride_CacheExpires(t *testing.T) {
+	t.Parallel()
+
+	// given
+	tenantAgent := newOverrideTestAgent(t, agent.WithLLMOverrideCacheTTL[AddNumbersResult](time.Nanosecond))
+	ctx := llm.WithLLMConfigOverride(context.Background(), llm.LLMConfig{APIKey: "tenant-key"})
+
+	// when
+	first, err := agent.RunLLM(ctx, tenantAgent)
+	require.NoError(t, err)
+	time.Sleep(time.Millisecond)
+	second, err := agent.RunLLM(ctx, tenantAgent)
+	require.NoError(t, err)
+
+	// then
+	assert.NotSame(t, first, second)
+}
+
+func TestLLMConfigOverride_OutputCacheIsPerTenant(t *testing.T) {
+	t.Parallel()
+
+	// given
+	tenantAgent := newOverrideTestAgent(t,
+		agent.WithOutputCache(agent.NewTTLOutputCache[AddNumbersResult](time.Hour), nil))
+	servers := map[string]*tenantServer{"tenant-a": {}, "tenant-b": {}}
+	contexts := make(map[string]context.Context, len(servers))
+	for apiKey, server := range servers {
+		contexts[apiKey] = llm.WithLLMConfigOverride(context.Background(), llm.LLMConfig{
+			APIKey:     apiKey,
+			HTTPClient: newTenantClient(t, server),
+		})
+	}
+	input := AddNumbers{Num1: 1, Num2: 2}
+
+	// when
+	for _, apiKey := range []string{"tenant-a", "tenant-b", "tenant-a", "tenant-b"} {
+		_, err := tenantAgent.Run(contexts[apiKey], input)
+		require.NoError(t, err)
+	}
+
+	// then
+	assert.Equal(t, []string{"Bearer tenant-a", "Bearer tenant-a"}, servers["tenant-a"].authHeaders,
+		"the second run of a tenant must be served from the cache")
+	assert.Equal(t, []string{"Bearer tenant-b", "Bearer tenant-b"}, servers["tenant-b"].authHeaders,
+		"a tenant must not get the cached result of another tenant")
+}
+
+func TestLLMConfigOverride_Invalid(t *testing.T) {
+	t.Parallel()
+
+	// given
+	tenantAgent := newOverrideTestAgent(t)
+	ctx := llm.WithLLMConfigOverride(context.Background(), llm.LLMConfig{Type: "unknown"})
+
+	// when
+	_, err := tenantAgent.Run(ctx, AddNumbers{Num1: 1, Num2: 2})
+
+	// then
+	require.ErrorIs(t, err, llm.ErrUnsupportedLLMType)
+}
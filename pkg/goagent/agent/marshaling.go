@@ -0,0 +1,135 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+// InputMarshaler serializes the agent input into the content of the user message
+type InputMarshaler func(input any) ([]byte, error)
+
+// OutputUnmarshaler deserializes the structured output of the LLM into v
+type OutputUnmarshaler func(data []byte, v any) error
+
+// WithInputMarshaler replaces json.Marshal for serializing the agent input, for types
+// that do not marshal cleanly, such as protobuf messages:
+//
+//	agent.WithInputMarshaler[Result](func(input any) ([]byte, error) {
+//		return protojson.Marshal(input.(proto.Message))
+//	})
+func WithInputMarshaler[T any](fn func(input any) ([]byte, error)) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.inputMarshaler = fn
+	}
+}
+
+// WithOutputUnmarshaler replaces json.Unmarshal for deserializing the structured output into T.
+// The unmarshaler receives a pointer to T.
+func WithOutputUnmarshaler[T any](fn func(data []byte, v any) error) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.outputUnmarshaler = fn
+	}
+}
+
+// WithOutputPatch applies a JSON Patch (RFC 6902) to the raw structured output before it
+// is unmarshaled, to correct predictable formatting errors of a model. Create the patch
+// with jsonpatch.DecodePatch from github.com/evanphx/json-patch/v5.
+//
+// Common fixes:
+//
+//	patch, err := jsonpatch.DecodePatch([]byte(`[
+//		{"op": "move", "from": "/firstName", "path": "/first_name"},
+//		{"op": "remove", "path": "/explanation"},
+//		{"op": "add", "path": "/tags", "value": []}
+//	]`))
+//
+// Operations that reference a missing path fail the run, so only patch fields the model
+// emits consistently. For slice results paths are relative to the slice, e.g. "/0/name".
+func WithOutputPatch[T any](patch jsonpatch.Patch) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.outputPatch = patch
+	}
+}
+
+func (a *Agent[T]) marshalInput(input any) ([]byte, error) {
+	if a.inputMarshaler != nil {
+		return a.inputMarshaler(input)
+	}
+
+	return json.Marshal(input)
+}
+
+func (a *Agent[T]) callWithStructuredOutput(ctx context.Context, msgs []llm.LLMMessage) (T, error) {
+	if a.outputSchemaMap == nil && isSliceOutput[T]() {
+		return a.callWithSliceOutput(ctx, msgs)
+	}
+
+	return callStructuredOutput[T](ctx, a.llm, msgs, a.structuredOutputSchema(), a.decodeOutput)
+}
+
+func (a *Agent[T]) callWithSliceOutput(ctx context.Context, msgs []llm.LLMMessage) (T, error) {
+	var result T
+
+	wrapped, err := callStructuredOutput[sliceOutput[json.RawMessage]](
+		ctx, a.llm, msgs, new(sliceOutput[T]), json.Unmarshal,
+	)
+	if err != nil {
+		return result, err
+	}
+
+	if err := a.decodeOutput(wrapped.Items, &result); err != nil {
+		malformed := &malformedOutputError{output: string(wrapped.Items), err: err}
+
+		return result, fmt.Errorf("%w: %w", llm.ErrStructuredOutput, malformed)
+	}
+
+	return result, nil
+}
+
+// decodeOutput applies the output patch, if any, and unmarshals the raw output into v
+func (a *Agent[T]) decodeOutput(data []byte, v any) error {
+	if len(a.outputPatch) > 0 {
+		patched, err := a.outputPatch.Apply(data)
+		if err != nil {
+			return fmt.Errorf("failed to patch output: %w", err)
+		}
+		data = patched
+	}
+
+	if a.schemaMaxRetries > 0 {
+		if err := checkRequiredFields(data, a.structuredOutputSchema()); err != nil {
+			return err
+		}
+	}
+
+	if a.outputUnmarshaler != nil {
+		return a.outputUnmarshaler(data, v)
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+func callStructuredOutput[R any](
+	ctx context.Context,
+	agentLLM llm.LLM,
+	msgs []llm.LLMMessage,
+	schemaT any,
+	unmarshal OutputUnmarshaler,
+) (R, error) {
+	var result R
+
+	output, err := agentLLM.CallWithStructuredOutput(ctx, msgs, schemaT)
+	if err != nil {
+		return result, fmt.Errorf("%w: %w", llm.ErrStructuredOutput, err)
+	}
+
+	if err := unmarshal([]byte(output), &result); err != nil {
+		return result, fmt.Errorf("%w: %w", llm.ErrStructuredOutput, &malformedOutputError{output: output, err: err})
+	}
+
+	return result, nil
+}
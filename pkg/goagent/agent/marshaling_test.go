@@ -0,0 +1,89 @@
+package agent_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+func TestWithInputMarshalerAndOutputUnmarshaler(t *testing.T) {
+	t.Parallel()
+
+	codecAgent, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("codec_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithInputMarshaler[AddNumbersResult](func(input any) ([]byte, error) {
+			numbers, ok := input.(AddNumbers)
+			require.True(t, ok)
+
+			return []byte(strings.Repeat("+", numbers.Num1+numbers.Num2)), nil
+		}),
+		agent.WithOutputUnmarshaler[AddNumbersResult](func(data []byte, v any) error {
+			result, ok := v.(*AddNumbersResult)
+			require.True(t, ok)
+			result.Sum = len(data)
+
+			return nil
+		}),
+	)
+	require.NoError(t, err)
+
+	mockLLM := llmtest.NewMockLLM("xxx", endMessage("done"))
+	agent.SetLLM(codecAgent, mockLLM)
+
+	result, err := codecAgent.Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Data.Sum)
+	assert.Equal(t, "+++", mockLLM.Calls()[0][1].Content)
+}
+
+func TestWithOutputUnmarshaler_Error(t *testing.T) {
+	t.Parallel()
+
+	codecAgent, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("codec_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithOutputUnmarshaler[AddNumbersResult](func(data []byte, v any) error {
+			return json.Unmarshal(data, v)
+		}),
+	)
+	require.NoError(t, err)
+
+	agent.SetLLM(codecAgent, llmtest.NewMockLLM("not json", endMessage("done")))
+
+	_, err = codecAgent.Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+
+	require.ErrorIs(t, err, agent.ErrLLMCall)
+}
+
+func TestWithOutputPatch(t *testing.T) {
+	t.Parallel()
+
+	patch, err := jsonpatch.DecodePatch([]byte(`[{"op": "move", "from": "/total", "path": "/sum"}]`))
+	require.NoError(t, err)
+
+	patchAgent, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("patch_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithOutputPatch[AddNumbersResult](patch),
+	)
+	require.NoError(t, err)
+
+	agent.SetLLM(patchAgent, llmtest.NewMockLLM(`{"total": 3}`, endMessage("done")))
+
+	result, err := patchAgent.Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Data.Sum)
+}
@@ -0,0 +1,180 @@
+package agent
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+// DiffType represents the kind of change between two messages
+type DiffType string
+
+const (
+	// DiffTypeAdded means the message exists only in the new conversation
+	DiffTypeAdded DiffType = "added"
+	// DiffTypeRemoved means the message exists only in the old conversation
+	DiffTypeRemoved DiffType = "removed"
+	// DiffTypeModified means the message exists in both conversations but differs
+	DiffTypeModified DiffType = "modified"
+)
+
+// MessageDiff describes a change of a single message between two conversations
+type MessageDiff struct {
+	Index int             `json:"index"`
+	Type  DiffType        `json:"type"`
+	Old   *llm.LLMMessage `json:"old,omitempty"`
+	New   *llm.LLMMessage `json:"new,omitempty"`
+}
+
+type wordOp struct {
+	kind byte // ' ' unchanged, '-' removed, '+' added
+	word string
+}
+
+// DiffMessages compares two conversations position by position and returns the
+// differences. It is useful for understanding why two runs of the same agent
+// produced different results:
+//
+//	for _, diff := range agent.DiffMessages(first.Messages, second.Messages) {
+//		fmt.Println(diff.String())
+//	}
+func DiffMessages(oldMsgs, newMsgs []llm.LLMMessage) []MessageDiff {
+	var diffs []MessageDiff
+
+	for i := range max(len(oldMsgs), len(newMsgs)) {
+		switch {
+		case i >= len(oldMsgs):
+			diffs = append(diffs, MessageDiff{Index: i, Type: DiffTypeAdded, New: &newMsgs[i]})
+		case i >= len(newMsgs):
+			diffs = append(diffs, MessageDiff{Index: i, Type: DiffTypeRemoved, Old: &oldMsgs[i]})
+		case !messagesEqual(oldMsgs[i], newMsgs[i]):
+			diffs = append(diffs, MessageDiff{Index: i, Type: DiffTypeModified, Old: &oldMsgs[i], New: &newMsgs[i]})
+		}
+	}
+
+	return diffs
+}
+
+// messagesEqual compares two messages ignoring their timestamps,
+// which differ between any two runs
+func messagesEqual(oldMsg, newMsg llm.LLMMessage) bool {
+	oldMsg.Timestamp = time.Time{}
+	newMsg.Timestamp = time.Time{}
+
+	return reflect.DeepEqual(oldMsg, newMsg)
+}
+
+// String renders the diff in unified diff format with word-level content changes
+// marked as [-removed-] and {+added+}
+func (d MessageDiff) String() string {
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "--- a/messages[%d]%s\n", d.Index, messageLabel(d.Old))
+	fmt.Fprintf(&builder, "+++ b/messages[%d]%s\n", d.Index, messageLabel(d.New))
+	fmt.Fprintf(&builder, "@@ %s @@\n", d.Type)
+
+	switch d.Type {
+	case DiffTypeAdded:
+		builder.WriteString(prefixLines("+", d.New.Content))
+	case DiffTypeRemoved:
+		builder.WriteString(prefixLines("-", d.Old.Content))
+	case DiffTypeModified:
+		if d.Old.Type != d.New.Type {
+			fmt.Fprintf(&builder, "-type: %s\n+type: %s\n", d.Old.Type, d.New.Type)
+		}
+		if d.Old.Content != d.New.Content {
+			builder.WriteString(" " + renderWordDiff(diffWords(d.Old.Content, d.New.Content)) + "\n")
+		}
+		if !reflect.DeepEqual(d.Old.ToolCalls, d.New.ToolCalls) {
+			fmt.Fprintf(&builder, "-tool calls: %d\n+tool calls: %d\n", len(d.Old.ToolCalls), len(d.New.ToolCalls))
+		}
+		if !reflect.DeepEqual(d.Old.ToolResults, d.New.ToolResults) {
+			fmt.Fprintf(&builder, "-tool results: %d\n+tool results: %d\n",
+				len(d.Old.ToolResults), len(d.New.ToolResults))
+		}
+	}
+
+	return builder.String()
+}
+
+func messageLabel(msg *llm.LLMMessage) string {
+	if msg == nil {
+		return " (none)"
+	}
+
+	return fmt.Sprintf(" (%s)", msg.Type)
+}
+
+func prefixLines(prefix, content string) string {
+	var builder strings.Builder
+	for _, line := range strings.Split(content, "\n") {
+		builder.WriteString(prefix + line + "\n")
+	}
+
+	return builder.String()
+}
+
+// diffWords computes a word-level diff of two strings using the longest common subsequence
+func diffWords(oldContent, newContent string) []wordOp {
+	oldWords := strings.Fields(oldContent)
+	newWords := strings.Fields(newContent)
+
+	// lcs[i][j] is the LCS length of oldWords[i:] and newWords[j:]
+	lcs := make([][]int, len(oldWords)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(newWords)+1)
+	}
+	for i := len(oldWords) - 1; i >= 0; i-- {
+		for j := len(newWords) - 1; j >= 0; j-- {
+			if oldWords[i] == newWords[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]wordOp, 0, len(oldWords)+len(newWords))
+	i, j := 0, 0
+	for i < len(oldWords) && j < len(newWords) {
+		switch {
+		case oldWords[i] == newWords[j]:
+			ops = append(ops, wordOp{kind: ' ', word: oldWords[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, wordOp{kind: '-', word: oldWords[i]})
+			i++
+		default:
+			ops = append(ops, wordOp{kind: '+', word: newWords[j]})
+			j++
+		}
+	}
+	for ; i < len(oldWords); i++ {
+		ops = append(ops, wordOp{kind: '-', word: oldWords[i]})
+	}
+	for ; j < len(newWords); j++ {
+		ops = append(ops, wordOp{kind: '+', word: newWords[j]})
+	}
+
+	return ops
+}
+
+func renderWordDiff(ops []wordOp) string {
+	words := make([]string, 0, len(ops))
+	for _, op := range ops {
+		switch op.kind {
+		case '-':
+			words = append(words, "[-"+op.word+"-]")
+		case '+':
+			words = append(words, "{+"+op.word+"+}")
+		default:
+			words = append(words, op.word)
+		}
+	}
+
+	return strings.Join(words, " ")
+}
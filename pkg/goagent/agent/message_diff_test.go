@@ -0,0 +1,66 @@
+package agent_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+func TestDiffMessages(t *testing.T) {
+	t.Parallel()
+
+	oldMsgs := []llm.LLMMessage{
+		llm.NewLLMMessage(llm.LLMMessageTypeSystem, "You are a calculator"),
+		llm.NewLLMMessage(llm.LLMMessageTypeAssistant, "the quick brown fox"),
+		llm.NewLLMMessage(llm.LLMMessageTypeAssistant, "removed message"),
+	}
+	newMsgs := []llm.LLMMessage{
+		llm.NewLLMMessage(llm.LLMMessageTypeSystem, "You are a calculator"),
+		llm.NewLLMMessage(llm.LLMMessageTypeAssistant, "the quick red fox"),
+	}
+
+	diffs := agent.DiffMessages(oldMsgs, newMsgs)
+
+	require.Len(t, diffs, 2)
+	assert.Equal(t, 1, diffs[0].Index)
+	assert.Equal(t, agent.DiffTypeModified, diffs[0].Type)
+	assert.Equal(t, 2, diffs[1].Index)
+	assert.Equal(t, agent.DiffTypeRemoved, diffs[1].Type)
+	assert.Nil(t, diffs[1].New)
+
+	assert.Equal(t,
+		"--- a/messages[1] (assistant)\n"+
+			"+++ b/messages[1] (assistant)\n"+
+			"@@ modified @@\n"+
+			" the quick [-brown-] {+red+} fox\n",
+		diffs[0].String())
+	assert.Equal(t,
+		"--- a/messages[2] (assistant)\n"+
+			"+++ b/messages[2] (none)\n"+
+			"@@ removed @@\n"+
+			"-removed message\n",
+		diffs[1].String())
+}
+
+func TestDiffMessages_Added(t *testing.T) {
+	t.Parallel()
+
+	newMsgs := []llm.LLMMessage{llm.NewLLMMessage(llm.LLMMessageTypeUser, "hello")}
+
+	diffs := agent.DiffMessages(nil, newMsgs)
+
+	require.Len(t, diffs, 1)
+	assert.Equal(t, agent.DiffTypeAdded, diffs[0].Type)
+	assert.Contains(t, diffs[0].String(), "+hello")
+}
+
+func TestDiffMessages_Identical(t *testing.T) {
+	t.Parallel()
+
+	msgs := []llm.LLMMessage{llm.NewLLMMessage(llm.LLMMessageTypeUser, "hello")}
+
+	assert.Empty(t, agent.DiffMessages(msgs, msgs))
+}
@@ -0,0 +1,83 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+// ErrMessageInterceptor is returned when a message interceptor fails
+var ErrMessageInterceptor = errors.New("message interceptor error occurred")
+
+// MessageInterceptor reads and modifies a message before it is added to the agent state
+type MessageInterceptor func(ctx context.Context, msg llm.LLMMessage) (llm.LLMMessage, error)
+
+// WithMessageInterceptor registers a function which is called for every message the agent adds
+// to its state: the system prompt, history, the user input, LLM responses with their tool
+// results and the prompts of the output phase. Unlike AgentMiddleware, which only sees LLM
+// responses, it can e.g. add metadata to user messages. The system prompt is rendered again
+// after every turn to update tool usage and is passed to the interceptors each time, so it can
+// be adjusted as the conversation goes on. Interceptors run in the order they were added,
+// before secrets are scrubbed. An error stops the run with ErrMessageInterceptor.
+//
+// Example:
+//
+//	turn := 0
+//	agent.WithMessageInterceptor[Result](func(ctx context.Context, msg llm.LLMMessage) (llm.LLMMessage, error) {
+//		if msg.Type == llm.LLMMessageTypeSystem {
+//			turn++
+//			msg.Content += fmt.Sprintf("\nThis is turn %d, answer within 5 turns.", turn)
+//		}
+//		return msg, nil
+//	})
+func WithMessageInterceptor[T any](fn MessageInterceptor) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.messageInterceptors = append(a.messageInterceptors, fn)
+	}
+}
+
+// addMessage passes msg through the message interceptors, adds it to the state and returns
+// the intercepted message
+func (a *AgentState) addMessage(ctx context.Context, msg llm.LLMMessage) (llm.LLMMessage, error) {
+	msg, err := a.interceptMessage(ctx, msg)
+	if err != nil {
+		return llm.LLMMessage{}, err
+	}
+
+	a.AddMessage(msg)
+
+	return msg, nil
+}
+
+// setSystemMessage passes the rendered system prompt through the message interceptors and
+// replaces the first message of the state with it
+func (a *AgentState) setSystemMessage(ctx context.Context, systemPrompt string) error {
+	msg := a.Messages[0]
+	msg.Content = systemPrompt
+
+	msg, err := a.interceptMessage(ctx, msg)
+	if err != nil {
+		return err
+	}
+
+	if a.scrubber != nil {
+		msg = a.scrubber.scrubMessage(msg)
+	}
+	a.Messages[0] = msg
+
+	return nil
+}
+
+func (a *AgentState) interceptMessage(ctx context.Context, msg llm.LLMMessage) (llm.LLMMessage, error) {
+	for _, intercept := range a.interceptors {
+		var err error
+		msg, err = intercept(ctx, msg)
+		if err != nil {
+			return llm.LLMMessage{}, fmt.Errorf("%w: %w", ErrMessageInterceptor, err)
+		}
+	}
+
+	return msg, nil
+}
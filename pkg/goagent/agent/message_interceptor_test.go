@@ -0,0 +1,140 @@
+package agent_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+var errInterceptorFailed = errors.New("interceptor failed")
+
+func createInterceptorAgent(
+	t *testing.T, opts ...agent.AgentOption[AddNumbersResult],
+) *agent.Agent[AddNumbersResult] {
+	t.Helper()
+
+	opts = append([]agent.AgentOption[AddNumbersResult]{
+		agent.WithName[AddNumbersResult]("interceptor_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithTool[AddNumbersResult]("add", createTestAddTool()),
+	}, opts...)
+
+	interceptorAgent, err := agent.NewAgent(opts...)
+	require.NoError(t, err)
+
+	return interceptorAgent
+}
+
+func TestWithMessageInterceptor(t *testing.T) {
+	t.Parallel()
+
+	// given
+	var intercepted []llm.LLMMessageType
+	systemPrompts := 0
+	interceptorAgent := createInterceptorAgent(t,
+		agent.WithMessageInterceptor[AddNumbersResult](
+			func(_ context.Context, msg llm.LLMMessage) (llm.LLMMessage, error) {
+				intercepted = append(intercepted, msg.Type)
+
+				switch msg.Type {
+				case llm.LLMMessageTypeSystem:
+					systemPrompts++
+					msg.Content += fmt.Sprintf("\nTurn %d", systemPrompts)
+				case llm.LLMMessageTypeUser:
+					msg.Content = "[tenant=acme] " + msg.Content
+				case llm.LLMMessageTypeAssistant:
+				}
+
+				return msg, nil
+			}),
+	)
+
+	mockLLM := llmtest.NewMockLLM(`{"sum": 3}`,
+		toolCallMessage(addCall("call_1")),
+		endMessage("done"),
+	)
+
+	// when
+	result, err := interceptorAgent.UsingLLM(mockLLM).Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, []llm.LLMMessageType{
+		llm.LLMMessageTypeSystem,
+		llm.LLMMessageTypeUser,
+		llm.LLMMessageTypeAssistant,
+		llm.LLMMessageTypeSystem,
+		llm.LLMMessageTypeAssistant,
+		llm.LLMMessageTypeUser,
+	}, intercepted)
+
+	calls := mockLLM.Calls()
+	require.Len(t, calls, 3)
+	assert.Contains(t, calls[0][0].Content, "\nTurn 1")
+	assert.Contains(t, calls[1][0].Content, "\nTurn 2")
+	assert.True(t, strings.HasPrefix(calls[0][1].Content, "[tenant=acme] "))
+
+	require.Len(t, result.Messages, 5)
+	assert.Contains(t, result.Messages[4].Content, "[tenant=acme] ")
+}
+
+func TestWithMessageInterceptor_Chained(t *testing.T) {
+	t.Parallel()
+
+	// given
+	appendText := func(text string) agent.MessageInterceptor {
+		return func(_ context.Context, msg llm.LLMMessage) (llm.LLMMessage, error) {
+			if msg.End {
+				msg.Content += text
+			}
+
+			return msg, nil
+		}
+	}
+	interceptorAgent := createInterceptorAgent(t,
+		agent.WithMessageInterceptor[AddNumbersResult](appendText(" first")),
+		agent.WithMessageInterceptor[AddNumbersResult](appendText(" second")),
+	)
+
+	// when
+	result, err := interceptorAgent.UsingLLM(llmtest.NewMockLLM(`{"sum": 3}`, endMessage("done"))).
+		Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "done first second", result.Messages[2].Content)
+}
+
+func TestWithMessageInterceptor_Error(t *testing.T) {
+	t.Parallel()
+
+	// given
+	interceptorAgent := createInterceptorAgent(t,
+		agent.WithMessageInterceptor[AddNumbersResult](
+			func(_ context.Context, msg llm.LLMMessage) (llm.LLMMessage, error) {
+				if msg.Type == llm.LLMMessageTypeAssistant {
+					return llm.LLMMessage{}, errInterceptorFailed
+				}
+
+				return msg, nil
+			}),
+	)
+
+	// when
+	result, err := interceptorAgent.UsingLLM(llmtest.NewMockLLM(`{"sum": 3}`, endMessage("done"))).
+		Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+
+	// then
+	require.ErrorIs(t, err, agent.ErrMessageInterceptor)
+	require.ErrorIs(t, err, errInterceptorFailed)
+	assert.Nil(t, result)
+}
@@ -0,0 +1,80 @@
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+var (
+	// ErrModelNotMultimodal is returned when multimodal input is enabled for a model without image support
+	ErrModelNotMultimodal = errors.New("model does not support multimodal input")
+	// ErrMultimodalDisabled is returned when image input is passed to an agent without multimodal support enabled
+	ErrMultimodalDisabled = errors.New("multimodal input is disabled")
+)
+
+// multimodalModelPrefixes lists model families that accept image input
+var multimodalModelPrefixes = []string{
+	"gpt-4o",
+	"gpt-4.1",
+	"gpt-4-turbo",
+	"gpt-4-vision",
+	"gpt-5",
+	"o1",
+	"o3",
+	"o4",
+}
+
+// WithMultimodal enables image input for the agent. When enabled, NewAgent validates
+// that the configured model supports images, and Run accepts []llm.MessagePart as input:
+//
+//	result, err := visionAgent.Run(ctx, []llm.MessagePart{
+//		llm.NewTextPart("Describe this image"),
+//		llm.NewImagePart(llm.ImageContent{URL: "https://example.com/cat.png"}),
+//	})
+func WithMultimodal[T any](enabled bool) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.multimodal = enabled
+	}
+}
+
+func (a *Agent[T]) validateMultimodal() error {
+	if !a.multimodal {
+		return nil
+	}
+
+	for _, prefix := range multimodalModelPrefixes {
+		if strings.HasPrefix(a.llmConfig.Model, prefix) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s", ErrModelNotMultimodal, a.llmConfig.Model)
+}
+
+func (a *Agent[T]) createMultimodalInput(parts []llm.MessagePart) (llm.LLMMessage, error) {
+	for _, part := range parts {
+		if err := part.Validate(); err != nil {
+			return llm.LLMMessage{}, fmt.Errorf("invalid input: %w", err)
+		}
+	}
+
+	partsJSON, err := json.Marshal(parts)
+	if err != nil {
+		return llm.LLMMessage{}, fmt.Errorf("failed to marshal input: %w", err)
+	}
+
+	if err := a.checkInputSize(partsJSON); err != nil {
+		return llm.LLMMessage{}, err
+	}
+
+	msg := llm.NewMultimodalLLMMessage(llm.LLMMessageTypeUser, parts...)
+	if msg.HasImages() && !a.multimodal {
+		return llm.LLMMessage{}, ErrMultimodalDisabled
+	}
+
+	return msg, nil
+}
@@ -0,0 +1,71 @@
+package agent_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+func createMultimodalAgent(t *testing.T, model string, enabled bool) (*agent.Agent[HashResult], error) {
+	t.Helper()
+
+	config := testLLMConfig()
+	config.Model = model
+
+	return agent.NewAgent(
+		agent.WithName[HashResult]("vision_agent"),
+		agent.WithLLMConfig[HashResult](config),
+		agent.WithBehavior[HashResult]("Describe the image."),
+		agent.WithMultimodal[HashResult](enabled),
+	)
+}
+
+func TestWithMultimodal_UnsupportedModel(t *testing.T) {
+	t.Parallel()
+
+	_, err := createMultimodalAgent(t, "gpt-3.5-turbo", true)
+
+	require.ErrorIs(t, err, agent.ErrModelNotMultimodal)
+}
+
+func TestWithMultimodal_ImageInput(t *testing.T) {
+	t.Parallel()
+
+	visionAgent, err := createMultimodalAgent(t, "gpt-4o", true)
+	require.NoError(t, err)
+
+	mockLLM := llmtest.NewMockLLM(`{"hash": "cat"}`, endMessage("a cat"))
+	agent.SetLLM(visionAgent, mockLLM)
+
+	parts := []llm.MessagePart{
+		llm.NewTextPart("What is in this image?"),
+		llm.NewImagePart(llm.ImageContent{URL: "https://example.com/cat.png"}),
+	}
+
+	result, err := visionAgent.Run(context.Background(), parts)
+
+	require.NoError(t, err)
+	assert.Equal(t, "cat", result.Data.Hash)
+	assert.Equal(t, parts, result.Messages[1].Parts)
+	assert.Empty(t, result.Messages[1].Content)
+}
+
+func TestWithMultimodal_ImageInputDisabled(t *testing.T) {
+	t.Parallel()
+
+	textAgent, err := createMultimodalAgent(t, "gpt-3.5-turbo", false)
+	require.NoError(t, err)
+
+	agent.SetLLM(textAgent, llmtest.NewMockLLM(`{}`))
+
+	_, err = textAgent.Run(context.Background(), []llm.MessagePart{
+		llm.NewImagePart(llm.ImageContent{URL: "https://example.com/cat.png"}),
+	})
+
+	require.ErrorIs(t, err, agent.ErrMultimodalDisabled)
+}
@@ -0,0 +1,213 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+const (
+	tracerName = "github.com/vitalii-honchar/go-agent"
+
+	runStatusSuccess = "success"
+	runStatusError   = "error"
+)
+
+// ObservabilityConfig configures logging, tracing and metrics of an agent.
+// Nil fields are disabled.
+type ObservabilityConfig struct {
+	Logger *slog.Logger
+	// TracerProvider provides the tracer of the "agent.run" spans
+	TracerProvider trace.TracerProvider
+	// MetricsRegisterer registers the agent metrics. Agents sharing a registerer share
+	// the collectors, which are labeled with the service and agent name.
+	MetricsRegisterer prometheus.Registerer
+	ServiceName       string
+}
+
+// NewDefaultObservabilityConfig returns a config that logs with slog.Default()
+// and has tracing and metrics disabled
+func NewDefaultObservabilityConfig(serviceName string) ObservabilityConfig {
+	return ObservabilityConfig{
+		Logger:      slog.Default(),
+		ServiceName: serviceName,
+	}
+}
+
+// WithObservabilityMiddleware sets up logging, tracing and metrics with a single option.
+// Every run is wrapped in an "agent.run" span and logged with its duration, and every
+// LLM message is logged and counted by a middleware.
+func WithObservabilityMiddleware[T any](cfg ObservabilityConfig) AgentOption[T] {
+	return func(a *Agent[T]) {
+		obs := &observer{ObservabilityConfig: cfg}
+		if cfg.TracerProvider != nil {
+			obs.tracer = cfg.TracerProvider.Tracer(tracerName)
+		}
+		if cfg.MetricsRegisterer != nil {
+			obs.metrics, a.observabilityErr = newAgentMetrics(cfg.MetricsRegisterer)
+		}
+
+		a.observability = obs
+		a.middlewares = append(a.middlewares, a.observabilityMiddleware)
+	}
+}
+
+// observer is the observability config with the tracer and collectors created from it
+type observer struct {
+	ObservabilityConfig
+
+	tracer  trace.Tracer
+	metrics *agentMetrics
+}
+
+// observeRun starts the run span and returns a function that finishes the observation
+func (a *Agent[T]) observeRun(ctx context.Context) (context.Context, func(error)) {
+	obs := a.observability
+	if obs == nil {
+		return ctx, func(error) {}
+	}
+
+	start := time.Now()
+
+	var span trace.Span
+	if obs.tracer != nil && isSampled(ctx) {
+		ctx, span = obs.tracer.Start(ctx, "agent.run")
+		span.SetAttributes(attribute.String("service.name", obs.ServiceName), attribute.String("agent.name", a.name))
+		if a.version != "" {
+			span.SetAttributes(attribute.String("agent.version", a.version))
+		}
+	}
+
+	if obs.Logger != nil {
+		obs.Logger.InfoContext(ctx, "agent run started", "service", obs.ServiceName, "agent", a.name)
+	}
+
+	return ctx, func(err error) {
+		duration := time.Since(start)
+
+		if obs.Logger != nil {
+			if err != nil {
+				obs.Logger.ErrorContext(ctx, "agent run failed",
+					"service", obs.ServiceName, "agent", a.name, "duration", duration, "error", err)
+			} else {
+				obs.Logger.InfoContext(ctx, "agent run finished",
+					"service", obs.ServiceName, "agent", a.name, "duration", duration)
+			}
+		}
+
+		if obs.metrics != nil {
+			obs.metrics.runCompleted(obs.ServiceName, a.name, duration, err)
+		}
+
+		if span != nil {
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+			span.End()
+		}
+	}
+}
+
+func (a *Agent[T]) observabilityMiddleware(
+	ctx context.Context,
+	state *AgentState,
+	msg llm.LLMMessage,
+) (llm.LLMMessage, error) {
+	obs := a.observability
+
+	if obs.Logger != nil && state.Sampled {
+		obs.Logger.DebugContext(ctx, "LLM message received",
+			"service", obs.ServiceName, "agent", a.name, "tool_calls", len(msg.ToolCalls), "end", msg.End)
+	}
+
+	if obs.metrics != nil {
+		obs.metrics.llmMessageReceived(obs.ServiceName, a.name, len(msg.ToolCalls))
+	}
+
+	return msg, nil
+}
+
+// agentMetrics records agent metrics into Prometheus collectors
+type agentMetrics struct {
+	runs        *prometheus.CounterVec
+	runDuration *prometheus.HistogramVec
+	llmMessages *prometheus.CounterVec
+	toolCalls   *prometheus.CounterVec
+}
+
+func newAgentMetrics(registerer prometheus.Registerer) (*agentMetrics, error) {
+	labels := []string{"service", "agent"}
+	metrics := &agentMetrics{
+		runs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "goagent_runs_total",
+			Help: "Number of agent runs by status",
+		}, append(labels, "status")),
+		runDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "goagent_run_duration_seconds",
+			Help:    "Duration of agent runs",
+			Buckets: prometheus.ExponentialBuckets(0.1, 2, 12),
+		}, labels),
+		llmMessages: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "goagent_llm_messages_total",
+			Help: "Number of messages received from the LLM",
+		}, labels),
+		toolCalls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "goagent_tool_calls_requested_total",
+			Help: "Number of tool calls requested by the LLM",
+		}, labels),
+	}
+
+	var err error
+	if metrics.runs, err = register(registerer, metrics.runs); err != nil {
+		return nil, err
+	}
+	if metrics.runDuration, err = register(registerer, metrics.runDuration); err != nil {
+		return nil, err
+	}
+	if metrics.llmMessages, err = register(registerer, metrics.llmMessages); err != nil {
+		return nil, err
+	}
+	if metrics.toolCalls, err = register(registerer, metrics.toolCalls); err != nil {
+		return nil, err
+	}
+
+	return metrics, nil
+}
+
+// register registers the collector, or returns the collector registered by another agent
+func register[C prometheus.Collector](registerer prometheus.Registerer, collector C) (C, error) {
+	err := registerer.Register(collector)
+
+	var registered prometheus.AlreadyRegisteredError
+	if errors.As(err, &registered) {
+		if existing, ok := registered.ExistingCollector.(C); ok {
+			return existing, nil
+		}
+	}
+
+	return collector, err
+}
+
+func (m *agentMetrics) runCompleted(serviceName, agentName string, duration time.Duration, err error) {
+	status := runStatusSuccess
+	if err != nil {
+		status = runStatusError
+	}
+
+	m.runs.WithLabelValues(serviceName, agentName, status).Inc()
+	m.runDuration.WithLabelValues(serviceName, agentName).Observe(duration.Seconds())
+}
+
+func (m *agentMetrics) llmMessageReceived(serviceName, agentName string, toolCalls int) {
+	m.llmMessages.WithLabelValues(serviceName, agentName).Inc()
+	m.toolCalls.WithLabelValues(serviceName, agentName).Add(float64(toolCalls))
+}
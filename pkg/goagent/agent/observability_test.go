@@ -0,0 +1,162 @@
+package agent_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newSpanRecorder() (*tracetest.SpanRecorder, *sdktrace.TracerProvider) {
+	recorder := tracetest.NewSpanRecorder()
+
+	return recorder, sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+}
+
+// metricValue sums the counter samples of the metric whose labels contain the given
+// name and value pairs
+func metricValue(t *testing.T, registry *prometheus.Registry, name string, labels ...string) float64 {
+	t.Helper()
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	var sum float64
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			values := make(map[string]string)
+			for _, label := range metric.GetLabel() {
+				values[label.GetName()] = label.GetValue()
+			}
+
+			matches := true
+			for i := 0; i+1 < len(labels); i += 2 {
+				matches = matches && values[labels[i]] == labels[i+1]
+			}
+			if matches {
+				sum += metric.GetCounter().GetValue()
+			}
+		}
+	}
+
+	return sum
+}
+
+func TestWithObservabilityMiddleware(t *testing.T) {
+	t.Parallel()
+
+	var logs bytes.Buffer
+	spans, tracerProvider := newSpanRecorder()
+	registry := prometheus.NewRegistry()
+
+	cfg := agent.NewDefaultObservabilityConfig("calculator_service")
+	cfg.Logger = slog.New(slog.NewTextHandler(&logs, nil))
+	cfg.TracerProvider = tracerProvider
+	cfg.MetricsRegisterer = registry
+
+	observedAgent, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("observed_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithTool[AddNumbersResult]("add", createTestAddTool()),
+		agent.WithObservabilityMiddleware[AddNumbersResult](cfg),
+	)
+	require.NoError(t, err)
+
+	agent.SetLLM(observedAgent, llmtest.NewMockLLM(`{"sum": 3}`,
+		toolCallMessage(llm.LLMToolCall{ID: "call_1", ToolName: "add", Args: `{"num1": 1, "num2": 2}`}),
+		endMessage("done"),
+	))
+	_, err = observedAgent.Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+	require.NoError(t, err)
+
+	agent.SetLLM(observedAgent, llmtest.NewMockLLM(`{"sum": 3}`))
+	_, err = observedAgent.Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+	require.Error(t, err)
+
+	ended := spans.Ended()
+	require.Len(t, ended, 2)
+	assert.Equal(t, "agent.run", ended[0].Name())
+	assert.Equal(t, codes.Unset, ended[0].Status().Code)
+	assert.Contains(t, ended[0].Attributes(), attribute.String("agent.name", "observed_agent"))
+	assert.Equal(t, codes.Error, ended[1].Status().Code)
+	assert.Len(t, ended[1].Events(), 1)
+
+	assert.InDelta(t, 1, metricValue(t, registry, "goagent_runs_total", "status", "success"), 0)
+	assert.InDelta(t, 1, metricValue(t, registry, "goagent_runs_total",
+		"service", "calculator_service", "agent", "observed_agent", "status", "error"), 0)
+	assert.InDelta(t, 2, metricValue(t, registry, "goagent_llm_messages_total"), 0)
+	assert.InDelta(t, 1, metricValue(t, registry, "goagent_tool_calls_requested_total"), 0)
+
+	assert.Contains(t, logs.String(), "agent run finished")
+	assert.Contains(t, logs.String(), "agent run failed")
+	assert.Contains(t, logs.String(), "service=calculator_service")
+}
+
+func TestWithObservabilityMiddleware_SharedRegisterer(t *testing.T) {
+	t.Parallel()
+
+	// given
+	registry := prometheus.NewRegistry()
+	cfg := agent.ObservabilityConfig{MetricsRegisterer: registry, ServiceName: "calculator_service"}
+
+	agents := make([]*agent.Agent[AddNumbersResult], 2)
+	for i, name := range []string{"first_agent", "second_agent"} {
+		observedAgent, err := agent.NewAgent(
+			agent.WithName[AddNumbersResult](name),
+			agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+			agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+			agent.WithObservabilityMiddleware[AddNumbersResult](cfg),
+		)
+		require.NoError(t, err)
+		agents[i] = observedAgent
+	}
+
+	// when
+	for _, observedAgent := range agents {
+		_, err := observedAgent.UsingLLM(llmtest.NewMockLLM(`{"sum": 3}`, endMessage("done"))).
+			Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+		require.NoError(t, err)
+	}
+
+	// then
+	assert.InDelta(t, 1, metricValue(t, registry, "goagent_runs_total", "agent", "first_agent"), 0)
+	assert.InDelta(t, 1, metricValue(t, registry, "goagent_runs_total", "agent", "second_agent"), 0)
+}
+
+func TestWithObservabilityMiddleware_ConflictingCollector(t *testing.T) {
+	t.Parallel()
+
+	// given
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "goagent_runs_total",
+		Help: "Conflicting collector",
+	}))
+
+	// when
+	_, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("observed_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithObservabilityMiddleware[AddNumbersResult](agent.ObservabilityConfig{MetricsRegisterer: registry}),
+	)
+
+	// then
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "observability")
+}
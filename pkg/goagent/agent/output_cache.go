@@ -0,0 +1,210 @@
+package agent
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OutputCache stores agent results by a key derived from the run input.
+// A zero ttl passed to Set means the default TTL of the cache.
+type OutputCache[T any] interface {
+	Get(key string) (*AgentResult[T], bool)
+	Set(key string, result *AgentResult[T], ttl time.Duration)
+}
+
+// CacheKeyFunc derives the output cache key from the run input
+type CacheKeyFunc func(input any) string
+
+type outputCacheConfig[T any] struct {
+	cache OutputCache[T]
+	keyFn CacheKeyFunc
+}
+
+// WithOutputCache makes Run return the cached result for an input it has already answered,
+// without any LLM or tool calls. Use it for deterministic agents, e.g. with temperature 0,
+// whose output depends only on the input. Only successful results are cached, and results
+// produced by a fallback agent are not.
+//
+// keyFn derives the cache key from the input. When it is nil, the key is the SHA-256 of the
+// JSON encoded input. Runs with an LLM config override from the context, see
+// llm.WithLLMConfigOverride, and runs in which a conditional tool is unavailable use their
+// own cache entries. Every run gets its own copy of a cached result; Data is copied shallowly.
+// Cache hits don't update the input history, so don't combine the cache with WithInputHistory.
+// A nil cache disables caching.
+//
+// Example:
+//
+//	myAgent, err := agent.NewAgent(
+//		// ... other options
+//		agent.WithOutputCache[Answer](agent.NewTTLOutputCache[Answer](time.Hour), nil),
+//	)
+func WithOutputCache[T any](cache OutputCache[T], keyFn CacheKeyFunc) AgentOption[T] {
+	return func(a *Agent[T]) {
+		if cache == nil {
+			a.outputCache = nil
+
+			return
+		}
+		if keyFn == nil {
+			keyFn = defaultCacheKey
+		}
+		a.outputCache = &outputCacheConfig[T]{cache: cache, keyFn: keyFn}
+	}
+}
+
+func defaultCacheKey(input any) string {
+	data, err := json.Marshal(input)
+	if err != nil {
+		data = fmt.Appendf(nil, "%#v", input)
+	}
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *outputCacheConfig[T]) get(input any, scope string) (string, *AgentResult[T], bool) {
+	if c == nil {
+		return "", nil, false
+	}
+
+	key := c.keyFn(input)
+	if scope != "" {
+		key += ":" + scope
+	}
+
+	result, ok := c.cache.Get(key)
+	if !ok {
+		return key, nil, false
+	}
+
+	return key, result.clone(), true
+}
+
+func (c *outputCacheConfig[T]) set(key string, result *AgentResult[T], err error) {
+	if c == nil || err != nil || result == nil || result.Metadata[MetadataUsedFallback] == true {
+		return
+	}
+
+	c.cache.Set(key, result.clone(), 0)
+}
+
+// clone copies the result, so a run which modifies its result doesn't change the cached one
+func (r *AgentResult[T]) clone() *AgentResult[T] {
+	clone := *r
+	if r.Data != nil {
+		data := *r.Data
+		clone.Data = &data
+	}
+	clone.Messages = slices.Clone(r.Messages)
+	clone.Metadata = maps.Clone(r.Metadata)
+
+	return &clone
+}
+
+type ttlCacheEntry[T any] struct {
+	result    *AgentResult[T]
+	expiresAt time.Time
+}
+
+// TTLOutputCache is an in-memory OutputCache whose entries expire after a TTL
+type TTLOutputCache[T any] struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]ttlCacheEntry[T]
+}
+
+// NewTTLOutputCache creates an in-memory output cache with the given default TTL.
+// Expired entries are removed when they are looked up or a new entry is stored.
+func NewTTLOutputCache[T any](ttl time.Duration) *TTLOutputCache[T] {
+	return &TTLOutputCache[T]{
+		ttl:     ttl,
+		entries: make(map[string]ttlCacheEntry[T]),
+	}
+}
+
+// Get returns the result stored under key unless it has expired
+func (c *TTLOutputCache[T]) Get(key string) (*AgentResult[T], bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(c.entries, key)
+
+		return nil, false
+	}
+
+	return entry.result, true
+}
+
+// Set stores the result under key for ttl, or for the default TTL when ttl is not positive
+func (c *TTLOutputCache[T]) Set(key string, result *AgentResult[T], ttl time.Duration) {
+	if ttl <= 0 {
+		ttl = c.ttl
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for cachedKey, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, cachedKey)
+		}
+	}
+	c.entries[key] = ttlCacheEntry[T]{result: result, expiresAt: now.Add(ttl)}
+}
+
+func (a *Agent[T]) runCached(ctx context.Context, input any) (*AgentResult[T], error) {
+	runAgent, err := a.withLLMConfigOverride(ctx)
+	if err != nil {
+		return nil, err
+	}
+	runAgent = runAgent.withConditionalTools(ctx)
+
+	scope, err := a.outputCacheScope(runAgent)
+	if err != nil {
+		return nil, err
+	}
+
+	key, cached, ok := a.outputCache.get(input, scope)
+	if ok {
+		return cached, nil
+	}
+
+	result, err := runAgent.run(ctx, input)
+	result, err = a.runFallback(ctx, input, result, err)
+	a.outputCache.set(key, result, err)
+
+	return result, err
+}
+
+// outputCacheScope identifies what the result of runAgent depends on besides its input:
+// the LLM config overridden for the run and the conditional tools which are unavailable
+func (a *Agent[T]) outputCacheScope(runAgent *Agent[T]) (string, error) {
+	var scope []string
+	if !reflect.DeepEqual(runAgent.llmConfig, a.llmConfig) {
+		configKey, err := overrideCacheKey(runAgent.llmConfig)
+		if err != nil {
+			return "", err
+		}
+		scope = append(scope, "llm_config="+configKey)
+	}
+	if len(runAgent.unavailableTools) > 0 {
+		scope = append(scope, "unavailable_tools="+strings.Join(runAgent.unavailableTools, ","))
+	}
+
+	return strings.Join(scope, ":"), nil
+}
@@ -0,0 +1,169 @@
+package agent_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+func newCachedAgent(t *testing.T, cache agent.OutputCache[AddNumbersResult], keyFn agent.CacheKeyFunc,
+	options ...agent.AgentOption[AddNumbersResult],
+) *agent.Agent[AddNumbersResult] {
+	t.Helper()
+
+	a, err := agent.NewAgent(append([]agent.AgentOption[AddNumbersResult]{
+		agent.WithName[AddNumbersResult]("cached_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithOutputCache(cache, keyFn),
+	}, options...)...)
+	require.NoError(t, err)
+
+	return a
+}
+
+func TestWithOutputCache(t *testing.T) {
+	t.Parallel()
+
+	// given
+	cachedAgent := newCachedAgent(t, agent.NewTTLOutputCache[AddNumbersResult](time.Hour), nil)
+	firstLLM := llmtest.NewMockLLM(`{"sum": 3}`, endMessage("done"))
+	secondLLM := llmtest.NewMockLLM(`{"sum": 7}`, endMessage("done"), endMessage("done"))
+
+	// when
+	first, err := cachedAgent.UsingLLM(firstLLM).Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+	require.NoError(t, err)
+	cached, err := cachedAgent.UsingLLM(secondLLM).Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+	require.NoError(t, err)
+	other, err := cachedAgent.UsingLLM(secondLLM).Run(context.Background(), AddNumbers{Num1: 3, Num2: 4})
+	require.NoError(t, err)
+
+	// then
+	assert.Equal(t, first, cached)
+	assert.NotSame(t, first, cached)
+	assert.InDelta(t, 7, other.Data.Sum, 0)
+	assert.Len(t, secondLLM.Calls(), 2, "only the run with a new input must call the LLM")
+}
+
+func TestWithOutputCache_ResultsAreCopied(t *testing.T) {
+	t.Parallel()
+
+	// given
+	cachedAgent := newCachedAgent(t, agent.NewTTLOutputCache[AddNumbersResult](time.Hour), nil)
+	first, err := cachedAgent.UsingLLM(llmtest.NewMockLLM(`{"sum": 3}`, endMessage("done"))).
+		Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+	require.NoError(t, err)
+
+	// when
+	first.Data.Sum = 100
+	first.Metadata = map[string]any{"changed": true}
+	first.Messages[0].Content = "changed"
+	cached, err := cachedAgent.Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+
+	// then
+	require.NoError(t, err)
+	assert.InDelta(t, 3, cached.Data.Sum, 0)
+	assert.Empty(t, cached.Metadata)
+	assert.NotEqual(t, "changed", cached.Messages[0].Content)
+}
+
+func TestWithOutputCache_ConditionalTools(t *testing.T) {
+	t.Parallel()
+
+	// given
+	cachedAgent := newCachedAgent(t, agent.NewTTLOutputCache[AddNumbersResult](time.Hour), nil,
+		agent.WithConditionalTool[AddNumbersResult]("add", createTestAddTool(), isAdmin))
+	adminCtx := context.WithValue(context.Background(), roleKey{}, "admin")
+	viewerCtx := context.WithValue(context.Background(), roleKey{}, "viewer")
+	viewerLLM := llmtest.NewMockLLM(`{"sum": 0}`, endMessage("done"))
+
+	// when
+	_, err := cachedAgent.UsingLLM(llmtest.NewMockLLM(`{"sum": 3}`, endMessage("done"))).
+		Run(adminCtx, AddNumbers{Num1: 1, Num2: 2})
+	require.NoError(t, err)
+	viewerResult, err := cachedAgent.UsingLLM(viewerLLM).Run(viewerCtx, AddNumbers{Num1: 1, Num2: 2})
+
+	// then
+	require.NoError(t, err)
+	assert.NotEmpty(t, viewerLLM.Calls(), "a result produced with the admin tools must not be served to a viewer")
+	assert.InDelta(t, 0, viewerResult.Data.Sum, 0)
+}
+
+func TestWithOutputCache_ErrorsAreNotCached(t *testing.T) {
+	t.Parallel()
+
+	// given
+	cachedAgent := newCachedAgent(t, agent.NewTTLOutputCache[AddNumbersResult](time.Hour), nil)
+
+	// when
+	_, err := cachedAgent.UsingLLM(llmtest.NewMockLLM(`{"sum": 0}`)).Run(context.Background(), AddNumbers{})
+	require.ErrorIs(t, err, agent.ErrLLMCall)
+	result, err := cachedAgent.UsingLLM(llmtest.NewMockLLM(`{"sum": 0}`, endMessage("done"))).
+		Run(context.Background(), AddNumbers{})
+
+	// then
+	require.NoError(t, err)
+	assert.NotNil(t, result)
+}
+
+func TestWithOutputCache_CustomKey(t *testing.T) {
+	t.Parallel()
+
+	// given
+	sameKey := func(any) string { return "all" }
+	cachedAgent := newCachedAgent(t, agent.NewTTLOutputCache[AddNumbersResult](time.Hour), sameKey)
+
+	// when
+	first, err := cachedAgent.UsingLLM(llmtest.NewMockLLM(`{"sum": 3}`, endMessage("done"))).
+		Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+	require.NoError(t, err)
+	second, err := cachedAgent.UsingLLM(llmtest.NewMockLLM(`{"sum": 7}`)).
+		Run(context.Background(), AddNumbers{Num1: 3, Num2: 4})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+}
+
+func TestTTLOutputCache(t *testing.T) {
+	t.Parallel()
+
+	cache := agent.NewTTLOutputCache[AddNumbersResult](time.Hour)
+	result := &agent.AgentResult[AddNumbersResult]{Data: &AddNumbersResult{Sum: 3}}
+
+	_, ok := cache.Get("key")
+	assert.False(t, ok)
+
+	cache.Set("key", result, 0)
+	cached, ok := cache.Get("key")
+	require.True(t, ok)
+	assert.Same(t, result, cached)
+
+	cache.Set("expired", result, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	_, ok = cache.Get("expired")
+	assert.False(t, ok)
+}
+
+func TestTTLOutputCache_SetRemovesExpiredEntries(t *testing.T) {
+	t.Parallel()
+
+	// given
+	cache := agent.NewTTLOutputCache[AddNumbersResult](time.Hour)
+	result := &agent.AgentResult[AddNumbersResult]{Data: &AddNumbersResult{Sum: 3}}
+	for _, key := range []string{"a", "b", "c"} {
+		cache.Set(key, result, time.Nanosecond)
+	}
+	time.Sleep(time.Millisecond)
+
+	// when
+	cache.Set("d", result, 0)
+
+	// then
+	assert.Equal(t, 1, agent.CachedEntries(cache))
+}
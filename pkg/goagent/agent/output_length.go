@@ -0,0 +1,177 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+)
+
+// ErrOutputTooLarge is returned when the total length of the string fields of the result
+// exceeds the limit set by WithMaxOutputLength
+var ErrOutputTooLarge = errors.New("output too large")
+
+type outputLengthLimit struct {
+	maxChars int
+	truncate bool
+}
+
+// outputString is a string found in the result together with a function replacing it.
+// set is nil for strings which can't be replaced, e.g. fields of a struct stored in a map.
+type outputString struct {
+	value string
+	set   func(string)
+}
+
+// WithMaxOutputLength limits the total number of characters in all string fields of the
+// result, including strings nested in structs, slices, maps and pointers. A hallucinating
+// model can fill string fields with thousands of tokens; with this option Run fails with
+// ErrOutputTooLarge instead of returning such a result.
+func WithMaxOutputLength[T any](chars int) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.outputLength = &outputLengthLimit{maxChars: chars}
+	}
+}
+
+// WithTruncateOutput is an alternative to WithMaxOutputLength which truncates the string
+// fields of the result instead of failing. The longest string is truncated first, then the
+// next longest, until the total length fits into chars.
+func WithTruncateOutput[T any](chars int) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.outputLength = &outputLengthLimit{maxChars: chars, truncate: true}
+	}
+}
+
+func (a *Agent[T]) validateOutputLength() error {
+	if a.outputLength == nil {
+		return nil
+	}
+
+	return validation.IntIsPositive(a.outputLength.maxChars, "max output length")
+}
+
+// limitOutputLength checks the total length of the string fields of result and truncates
+// them when WithTruncateOutput is used
+func (a *Agent[T]) limitOutputLength(result *T) error {
+	if a.outputLength == nil {
+		return nil
+	}
+
+	var strs []outputString
+	output := reflect.ValueOf(result).Elem()
+	collectOutputStrings(output, setterOf(output), &strs)
+
+	total := 0
+	for _, s := range strs {
+		total += utf8.RuneCountInString(s.value)
+	}
+
+	excess := total - a.outputLength.maxChars
+	if excess <= 0 {
+		return nil
+	}
+
+	if a.outputLength.truncate {
+		excess = truncateOutputStrings(strs, excess)
+	}
+
+	if excess > 0 {
+		return fmt.Errorf("%w: string fields have %d characters, limit is %d",
+			ErrOutputTooLarge, total, a.outputLength.maxChars)
+	}
+
+	return nil
+}
+
+// truncateOutputStrings shortens the longest strings first by excess characters in total
+// and returns the number of characters which could not be removed
+func truncateOutputStrings(strs []outputString, excess int) int {
+	sort.SliceStable(strs, func(i, j int) bool {
+		return utf8.RuneCountInString(strs[i].value) > utf8.RuneCountInString(strs[j].value)
+	})
+
+	for _, s := range strs {
+		if excess <= 0 {
+			break
+		}
+		if s.set == nil {
+			continue
+		}
+
+		runes := []rune(s.value)
+		cut := min(excess, len(runes))
+		s.set(string(runes[:len(runes)-cut]))
+		excess -= cut
+	}
+
+	return excess
+}
+
+func collectOutputStrings(v reflect.Value, set func(string), strs *[]outputString) {
+	//nolint:exhaustive // other kinds don't contain strings
+	switch v.Kind() {
+	case reflect.String:
+		*strs = append(*strs, outputString{value: v.String(), set: set})
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+
+		// an interface element is replaced through the interface itself
+		elemSet := set
+		if v.Kind() == reflect.Pointer {
+			elemSet = setterOf(v.Elem())
+		}
+		collectOutputStrings(v.Elem(), elemSet, strs)
+	case reflect.Struct:
+		for i := range v.NumField() {
+			if v.Type().Field(i).IsExported() {
+				collectOutputStrings(v.Field(i), setterOf(v.Field(i)), strs)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			collectOutputStrings(v.Index(i), setterOf(v.Index(i)), strs)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			key, elem := iter.Key(), iter.Value()
+			elemSet := func(s string) { v.SetMapIndex(key, stringValue(s, mapStringType(elem))) }
+			collectOutputStrings(elem, elemSet, strs)
+		}
+	}
+}
+
+// setterOf returns a function replacing the string held by v, or nil if v can't be set
+func setterOf(v reflect.Value) func(string) {
+	if !v.CanSet() {
+		return nil
+	}
+
+	return func(s string) {
+		typ := v.Type()
+		if v.Kind() == reflect.Interface {
+			typ = v.Elem().Type()
+		}
+		v.Set(stringValue(s, typ))
+	}
+}
+
+// mapStringType returns the type of the string stored in a map element, which may be
+// wrapped in an interface like in map[string]any
+func mapStringType(elem reflect.Value) reflect.Type {
+	if elem.Kind() == reflect.Interface {
+		return elem.Elem().Type()
+	}
+
+	return elem.Type()
+}
+
+// stringValue converts s to typ, which is string or a type defined as string
+func stringValue(s string, typ reflect.Type) reflect.Value {
+	return reflect.ValueOf(s).Convert(typ)
+}
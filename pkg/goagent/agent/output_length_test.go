@@ -0,0 +1,129 @@
+package agent_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+type SummaryResult struct {
+	Title    string            `json:"title"`
+	Body     string            `json:"body"`
+	Tags     []string          `json:"tags"`
+	Metadata map[string]any    `json:"metadata"`
+	Labels   map[string]string `json:"labels"`
+}
+
+func runSummaryAgent(
+	t *testing.T, output string, opts ...agent.AgentOption[SummaryResult],
+) (*agent.AgentResult[SummaryResult], error) {
+	t.Helper()
+
+	opts = append([]agent.AgentOption[SummaryResult]{
+		agent.WithName[SummaryResult]("summary_agent"),
+		agent.WithLLMConfig[SummaryResult](testLLMConfig()),
+		agent.WithBehavior[SummaryResult]("You summarize texts."),
+	}, opts...)
+
+	summaryAgent, err := agent.NewAgent(opts...)
+	require.NoError(t, err)
+
+	agent.SetLLM(summaryAgent, llmtest.NewMockLLM(output, endMessage("done")))
+
+	return summaryAgent.Run(context.Background(), AddNumbers{})
+}
+
+func TestWithMaxOutputLength(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		output  string
+		wantErr bool
+	}{
+		{name: "within limit", output: `{"title": "Go", "body": "Fast", "tags": ["a"]}`, wantErr: false},
+		{name: "exactly at limit", output: `{"title": "Hello", "body": "World"}`, wantErr: false},
+		{name: "nested strings exceed limit", output: `{"tags": ["abcdefg", "hijk"]}`, wantErr: true},
+		{name: "map values exceed limit", output: `{"metadata": {"note": "abcdefghijk"}}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// when
+			result, err := runSummaryAgent(t, tt.output, agent.WithMaxOutputLength[SummaryResult](10))
+
+			// then
+			if tt.wantErr {
+				require.ErrorIs(t, err, agent.ErrOutputTooLarge)
+			} else {
+				require.NoError(t, err)
+				assert.NotNil(t, result.Data)
+			}
+		})
+	}
+}
+
+func TestWithTruncateOutput(t *testing.T) {
+	t.Parallel()
+
+	// given
+	output := `{
+		"title": "Short title",
+		"body": "` + strings.Repeat("ж", 100) + `",
+		"tags": ["tag"],
+		"metadata": {"note": "` + strings.Repeat("n", 20) + `", "count": 2},
+		"labels": {"lang": "` + strings.Repeat("l", 15) + `"}
+	}`
+
+	// when
+	result, err := runSummaryAgent(t, output, agent.WithTruncateOutput[SummaryResult](50))
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "Short title", result.Data.Title)
+	assert.Equal(t, []string{"tag"}, result.Data.Tags)
+	assert.Equal(t, strings.Repeat("n", 20), result.Data.Metadata["note"])
+	assert.Equal(t, strings.Repeat("l", 15), result.Data.Labels["lang"])
+	assert.Equal(t, strings.Repeat("ж", 1), result.Data.Body)
+}
+
+func TestWithTruncateOutput_SeveralFields(t *testing.T) {
+	t.Parallel()
+
+	// given
+	output := `{"title": "` + strings.Repeat("t", 8) + `", "body": "` + strings.Repeat("b", 10) + `",
+		"metadata": {"note": "` + strings.Repeat("n", 6) + `"}}`
+
+	// when
+	result, err := runSummaryAgent(t, output, agent.WithTruncateOutput[SummaryResult](10))
+
+	// then
+	require.NoError(t, err)
+	assert.Empty(t, result.Data.Body)
+	assert.Equal(t, strings.Repeat("t", 4), result.Data.Title)
+	assert.Equal(t, strings.Repeat("n", 6), result.Data.Metadata["note"])
+}
+
+func TestWithMaxOutputLength_InvalidLimit(t *testing.T) {
+	t.Parallel()
+
+	// when
+	_, err := agent.NewAgent(
+		agent.WithName[SummaryResult]("summary_agent"),
+		agent.WithLLMConfig[SummaryResult](testLLMConfig()),
+		agent.WithBehavior[SummaryResult]("You summarize texts."),
+		agent.WithMaxOutputLength[SummaryResult](0),
+	)
+
+	// then
+	require.ErrorIs(t, err, validation.ErrValidationFailed)
+	assert.Contains(t, err.Error(), "max output length")
+}
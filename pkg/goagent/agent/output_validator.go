@@ -0,0 +1,117 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+const defaultValidatorMaxRetries = 1
+
+// ErrOutputValidationFailed is returned when the result does not pass an output validator
+// after all re-prompt attempts
+var ErrOutputValidationFailed = errors.New("output validation failed")
+
+var validationFixPromptTemplate = NewPrompt(`Your final output above failed validation:
+
+{{.error}}
+
+Fix the fields mentioned in the validation error and provide the corrected final output.
+Keep all other fields unchanged.
+Output ONLY the JSON object with no additional text`)
+
+// Validator checks business rules on the agent result which can't be expressed
+// with the JSON schema, e.g. a number must be positive or a URL must use https.
+// It returns an error describing which field is invalid and why.
+type Validator[T any] func(ctx context.Context, data *T) error
+
+// WithOutputValidator adds a validator which is run on the agent result. When validation
+// fails, the agent re-prompts the LLM with the validation error to fix the result, up to
+// the number of times set by WithValidatorMaxRetries (1 by default). Run returns
+// ErrOutputValidationFailed if the result is still invalid after that.
+//
+// Example:
+//
+//	agent.WithOutputValidator(func(_ context.Context, data *AddNumbersResult) error {
+//		if data.Sum <= 0 {
+//			return errors.New("sum must be positive")
+//		}
+//
+//		return nil
+//	})
+func WithOutputValidator[T any](v Validator[T]) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.outputValidators = append(a.outputValidators, v)
+	}
+}
+
+// WithValidatorMaxRetries sets how many times the agent re-prompts the LLM to fix
+// a result which failed output validation. Zero disables re-prompting.
+func WithValidatorMaxRetries[T any](n int) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.validatorMaxRetries = n
+	}
+}
+
+// validateOutput runs the output validators on result and re-prompts the LLM
+// to fix the result while validation fails and re-prompt attempts are left
+func (a *Agent[T]) validateOutput(ctx context.Context, state *AgentState, result T) (T, error) {
+	err := a.runOutputValidators(ctx, &result)
+
+	for attempt := 0; err != nil && attempt < a.validatorMaxRetries; attempt++ {
+		result, err = a.fixOutput(ctx, state, result, err)
+		if err != nil {
+			return result, err
+		}
+
+		err = a.runOutputValidators(ctx, &result)
+	}
+
+	if err != nil {
+		return result, fmt.Errorf("%w: %w", ErrOutputValidationFailed, err)
+	}
+
+	return result, nil
+}
+
+func (a *Agent[T]) runOutputValidators(ctx context.Context, result *T) error {
+	for _, validator := range a.outputValidators {
+		if err := validator(ctx, result); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (a *Agent[T]) fixOutput(ctx context.Context, state *AgentState, result T, validationErr error) (T, error) {
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return result, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	fixPrompt, err := validationFixPromptTemplate.Render(map[string]any{"error": validationErr.Error()})
+	if err != nil {
+		return result, fmt.Errorf("failed to render validation fix prompt: %w", err)
+	}
+
+	fixMessages := []llm.LLMMessage{
+		llm.NewLLMMessage(llm.LLMMessageTypeAssistant, string(resultJSON)),
+		llm.NewLLMMessage(llm.LLMMessageTypeUser, fixPrompt),
+	}
+	for _, msg := range fixMessages {
+		if _, err := state.addMessage(ctx, msg); err != nil {
+			return result, err
+		}
+	}
+
+	fixed, err := a.callWithSchemaRetry(ctx, state)
+	if err != nil {
+		return result, fmt.Errorf("%w: %w", ErrLLMCall, err)
+	}
+
+	return fixed, nil
+}
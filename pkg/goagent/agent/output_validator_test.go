@@ -0,0 +1,137 @@
+package agent_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+var errSumNotPositive = errors.New("sum must be positive")
+
+// sequenceLLM returns the structured outputs one by one, repeating the last one
+type sequenceLLM struct {
+	*llmtest.MockLLM
+
+	outputs []string
+}
+
+func (s *sequenceLLM) CallWithStructuredOutput(
+	ctx context.Context,
+	msgs []llm.LLMMessage,
+	schemaT any,
+) (string, error) {
+	if _, err := s.MockLLM.CallWithStructuredOutput(ctx, msgs, schemaT); err != nil {
+		return "", err
+	}
+
+	output := s.outputs[0]
+	if len(s.outputs) > 1 {
+		s.outputs = s.outputs[1:]
+	}
+
+	return output, nil
+}
+
+func positiveSum(_ context.Context, data *AddNumbersResult) error {
+	if data.Sum <= 0 {
+		return errSumNotPositive
+	}
+
+	return nil
+}
+
+func newValidatedAgent(t *testing.T, options ...agent.AgentOption[AddNumbersResult]) *agent.Agent[AddNumbersResult] {
+	t.Helper()
+
+	options = append([]agent.AgentOption[AddNumbersResult]{
+		agent.WithName[AddNumbersResult]("validated"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("Add numbers"),
+		agent.WithOutputValidator(positiveSum),
+	}, options...)
+
+	a, err := agent.NewAgent(options...)
+	require.NoError(t, err)
+
+	return a
+}
+
+func TestWithOutputValidator_RepromptFixesResult(t *testing.T) {
+	t.Parallel()
+
+	// given
+	mockLLM := &sequenceLLM{
+		MockLLM: llmtest.NewMockLLM("", endMessage("done")),
+		outputs: []string{`{"sum": -1}`, `{"sum": 3}`},
+	}
+	a := newValidatedAgent(t).UsingLLM(mockLLM)
+
+	// when
+	result, err := a.Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Data.Sum)
+
+	calls := mockLLM.Calls()
+	require.Len(t, calls, 3)
+
+	fixCall := calls[2]
+	assert.JSONEq(t, `{"sum": -1}`, fixCall[len(fixCall)-2].Content)
+	assert.Contains(t, fixCall[len(fixCall)-1].Content, errSumNotPositive.Error())
+}
+
+func TestWithOutputValidator_FailsAfterRetries(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		maxRetries    int
+		expectedCalls int
+	}{
+		{"one retry", 1, 3},
+		{"no retries", 0, 2},
+		{"several retries", 3, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			mockLLM := llmtest.NewMockLLM(`{"sum": 0}`, endMessage("done"))
+			a := newValidatedAgent(t,
+				agent.WithValidatorMaxRetries[AddNumbersResult](tt.maxRetries),
+			).UsingLLM(mockLLM)
+
+			// when
+			result, err := a.Run(context.Background(), AddNumbers{Num1: 1, Num2: -1})
+
+			// then
+			require.ErrorIs(t, err, agent.ErrOutputValidationFailed)
+			require.ErrorIs(t, err, errSumNotPositive)
+			assert.Nil(t, result)
+			assert.Len(t, mockLLM.Calls(), tt.expectedCalls)
+		})
+	}
+}
+
+func TestWithValidatorMaxRetries_Negative(t *testing.T) {
+	t.Parallel()
+
+	_, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("validated"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("Add numbers"),
+		agent.WithValidatorMaxRetries[AddNumbersResult](-1),
+	)
+
+	require.ErrorIs(t, err, validation.ErrValidationFailed)
+}
@@ -0,0 +1,130 @@
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+// ErrOutputWrite is returned when the progress of a run cannot be written to the writer set
+// by WithOutput
+var ErrOutputWrite = errors.New("failed to write output")
+
+// OutputFormat is the format in which WithOutput writes the progress of a run
+type OutputFormat string
+
+const (
+	// OutputFormatMarkdown writes every reasoning step as a paragraph and the result as a
+	// fenced JSON code block
+	OutputFormatMarkdown OutputFormat = "markdown"
+	// OutputFormatJSON writes one JSON object per line: {"type": "step", "content": ...} for
+	// every reasoning step and {"type": "result", "data": ...} for the result
+	OutputFormatJSON OutputFormat = "json"
+	// OutputFormatPlainText writes every reasoning step and the indented JSON of the result
+	// on lines of their own
+	OutputFormatPlainText OutputFormat = "plain_text"
+)
+
+var outputFormats = []OutputFormat{OutputFormatMarkdown, OutputFormatJSON, OutputFormatPlainText}
+
+type outputWriter struct {
+	mu     sync.Mutex
+	writer io.Writer
+	format OutputFormat
+}
+
+// outputEvent is a line written in OutputFormatJSON
+type outputEvent struct {
+	Type    string `json:"type"`
+	Content string `json:"content,omitempty"`
+	Data    any    `json:"data,omitempty"`
+}
+
+// WithOutput writes the progress of every run to writer while it runs, e.g. for a live
+// display in CLI tools: the content of every assistant message which does not end the
+// conversation, and the result once it is collected. Write errors fail the run with
+// ErrOutputWrite.
+//
+// Example:
+//
+//	agent.WithOutput[Report](os.Stdout, agent.OutputFormatMarkdown)
+func WithOutput[T any](writer io.Writer, format OutputFormat) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.output = &outputWriter{writer: writer, format: format}
+	}
+}
+
+func (w *outputWriter) validate() error {
+	if w.writer == nil {
+		return fmt.Errorf("writer: %w: value cannot be nil", validation.ErrValidationFailed)
+	}
+	if !slices.Contains(outputFormats, w.format) {
+		return fmt.Errorf("%w: unknown format %q", validation.ErrValidationFailed, w.format)
+	}
+
+	return nil
+}
+
+// writeStep writes the content of an assistant message which does not end the conversation
+func (w *outputWriter) writeStep(msg llm.LLMMessage) error {
+	if w == nil || msg.Type != llm.LLMMessageTypeAssistant || msg.End || msg.Content == "" {
+		return nil
+	}
+
+	switch w.format {
+	case OutputFormatJSON:
+		return w.writeEvent(outputEvent{Type: "step", Content: msg.Content})
+	case OutputFormatMarkdown:
+		return w.write(msg.Content + "\n\n")
+	default:
+		return w.write(msg.Content + "\n")
+	}
+}
+
+// writeResult writes the structured output of the run
+func (w *outputWriter) writeResult(data any) error {
+	if w == nil {
+		return nil
+	}
+
+	if w.format == OutputFormatJSON {
+		return w.writeEvent(outputEvent{Type: "result", Data: data})
+	}
+
+	dataJSON, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrOutputWrite, err)
+	}
+
+	if w.format == OutputFormatMarkdown {
+		return w.write("```json\n" + string(dataJSON) + "\n```\n")
+	}
+
+	return w.write(string(dataJSON) + "\n")
+}
+
+func (w *outputWriter) writeEvent(event outputEvent) error {
+	eventJSON, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrOutputWrite, err)
+	}
+
+	return w.write(string(eventJSON) + "\n")
+}
+
+func (w *outputWriter) write(text string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, err := io.WriteString(w.writer, text); err != nil {
+		return fmt.Errorf("%w: %w", ErrOutputWrite, err)
+	}
+
+	return nil
+}
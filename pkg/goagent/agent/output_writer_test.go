@@ -0,0 +1,153 @@
+package agent_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+var errWriterClosed = errors.New("writer closed")
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errWriterClosed
+}
+
+func runWithOutput(t *testing.T, writer io.Writer, format agent.OutputFormat) error {
+	t.Helper()
+
+	outputAgent, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("output_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithTool[AddNumbersResult]("add", createTestAddTool()),
+		agent.WithOutput[AddNumbersResult](writer, format),
+	)
+	require.NoError(t, err)
+
+	step := toolCallMessage(addCall("call_1"))
+	step.Content = "I will add the numbers."
+	mockLLM := llmtest.NewMockLLM(`{"sum": 3}`, step, endMessage("done"))
+
+	_, err = outputAgent.UsingLLM(mockLLM).Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+
+	return err
+}
+
+func TestWithOutput(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		format agent.OutputFormat
+		want   string
+	}{
+		{
+			name:   "markdown",
+			format: agent.OutputFormatMarkdown,
+			want:   "I will add the numbers.\n\n```json\n{\n  \"sum\": 3\n}\n```\n",
+		},
+		{
+			name:   "json",
+			format: agent.OutputFormatJSON,
+			want: `{"type":"step","content":"I will add the numbers."}` + "\n" +
+				`{"type":"result","data":{"sum":3}}` + "\n",
+		},
+		{
+			name:   "plain text",
+			format: agent.OutputFormatPlainText,
+			want:   "I will add the numbers.\n{\n  \"sum\": 3\n}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			var output bytes.Buffer
+
+			// when
+			err := runWithOutput(t, &output, tt.format)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, output.String())
+		})
+	}
+}
+
+func TestWithOutput_WriteError(t *testing.T) {
+	t.Parallel()
+
+	err := runWithOutput(t, failingWriter{}, agent.OutputFormatPlainText)
+
+	require.ErrorIs(t, err, agent.ErrOutputWrite)
+	require.ErrorIs(t, err, errWriterClosed)
+}
+
+func TestWithOutput_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		writer io.Writer
+		format agent.OutputFormat
+	}{
+		{name: "nil writer", writer: nil, format: agent.OutputFormatJSON},
+		{name: "unknown format", writer: io.Discard, format: "html"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := agent.NewAgent(
+				agent.WithName[AddNumbersResult]("output_agent"),
+				agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+				agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+				agent.WithOutput[AddNumbersResult](tt.writer, tt.format),
+			)
+
+			require.ErrorIs(t, err, validation.ErrValidationFailed)
+			assert.Contains(t, err.Error(), "output")
+		})
+	}
+}
+
+func TestWithOutput_SkipsMessagesWithoutContent(t *testing.T) {
+	t.Parallel()
+
+	// given
+	var output bytes.Buffer
+	outputAgent, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("output_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithTool[AddNumbersResult]("add", createTestAddTool()),
+		agent.WithOutput[AddNumbersResult](&output, agent.OutputFormatJSON),
+	)
+	require.NoError(t, err)
+
+	mockLLM := llmtest.NewMockLLM(`{"sum": 3}`,
+		toolCallMessage(addCall("call_1")),
+		llm.LLMMessage{Type: llm.LLMMessageTypeAssistant, Content: "The sum is 3.", End: true},
+	)
+
+	// when
+	_, err = outputAgent.UsingLLM(mockLLM).Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, `{"type":"result","data":{"sum":3}}`+"\n", output.String())
+}
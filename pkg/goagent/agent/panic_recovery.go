@@ -0,0 +1,66 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+// ErrToolPanic is returned when a tool panics and panic recovery is enabled
+var ErrToolPanic = errors.New("tool panicked")
+
+// ToolPanicError describes a recovered tool panic. It matches ErrToolPanic with errors.Is.
+type ToolPanicError struct {
+	ToolName string
+	Value    any
+	// Stack is the stack trace of the goroutine at the time of the panic
+	Stack []byte
+}
+
+func (e *ToolPanicError) Error() string {
+	return fmt.Sprintf("%s: %s: %v", ErrToolPanic, e.ToolName, e.Value)
+}
+
+func (e *ToolPanicError) Unwrap() error {
+	return ErrToolPanic
+}
+
+// WithPanicRecovery makes the agent recover from panics in tool functions. A recovered
+// panic is logged with its stack trace and reported to the LLM as a tool error, so the run
+// continues instead of crashing the process. Disabled by default.
+func WithPanicRecovery[T any](enabled bool) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.panicRecovery = enabled
+	}
+}
+
+// callToolFunc calls the tool function, or submits the call to its batch when the tool is
+// batched, converting a panic into a ToolPanicError when panic recovery is enabled
+func (a *Agent[T]) callToolFunc(tool llm.LLMTool, toolCall llm.LLMToolCall) (result llm.LLMToolResult, err error) {
+	if a.toolCallBatch != nil && tool.Batch != nil {
+		return a.callBatched(tool, toolCall)
+	}
+	if !a.panicRecovery {
+		return tool.Call(toolCall.ID, toolCall.Args)
+	}
+
+	defer func() {
+		if value := recover(); value != nil {
+			panicErr := &ToolPanicError{ToolName: toolCall.ToolName, Value: value, Stack: debug.Stack()}
+			slog.Error("recovered from tool panic",
+				"agent", a.name,
+				"tool", toolCall.ToolName,
+				"call_id", toolCall.ID,
+				"panic", fmt.Sprint(value),
+				"stack", string(panicErr.Stack),
+			)
+
+			result, err = nil, panicErr
+		}
+	}()
+
+	return tool.Call(toolCall.ID, toolCall.Args)
+}
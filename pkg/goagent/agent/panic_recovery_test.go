@@ -0,0 +1,89 @@
+package agent_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+func runPanickingToolAgent(t *testing.T, opts ...agent.AgentOption[AddNumbersResult]) llm.LLMToolResult {
+	t.Helper()
+
+	tool, err := llm.NewLLMTool(
+		llm.WithLLMToolName("add"),
+		llm.WithLLMToolDescription("Panicking add"),
+		llm.WithLLMToolParametersSchema[AddToolParams](),
+		llm.WithLLMToolCall(func(_ string, _ AddToolParams) (AddToolResult, error) {
+			panic("division by zero")
+		}),
+	)
+	require.NoError(t, err)
+
+	opts = append([]agent.AgentOption[AddNumbersResult]{
+		agent.WithName[AddNumbersResult]("panic_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You add numbers."),
+		agent.WithTool[AddNumbersResult]("add", tool),
+		agent.WithPanicRecovery[AddNumbersResult](true),
+	}, opts...)
+
+	panicAgent, err := agent.NewAgent(opts...)
+	require.NoError(t, err)
+
+	agent.SetLLM(panicAgent, llmtest.NewMockLLM(`{"sum": 3}`,
+		toolCallMessage(llm.LLMToolCall{ID: "call_1", ToolName: "add", Args: `{"num1": 1, "num2": 2}`}),
+		endMessage("done"),
+	))
+
+	result, err := panicAgent.Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+	require.NoError(t, err)
+
+	toolResults := result.Messages[2].ToolResults
+	require.Len(t, toolResults, 1)
+
+	return toolResults[0]
+}
+
+func TestWithPanicRecovery(t *testing.T) {
+	t.Parallel()
+
+	// when
+	toolResult := runPanickingToolAgent(t)
+
+	// then
+	errorResult, isOK := toolResult.(llm.ErrorLLMToolResult)
+	require.True(t, isOK)
+	assert.Equal(t, "call_1", errorResult.GetID())
+	assert.Contains(t, errorResult.Error, agent.ErrToolPanic.Error())
+	assert.Contains(t, errorResult.Error, "division by zero")
+}
+
+func TestWithPanicRecovery_WithToolTimeout(t *testing.T) {
+	t.Parallel()
+
+	// when
+	toolResult := runPanickingToolAgent(t, agent.WithToolTimeout[AddNumbersResult]("add", time.Second))
+
+	// then
+	errorResult, isOK := toolResult.(llm.ErrorLLMToolResult)
+	require.True(t, isOK)
+	assert.Contains(t, errorResult.Error, agent.ErrToolPanic.Error())
+	assert.Contains(t, errorResult.Error, "division by zero")
+}
+
+func TestToolPanicError(t *testing.T) {
+	t.Parallel()
+
+	// given
+	err := &agent.ToolPanicError{ToolName: "add", Value: "boom", Stack: []byte("stack")}
+
+	// then
+	require.ErrorIs(t, err, agent.ErrToolPanic)
+	assert.Equal(t, "tool panicked: add: boom", err.Error())
+}
@@ -0,0 +1,121 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+const defaultConcurrentToolLimit = 5
+
+// WithParallelToolCalls enables concurrent execution of the tool calls the LLM
+// requests in a single turn. Results keep the priority order set by WithToolPriority.
+// Tools must be safe for concurrent use when this option is enabled.
+func WithParallelToolCalls[T any](enabled bool) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.parallelToolCalls = enabled
+	}
+}
+
+// WithConcurrentToolLimit caps how many tool calls run at the same time when
+// parallel tool calls are enabled. Defaults to 5.
+func WithConcurrentToolLimit[T any](n int) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.concurrentToolLimit = n
+	}
+}
+
+type pendingToolCall struct {
+	index    int
+	toolCall llm.LLMToolCall
+	tool     llm.LLMTool
+}
+
+func (a *Agent[T]) callToolsParallel(
+	ctx context.Context,
+	state *AgentState,
+	toolCalls []llm.LLMToolCall,
+	usage map[string]int,
+	concurrency int,
+) ([]llm.LLMToolResult, error) {
+	results := make([]llm.LLMToolResult, len(toolCalls))
+	pending := make([]pendingToolCall, 0, len(toolCalls))
+	planned := make(map[string]int)
+
+	for i, toolCall := range toolCalls {
+		tool, ok := a.tools[toolCall.ToolName]
+		if !ok {
+			results[i] = a.createErrorToolResult(toolCall.ID, fmt.Errorf("%w: %s", ErrToolNotFound, toolCall.ToolName))
+
+			continue
+		}
+
+		if usage[toolCall.ToolName]+planned[toolCall.ToolName] >= a.getToolLimit(toolCall.ToolName) {
+			return nil, ErrLimitReached
+		}
+		if a.globalToolLimitReached(usage, len(pending)) {
+			return nil, ErrGlobalLimitReached
+		}
+		if err := a.checkToolDependencies(toolCall.ToolName, usage); err != nil {
+			results[i] = a.createErrorToolResult(toolCall.ID, err)
+
+			continue
+		}
+
+		planned[toolCall.ToolName]++
+		pending = append(pending, pendingToolCall{index: i, toolCall: toolCall, tool: tool})
+	}
+
+	succeeded := make([]bool, len(toolCalls))
+	records := make([]*ToolCallRecord, len(toolCalls))
+	rateLimitErrs := make([]error, len(toolCalls))
+	semaphore := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+	for _, call := range pending {
+		wg.Add(1)
+		semaphore <- struct{}{}
+
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			toolRes, record, err := a.runTool(ctx, state, call.tool, call.toolCall)
+			records[call.index] = &record
+			if errors.Is(err, ErrRateLimitExceeded) {
+				rateLimitErrs[call.index] = err
+
+				return
+			}
+			if err != nil {
+				results[call.index] = a.createErrorToolResult(call.toolCall.ID, fmt.Errorf("%w: %s", ErrToolError, err))
+
+				return
+			}
+
+			results[call.index] = toolRes
+			succeeded[call.index] = true
+		}()
+	}
+	wg.Wait()
+
+	for i, ok := range succeeded {
+		if ok {
+			usage[toolCalls[i].ToolName]++
+		}
+		if records[i] != nil {
+			state.recordToolCall(*records[i])
+		}
+	}
+
+	for _, err := range rateLimitErrs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
@@ -0,0 +1,125 @@
+package agent_test
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+func createConcurrencyTrackingTool(t *testing.T, running, maxRunning *int64) llm.LLMTool {
+	t.Helper()
+
+	tool, err := llm.NewLLMTool(
+		llm.WithLLMToolName("slow_add"),
+		llm.WithLLMToolDescription("Adds two numbers slowly"),
+		llm.WithLLMToolParametersSchema[AddToolParams](),
+		llm.WithLLMToolCall(func(callID string, params AddToolParams) (AddToolResult, error) {
+			current := atomic.AddInt64(running, 1)
+			defer atomic.AddInt64(running, -1)
+
+			for {
+				observed := atomic.LoadInt64(maxRunning)
+				if current <= observed || atomic.CompareAndSwapInt64(maxRunning, observed, current) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+
+			return AddToolResult{
+				BaseLLMToolResult: llm.BaseLLMToolResult{ID: callID},
+				Sum:               params.Num1 + params.Num2,
+			}, nil
+		}),
+	)
+	require.NoError(t, err)
+
+	return tool
+}
+
+func TestWithConcurrentToolLimit(t *testing.T) {
+	t.Parallel()
+
+	var running, maxRunning int64
+	const concurrencyLimit = 3
+
+	toolCalls := make([]llm.LLMToolCall, 0, 10)
+	for i := range 10 {
+		toolCalls = append(toolCalls, llm.LLMToolCall{
+			ID:       fmt.Sprintf("call_%d", i),
+			ToolName: "slow_add",
+			Args:     fmt.Sprintf(`{"num1": %d, "num2": 1}`, i),
+		})
+	}
+
+	parallelAgent, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("parallel_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithTool[AddNumbersResult]("slow_add", createConcurrencyTrackingTool(t, &running, &maxRunning)),
+		agent.WithToolLimit[AddNumbersResult]("slow_add", len(toolCalls)),
+		agent.WithParallelToolCalls[AddNumbersResult](true),
+		agent.WithConcurrentToolLimit[AddNumbersResult](concurrencyLimit),
+	)
+	require.NoError(t, err)
+
+	agent.SetLLM(parallelAgent, llmtest.NewMockLLM(`{"sum": 0}`,
+		toolCallMessage(toolCalls...),
+		endMessage("done"),
+	))
+
+	result, err := parallelAgent.Run(context.Background(), AddNumbers{})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(concurrencyLimit), atomic.LoadInt64(&maxRunning))
+
+	toolResults := result.Messages[2].ToolResults
+	require.Len(t, toolResults, len(toolCalls))
+	for i, toolResult := range toolResults {
+		assert.Equal(t, toolCalls[i].ID, toolResult.GetID(), "results must keep the tool call order")
+	}
+}
+
+func TestWithParallelToolCalls_LimitReached(t *testing.T) {
+	t.Parallel()
+
+	parallelAgent, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("parallel_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithTool[AddNumbersResult]("add", createTestAddTool()),
+		agent.WithToolLimit[AddNumbersResult]("add", 1),
+		agent.WithParallelToolCalls[AddNumbersResult](true),
+	)
+	require.NoError(t, err)
+
+	agent.SetLLM(parallelAgent, llmtest.NewMockLLM(`{"sum": 0}`, toolCallMessage(
+		llm.LLMToolCall{ID: "call_1", ToolName: "add", Args: `{}`},
+		llm.LLMToolCall{ID: "call_2", ToolName: "add", Args: `{}`},
+	)))
+
+	_, err = parallelAgent.Run(context.Background(), AddNumbers{})
+
+	require.ErrorIs(t, err, agent.ErrLimitReached)
+}
+
+func TestWithConcurrentToolLimit_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("parallel_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithConcurrentToolLimit[AddNumbersResult](0),
+	)
+
+	require.ErrorIs(t, err, validation.ErrValidationFailed)
+}
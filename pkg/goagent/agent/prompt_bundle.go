@@ -0,0 +1,131 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+)
+
+// DefaultLocale is the locale of the prompt used when a PromptBundle has no prompt for the
+// locale of a run
+const DefaultLocale = "en"
+
+const promptFileExt = ".tmpl"
+
+// PromptBundle holds the translations of a system prompt by locale code, e.g. "en" or "de-AT"
+type PromptBundle map[string]Prompt
+
+// prompt returns the prompt of the locale, of its language when the bundle has no prompt for
+// the region, e.g. "de" for "de-AT", or of DefaultLocale
+func (b PromptBundle) prompt(locale string) Prompt {
+	if prompt, ok := b[locale]; ok {
+		return prompt
+	}
+
+	if language, _, found := strings.Cut(locale, "-"); found {
+		if prompt, ok := b[language]; ok {
+			return prompt
+		}
+	}
+
+	return b[DefaultLocale]
+}
+
+func (b PromptBundle) validate() error {
+	if _, ok := b[DefaultLocale]; !ok {
+		return fmt.Errorf("%w: no prompt for the default locale %q", validation.ErrValidationFailed, DefaultLocale)
+	}
+
+	for _, locale := range slices.Sorted(maps.Keys(b)) {
+		if _, err := template.New("prompt").Parse(b[locale].Template); err != nil {
+			return fmt.Errorf("%w: locale %s: %w", ErrInvalidPromptTemplate, locale, err)
+		}
+	}
+
+	return nil
+}
+
+// NewPromptBundleFromDir loads the system prompt templates stored as <locale>.tmpl files in
+// dir, e.g. en.tmpl and de.tmpl. It returns ErrPromptFileNotFound when dir has no such files
+// and ErrInvalidPromptTemplate when a template cannot be parsed.
+func NewPromptBundleFromDir(dir string) (PromptBundle, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+promptFileExt))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list prompt files: %w", err)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("%w: no %s files in %s", ErrPromptFileNotFound, promptFileExt, dir)
+	}
+
+	bundle := make(PromptBundle, len(paths))
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		prompt, err := newPromptFromFile(path, content, err)
+		if err != nil {
+			return nil, err
+		}
+
+		bundle[strings.TrimSuffix(filepath.Base(path), promptFileExt)] = prompt
+	}
+
+	return bundle, nil
+}
+
+// MustNewPromptBundleFromDir is like NewPromptBundleFromDir but panics when the prompts
+// cannot be loaded. It simplifies loading the prompts in package level variables.
+func MustNewPromptBundleFromDir(dir string) PromptBundle {
+	bundle, err := NewPromptBundleFromDir(dir)
+	if err != nil {
+		panic(err)
+	}
+
+	return bundle
+}
+
+// WithLocalizedSystemPrompt selects the system prompt template from bundle by the locale
+// localeFn returns for the context of every run, e.g. a locale set by an HTTP middleware
+// from the Accept-Language header. When the bundle has no prompt for the locale, the prompt
+// of its language or of DefaultLocale is used. The bundle must contain DefaultLocale.
+// It takes precedence over WithSystemPrompt.
+//
+// Example:
+//
+//	agent.WithLocalizedSystemPrompt[Answer](agent.MustNewPromptBundleFromDir("prompts"),
+//		func(ctx context.Context) string {
+//			locale, _ := ctx.Value(localeKey{}).(string)
+//			return locale
+//		},
+//	)
+func WithLocalizedSystemPrompt[T any](bundle PromptBundle, localeFn func(ctx context.Context) string) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.promptBundle = maps.Clone(bundle)
+		a.localeFn = localeFn
+	}
+}
+
+func (a *Agent[T]) validatePromptBundle() error {
+	if a.promptBundle == nil && a.localeFn == nil {
+		return nil
+	}
+	if a.localeFn == nil {
+		return fmt.Errorf("locale fn: %w: value cannot be nil", validation.ErrValidationFailed)
+	}
+
+	return a.promptBundle.validate()
+}
+
+// systemPromptFor returns the system prompt template for the run of ctx
+func (a *Agent[T]) systemPromptFor(ctx context.Context) Prompt {
+	if a.promptBundle == nil {
+		return a.systemPrompt
+	}
+
+	return a.promptBundle.prompt(a.localeFn(ctx))
+}
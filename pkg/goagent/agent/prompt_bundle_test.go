@@ -0,0 +1,182 @@
+package agent_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+type localeKey struct{}
+
+func localeFromContext(ctx context.Context) string {
+	locale, _ := ctx.Value(localeKey{}).(string)
+
+	return locale
+}
+
+func writePromptFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+
+	return dir
+}
+
+func TestWithLocalizedSystemPrompt(t *testing.T) {
+	t.Parallel()
+
+	bundle := agent.PromptBundle{
+		"en":    agent.NewPrompt("Answer in English. {{.behavior}}"),
+		"de":    agent.NewPrompt("Antworte auf Deutsch. {{.behavior}}"),
+		"pt-BR": agent.NewPrompt("Responda em português do Brasil. {{.behavior}}"),
+	}
+
+	tests := []struct {
+		name   string
+		locale string
+		want   string
+	}{
+		{name: "exact locale", locale: "pt-BR", want: "Responda em português do Brasil. You are a calculator."},
+		{name: "language of locale", locale: "de-AT", want: "Antworte auf Deutsch. You are a calculator."},
+		{name: "default locale", locale: "fr", want: "Answer in English. You are a calculator."},
+		{name: "no locale", locale: "", want: "Answer in English. You are a calculator."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			localizedAgent, err := createPromptFileAgent(
+				agent.WithLocalizedSystemPrompt[AddNumbersResult](bundle, localeFromContext),
+			)
+			require.NoError(t, err)
+
+			mockLLM := llmtest.NewMockLLM(`{"sum": 3}`, endMessage("done"))
+			ctx := context.WithValue(context.Background(), localeKey{}, tt.locale)
+
+			// when
+			_, err = localizedAgent.UsingLLM(mockLLM).Run(ctx, AddNumbers{Num1: 1, Num2: 2})
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, mockLLM.Calls()[0][0].Content)
+		})
+	}
+}
+
+func TestWithLocalizedSystemPrompt_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		bundle   agent.PromptBundle
+		localeFn func(ctx context.Context) string
+		wantErr  error
+	}{
+		{
+			name:     "no default locale",
+			bundle:   agent.PromptBundle{"de": agent.NewPrompt("{{.behavior}}")},
+			localeFn: localeFromContext,
+			wantErr:  validation.ErrValidationFailed,
+		},
+		{
+			name:     "nil locale fn",
+			bundle:   agent.PromptBundle{"en": agent.NewPrompt("{{.behavior}}")},
+			localeFn: nil,
+			wantErr:  validation.ErrValidationFailed,
+		},
+		{
+			name:     "invalid template",
+			bundle:   agent.PromptBundle{"en": agent.NewPrompt("{{.behavior")},
+			localeFn: localeFromContext,
+			wantErr:  agent.ErrInvalidPromptTemplate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := createPromptFileAgent(agent.WithLocalizedSystemPrompt[AddNumbersResult](tt.bundle, tt.localeFn))
+
+			require.ErrorIs(t, err, tt.wantErr)
+			assert.Contains(t, err.Error(), "localized system prompt")
+		})
+	}
+}
+
+func TestNewPromptBundleFromDir(t *testing.T) {
+	t.Parallel()
+
+	// given
+	dir := writePromptFiles(t, map[string]string{
+		"en.tmpl":   "Answer in English. {{.behavior}}",
+		"de.tmpl":   "Antworte auf Deutsch. {{.behavior}}",
+		"notes.txt": "not a prompt",
+	})
+
+	// when
+	bundle, err := agent.NewPromptBundleFromDir(dir)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, agent.PromptBundle{
+		"en": agent.NewPrompt("Answer in English. {{.behavior}}"),
+		"de": agent.NewPrompt("Antworte auf Deutsch. {{.behavior}}"),
+	}, bundle)
+}
+
+func TestNewPromptBundleFromDir_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr error
+	}{
+		{
+			name:    "no prompt files",
+			files:   map[string]string{"notes.txt": "not a prompt"},
+			wantErr: agent.ErrPromptFileNotFound,
+		},
+		{
+			name:    "invalid template",
+			files:   map[string]string{"en.tmpl": "{{.behavior"},
+			wantErr: agent.ErrInvalidPromptTemplate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := agent.NewPromptBundleFromDir(writePromptFiles(t, tt.files))
+
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestMustNewPromptBundleFromDir(t *testing.T) {
+	t.Parallel()
+
+	dir := writePromptFiles(t, map[string]string{"en.tmpl": "{{.behavior}}"})
+
+	assert.NotPanics(t, func() {
+		assert.Len(t, agent.MustNewPromptBundleFromDir(dir), 1)
+	})
+	assert.Panics(t, func() {
+		agent.MustNewPromptBundleFromDir(t.TempDir())
+	})
+}
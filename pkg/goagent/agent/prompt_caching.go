@@ -0,0 +1,26 @@
+package agent
+
+import "github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+
+// WithPromptCaching marks the system message with llm.CacheControlEphemeral, so providers
+// with explicit prompt caching, such as Anthropic, cache it between LLM calls. Cached input
+// tokens are billed at a small fraction of the normal input price, so it pays off for agents
+// with long, stable behaviors which run often. Prompts shorter than the provider minimum are
+// not cached. The system prompt lists the tool usage of the run, so the cache is only hit
+// before the first tool call of each run.
+//
+// OpenAI caches long prompts automatically, so the option has no effect on the OpenAI LLMs.
+func WithPromptCaching[T any](enabled bool) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.promptCaching = enabled
+	}
+}
+
+func (a *Agent[T]) newSystemMessage(systemPrompt string) llm.LLMMessage {
+	msg := llm.NewLLMMessage(llm.LLMMessageTypeSystem, systemPrompt)
+	if a.promptCaching {
+		msg.CacheControl = llm.CacheControlEphemeral
+	}
+
+	return msg
+}
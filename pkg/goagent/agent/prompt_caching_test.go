@@ -0,0 +1,91 @@
+package agent_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+func TestWithPromptCaching(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		enabled      bool
+		cacheControl llm.CacheControl
+	}{
+		{name: "enabled", enabled: true, cacheControl: llm.CacheControlEphemeral},
+		{name: "disabled", enabled: false, cacheControl: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			cachingAgent, err := agent.NewAgent(
+				agent.WithName[AddNumbersResult]("caching_agent"),
+				agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+				agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+				agent.WithTool[AddNumbersResult]("add", createTestAddTool()),
+				agent.WithPromptCaching[AddNumbersResult](tt.enabled),
+			)
+			require.NoError(t, err)
+
+			mockLLM := llmtest.NewMockLLM(`{"sum": 3}`,
+				toolCallMessage(addCall("call_1")),
+				endMessage("done"),
+			)
+
+			// when
+			_, err = cachingAgent.UsingLLM(mockLLM).Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+
+			// then
+			require.NoError(t, err)
+			for _, call := range mockLLM.Calls() {
+				assert.Equal(t, llm.LLMMessageTypeSystem, call[0].Type)
+				assert.Equal(t, tt.cacheControl, call[0].CacheControl)
+				for _, msg := range call[1:] {
+					assert.Empty(t, msg.CacheControl)
+				}
+			}
+		})
+	}
+}
+
+func TestWithPromptCaching_OpenAI(t *testing.T) {
+	t.Parallel()
+
+	// given
+	server := &tenantServer{}
+	llmConfig := testLLMConfig()
+	llmConfig.HTTPClient = newTenantClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.NotContains(t, string(body), "cache_control")
+
+		server.ServeHTTP(w, r)
+	}))
+
+	cachingAgent, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("caching_agent"),
+		agent.WithLLMConfig[AddNumbersResult](llmConfig),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithPromptCaching[AddNumbersResult](true),
+	)
+	require.NoError(t, err)
+
+	// when
+	result, err := cachingAgent.Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+
+	// then
+	require.NoError(t, err)
+	assert.InDelta(t, 3, result.Data.Sum, 0)
+}
@@ -0,0 +1,61 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"text/template"
+)
+
+var (
+	// ErrPromptFileNotFound is returned by NewAgent when the system prompt file does not exist
+	ErrPromptFileNotFound = errors.New("prompt file not found")
+	// ErrInvalidPromptTemplate is returned by NewAgent when the system prompt file is not a valid template
+	ErrInvalidPromptTemplate = errors.New("invalid prompt template")
+)
+
+// WithSystemPromptFromFile sets the system prompt template from a file, which is read when the
+// agent is created. The file uses Go template syntax like NewPrompt, see WithSystemPrompt.
+// NewAgent fails with ErrPromptFileNotFound when the file does not exist and with
+// ErrInvalidPromptTemplate when the template cannot be parsed.
+func WithSystemPromptFromFile[T any](path string) AgentOption[T] {
+	return func(a *Agent[T]) {
+		content, err := os.ReadFile(path)
+		a.systemPrompt, a.systemPromptErr = newPromptFromFile(path, content, err)
+	}
+}
+
+// WithSystemPromptFromFS is like WithSystemPromptFromFile but reads the file from fsys,
+// e.g. an embed.FS which ships the prompts inside the binary.
+//
+// Example:
+//
+//	//go:embed prompts
+//	var prompts embed.FS
+//
+//	agent.WithSystemPromptFromFS[Result](prompts, "prompts/support.tmpl")
+func WithSystemPromptFromFS[T any](fsys fs.FS, path string) AgentOption[T] {
+	return func(a *Agent[T]) {
+		content, err := fs.ReadFile(fsys, path)
+		a.systemPrompt, a.systemPromptErr = newPromptFromFile(path, content, err)
+	}
+}
+
+// newPromptFromFile creates the prompt from the content of the file at path, or returns
+// the error of reading the file
+func newPromptFromFile(path string, content []byte, err error) (Prompt, error) {
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return Prompt{}, fmt.Errorf("%w: %w", ErrPromptFileNotFound, err)
+		}
+
+		return Prompt{}, fmt.Errorf("failed to read prompt file: %w", err)
+	}
+
+	if _, err := template.New("prompt").Parse(string(content)); err != nil {
+		return Prompt{}, fmt.Errorf("%w: %s: %w", ErrInvalidPromptTemplate, path, err)
+	}
+
+	return NewPrompt(string(content)), nil
+}
@@ -0,0 +1,119 @@
+package agent_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+const filePromptTemplate = "You follow the rules.\n\nBEHAVIOR: {{.behavior}}"
+
+func createPromptFileAgent(option agent.AgentOption[AddNumbersResult]) (*agent.Agent[AddNumbersResult], error) {
+	return agent.NewAgent(
+		agent.WithName[AddNumbersResult]("prompt_file_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		option,
+	)
+}
+
+func firstSystemPrompt(t *testing.T, promptAgent *agent.Agent[AddNumbersResult]) string {
+	t.Helper()
+
+	mockLLM := llmtest.NewMockLLM(`{"sum": 3}`, endMessage("done"))
+	_, err := promptAgent.UsingLLM(mockLLM).Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+	require.NoError(t, err)
+
+	return mockLLM.Calls()[0][0].Content
+}
+
+func TestWithSystemPromptFromFile(t *testing.T) {
+	t.Parallel()
+
+	// given
+	path := filepath.Join(t.TempDir(), "system.tmpl")
+	require.NoError(t, os.WriteFile(path, []byte(filePromptTemplate), 0o600))
+
+	// when
+	promptAgent, err := createPromptFileAgent(agent.WithSystemPromptFromFile[AddNumbersResult](path))
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "You follow the rules.\n\nBEHAVIOR: You are a calculator.", firstSystemPrompt(t, promptAgent))
+}
+
+func TestWithSystemPromptFromFS(t *testing.T) {
+	t.Parallel()
+
+	// given
+	prompts := fstest.MapFS{"prompts/system.tmpl": {Data: []byte(filePromptTemplate)}}
+
+	// when
+	promptAgent, err := createPromptFileAgent(
+		agent.WithSystemPromptFromFS[AddNumbersResult](prompts, "prompts/system.tmpl"),
+	)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "You follow the rules.\n\nBEHAVIOR: You are a calculator.", firstSystemPrompt(t, promptAgent))
+}
+
+func TestWithSystemPromptFromFile_Errors(t *testing.T) {
+	t.Parallel()
+
+	prompts := fstest.MapFS{"invalid.tmpl": {Data: []byte("BEHAVIOR: {{.behavior")}}
+
+	tests := []struct {
+		name    string
+		option  agent.AgentOption[AddNumbersResult]
+		wantErr error
+	}{
+		{
+			name:    "missing file",
+			option:  agent.WithSystemPromptFromFile[AddNumbersResult](filepath.Join(t.TempDir(), "missing.tmpl")),
+			wantErr: agent.ErrPromptFileNotFound,
+		},
+		{
+			name:    "missing file in fs",
+			option:  agent.WithSystemPromptFromFS[AddNumbersResult](prompts, "missing.tmpl"),
+			wantErr: agent.ErrPromptFileNotFound,
+		},
+		{
+			name:    "invalid template",
+			option:  agent.WithSystemPromptFromFS[AddNumbersResult](prompts, "invalid.tmpl"),
+			wantErr: agent.ErrInvalidPromptTemplate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := createPromptFileAgent(tt.option)
+
+			require.ErrorIs(t, err, tt.wantErr)
+			assert.Contains(t, err.Error(), "system prompt")
+		})
+	}
+}
+
+func TestWithSystemPromptFromFile_OverriddenBySystemPrompt(t *testing.T) {
+	t.Parallel()
+
+	_, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("prompt_file_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithSystemPromptFromFS[AddNumbersResult](fstest.MapFS{}, "missing.tmpl"),
+		agent.WithSystemPrompt[AddNumbersResult](agent.NewPrompt("{{.behavior}}")),
+	)
+
+	require.NoError(t, err)
+}
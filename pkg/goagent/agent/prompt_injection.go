@@ -0,0 +1,157 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+// ErrPromptInjectionDetected is returned when user input looks like an attempt to override
+// the agent instructions
+var ErrPromptInjectionDetected = errors.New("prompt injection detected")
+
+// injectionSignal is a phrase typical for prompt injections together with the confidence
+// that a message containing it is an injection attempt
+type injectionSignal struct {
+	pattern    *regexp.Regexp
+	confidence float64
+}
+
+var promptInjectionSignals = []injectionSignal{
+	{
+		pattern: regexp.MustCompile(
+			`(?i)\b(ignore|disregard|forget|skip)\b.{0,20}\b(previous|prior|above|earlier|preceding|all|your|the)\b` +
+				`.{0,20}\b(instructions?|prompts?|rules|directions|guidelines|context)\b`),
+		confidence: 0.9,
+	},
+	{
+		pattern: regexp.MustCompile(
+			`(?i)\b(override|bypass|circumvent)\b.{0,20}` +
+				`\b(instructions?|rules|restrictions|safety|guardrails|filters?)\b`),
+		confidence: 0.8,
+	},
+	{
+		pattern: regexp.MustCompile(
+			`(?i)\b(reveal|show|print|repeat|output|leak)\b.{0,20}` +
+				`\b(system prompt|initial instructions|hidden instructions)\b`),
+		confidence: 0.8,
+	},
+	{
+		pattern: regexp.MustCompile(
+			`(?i)(<\|im_start\|>|<\|system\|>|\[system\]|^\s*system\s*:|\bnew instructions\s*:)`),
+		confidence: 0.7,
+	},
+	{
+		pattern:    regexp.MustCompile(`(?i)\b(jailbreak|developer mode|DAN mode|do anything now)\b`),
+		confidence: 0.6,
+	},
+	{
+		pattern:    regexp.MustCompile(`(?i)\b(you are now|from now on you are|pretend (to be|you are))\b`),
+		confidence: 0.4,
+	},
+}
+
+type promptInjectionDetector struct {
+	sensitivity float64
+}
+
+// NewPromptInjectionDetector creates a message interceptor that scans every user message added
+// to a run, including the input history, for instruction override attempts such as "Ignore
+// previous instructions". Each message gets a confidence score between 0 and 1 from a keyword
+// heuristic: several suspicious phrases in one message raise the score. When the score exceeds
+// sensitivity, a warning is logged and the run fails with ErrPromptInjectionDetected before
+// the message is sent to the LLM.
+//
+// Sensitivity must be between 0 and 1. Lower values block more messages: 0.5 blocks explicit
+// override phrases, while role-play requests like "pretend you are" alone pass.
+//
+// Example:
+//
+//	detector, err := agent.NewPromptInjectionDetector(0.5)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	myAgent, err := agent.NewAgent(
+//		// ... other options
+//		agent.WithMessageInterceptor[MyResult](detector),
+//	)
+func NewPromptInjectionDetector(sensitivity float64) (MessageInterceptor, error) {
+	detector, err := newPromptInjectionDetector(sensitivity)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, msg llm.LLMMessage) (llm.LLMMessage, error) {
+		if msg.Type != llm.LLMMessageTypeUser {
+			return msg, nil
+		}
+
+		return msg, detector.check(ctx, msg)
+	}, nil
+}
+
+// WithPromptInjectionProtection checks the input of every run with a prompt injection
+// detector with the given sensitivity, see NewPromptInjectionDetector. Only the input is
+// checked, once, before the first LLM call.
+func WithPromptInjectionProtection[T any](sensitivity float64) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.promptInjection, a.promptInjectionErr = newPromptInjectionDetector(sensitivity)
+	}
+}
+
+func newPromptInjectionDetector(sensitivity float64) (*promptInjectionDetector, error) {
+	if sensitivity < 0 || sensitivity > 1 {
+		return nil, fmt.Errorf("%w: sensitivity must be between 0 and 1, got %v",
+			validation.ErrValidationFailed, sensitivity)
+	}
+
+	return &promptInjectionDetector{sensitivity: sensitivity}, nil
+}
+
+// check returns ErrPromptInjectionDetected when the message looks like a prompt injection
+func (d *promptInjectionDetector) check(ctx context.Context, msg llm.LLMMessage) error {
+	if d == nil {
+		return nil
+	}
+
+	confidence := promptInjectionConfidence(msg)
+	if confidence <= d.sensitivity {
+		return nil
+	}
+
+	slog.WarnContext(ctx, "prompt injection detected in user message",
+		"confidence", confidence, "sensitivity", d.sensitivity)
+
+	return fmt.Errorf("%w: confidence %.2f exceeds sensitivity %.2f",
+		ErrPromptInjectionDetected, confidence, d.sensitivity)
+}
+
+// promptInjectionConfidence combines the confidences of all matched signals as independent
+// evidence, so that the score grows with every additional suspicious phrase
+func promptInjectionConfidence(msg llm.LLMMessage) float64 {
+	texts := []string{msg.Content}
+	for _, part := range msg.Parts {
+		if part.Text != nil {
+			texts = append(texts, part.Text.Text)
+		}
+	}
+
+	notInjection := 1.0
+	for _, signal := range promptInjectionSignals {
+		for _, text := range texts {
+			if signal.pattern.MatchString(text) {
+				notInjection *= 1 - signal.confidence
+
+				break
+			}
+		}
+	}
+
+	return 1 - notInjection
+}
@@ -0,0 +1,84 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/vectorstore"
+)
+
+var (
+	// ErrInvalidTopK is returned when a RAG tool is created with a non-positive topK
+	ErrInvalidTopK = errors.New("topK must be positive")
+	// ErrNoEmbedding is returned when the embedder returns no vector for the query
+	ErrNoEmbedding = errors.New("embedder returned no embedding")
+)
+
+// RAGToolParams are the parameters the LLM passes to a RAG tool
+type RAGToolParams struct {
+	Query string `json:"query" jsonschema_description:"Search query to find relevant documents"`
+}
+
+// RAGToolResult contains the documents most relevant to the query
+type RAGToolResult struct {
+	llm.BaseLLMToolResult
+
+	Documents []vectorstore.SearchResult `json:"documents" jsonschema_description:"Relevant documents with scores"`
+}
+
+// NewRAGTool creates a ready-made retrieval tool that embeds the LLM's query with embedder
+// and returns the topK most similar documents from store.
+//
+// Example:
+//
+//	store := vectorstore.NewInMemoryVectorStore()
+//	embedder := openai.NewOpenAIEmbedder(apiKey)
+//
+//	ragTool, err := agent.NewRAGTool("search_docs", "Searches product documentation", store, embedder, 3)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	myAgent, err := agent.NewAgent(
+//		// ... other options
+//		agent.WithTool[MyResult]("search_docs", ragTool),
+//	)
+func NewRAGTool(
+	name, description string,
+	store vectorstore.VectorStore,
+	embedder llm.Embedder,
+	topK int,
+) (llm.LLMTool, error) {
+	if topK <= 0 {
+		return llm.LLMTool{}, fmt.Errorf("failed to create RAG tool: %w: %d", ErrInvalidTopK, topK)
+	}
+
+	return llm.NewLLMTool(
+		llm.WithLLMToolName(name),
+		llm.WithLLMToolDescription(description),
+		llm.WithLLMToolParametersSchema[RAGToolParams](),
+		llm.WithLLMToolCall(func(callID string, params RAGToolParams) (RAGToolResult, error) {
+			ctx := context.Background()
+
+			embeddings, err := embedder.Embed(ctx, []string{params.Query})
+			if err != nil {
+				return RAGToolResult{}, fmt.Errorf("failed to embed query: %w", err)
+			}
+			if len(embeddings) == 0 {
+				return RAGToolResult{}, ErrNoEmbedding
+			}
+
+			documents, err := store.Search(ctx, embeddings[0], topK)
+			if err != nil {
+				return RAGToolResult{}, fmt.Errorf("failed to search documents: %w", err)
+			}
+
+			return RAGToolResult{
+				BaseLLMToolResult: llm.BaseLLMToolResult{ID: callID},
+				Documents:         documents,
+			}, nil
+		}),
+	)
+}
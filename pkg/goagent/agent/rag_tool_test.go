@@ -0,0 +1,63 @@
+package agent_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/vectorstore"
+)
+
+// keywordEmbedder embeds texts as a one-hot vector over a fixed vocabulary
+type keywordEmbedder struct {
+	vocabulary []string
+}
+
+func (e keywordEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	embeddings := make([][]float32, 0, len(texts))
+	for _, text := range texts {
+		vector := make([]float32, len(e.vocabulary))
+		for i, word := range e.vocabulary {
+			if text == word {
+				vector[i] = 1
+			}
+		}
+		embeddings = append(embeddings, vector)
+	}
+
+	return embeddings, nil
+}
+
+func TestNewRAGTool(t *testing.T) {
+	t.Parallel()
+
+	store := vectorstore.NewInMemoryVectorStore()
+	require.NoError(t, store.Add(context.Background(),
+		vectorstore.Document{ID: "1", Content: "Go is a programming language", Embedding: []float32{1, 0}},
+		vectorstore.Document{ID: "2", Content: "Paris is in France", Embedding: []float32{0, 1}},
+	))
+	embedder := keywordEmbedder{vocabulary: []string{"golang", "geography"}}
+
+	ragTool, err := agent.NewRAGTool("search_docs", "Searches documents", store, embedder, 1)
+	require.NoError(t, err)
+
+	result, err := ragTool.Call("call_1", `{"query": "geography"}`)
+
+	require.NoError(t, err)
+	ragResult, isOK := result.(agent.RAGToolResult)
+	require.True(t, isOK)
+	assert.Equal(t, "call_1", ragResult.GetID())
+	require.Len(t, ragResult.Documents, 1)
+	assert.Equal(t, "Paris is in France", ragResult.Documents[0].Document.Content)
+}
+
+func TestNewRAGTool_InvalidTopK(t *testing.T) {
+	t.Parallel()
+
+	_, err := agent.NewRAGTool("search_docs", "Searches documents",
+		vectorstore.NewInMemoryVectorStore(), keywordEmbedder{}, 0)
+
+	require.ErrorIs(t, err, agent.ErrInvalidTopK)
+}
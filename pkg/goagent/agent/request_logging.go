@@ -0,0 +1,24 @@
+package agent
+
+import (
+	"log/slog"
+
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+// WithRequestResponseLogging logs the messages the agent sends to the LLM and the raw
+// responses at debug level, see llm.NewLoggingLLM. A nil logger logs to slog.Default().
+func WithRequestResponseLogging[T any](logger *slog.Logger) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.requestLogging = true
+		a.requestLogger = logger
+	}
+}
+
+func (a *Agent[T]) withRequestLogging(agentLLM llm.LLM) llm.LLM {
+	if !a.requestLogging {
+		return agentLLM
+	}
+
+	return llm.NewLoggingLLM(agentLLM, a.requestLogger, slog.LevelDebug)
+}
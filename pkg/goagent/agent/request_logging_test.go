@@ -0,0 +1,43 @@
+package agent_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+func TestWithRequestResponseLogging(t *testing.T) {
+	t.Parallel()
+
+	// given
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	loggingAgent, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("logging_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithRequestResponseLogging[AddNumbersResult](logger),
+	)
+	require.NoError(t, err)
+
+	mockLLM := llmtest.NewMockLLM(`{"sum": 3}`, endMessage("done"))
+
+	// when
+	result, err := loggingAgent.UsingLLM(mockLLM).Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+
+	// then
+	require.NoError(t, err)
+	assert.InDelta(t, 3, result.Data.Sum, 0)
+
+	logs := buf.String()
+	assert.Contains(t, logs, `msg="LLM request" method=Call`)
+	assert.Contains(t, logs, `\"content\":\"done\"`)
+	assert.Contains(t, logs, `msg="LLM response" method=CallWithStructuredOutput`)
+	assert.Contains(t, logs, `response="{\"sum\": 3}"`)
+}
@@ -0,0 +1,152 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+// defaultMaxRetryDelay caps the backoff of RunWithRetry unless WithMaxRetryDelay is set
+const defaultMaxRetryDelay = 30 * time.Second
+
+type retryBackoff struct {
+	baseDelay time.Duration
+	maxDelay  time.Duration
+	jitter    float64
+}
+
+// WithRetryPromptSuffix sets a note that RunWithRetry appends to the agent behavior
+// on every retry, e.g. to remind the model of the output format or tool limits
+func WithRetryPromptSuffix[T any](suffix string) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.retrySuffix = suffix
+	}
+}
+
+// WithRetryBackoff makes RunWithRetry wait before every retry, starting with baseDelay and
+// doubling the delay on every further retry, e.g. 1s, 2s, 4s. The delay is capped by
+// WithMaxRetryDelay. Without this option retries start immediately.
+func WithRetryBackoff[T any](baseDelay time.Duration) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.retryBackoff.baseDelay = baseDelay
+	}
+}
+
+// WithMaxRetryDelay caps the delay between the retries of RunWithRetry, however many retries
+// were made. Defaults to 30 seconds.
+func WithMaxRetryDelay[T any](d time.Duration) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.retryBackoff.maxDelay = d
+	}
+}
+
+// WithRetryJitter randomizes every retry delay by up to fraction of it in either direction,
+// e.g. 0.2 turns a 10s delay into 8s to 12s, so agents which failed together don't retry
+// together. The jittered delay is still capped by WithMaxRetryDelay. fraction must be
+// between 0 and 1.
+func WithRetryJitter[T any](fraction float64) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.retryBackoff.jitter = fraction
+	}
+}
+
+func (b retryBackoff) validate() error {
+	if b.baseDelay < 0 {
+		return fmt.Errorf("%w: base delay must not be negative, got %v", validation.ErrValidationFailed, b.baseDelay)
+	}
+	if b.maxDelay <= 0 {
+		return fmt.Errorf("%w: max delay must be positive, got %v", validation.ErrValidationFailed, b.maxDelay)
+	}
+	if b.jitter < 0 || b.jitter > 1 {
+		return fmt.Errorf("%w: jitter %v must be between 0 and 1", validation.ErrValidationFailed, b.jitter)
+	}
+
+	return nil
+}
+
+// delay returns how long to wait before the retry with the given number, starting with 1
+func (b retryBackoff) delay(attempt int) time.Duration {
+	if b.baseDelay <= 0 {
+		return 0
+	}
+
+	delay := b.maxDelay
+	if shift := attempt - 1; shift < 63 && b.baseDelay <= b.maxDelay>>shift {
+		delay = b.baseDelay << shift
+	}
+
+	if b.jitter > 0 {
+		spread := (rand.Float64()*2 - 1) * b.jitter //nolint:gosec // jitter needs no crypto
+		delay += time.Duration(spread * float64(delay))
+	}
+
+	return min(max(delay, 0), b.maxDelay)
+}
+
+// RunWithRetry runs the agent and retries the whole run, with a fresh state, when the
+// structured output cannot be produced or a tool limit is reached. It makes at most
+// maxRetries retries and returns the last error when all of them fail. Other errors
+// are returned immediately. With WithToolCallIdempotencyKey, successful tool results are
+// shared between the attempts. See WithRetryBackoff for waiting between the attempts.
+func (a *Agent[T]) RunWithRetry(ctx context.Context, input any, maxRetries int) (*AgentResult[T], error) {
+	if a.idempotencyKey != nil {
+		ctx = withIdempotentResults(ctx, newIdempotentResults())
+	}
+
+	result, err := a.Run(ctx, input)
+
+	for attempt := 1; attempt <= maxRetries && isRetryableRunError(err); attempt++ {
+		if !sleepContext(ctx, a.retryBackoff.delay(attempt)) {
+			break
+		}
+
+		slog.WarnContext(ctx, "retrying agent run",
+			"agent", a.name,
+			"attempt", attempt,
+			"max_retries", maxRetries,
+			"error", err,
+		)
+
+		result, err = a.retryAgent().Run(ctx, input)
+	}
+
+	return result, err
+}
+
+func (a *Agent[T]) retryAgent() *Agent[T] {
+	if a.retrySuffix == "" {
+		return a
+	}
+
+	clone := *a
+	clone.behavior = a.behavior + "\n\n" + a.retrySuffix
+
+	return &clone
+}
+
+func isRetryableRunError(err error) bool {
+	return errors.Is(err, llm.ErrStructuredOutput) || errors.Is(err, ErrLimitReached)
+}
+
+// sleepContext waits for d and reports false when ctx is done before
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
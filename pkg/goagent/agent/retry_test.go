@@ -0,0 +1,224 @@
+package agent_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+func createRetryAgent(t *testing.T, options ...agent.AgentOption[AddNumbersResult]) *agent.Agent[AddNumbersResult] {
+	t.Helper()
+
+	retryAgent, err := newRetryAgent(options...)
+	require.NoError(t, err)
+
+	return retryAgent
+}
+
+func newRetryAgent(options ...agent.AgentOption[AddNumbersResult]) (*agent.Agent[AddNumbersResult], error) {
+	return agent.NewAgent(append([]agent.AgentOption[AddNumbersResult]{
+		agent.WithName[AddNumbersResult]("retry_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithTool[AddNumbersResult]("add", createTestAddTool()),
+		agent.WithToolLimit[AddNumbersResult]("add", 1),
+		agent.WithRetryPromptSuffix[AddNumbersResult]("Call the add tool at most once."),
+	}, options...)...)
+}
+
+func overLimitMessage() llm.LLMMessage {
+	return toolCallMessage(
+		llm.LLMToolCall{ID: "call_1", ToolName: "add", Args: `{"num1": 1, "num2": 2}`},
+		llm.LLMToolCall{ID: "call_2", ToolName: "add", Args: `{"num1": 1, "num2": 2}`},
+	)
+}
+
+func TestRunWithRetry(t *testing.T) {
+	t.Parallel()
+
+	retryAgent := createRetryAgent(t)
+	mockLLM := llmtest.NewMockLLM(`{"sum": 3}`, overLimitMessage(), endMessage("done"))
+	agent.SetLLM(retryAgent, mockLLM)
+
+	result, err := retryAgent.RunWithRetry(context.Background(), AddNumbers{Num1: 1, Num2: 2}, 2)
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Data.Sum)
+
+	calls := mockLLM.Calls()
+	require.Len(t, calls, 3)
+	assert.NotContains(t, calls[0][0].Content, "Call the add tool at most once.")
+	assert.Contains(t, calls[1][0].Content, "Call the add tool at most once.")
+}
+
+func TestRunWithRetry_Exhausted(t *testing.T) {
+	t.Parallel()
+
+	retryAgent := createRetryAgent(t)
+	mockLLM := llmtest.NewMockLLM(`{"sum": 3}`, overLimitMessage(), overLimitMessage())
+	agent.SetLLM(retryAgent, mockLLM)
+
+	_, err := retryAgent.RunWithRetry(context.Background(), AddNumbers{Num1: 1, Num2: 2}, 1)
+
+	require.ErrorIs(t, err, agent.ErrLimitReached)
+	assert.Len(t, mockLLM.Calls(), 2)
+}
+
+func TestRunWithRetry_NonRetryableError(t *testing.T) {
+	t.Parallel()
+
+	retryAgent := createRetryAgent(t)
+	mockLLM := llmtest.NewMockLLM(`{"sum": 3}`)
+	agent.SetLLM(retryAgent, mockLLM)
+
+	_, err := retryAgent.RunWithRetry(context.Background(), AddNumbers{Num1: 1, Num2: 2}, 3)
+
+	require.ErrorIs(t, err, agent.ErrLLMCall)
+	assert.Len(t, mockLLM.Calls(), 1)
+}
+
+func TestRunWithRetry_Backoff(t *testing.T) {
+	t.Parallel()
+
+	// given
+	retryAgent := createRetryAgent(t, agent.WithRetryBackoff[AddNumbersResult](20*time.Millisecond))
+	mockLLM := llmtest.NewMockLLM(`{"sum": 3}`, overLimitMessage(), overLimitMessage(), endMessage("done"))
+	agent.SetLLM(retryAgent, mockLLM)
+	start := time.Now()
+
+	// when
+	result, err := retryAgent.RunWithRetry(context.Background(), AddNumbers{Num1: 1, Num2: 2}, 2)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Data.Sum)
+	assert.GreaterOrEqual(t, time.Since(start), 60*time.Millisecond)
+}
+
+func TestRunWithRetry_BackoffCancelled(t *testing.T) {
+	t.Parallel()
+
+	// given
+	retryAgent := createRetryAgent(t, agent.WithRetryBackoff[AddNumbersResult](time.Hour))
+	mockLLM := llmtest.NewMockLLM(`{"sum": 3}`, overLimitMessage(), endMessage("done"))
+	agent.SetLLM(retryAgent, mockLLM)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	// when
+	_, err := retryAgent.RunWithRetry(ctx, AddNumbers{Num1: 1, Num2: 2}, 1)
+
+	// then
+	require.ErrorIs(t, err, agent.ErrLimitReached)
+	assert.Len(t, mockLLM.Calls(), 1)
+}
+
+func TestRetryDelay(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		options []agent.AgentOption[AddNumbersResult]
+		want    map[int]time.Duration
+	}{
+		{
+			name: "no backoff",
+			want: map[int]time.Duration{1: 0, 2: 0, 3: 0},
+		},
+		{
+			name:    "exponential",
+			options: []agent.AgentOption[AddNumbersResult]{agent.WithRetryBackoff[AddNumbersResult](time.Second)},
+			want:    map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 8 * time.Second},
+		},
+		{
+			name:    "default max delay",
+			options: []agent.AgentOption[AddNumbersResult]{agent.WithRetryBackoff[AddNumbersResult](10 * time.Second)},
+			want: map[int]time.Duration{
+				1: 10 * time.Second, 2: 20 * time.Second, 3: 30 * time.Second, 4: 30 * time.Second,
+			},
+		},
+		{
+			name: "max delay before backoff",
+			options: []agent.AgentOption[AddNumbersResult]{
+				agent.WithMaxRetryDelay[AddNumbersResult](3 * time.Second),
+				agent.WithRetryBackoff[AddNumbersResult](time.Second),
+			},
+			want: map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 3 * time.Second, 4: 3 * time.Second},
+		},
+		{
+			name: "overflow",
+			options: []agent.AgentOption[AddNumbersResult]{
+				agent.WithRetryBackoff[AddNumbersResult](time.Second),
+				agent.WithMaxRetryDelay[AddNumbersResult](time.Minute),
+			},
+			want: map[int]time.Duration{1: time.Second, 7: time.Minute, 64: time.Minute, 1000: time.Minute},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			retryAgent := createRetryAgent(t, tt.options...)
+
+			for attempt, want := range tt.want {
+				assert.Equal(t, want, agent.RetryDelay(retryAgent, attempt), "attempt %d", attempt)
+			}
+		})
+	}
+}
+
+func TestRetryDelay_Jitter(t *testing.T) {
+	t.Parallel()
+
+	// given
+	retryAgent := createRetryAgent(t,
+		agent.WithRetryJitter[AddNumbersResult](0.5),
+		agent.WithRetryBackoff[AddNumbersResult](time.Second),
+		agent.WithMaxRetryDelay[AddNumbersResult](5*time.Second),
+	)
+
+	for range 100 {
+		// when
+		first := agent.RetryDelay(retryAgent, 1)
+		capped := agent.RetryDelay(retryAgent, 4)
+
+		// then
+		assert.GreaterOrEqual(t, first, 500*time.Millisecond)
+		assert.LessOrEqual(t, first, 1500*time.Millisecond)
+		assert.GreaterOrEqual(t, capped, 2500*time.Millisecond)
+		assert.LessOrEqual(t, capped, 5*time.Second)
+	}
+}
+
+func TestRetryBackoff_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		option agent.AgentOption[AddNumbersResult]
+	}{
+		{name: "negative backoff", option: agent.WithRetryBackoff[AddNumbersResult](-time.Second)},
+		{name: "zero max delay", option: agent.WithMaxRetryDelay[AddNumbersResult](0)},
+		{name: "negative jitter", option: agent.WithRetryJitter[AddNumbersResult](-0.1)},
+		{name: "jitter above one", option: agent.WithRetryJitter[AddNumbersResult](1.5)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := newRetryAgent(tt.option)
+
+			require.ErrorIs(t, err, validation.ErrValidationFailed)
+			assert.Contains(t, err.Error(), "retry backoff")
+		})
+	}
+}
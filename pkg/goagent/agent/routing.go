@@ -0,0 +1,41 @@
+package agent
+
+import (
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmfactory"
+)
+
+// WithRoutingFactory selects the LLM config of the agent by its name from the routes of the
+// factory, as an alternative to WithLLMConfig. It replaces a config set with WithLLMConfig.
+// Creating the agent fails with llmfactory.ErrNoRoute when neither the agent name nor
+// llmfactory.DefaultRoute has a route.
+//
+// Example:
+//
+//	factory := llmfactory.NewRoutingFactory(map[string]llm.LLMConfig{
+//		"billing_agent":         billingConfig,
+//		llmfactory.DefaultRoute: sharedConfig,
+//	})
+//
+//	billingAgent, err := agent.NewAgent(
+//		agent.WithName[Invoice]("billing_agent"),
+//		agent.WithRoutingFactory[Invoice](factory),
+//		agent.WithBehavior[Invoice]("You prepare invoices."),
+//	)
+func WithRoutingFactory[T any](f *llmfactory.RoutingFactory) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.routingFactory = f
+	}
+}
+
+// validateLLMConfig selects the config from the routing factory, when one is set, and validates it
+func (a *Agent[T]) validateLLMConfig() error {
+	if a.routingFactory != nil {
+		cfg, err := a.routingFactory.Config(a.name)
+		if err != nil {
+			return err
+		}
+		a.llmConfig = cfg
+	}
+
+	return a.llmConfig.Validate()
+}
@@ -0,0 +1,57 @@
+package agent_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmfactory"
+)
+
+func TestWithRoutingFactory(t *testing.T) {
+	t.Parallel()
+
+	// given
+	server := &tenantServer{}
+	billingConfig := testLLMConfig()
+	billingConfig.APIKey = "billing-key"
+	billingConfig.HTTPClient = newTenantClient(t, server)
+
+	factory := llmfactory.NewRoutingFactory(map[string]llm.LLMConfig{
+		"billing_agent":         billingConfig,
+		llmfactory.DefaultRoute: testLLMConfig(),
+	})
+
+	billingAgent, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("billing_agent"),
+		agent.WithRoutingFactory[AddNumbersResult](factory),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+	)
+	require.NoError(t, err)
+
+	// when
+	result, err := billingAgent.Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+
+	// then
+	require.NoError(t, err)
+	assert.InDelta(t, 3, result.Data.Sum, 0)
+	assert.Equal(t, []string{"Bearer billing-key", "Bearer billing-key"}, server.authHeaders)
+}
+
+func TestWithRoutingFactory_NoRoute(t *testing.T) {
+	t.Parallel()
+
+	factory := llmfactory.NewRoutingFactory(map[string]llm.LLMConfig{"billing_agent": testLLMConfig()})
+
+	_, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("support_agent"),
+		agent.WithRoutingFactory[AddNumbersResult](factory),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+	)
+
+	require.ErrorIs(t, err, llmfactory.ErrNoRoute)
+	assert.Contains(t, err.Error(), "llm config")
+}
@@ -0,0 +1,37 @@
+package agent
+
+import (
+	"context"
+)
+
+// RunFunc creates an agent from the options, runs it once with the input and discards it.
+// It is meant for one-off calls in scripts and CLI tools, where configuring and keeping an
+// Agent is boilerplate. Agents which run more than once should be created with NewAgent,
+// so the LLM client and generated schemas are reused.
+//
+// Example:
+//
+//	result, err := agent.RunFunc(ctx, "What is the capital of France?",
+//		agent.WithName[Answer]("qa_agent"),
+//		agent.WithLLMConfig[Answer](llmConfig),
+//		agent.WithBehavior[Answer]("Answer the question."),
+//	)
+func RunFunc[In any, Out any](ctx context.Context, input In, opts ...AgentOption[Out]) (*AgentResult[Out], error) {
+	a, err := NewAgent(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.Run(ctx, input)
+}
+
+// MustRunFunc is like RunFunc but panics when the agent cannot be created from the options.
+// Errors of the run itself are returned.
+func MustRunFunc[In any, Out any](ctx context.Context, input In, opts ...AgentOption[Out]) (*AgentResult[Out], error) {
+	a, err := NewAgent(opts...)
+	if err != nil {
+		panic(err)
+	}
+
+	return a.Run(ctx, input)
+}
@@ -0,0 +1,95 @@
+package agent_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+)
+
+func runFuncOptions(httpClient *http.Client) []agent.AgentOption[AddNumbersResult] {
+	llmConfig := testLLMConfig()
+	llmConfig.HTTPClient = httpClient
+
+	return []agent.AgentOption[AddNumbersResult]{
+		agent.WithName[AddNumbersResult]("run_func_agent"),
+		agent.WithLLMConfig[AddNumbersResult](llmConfig),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+	}
+}
+
+func TestRunFunc(t *testing.T) {
+	t.Parallel()
+
+	// given
+	server := &tenantServer{}
+	opts := runFuncOptions(newTenantClient(t, server))
+
+	// when
+	result, err := agent.RunFunc(context.Background(), AddNumbers{Num1: 1, Num2: 2}, opts...)
+
+	// then
+	require.NoError(t, err)
+	assert.InDelta(t, 3, result.Data.Sum, 0)
+	assert.Len(t, server.authHeaders, 2)
+}
+
+func TestRunFunc_InvalidOptions(t *testing.T) {
+	t.Parallel()
+
+	// when
+	result, err := agent.RunFunc(context.Background(), "input", agent.WithName[AddNumbersResult](""))
+
+	// then
+	require.ErrorIs(t, err, validation.ErrValidationFailed)
+	assert.Nil(t, result)
+}
+
+func TestMustRunFunc(t *testing.T) {
+	t.Parallel()
+
+	// given
+	opts := runFuncOptions(newTenantClient(t, &tenantServer{}))
+
+	// when
+	result, err := agent.MustRunFunc(context.Background(), AddNumbers{Num1: 1, Num2: 2}, opts...)
+
+	// then
+	require.NoError(t, err)
+	assert.InDelta(t, 3, result.Data.Sum, 0)
+}
+
+func TestMustRunFunc_PanicsOnInvalidOptions(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() {
+		_, _ = agent.MustRunFunc(context.Background(), "input", agent.WithName[AddNumbersResult](""))
+	})
+}
+
+func TestMustRunFunc_ReturnsRunErrors(t *testing.T) {
+	t.Parallel()
+
+	// given
+	failingServer := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, `{"error": {"message": "boom"}}`, http.StatusBadRequest)
+	})
+	opts := runFuncOptions(newTenantClient(t, failingServer))
+
+	// when
+	var (
+		result *agent.AgentResult[AddNumbersResult]
+		err    error
+	)
+	require.NotPanics(t, func() {
+		result, err = agent.MustRunFunc(context.Background(), AddNumbers{Num1: 1, Num2: 2}, opts...)
+	})
+
+	// then
+	require.ErrorIs(t, err, agent.ErrLLMCall)
+	assert.Nil(t, result)
+}
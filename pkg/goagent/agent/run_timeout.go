@@ -0,0 +1,62 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrRunTimeout is returned when a run exceeds the limit set by WithTimeoutMiddleware
+	ErrRunTimeout = errors.New("agent run timed out")
+	// ErrRunCancelled is returned when the context passed to Run is cancelled or its deadline
+	// is exceeded before the run completes
+	ErrRunCancelled = errors.New("agent run cancelled")
+)
+
+// WithTimeoutMiddleware limits the wall-clock duration of the entire run, including tool
+// calls, output validation and fallback agents. It is separate from the context passed to Run:
+// when d elapses first, the run is cancelled and Run returns ErrRunTimeout, while cancellation
+// of the caller context is reported as ErrRunCancelled. This lets telemetry tell the agent's
+// own timeouts apart from upstream cancellations. A non-positive d disables the limit.
+func WithTimeoutMiddleware[T any](d time.Duration) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.runTimeout = d
+	}
+}
+
+// withRunTimeout returns the context for the run with the deadline set by WithTimeoutMiddleware
+func (a *Agent[T]) withRunTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if a.runTimeout <= 0 {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeoutCause(ctx, a.runTimeout, ErrRunTimeout)
+}
+
+// classifyRunError reports whether a failed run was stopped by the caller context or by the
+// run timeout. The context errors are often lost in the error chain of LLM and tool errors,
+// so the contexts are inspected instead.
+func classifyRunError(callerCtx context.Context, runCtx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	switch {
+	case callerCtx.Err() != nil:
+		if errors.Is(err, ErrRunCancelled) {
+			return err
+		}
+
+		return fmt.Errorf("%w: %w", ErrRunCancelled, err)
+	case errors.Is(context.Cause(runCtx), ErrRunTimeout):
+		if errors.Is(err, ErrRunTimeout) {
+			return err
+		}
+
+		return fmt.Errorf("%w: %w", ErrRunTimeout, err)
+	default:
+		return err
+	}
+}
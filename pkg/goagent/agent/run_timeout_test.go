@@ -0,0 +1,89 @@
+package agent_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+// blockingLLM blocks every call until the context is done
+type blockingLLM struct{}
+
+func (blockingLLM) Call(ctx context.Context, _ []llm.LLMMessage) (llm.LLMMessage, error) {
+	<-ctx.Done()
+
+	return llm.LLMMessage{}, ctx.Err()
+}
+
+func (blockingLLM) CallWithStructuredOutput(ctx context.Context, _ []llm.LLMMessage, _ any) (string, error) {
+	<-ctx.Done()
+
+	return "", ctx.Err()
+}
+
+func createTimeoutAgent(t *testing.T, timeout time.Duration, agentLLM llm.LLM) *agent.Agent[AddNumbersResult] {
+	t.Helper()
+
+	timeoutAgent, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("timeout_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You add numbers."),
+		agent.WithTimeoutMiddleware[AddNumbersResult](timeout),
+	)
+	require.NoError(t, err)
+
+	agent.SetLLM(timeoutAgent, agentLLM)
+
+	return timeoutAgent
+}
+
+func TestWithTimeoutMiddleware(t *testing.T) {
+	t.Parallel()
+
+	// given
+	timeoutAgent := createTimeoutAgent(t, 20*time.Millisecond, blockingLLM{})
+
+	// when
+	_, err := timeoutAgent.Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+
+	// then
+	require.ErrorIs(t, err, agent.ErrRunTimeout)
+	assert.NotErrorIs(t, err, agent.ErrRunCancelled)
+}
+
+func TestWithTimeoutMiddleware_CallerCancellation(t *testing.T) {
+	t.Parallel()
+
+	// given
+	timeoutAgent := createTimeoutAgent(t, time.Minute, blockingLLM{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	// when
+	_, err := timeoutAgent.Run(ctx, AddNumbers{Num1: 1, Num2: 2})
+
+	// then
+	require.ErrorIs(t, err, agent.ErrRunCancelled)
+	assert.NotErrorIs(t, err, agent.ErrRunTimeout)
+}
+
+func TestWithTimeoutMiddleware_CompletesInTime(t *testing.T) {
+	t.Parallel()
+
+	// given
+	timeoutAgent := createTimeoutAgent(t, time.Minute, llmtest.NewMockLLM(`{"sum": 3}`, endMessage("done")))
+
+	// when
+	result, err := timeoutAgent.Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Data.Sum)
+}
@@ -0,0 +1,135 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/schema"
+)
+
+var (
+	// ErrSchemaNotFound is returned when a schema is not registered in the registry
+	ErrSchemaNotFound = errors.New("schema not found")
+	// ErrSchemaAlreadyRegistered is returned when a schema name is already taken
+	ErrSchemaAlreadyRegistered = errors.New("schema already registered")
+	// ErrSchemaTypeMismatch is returned when a registered schema does not match the agent output type
+	ErrSchemaTypeMismatch = errors.New("schema type mismatch")
+)
+
+var globalSchemaRegistry = NewSchemaRegistry()
+
+type registeredSchema struct {
+	schemaType reflect.Type
+	schema     map[string]any
+}
+
+// SchemaRegistry stores pre-generated output schemas by name, so agents in
+// different packages can share result types without repeated reflection.
+type SchemaRegistry struct {
+	mu      sync.RWMutex
+	schemas map[string]registeredSchema
+}
+
+// NewSchemaRegistry creates an empty schema registry
+func NewSchemaRegistry() *SchemaRegistry {
+	return &SchemaRegistry{
+		schemas: make(map[string]registeredSchema),
+	}
+}
+
+// GlobalSchemaRegistry returns the default process-wide schema registry
+func GlobalSchemaRegistry() *SchemaRegistry {
+	return globalSchemaRegistry
+}
+
+// Register generates the JSON schema for T and stores it in the registry under the given name.
+//
+// Example:
+//
+//	type Summary struct {
+//		Text string `json:"text" jsonschema_description:"Summary text"`
+//	}
+//
+//	if err := agent.Register[Summary](agent.GlobalSchemaRegistry(), "summary"); err != nil {
+//		log.Fatal(err)
+//	}
+func Register[T any](reg *SchemaRegistry, name string) error {
+	if err := validation.NameIsValid(name); err != nil {
+		return fmt.Errorf("schema name: %w", err)
+	}
+
+	schemaMap, err := schema.GenerateSchema(new(T))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrCannotCreateSchema, err)
+	}
+
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	if _, exists := reg.schemas[name]; exists {
+		return fmt.Errorf("%w: %s", ErrSchemaAlreadyRegistered, name)
+	}
+
+	reg.schemas[name] = registeredSchema{
+		schemaType: reflect.TypeFor[T](),
+		schema:     schemaMap,
+	}
+
+	return nil
+}
+
+// Get returns the registered schema with the given name.
+// The returned map is shared and must not be modified.
+func (r *SchemaRegistry) Get(name string) (map[string]any, error) {
+	entry, err := r.get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return entry.schema, nil
+}
+
+func (r *SchemaRegistry) get(name string) (registeredSchema, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entry, exists := r.schemas[name]
+	if !exists {
+		return registeredSchema{}, fmt.Errorf("%w: %s", ErrSchemaNotFound, name)
+	}
+
+	return entry, nil
+}
+
+// WithOutputSchemaFromRegistry makes the agent use the output schema registered under
+// the given name instead of generating it from T on every run. The registered schema
+// must have been created for the same type T.
+func WithOutputSchemaFromRegistry[T any](reg *SchemaRegistry, name string) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.schemaRegistry = reg
+		a.schemaName = name
+	}
+}
+
+func (a *Agent[T]) resolveRegistrySchema() error {
+	if a.schemaRegistry == nil {
+		return nil
+	}
+
+	entry, err := a.schemaRegistry.get(a.schemaName)
+	if err != nil {
+		return err
+	}
+
+	if entry.schemaType != reflect.TypeFor[T]() {
+		return fmt.Errorf("%w: schema %s is registered for %s, agent output type is %s",
+			ErrSchemaTypeMismatch, a.schemaName, entry.schemaType, reflect.TypeFor[T]())
+	}
+
+	a.outputSchemaMap = entry.schema
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package agent_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+func TestSchemaRegistry_RegisterAndGet(t *testing.T) {
+	t.Parallel()
+
+	reg := agent.NewSchemaRegistry()
+
+	err := agent.Register[AddNumbersResult](reg, "add_numbers_result")
+	require.NoError(t, err)
+
+	schemaMap, err := reg.Get("add_numbers_result")
+	require.NoError(t, err)
+	assert.Equal(t, "object", schemaMap["type"])
+
+	properties, isOK := schemaMap["properties"].(map[string]any)
+	require.True(t, isOK)
+	assert.Contains(t, properties, "sum")
+}
+
+func TestSchemaRegistry_DuplicateRegistration(t *testing.T) {
+	t.Parallel()
+
+	reg := agent.NewSchemaRegistry()
+	require.NoError(t, agent.Register[AddNumbersResult](reg, "result"))
+
+	err := agent.Register[HashResult](reg, "result")
+
+	require.ErrorIs(t, err, agent.ErrSchemaAlreadyRegistered)
+}
+
+func TestSchemaRegistry_NotFound(t *testing.T) {
+	t.Parallel()
+
+	_, err := agent.NewSchemaRegistry().Get("missing")
+
+	require.ErrorIs(t, err, agent.ErrSchemaNotFound)
+}
+
+func TestGlobalSchemaRegistry(t *testing.T) {
+	t.Parallel()
+
+	assert.Same(t, agent.GlobalSchemaRegistry(), agent.GlobalSchemaRegistry())
+}
+
+func TestWithOutputSchemaFromRegistry(t *testing.T) {
+	t.Parallel()
+
+	reg := agent.NewSchemaRegistry()
+	require.NoError(t, agent.Register[AddNumbersResult](reg, "add_numbers_result"))
+	registered, err := reg.Get("add_numbers_result")
+	require.NoError(t, err)
+
+	registryAgent, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("registry_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithOutputSchemaFromRegistry[AddNumbersResult](reg, "add_numbers_result"),
+	)
+	require.NoError(t, err)
+
+	mockLLM := llmtest.NewMockLLM(`{"sum": 3}`, endMessage("done"))
+	agent.SetLLM(registryAgent, mockLLM)
+
+	result, err := registryAgent.Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Data.Sum)
+	require.Len(t, mockLLM.Schemas(), 1)
+	assert.Equal(t, registered, mockLLM.Schemas()[0])
+}
+
+func TestWithOutputSchemaFromRegistry_TypeMismatch(t *testing.T) {
+	t.Parallel()
+
+	reg := agent.NewSchemaRegistry()
+	require.NoError(t, agent.Register[HashResult](reg, "hash_result"))
+
+	_, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("registry_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithOutputSchemaFromRegistry[AddNumbersResult](reg, "hash_result"),
+	)
+
+	require.ErrorIs(t, err, agent.ErrSchemaTypeMismatch)
+}
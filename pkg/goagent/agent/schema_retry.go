@@ -0,0 +1,126 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/schema"
+)
+
+// ErrMissingRequiredField is returned when the output lacks a field required by the output
+// schema. The output is only checked for required fields with WithRetryOnSchema.
+var ErrMissingRequiredField = errors.New("missing required field")
+
+var schemaFixPromptTemplate = NewPrompt(`Your final output above could not be parsed:
+
+{{.error}}
+
+Fix the output so it matches the JSON schema and provide the corrected final output.
+Output ONLY the JSON object with no additional text`)
+
+// malformedOutputError is returned when the structured output of the LLM can't be decoded
+type malformedOutputError struct {
+	output string
+	err    error
+}
+
+func (e *malformedOutputError) Error() string {
+	return e.err.Error()
+}
+
+func (e *malformedOutputError) Unwrap() error {
+	return e.err
+}
+
+// WithRetryOnSchema re-prompts the LLM up to maxRetries times when its structured output
+// can't be decoded, e.g. because of a json.UnmarshalTypeError or a missing top-level field
+// required by the output schema. The re-prompt contains the malformed output and the
+// decoding error. Run fails with llm.ErrStructuredOutput when the output is still
+// malformed after the last retry.
+func WithRetryOnSchema[T any](maxRetries int) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.schemaMaxRetries = maxRetries
+	}
+}
+
+// callWithSchemaRetry calls the LLM with structured output and re-prompts it with the
+// decoding error while the output is malformed and retries are left
+func (a *Agent[T]) callWithSchemaRetry(ctx context.Context, state *AgentState) (T, error) {
+	start := time.Now()
+	result, err := a.callWithStructuredOutput(ctx, state.Messages)
+	state.recordLLMCall(start)
+
+	for attempt := 0; attempt < a.schemaMaxRetries; attempt++ {
+		var malformed *malformedOutputError
+		if !errors.As(err, &malformed) {
+			break
+		}
+
+		fixPrompt, renderErr := schemaFixPromptTemplate.Render(map[string]any{"error": malformed.Error()})
+		if renderErr != nil {
+			return result, fmt.Errorf("failed to render schema fix prompt: %w", renderErr)
+		}
+
+		fixMessages := []llm.LLMMessage{
+			llm.NewLLMMessage(llm.LLMMessageTypeAssistant, malformed.output),
+			llm.NewLLMMessage(llm.LLMMessageTypeUser, fixPrompt),
+		}
+		for _, msg := range fixMessages {
+			if _, err := state.addMessage(ctx, msg); err != nil {
+				return result, err
+			}
+		}
+
+		start = time.Now()
+		result, err = a.callWithStructuredOutput(ctx, state.Messages)
+		state.recordLLMCall(start)
+	}
+
+	return result, err
+}
+
+// checkRequiredFields returns ErrMissingRequiredField when the output is a JSON object which
+// lacks a top-level field listed as required by the schema
+func checkRequiredFields(data []byte, schemaT any) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil //nolint:nilerr // outputs which are not objects are checked by the unmarshaler
+	}
+
+	outputSchema, err := schema.GenerateSchema(schemaT)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range requiredFields(outputSchema) {
+		if _, ok := fields[name]; !ok {
+			return fmt.Errorf("%w: %q", ErrMissingRequiredField, name)
+		}
+	}
+
+	return nil
+}
+
+// requiredFields returns the required properties of the schema, which are []any in
+// generated schemas and may be []string in schemas written by hand
+func requiredFields(outputSchema map[string]any) []string {
+	switch required := outputSchema["required"].(type) {
+	case []string:
+		return required
+	case []any:
+		names := make([]string, 0, len(required))
+		for _, name := range required {
+			if fieldName, ok := name.(string); ok {
+				names = append(names, fieldName)
+			}
+		}
+
+		return names
+	default:
+		return nil
+	}
+}
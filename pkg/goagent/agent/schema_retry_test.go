@@ -0,0 +1,149 @@
+package agent_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+// structuredOutputsLLM ends the conversation right away and returns the structured outputs
+// in order, repeating the last one
+type structuredOutputsLLM struct {
+	mu      sync.Mutex
+	outputs []string
+	calls   [][]llm.LLMMessage
+}
+
+func (l *structuredOutputsLLM) Call(_ context.Context, _ []llm.LLMMessage) (llm.LLMMessage, error) {
+	return endMessage("done"), nil
+}
+
+func (l *structuredOutputsLLM) CallWithStructuredOutput(
+	_ context.Context, msgs []llm.LLMMessage, _ any,
+) (string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.calls = append(l.calls, append([]llm.LLMMessage(nil), msgs...))
+	output := l.outputs[0]
+	if len(l.outputs) > 1 {
+		l.outputs = l.outputs[1:]
+	}
+
+	return output, nil
+}
+
+func runWithSchemaRetry(
+	t *testing.T, schemaLLM *structuredOutputsLLM, options ...agent.AgentOption[AddNumbersResult],
+) (*agent.AgentResult[AddNumbersResult], error) {
+	t.Helper()
+
+	schemaAgent, err := agent.NewAgent(append([]agent.AgentOption[AddNumbersResult]{
+		agent.WithName[AddNumbersResult]("schema_retry_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+	}, options...)...)
+	require.NoError(t, err)
+
+	return schemaAgent.UsingLLM(schemaLLM).Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+}
+
+func TestWithRetryOnSchema(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		malformed   string
+		wantInError string
+	}{
+		{name: "type error", malformed: `{"sum": "three"}`, wantInError: "cannot unmarshal string"},
+		{name: "missing required field", malformed: `{"total": 3}`, wantInError: `missing required field: "sum"`},
+		{name: "invalid JSON", malformed: `{"sum": 3`, wantInError: "unexpected end of JSON input"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			schemaLLM := &structuredOutputsLLM{outputs: []string{tt.malformed, `{"sum": 3}`}}
+
+			// when
+			result, err := runWithSchemaRetry(t, schemaLLM, agent.WithRetryOnSchema[AddNumbersResult](1))
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, 3, result.Data.Sum)
+
+			require.Len(t, schemaLLM.calls, 2)
+			retryMessages := schemaLLM.calls[1]
+			malformedOutput := retryMessages[len(retryMessages)-2]
+			fixPrompt := retryMessages[len(retryMessages)-1]
+			assert.Equal(t, llm.LLMMessageTypeAssistant, malformedOutput.Type)
+			assert.Equal(t, tt.malformed, malformedOutput.Content)
+			assert.Equal(t, llm.LLMMessageTypeUser, fixPrompt.Type)
+			assert.Contains(t, fixPrompt.Content, tt.wantInError)
+		})
+	}
+}
+
+func TestWithRetryOnSchema_RetriesExhausted(t *testing.T) {
+	t.Parallel()
+
+	// given
+	schemaLLM := &structuredOutputsLLM{outputs: []string{`{"sum": "three"}`}}
+
+	// when
+	result, err := runWithSchemaRetry(t, schemaLLM, agent.WithRetryOnSchema[AddNumbersResult](2))
+
+	// then
+	require.ErrorIs(t, err, llm.ErrStructuredOutput)
+	assert.Nil(t, result)
+	assert.Len(t, schemaLLM.calls, 3)
+}
+
+func TestWithRetryOnSchema_Disabled(t *testing.T) {
+	t.Parallel()
+
+	t.Run("malformed output fails", func(t *testing.T) {
+		t.Parallel()
+
+		schemaLLM := &structuredOutputsLLM{outputs: []string{`{"sum": "three"}`, `{"sum": 3}`}}
+
+		_, err := runWithSchemaRetry(t, schemaLLM)
+
+		require.ErrorIs(t, err, llm.ErrStructuredOutput)
+		assert.Len(t, schemaLLM.calls, 1)
+	})
+
+	t.Run("required fields are not checked", func(t *testing.T) {
+		t.Parallel()
+
+		schemaLLM := &structuredOutputsLLM{outputs: []string{`{}`}}
+
+		result, err := runWithSchemaRetry(t, schemaLLM)
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, result.Data.Sum)
+	})
+}
+
+func TestWithRetryOnSchema_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("schema_retry_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithRetryOnSchema[AddNumbersResult](-1),
+	)
+
+	require.ErrorIs(t, err, validation.ErrValidationFailed)
+	assert.Contains(t, err.Error(), "schema retries")
+}
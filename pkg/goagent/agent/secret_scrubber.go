@@ -0,0 +1,166 @@
+package agent
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+const secretPlaceholder = "[SECRET]"
+
+// secretScrubber replaces exact occurrences of secrets with a placeholder
+type secretScrubber struct {
+	replacer *strings.Replacer
+}
+
+// WithSecretScrubber replaces every exact occurrence of the given secrets with [SECRET]
+// in messages before they are added to the agent state, so secrets that slip into the
+// input or tool results never reach the LLM context or logs. Message content, multimodal
+// text parts, tool call arguments and all string fields of tool results are scrubbed.
+func WithSecretScrubber[T any](secrets []string) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.secretScrubber = newSecretScrubber(secrets)
+	}
+}
+
+func newSecretScrubber(secrets []string) *secretScrubber {
+	sorted := make([]string, 0, len(secrets))
+	for _, secret := range secrets {
+		if secret != "" {
+			sorted = append(sorted, secret)
+		}
+	}
+	if len(sorted) == 0 {
+		return nil
+	}
+
+	// Longer secrets first, so a secret containing another one is replaced as a whole
+	sort.Slice(sorted, func(i, j int) bool {
+		return len(sorted[i]) > len(sorted[j])
+	})
+
+	pairs := make([]string, 0, 2*len(sorted))
+	for _, secret := range sorted {
+		pairs = append(pairs, secret, secretPlaceholder)
+	}
+
+	return &secretScrubber{replacer: strings.NewReplacer(pairs...)}
+}
+
+func (s *secretScrubber) scrubMessage(msg llm.LLMMessage) llm.LLMMessage {
+	msg.Content = s.replacer.Replace(msg.Content)
+
+	if msg.Parts != nil {
+		parts := make([]llm.MessagePart, len(msg.Parts))
+		for i, part := range msg.Parts {
+			if part.Text != nil {
+				part.Text = &llm.TextPart{Text: s.replacer.Replace(part.Text.Text)}
+			}
+			parts[i] = part
+		}
+		msg.Parts = parts
+	}
+
+	if msg.ToolCalls != nil {
+		toolCalls := make([]llm.LLMToolCall, len(msg.ToolCalls))
+		for i, toolCall := range msg.ToolCalls {
+			toolCall.Args = s.replacer.Replace(toolCall.Args)
+			toolCalls[i] = toolCall
+		}
+		msg.ToolCalls = toolCalls
+	}
+
+	if msg.ToolResults != nil {
+		toolResults := make([]llm.LLMToolResult, len(msg.ToolResults))
+		for i, toolRes := range msg.ToolResults {
+			toolResults[i] = s.scrubToolResult(toolRes)
+		}
+		msg.ToolResults = toolResults
+	}
+
+	return msg
+}
+
+// scrubToolResult returns a copy of the tool result with secrets removed from all string fields
+func (s *secretScrubber) scrubToolResult(toolRes llm.LLMToolResult) llm.LLMToolResult {
+	if toolRes == nil {
+		return nil
+	}
+
+	scrubbed, ok := s.scrubValue(reflect.ValueOf(toolRes)).Interface().(llm.LLMToolResult)
+	if !ok {
+		return toolRes
+	}
+
+	return scrubbed
+}
+
+// scrubValue returns a deep copy of value with secrets replaced in every string it contains.
+// Unexported struct fields are copied as is.
+func (s *secretScrubber) scrubValue(value reflect.Value) reflect.Value {
+	switch value.Kind() {
+	case reflect.String:
+		scrubbed := reflect.New(value.Type()).Elem()
+		scrubbed.SetString(s.replacer.Replace(value.String()))
+
+		return scrubbed
+	case reflect.Pointer:
+		if value.IsNil() {
+			return value
+		}
+		scrubbed := reflect.New(value.Type().Elem())
+		scrubbed.Elem().Set(s.scrubValue(value.Elem()))
+
+		return scrubbed
+	case reflect.Interface:
+		if value.IsNil() {
+			return value
+		}
+		scrubbed := reflect.New(value.Type()).Elem()
+		scrubbed.Set(s.scrubValue(value.Elem()))
+
+		return scrubbed
+	case reflect.Struct:
+		scrubbed := reflect.New(value.Type()).Elem()
+		scrubbed.Set(value)
+		for i := range value.NumField() {
+			if scrubbed.Field(i).CanSet() {
+				scrubbed.Field(i).Set(s.scrubValue(value.Field(i)))
+			}
+		}
+
+		return scrubbed
+	case reflect.Slice:
+		if value.IsNil() {
+			return value
+		}
+		scrubbed := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+		for i := range value.Len() {
+			scrubbed.Index(i).Set(s.scrubValue(value.Index(i)))
+		}
+
+		return scrubbed
+	case reflect.Array:
+		scrubbed := reflect.New(value.Type()).Elem()
+		for i := range value.Len() {
+			scrubbed.Index(i).Set(s.scrubValue(value.Index(i)))
+		}
+
+		return scrubbed
+	case reflect.Map:
+		if value.IsNil() {
+			return value
+		}
+		scrubbed := reflect.MakeMapWithSize(value.Type(), value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			scrubbed.SetMapIndex(iter.Key(), s.scrubValue(iter.Value()))
+		}
+
+		return scrubbed
+	default:
+		return value
+	}
+}
@@ -0,0 +1,85 @@
+package agent_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+type secretToolParams struct {
+	Query string `json:"query"`
+}
+
+type secretToolResult struct {
+	llm.BaseLLMToolResult
+	Body    string            `json:"body"`
+	Headers map[string]string `json:"headers"`
+}
+
+func TestWithSecretScrubber(t *testing.T) {
+	t.Parallel()
+
+	// given
+	const secret = "sk-live-12345"
+
+	lookupTool, err := llm.NewLLMTool(
+		llm.WithLLMToolName("lookup"),
+		llm.WithLLMToolDescription("Looks up data"),
+		llm.WithLLMToolParametersSchema[secretToolParams](),
+		llm.WithLLMToolCall(func(id string, params secretToolParams) (secretToolResult, error) {
+			return secretToolResult{
+				BaseLLMToolResult: llm.BaseLLMToolResult{ID: id},
+				Body:              "token " + secret,
+				Headers:           map[string]string{"Authorization": "Bearer " + secret},
+			}, nil
+		}),
+	)
+	require.NoError(t, err)
+
+	mockLLM := llmtest.NewMockLLM(`{"sum": 3}`,
+		toolCallMessage(llm.LLMToolCall{ID: "call-1", ToolName: "lookup", Args: `{"query":"` + secret + `"}`}),
+		endMessage("done"),
+	)
+
+	a, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("scrubber"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("Look up data"),
+		agent.WithTool[AddNumbersResult]("lookup", lookupTool),
+		agent.WithSecretScrubber[AddNumbersResult]([]string{secret, ""}),
+	)
+	require.NoError(t, err)
+	a = a.UsingLLM(mockLLM)
+
+	// when
+	result, err := a.Run(context.Background(), map[string]string{"key": secret})
+
+	// then
+	require.NoError(t, err)
+
+	for _, msg := range result.Messages {
+		assert.NotContains(t, msg.Content, secret)
+		for _, toolCall := range msg.ToolCalls {
+			assert.NotContains(t, toolCall.Args, secret)
+		}
+	}
+
+	assert.Contains(t, result.Messages[1].Content, "[SECRET]")
+
+	var toolResult secretToolResult
+	for _, msg := range result.Messages {
+		for _, toolRes := range msg.ToolResults {
+			res, ok := toolRes.(secretToolResult)
+			require.True(t, ok)
+			toolResult = res
+		}
+	}
+	assert.Equal(t, "call-1", toolResult.ID)
+	assert.Equal(t, "token [SECRET]", toolResult.Body)
+	assert.Equal(t, "Bearer [SECRET]", toolResult.Headers["Authorization"])
+}
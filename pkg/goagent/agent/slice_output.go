@@ -0,0 +1,14 @@
+package agent
+
+import "reflect"
+
+// sliceOutput wraps slice results for structured output. OpenAI requires the root of a
+// response schema to be an object, so a T of []Item is requested as {"items": [...]}
+// and unwrapped before it is returned to the caller.
+type sliceOutput[T any] struct {
+	Items T `json:"items" jsonschema_description:"All items of the result"`
+}
+
+func isSliceOutput[T any]() bool {
+	return reflect.TypeFor[T]().Kind() == reflect.Slice
+}
@@ -0,0 +1,84 @@
+package agent_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/schema"
+)
+
+type Entity struct {
+	Name string `json:"name" jsonschema_description:"Name of the entity"`
+	Kind string `json:"kind" jsonschema_description:"Kind of the entity: person, organization or location"`
+}
+
+func TestSliceAgentResult(t *testing.T) {
+	t.Parallel()
+	// given
+	apiKey := os.Getenv("OPENAI_API_KEY")
+	require.NotEmpty(t, apiKey, "OPENAI_API_KEY environment variable must be set")
+
+	entityAgent, err := agent.NewAgent(
+		agent.WithName[[]Entity]("entity_agent"),
+		agent.WithLLMConfig[[]Entity](llm.LLMConfig{
+			Type:        llm.LLMTypeOpenAI,
+			APIKey:      apiKey,
+			Model:       "gpt-4.1",
+			Temperature: 0.0,
+		}),
+		agent.WithBehavior[[]Entity]("You extract every named entity mentioned in the text."),
+	)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// when
+	result, err := entityAgent.Run(ctx, "Ada Lovelace worked with Charles Babbage in London.")
+
+	// then
+	require.NoError(t, err)
+	require.NotNil(t, result.Data)
+
+	names := make([]string, 0, len(*result.Data))
+	for _, entity := range *result.Data {
+		names = append(names, entity.Name)
+	}
+	assert.Contains(t, names, "Ada Lovelace")
+	assert.Contains(t, names, "Charles Babbage")
+	assert.Contains(t, names, "London")
+}
+
+func TestSliceAgentResult_Unwrapped(t *testing.T) {
+	t.Parallel()
+
+	entityAgent, err := agent.NewAgent(
+		agent.WithName[[]Entity]("entity_agent"),
+		agent.WithLLMConfig[[]Entity](testLLMConfig()),
+		agent.WithBehavior[[]Entity]("You extract every named entity mentioned in the text."),
+	)
+	require.NoError(t, err)
+
+	mockLLM := llmtest.NewMockLLM(`{"items": [{"name": "London", "kind": "location"}]}`, endMessage("done"))
+	agent.SetLLM(entityAgent, mockLLM)
+
+	result, err := entityAgent.Run(context.Background(), "I live in London.")
+
+	require.NoError(t, err)
+	assert.Equal(t, []Entity{{Name: "London", Kind: "location"}}, *result.Data)
+
+	schemas := mockLLM.Schemas()
+	require.Len(t, schemas, 1)
+
+	outputSchema, err := schema.GenerateSchema(schemas[0])
+	require.NoError(t, err)
+	assert.Equal(t, "object", outputSchema["type"], "OpenAI requires an object at the schema root")
+	assert.Contains(t, outputSchema["properties"], "items")
+}
@@ -0,0 +1,197 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+type speculativeExecution[T any] struct {
+	branches int
+	scorer   func(*AgentResult[T]) float64
+}
+
+// WithSpeculativeExecution runs every conversation in several concurrent branches and
+// returns the result with the highest score. The run fails only when all branches fail.
+// WithBudget limits the usage of all branches together, and stream callbacks receive only
+// the messages of the selected branch once all branches have finished.
+func WithSpeculativeExecution[T any](branches int, scorer func(*AgentResult[T]) float64) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.speculative = &speculativeExecution[T]{branches: branches, scorer: scorer}
+	}
+}
+
+func (a *Agent[T]) validateSpeculativeExecution() error {
+	if a.speculative == nil {
+		return nil
+	}
+	if err := validation.IntIsPositive(a.speculative.branches, "branches"); err != nil {
+		return err
+	}
+	if a.speculative.scorer == nil {
+		return fmt.Errorf("scorer: %w: value cannot be nil", validation.ErrValidationFailed)
+	}
+
+	return nil
+}
+
+// Branch returns a deep copy of the state which continues the conversation independently.
+// Messages added to the branch are not visible in the state and vice versa.
+func (a *AgentState) Branch() *AgentState {
+	branch := *a
+	branch.Messages = cloneMessages(a.Messages)
+	branch.llmCallDurations = slices.Clone(a.llmCallDurations)
+	branch.toolCallHistory = slices.Clone(a.toolCallHistory)
+	branch.injectedResults = a.injectedResults.clone()
+
+	return &branch
+}
+
+// sharedUsage is the token usage of all branches of a speculative run
+type sharedUsage struct {
+	mu    sync.Mutex
+	total llm.TokenUsage
+}
+
+func (u *sharedUsage) add(usage llm.TokenUsage) {
+	if u == nil {
+		return
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.total = u.total.Add(usage)
+}
+
+// budgetUsage returns the usage WithBudget applies to: the usage of all branches of a
+// speculative run, or the usage of the state
+func (a *AgentState) budgetUsage() llm.TokenUsage {
+	if a.branchesUsage == nil {
+		return a.TotalUsage
+	}
+
+	a.branchesUsage.mu.Lock()
+	defer a.branchesUsage.mu.Unlock()
+
+	return a.branchesUsage.total
+}
+
+// Merge returns the branch chosen by selector, with the tokens consumed by the other
+// branches since they were created from the state added to its TotalUsage, so the cost of
+// the discarded paths stays accounted. It returns the state itself when selector returns nil.
+func (a *AgentState) Merge(branches []*AgentState, selector func([]*AgentState) *AgentState) *AgentState {
+	selected := selector(branches)
+	if selected == nil {
+		return a
+	}
+
+	for _, branch := range branches {
+		if branch == selected {
+			continue
+		}
+		selected.TotalUsage = selected.TotalUsage.Add(llm.TokenUsage{
+			InputTokens:  branch.TotalUsage.InputTokens - a.TotalUsage.InputTokens,
+			OutputTokens: branch.TotalUsage.OutputTokens - a.TotalUsage.OutputTokens,
+		})
+	}
+
+	return selected
+}
+
+// runSpeculative runs the conversation of the state in the configured number of branches
+// and returns the result with the highest score
+func (a *Agent[T]) runSpeculative(ctx context.Context, state *AgentState) (*AgentResult[T], error) {
+	branches := make([]*AgentState, a.speculative.branches)
+	results := make([]*AgentResult[T], len(branches))
+	errs := make([]error, len(branches))
+
+	// the branches don't stream or write steps, the messages of the selected one are
+	// reported once it is known
+	branchAgent := *a
+	branchAgent.output = nil
+	branchCtx := WithStreamCallback(ctx, nil)
+	usage := &sharedUsage{total: state.TotalUsage}
+
+	var wg sync.WaitGroup
+	for i := range branches {
+		branches[i] = state.Branch()
+		branches[i].branchesUsage = usage
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = branchAgent.runLoop(branchCtx, branches[i])
+		}()
+	}
+	wg.Wait()
+
+	resultsByBranch := make(map[*AgentState]*AgentResult[T], len(branches))
+	for i, branch := range branches {
+		if errs[i] == nil {
+			resultsByBranch[branch] = results[i]
+		}
+	}
+
+	selected := state.Merge(branches, func(branches []*AgentState) *AgentState {
+		var (
+			best      *AgentState
+			bestScore float64
+		)
+		for _, branch := range branches {
+			result, ok := resultsByBranch[branch]
+			if !ok {
+				continue
+			}
+			if score := a.speculative.scorer(result); best == nil || score > bestScore {
+				best, bestScore = branch, score
+			}
+		}
+
+		return best
+	})
+	if selected == state {
+		return results[0], errs[0]
+	}
+
+	branchMessages := selected.Messages[len(state.Messages):]
+	*state = *selected
+	state.branchesUsage = nil
+
+	if err := a.reportSteps(ctx, branchMessages); err != nil {
+		return nil, err
+	}
+
+	return resultsByBranch[selected], nil
+}
+
+// reportSteps streams the LLM responses of the selected branch and writes them to the output
+func (a *Agent[T]) reportSteps(ctx context.Context, messages []llm.LLMMessage) error {
+	for _, msg := range messages {
+		if msg.Type != llm.LLMMessageTypeAssistant {
+			continue
+		}
+
+		streamMessage(ctx, msg)
+		if err := a.output.writeStep(msg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func cloneMessages(messages []llm.LLMMessage) []llm.LLMMessage {
+	cloned := make([]llm.LLMMessage, len(messages))
+	for i, msg := range messages {
+		msg.Parts = slices.Clone(msg.Parts)
+		msg.ToolCalls = slices.Clone(msg.ToolCalls)
+		msg.ToolResults = slices.Clone(msg.ToolResults)
+		cloned[i] = msg
+	}
+
+	return cloned
+}
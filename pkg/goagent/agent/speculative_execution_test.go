@@ -0,0 +1,236 @@
+package agent_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+var errBranchFailed = errors.New("branch failed")
+
+// branchLLM ends every conversation right away, fails the call with the given number and
+// returns an increasing sum from every structured output call
+type branchLLM struct {
+	mu               sync.Mutex
+	calls            int
+	failingCall      int
+	structuredOutput int
+}
+
+func (l *branchLLM) Call(_ context.Context, _ []llm.LLMMessage) (llm.LLMMessage, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.calls++
+	if l.calls == l.failingCall || l.failingCall < 0 {
+		return llm.LLMMessage{}, errBranchFailed
+	}
+
+	return llm.LLMMessage{Type: llm.LLMMessageTypeAssistant, Content: "done", End: true}, nil
+}
+
+func (l *branchLLM) CallWithStructuredOutput(_ context.Context, _ []llm.LLMMessage, _ any) (string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.structuredOutput++
+
+	return fmt.Sprintf(`{"sum": %d}`, l.structuredOutput), nil
+}
+
+// endlessLLM never ends the conversation and reports the given usage for every call
+type endlessLLM struct {
+	usage llm.TokenUsage
+	calls atomic.Int64
+}
+
+func (l *endlessLLM) Call(_ context.Context, _ []llm.LLMMessage) (llm.LLMMessage, error) {
+	l.calls.Add(1)
+
+	return llm.LLMMessage{Type: llm.LLMMessageTypeAssistant, Content: "thinking", Usage: l.usage}, nil
+}
+
+func (l *endlessLLM) CallWithStructuredOutput(_ context.Context, _ []llm.LLMMessage, _ any) (string, error) {
+	return `{"sum": 0}`, nil
+}
+
+func newSpeculativeAgent(
+	t *testing.T, branches int, options ...agent.AgentOption[AddNumbersResult],
+) *agent.Agent[AddNumbersResult] {
+	t.Helper()
+
+	scorer := func(result *agent.AgentResult[AddNumbersResult]) float64 {
+		return float64(result.Data.Sum)
+	}
+	speculativeAgent, err := agent.NewAgent(append([]agent.AgentOption[AddNumbersResult]{
+		agent.WithName[AddNumbersResult]("speculative_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithSpeculativeExecution[AddNumbersResult](branches, scorer),
+	}, options...)...)
+	require.NoError(t, err)
+
+	return speculativeAgent
+}
+
+func TestAgentState_Branch(t *testing.T) {
+	t.Parallel()
+
+	// given
+	state := &agent.AgentState{Messages: []llm.LLMMessage{
+		llm.NewLLMMessage(llm.LLMMessageTypeUser, "add 1 and 2"),
+		{Type: llm.LLMMessageTypeAssistant, ToolCalls: []llm.LLMToolCall{addCall("call_1")}},
+	}}
+
+	// when
+	branch := state.Branch()
+	branch.AddMessage(llm.NewLLMMessage(llm.LLMMessageTypeUser, "add 3 and 4"))
+	branch.Messages[1].ToolCalls[0].ID = "call_2"
+
+	// then
+	assert.Len(t, state.Messages, 2)
+	assert.Equal(t, "call_1", state.Messages[1].ToolCalls[0].ID)
+	assert.Len(t, branch.Messages, 3)
+}
+
+func TestAgentState_Merge(t *testing.T) {
+	t.Parallel()
+
+	// given
+	state := &agent.AgentState{TotalUsage: llm.TokenUsage{InputTokens: 100, OutputTokens: 10}}
+	first, second := state.Branch(), state.Branch()
+	first.TotalUsage = first.TotalUsage.Add(llm.TokenUsage{InputTokens: 50, OutputTokens: 5})
+	second.TotalUsage = second.TotalUsage.Add(llm.TokenUsage{InputTokens: 30, OutputTokens: 3})
+
+	// when
+	merged := state.Merge([]*agent.AgentState{first, second}, func(branches []*agent.AgentState) *agent.AgentState {
+		return branches[1]
+	})
+
+	// then
+	assert.Same(t, second, merged)
+	assert.Equal(t, llm.TokenUsage{InputTokens: 180, OutputTokens: 18}, merged.TotalUsage)
+}
+
+func TestAgentState_Merge_NoSelection(t *testing.T) {
+	t.Parallel()
+
+	state := &agent.AgentState{}
+
+	merged := state.Merge([]*agent.AgentState{state.Branch()}, func([]*agent.AgentState) *agent.AgentState {
+		return nil
+	})
+
+	assert.Same(t, state, merged)
+}
+
+func TestWithSpeculativeExecution(t *testing.T) {
+	t.Parallel()
+
+	// given
+	speculativeAgent := newSpeculativeAgent(t, 3)
+	branchLLM := &branchLLM{failingCall: 2}
+
+	// when
+	result, err := speculativeAgent.UsingLLM(branchLLM).Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Data.Sum)
+	assert.Equal(t, 3, branchLLM.calls)
+	assert.Equal(t, 2, branchLLM.structuredOutput)
+}
+
+func TestWithSpeculativeExecution_StreamsSelectedBranch(t *testing.T) {
+	t.Parallel()
+
+	// given
+	speculativeAgent := newSpeculativeAgent(t, 3)
+	var streamed []llm.LLMMessage
+	ctx := agent.WithStreamCallback(context.Background(), func(_ context.Context, msg llm.LLMMessage) {
+		streamed = append(streamed, msg)
+	})
+
+	// when
+	_, err := speculativeAgent.UsingLLM(&branchLLM{failingCall: 2}).Run(ctx, AddNumbers{Num1: 1, Num2: 2})
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, streamed, 1, "only the messages of the selected branch must be streamed")
+	assert.True(t, streamed[0].End)
+}
+
+func TestWithSpeculativeExecution_BudgetOfAllBranches(t *testing.T) {
+	t.Parallel()
+
+	// given
+	const branches = 3
+	speculativeAgent := newSpeculativeAgent(t, branches,
+		agent.WithBudget[AddNumbersResult](1),
+		agent.WithModelPrice[AddNumbersResult](oneDollarPerMillion),
+	)
+	// every call costs 0.3 USD, so a single branch would exceed the budget with its 4th call
+	endless := &endlessLLM{usage: llm.TokenUsage{InputTokens: 300_000}}
+
+	// when
+	_, err := speculativeAgent.UsingLLM(endless).Run(context.Background(), AddNumbers{})
+
+	// then
+	require.ErrorIs(t, err, agent.ErrBudgetExceeded)
+	assert.LessOrEqual(t, endless.calls.Load(), int64(4+branches-1),
+		"the branches must stop once their total usage exceeds the budget")
+}
+
+func TestWithSpeculativeExecution_AllBranchesFail(t *testing.T) {
+	t.Parallel()
+
+	// given
+	speculativeAgent := newSpeculativeAgent(t, 2)
+
+	// when
+	result, err := speculativeAgent.UsingLLM(&branchLLM{failingCall: -1}).Run(context.Background(), AddNumbers{})
+
+	// then
+	require.ErrorIs(t, err, agent.ErrLLMCall)
+	assert.Nil(t, result)
+}
+
+func TestWithSpeculativeExecution_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		branches int
+		scorer   func(*agent.AgentResult[AddNumbersResult]) float64
+	}{
+		{name: "no branches", branches: 0, scorer: func(*agent.AgentResult[AddNumbersResult]) float64 { return 0 }},
+		{name: "nil scorer", branches: 2, scorer: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// when
+			_, err := agent.NewAgent(
+				agent.WithName[AddNumbersResult]("speculative_agent"),
+				agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+				agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+				agent.WithSpeculativeExecution[AddNumbersResult](tt.branches, tt.scorer),
+			)
+
+			// then
+			require.ErrorIs(t, err, validation.ErrValidationFailed)
+			assert.Contains(t, err.Error(), "speculative execution")
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package agent
+
+import (
+	"context"
+
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+// StreamCallback receives the messages of a run as they are produced: every LLM response
+// together with the results of the tools it called. The final result is returned by Run.
+type StreamCallback func(ctx context.Context, msg llm.LLMMessage)
+
+type streamCallbackKey struct{}
+
+// WithStreamCallback returns a context which makes Run report the messages of the run to
+// callback while it runs, e.g. to forward the progress of a long run to a client. The callback
+// is called synchronously from the run, so it should return quickly.
+//
+// Example:
+//
+//	ctx = agent.WithStreamCallback(ctx, func(ctx context.Context, msg llm.LLMMessage) {
+//		fmt.Println(msg.Type, msg.Content)
+//	})
+//	result, err := myAgent.Run(ctx, input)
+func WithStreamCallback(ctx context.Context, callback StreamCallback) context.Context {
+	return context.WithValue(ctx, streamCallbackKey{}, callback)
+}
+
+func streamMessage(ctx context.Context, msg llm.LLMMessage) {
+	if callback, ok := ctx.Value(streamCallbackKey{}).(StreamCallback); ok && callback != nil {
+		callback(ctx, msg)
+	}
+}
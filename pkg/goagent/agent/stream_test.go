@@ -0,0 +1,47 @@
+package agent_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+func TestWithStreamCallback(t *testing.T) {
+	t.Parallel()
+
+	// given
+	streamAgent, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("stream_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithTool[AddNumbersResult]("add", createTestAddTool()),
+	)
+	require.NoError(t, err)
+
+	agent.SetLLM(streamAgent, llmtest.NewMockLLM(`{"sum": 3}`,
+		toolCallMessage(llm.LLMToolCall{ID: "call_1", ToolName: "add", Args: `{"num1": 1, "num2": 2}`}),
+		endMessage("done"),
+	))
+
+	var streamed []llm.LLMMessage
+	ctx := agent.WithStreamCallback(context.Background(), func(_ context.Context, msg llm.LLMMessage) {
+		streamed = append(streamed, msg)
+	})
+
+	// when
+	result, err := streamAgent.Run(ctx, AddNumbers{Num1: 1, Num2: 2})
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, streamed, 2)
+	assert.Equal(t, "call_1", streamed[0].ToolCalls[0].ID)
+	require.Len(t, streamed[0].ToolResults, 1)
+	assert.Equal(t, "call_1", streamed[0].ToolResults[0].GetID())
+	assert.True(t, streamed[1].End)
+	assert.Equal(t, result.Messages[2:4], streamed)
+}
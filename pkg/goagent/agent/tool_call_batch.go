@@ -0,0 +1,226 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+// ErrToolBatch is returned for every call of a batch when the batch returns a different
+// number of results than it received calls
+var ErrToolBatch = errors.New("tool batch error occurred")
+
+// WithToolCallBatch groups the calls of tools created with llm.WithLLMToolBatchCall and
+// dispatches them together with a single BatchCall. A batch is dispatched once it holds
+// batchSize calls or batchTimeout after its first call, whichever comes first. The calls
+// of a batch may come from one LLM message, where consecutive calls of the same tool are
+// submitted together, or from concurrent runs of the agent. The other tools are called
+// one by one as before. Tool limits, timeouts and the tool call log apply to every call
+// of a batch. batchSize and batchTimeout must be positive.
+//
+// Example:
+//
+//	agent.WithToolCallBatch[Answer](20, 10*time.Millisecond)
+func WithToolCallBatch[T any](batchSize int, batchTimeout time.Duration) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.toolCallBatch = &toolCallBatch{
+			size:     batchSize,
+			timeout:  batchTimeout,
+			batchers: make(map[string]*toolBatcher),
+		}
+	}
+}
+
+type toolCallBatch struct {
+	size    int
+	timeout time.Duration
+
+	mu       sync.Mutex
+	batchers map[string]*toolBatcher
+}
+
+func (b *toolCallBatch) validate() error {
+	if b == nil {
+		return nil
+	}
+	if err := validation.IntIsPositive(b.size, "batch size"); err != nil {
+		return err
+	}
+
+	if b.timeout <= 0 {
+		return fmt.Errorf("%w: batch timeout must be positive, got %v", validation.ErrValidationFailed, b.timeout)
+	}
+
+	return nil
+}
+
+// batcher returns the batcher of the tool, which is shared by all runs of the agent
+func (b *toolCallBatch) batcher(name string, call batchCallFunc) *toolBatcher {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	batcher, ok := b.batchers[name]
+	if !ok {
+		batcher = &toolBatcher{size: b.size, timeout: b.timeout, call: call}
+		b.batchers[name] = batcher
+	}
+
+	return batcher
+}
+
+// batches reports whether the calls of the tool are batched
+func (a *Agent[T]) batches(toolName string) bool {
+	if a.toolCallBatch == nil {
+		return false
+	}
+	tool, ok := a.tools[toolName]
+
+	return ok && tool.Batch != nil
+}
+
+// groupToolCallBatches splits the tool calls into groups of consecutive calls of the same
+// batched tool. Every other call gets a group of its own.
+func (a *Agent[T]) groupToolCallBatches(toolCalls []llm.LLMToolCall) [][]llm.LLMToolCall {
+	groups := make([][]llm.LLMToolCall, 0, len(toolCalls))
+	for i, toolCall := range toolCalls {
+		if i > 0 && a.batches(toolCall.ToolName) && toolCalls[i-1].ToolName == toolCall.ToolName {
+			groups[len(groups)-1] = append(groups[len(groups)-1], toolCall)
+
+			continue
+		}
+		groups = append(groups, []llm.LLMToolCall{toolCall})
+	}
+
+	return groups
+}
+
+// callBatched submits the tool call to the batcher of the tool and waits for its result
+func (a *Agent[T]) callBatched(tool llm.LLMTool, toolCall llm.LLMToolCall) (llm.LLMToolResult, error) {
+	batcher := a.toolCallBatch.batcher(tool.Name, func(calls []llm.ToolCallBatch) ([]llm.LLMToolResult, error) {
+		return a.batchCallFunc(tool, calls)
+	})
+
+	return batcher.submit(llm.ToolCallBatch{ID: toolCall.ID, Args: toolCall.Args})
+}
+
+// batchCallFunc calls BatchCall of the tool, converting a panic into a ToolPanicError when
+// panic recovery is enabled
+func (a *Agent[T]) batchCallFunc(
+	tool llm.LLMTool,
+	calls []llm.ToolCallBatch,
+) (results []llm.LLMToolResult, err error) {
+	if a.panicRecovery {
+		defer func() {
+			if value := recover(); value != nil {
+				panicErr := &ToolPanicError{ToolName: tool.Name, Value: value, Stack: debug.Stack()}
+				slog.Error("recovered from tool panic",
+					"agent", a.name,
+					"tool", tool.Name,
+					"batch_size", len(calls),
+					"panic", fmt.Sprint(value),
+					"stack", string(panicErr.Stack),
+				)
+
+				results, err = nil, panicErr
+			}
+		}()
+	}
+
+	results, err = tool.Batch.BatchCall(calls)
+	if err == nil && len(results) != len(calls) {
+		return nil, fmt.Errorf("%w: %s returned %d results for %d calls",
+			ErrToolBatch, tool.Name, len(results), len(calls))
+	}
+
+	return results, err
+}
+
+type batchCallFunc func(calls []llm.ToolCallBatch) ([]llm.LLMToolResult, error)
+
+type batchedToolCall struct {
+	call    llm.ToolCallBatch
+	outcome chan toolCallOutcome
+}
+
+// toolBatcher collects the calls of one tool until the batch is full or its timeout expires
+type toolBatcher struct {
+	size    int
+	timeout time.Duration
+	call    batchCallFunc
+
+	mu      sync.Mutex
+	pending *[]batchedToolCall
+	timer   *time.Timer
+}
+
+func (b *toolBatcher) submit(call llm.ToolCallBatch) (llm.LLMToolResult, error) {
+	outcome := make(chan toolCallOutcome, 1)
+
+	b.mu.Lock()
+	if b.pending == nil {
+		pending := make([]batchedToolCall, 0, b.size)
+		b.pending = &pending
+		b.timer = time.AfterFunc(b.timeout, func() { b.flush(&pending) })
+	}
+	*b.pending = append(*b.pending, batchedToolCall{call: call, outcome: outcome})
+
+	var full []batchedToolCall
+	if len(*b.pending) >= b.size {
+		full = b.take()
+	}
+	b.mu.Unlock()
+
+	if full != nil {
+		b.dispatch(full)
+	}
+	res := <-outcome
+
+	return res.result, res.err
+}
+
+// flush dispatches the pending batch when its timeout expires, unless it was already
+// dispatched because it got full
+func (b *toolBatcher) flush(pending *[]batchedToolCall) {
+	b.mu.Lock()
+	if b.pending != pending {
+		b.mu.Unlock()
+
+		return
+	}
+	calls := b.take()
+	b.mu.Unlock()
+
+	b.dispatch(calls)
+}
+
+// take removes the pending batch. It must be called with mu held.
+func (b *toolBatcher) take() []batchedToolCall {
+	calls := *b.pending
+	b.pending = nil
+	b.timer.Stop()
+
+	return calls
+}
+
+func (b *toolBatcher) dispatch(calls []batchedToolCall) {
+	batch := make([]llm.ToolCallBatch, len(calls))
+	for i, call := range calls {
+		batch[i] = call.call
+	}
+
+	results, err := b.call(batch)
+	for i, call := range calls {
+		if err != nil {
+			call.outcome <- toolCallOutcome{err: err}
+
+			continue
+		}
+		call.outcome <- toolCallOutcome{result: results[i]}
+	}
+}
@@ -0,0 +1,309 @@
+package agent_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+const testBatchTimeout = 20 * time.Millisecond
+
+// batchAddTool adds the numbers of every call of a batch and records the batch sizes
+type batchAddTool struct {
+	mu      sync.Mutex
+	batches []int
+	err     error
+	// missingResults drops the given number of results from every batch
+	missingResults int
+}
+
+func (b *batchAddTool) BatchCall(calls []llm.ToolCallBatch) ([]llm.LLMToolResult, error) {
+	b.mu.Lock()
+	b.batches = append(b.batches, len(calls))
+	b.mu.Unlock()
+
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	results := make([]llm.LLMToolResult, 0, len(calls))
+	for _, call := range calls {
+		var params AddToolParams
+		if err := json.Unmarshal([]byte(call.Args), &params); err != nil {
+			return nil, err
+		}
+		results = append(results, AddToolResult{
+			BaseLLMToolResult: llm.BaseLLMToolResult{ID: call.ID},
+			Sum:               params.Num1 + params.Num2,
+		})
+	}
+
+	return results[b.missingResults:], nil
+}
+
+func (b *batchAddTool) batchSizes() []int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]int(nil), b.batches...)
+}
+
+func createBatchAddTool(t *testing.T, batch *batchAddTool) llm.LLMTool {
+	t.Helper()
+
+	tool, err := llm.NewLLMTool(
+		llm.WithLLMToolName("add"),
+		llm.WithLLMToolDescription("Adds two numbers"),
+		llm.WithLLMToolParametersSchema[AddToolParams](),
+		llm.WithLLMToolCall(func(callID string, params AddToolParams) (AddToolResult, error) {
+			return AddToolResult{
+				BaseLLMToolResult: llm.BaseLLMToolResult{ID: callID},
+				Sum:               params.Num1 + params.Num2,
+			}, nil
+		}),
+		llm.WithLLMToolBatchCall(batch),
+	)
+	require.NoError(t, err)
+
+	return tool
+}
+
+func addCallOf(id string, num1, num2 float64) llm.LLMToolCall {
+	return llm.LLMToolCall{ID: id, ToolName: "add", Args: fmt.Sprintf(`{"num1": %v, "num2": %v}`, num1, num2)}
+}
+
+func createBatchAgent(
+	t *testing.T, batch *batchAddTool, options ...agent.AgentOption[AddNumbersResult],
+) *agent.Agent[AddNumbersResult] {
+	t.Helper()
+
+	batchAgent, err := agent.NewAgent(append([]agent.AgentOption[AddNumbersResult]{
+		agent.WithName[AddNumbersResult]("batch_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithTool[AddNumbersResult]("add", createBatchAddTool(t, batch)),
+		agent.WithToolLimit[AddNumbersResult]("add", 10),
+	}, options...)...)
+	require.NoError(t, err)
+
+	return batchAgent
+}
+
+func TestWithToolCallBatch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		batchSize   int
+		wantBatches []int
+	}{
+		{name: "full batch", batchSize: 3, wantBatches: []int{3}},
+		{name: "batch size exceeded", batchSize: 2, wantBatches: []int{2, 1}},
+		{name: "batch timeout", batchSize: 10, wantBatches: []int{3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			batch := &batchAddTool{}
+			batchAgent := createBatchAgent(t, batch,
+				agent.WithToolCallBatch[AddNumbersResult](tt.batchSize, testBatchTimeout))
+			agent.SetLLM(batchAgent, llmtest.NewMockLLM(`{"sum": 0}`,
+				toolCallMessage(addCallOf("call_1", 1, 2), addCallOf("call_2", 3, 4), addCallOf("call_3", 5, 6)),
+				endMessage("done"),
+			))
+
+			// when
+			result, err := batchAgent.Run(context.Background(), AddNumbers{})
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantBatches, batch.batchSizes())
+			assert.Equal(t, []llm.LLMToolResult{
+				AddToolResult{BaseLLMToolResult: llm.BaseLLMToolResult{ID: "call_1"}, Sum: 3},
+				AddToolResult{BaseLLMToolResult: llm.BaseLLMToolResult{ID: "call_2"}, Sum: 7},
+				AddToolResult{BaseLLMToolResult: llm.BaseLLMToolResult{ID: "call_3"}, Sum: 11},
+			}, result.Messages[2].ToolResults)
+		})
+	}
+}
+
+func TestWithToolCallBatch_NonBatchableTools(t *testing.T) {
+	t.Parallel()
+
+	multiplyTool := llm.MustNewLLMToolFromFunc("multiply", "Multiplies two numbers",
+		func(callID string, params AddToolParams) (AddToolResult, error) {
+			return AddToolResult{
+				BaseLLMToolResult: llm.BaseLLMToolResult{ID: callID},
+				Sum:               params.Num1 * params.Num2,
+			}, nil
+		},
+	)
+
+	t.Run("only consecutive calls are batched", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		batch := &batchAddTool{}
+		batchAgent := createBatchAgent(t, batch,
+			agent.WithTool[AddNumbersResult]("multiply", multiplyTool),
+			agent.WithToolCallBatch[AddNumbersResult](10, testBatchTimeout),
+		)
+		agent.SetLLM(batchAgent, llmtest.NewMockLLM(`{"sum": 0}`,
+			toolCallMessage(
+				addCallOf("call_1", 1, 2),
+				llm.LLMToolCall{ID: "call_2", ToolName: "multiply", Args: `{"num1": 3, "num2": 4}`},
+				addCallOf("call_3", 5, 6),
+			),
+			endMessage("done"),
+		))
+
+		// when
+		result, err := batchAgent.Run(context.Background(), AddNumbers{})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 1}, batch.batchSizes())
+		assert.Equal(t, []llm.LLMToolResult{
+			AddToolResult{BaseLLMToolResult: llm.BaseLLMToolResult{ID: "call_1"}, Sum: 3},
+			AddToolResult{BaseLLMToolResult: llm.BaseLLMToolResult{ID: "call_2"}, Sum: 12},
+			AddToolResult{BaseLLMToolResult: llm.BaseLLMToolResult{ID: "call_3"}, Sum: 11},
+		}, result.Messages[2].ToolResults)
+	})
+
+	t.Run("batching disabled", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		batch := &batchAddTool{}
+		batchAgent := createBatchAgent(t, batch)
+		agent.SetLLM(batchAgent, llmtest.NewMockLLM(`{"sum": 0}`,
+			toolCallMessage(addCallOf("call_1", 1, 2), addCallOf("call_2", 3, 4)),
+			endMessage("done"),
+		))
+
+		// when
+		result, err := batchAgent.Run(context.Background(), AddNumbers{})
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, batch.batchSizes())
+		assert.Len(t, result.Messages[2].ToolResults, 2)
+	})
+}
+
+func TestWithToolCallBatch_ConcurrentRuns(t *testing.T) {
+	t.Parallel()
+
+	// given
+	batch := &batchAddTool{}
+	batchAgent := createBatchAgent(t, batch, agent.WithToolCallBatch[AddNumbersResult](2, time.Minute))
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			mockLLM := llmtest.NewMockLLM(`{"sum": 3}`, toolCallMessage(addCallOf("call_1", 1, 2)), endMessage("done"))
+			_, errs[i] = batchAgent.UsingLLM(mockLLM).Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+		}()
+	}
+
+	// when
+	wg.Wait()
+
+	// then
+	require.NoError(t, errors.Join(errs...))
+	assert.Equal(t, []int{2}, batch.batchSizes())
+}
+
+func TestWithToolCallBatch_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		batch     *batchAddTool
+		wantError string
+	}{
+		{
+			name:      "batch call error",
+			batch:     &batchAddTool{err: errors.New("database unavailable")},
+			wantError: "database unavailable",
+		},
+		{
+			name:      "missing results",
+			batch:     &batchAddTool{missingResults: 1},
+			wantError: agent.ErrToolBatch.Error() + ": add returned 1 results for 2 calls",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			batchAgent := createBatchAgent(t, tt.batch, agent.WithToolCallBatch[AddNumbersResult](2, testBatchTimeout))
+			agent.SetLLM(batchAgent, llmtest.NewMockLLM(`{"sum": 0}`,
+				toolCallMessage(addCallOf("call_1", 1, 2), addCallOf("call_2", 3, 4)),
+				endMessage("done"),
+			))
+
+			// when
+			result, err := batchAgent.Run(context.Background(), AddNumbers{})
+
+			// then
+			require.NoError(t, err)
+			toolResults := result.Messages[2].ToolResults
+			require.Len(t, toolResults, 2)
+			for _, toolResult := range toolResults {
+				errorResult, ok := toolResult.(llm.ErrorLLMToolResult)
+				require.True(t, ok, "expected an error result, got %T", toolResult)
+				assert.Contains(t, errorResult.Error, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestWithToolCallBatch_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		batchSize    int
+		batchTimeout time.Duration
+	}{
+		{name: "zero batch size", batchSize: 0, batchTimeout: testBatchTimeout},
+		{name: "negative batch timeout", batchSize: 2, batchTimeout: -time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := agent.NewAgent(
+				agent.WithName[AddNumbersResult]("batch_agent"),
+				agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+				agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+				agent.WithToolCallBatch[AddNumbersResult](tt.batchSize, tt.batchTimeout),
+			)
+
+			require.ErrorIs(t, err, validation.ErrValidationFailed)
+			assert.Contains(t, err.Error(), "tool call batch")
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package agent
+
+import (
+	"time"
+
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+// ToolCallRecord describes a tool execution of a run
+type ToolCallRecord struct {
+	ToolName string `json:"tool_name"`
+	CallID   string `json:"call_id"`
+	// Args are the JSON encoded arguments the tool ran with, after tool call transformers
+	Args   string            `json:"args"`
+	Result llm.LLMToolResult `json:"result,omitempty"`
+	// DurationMs is how long the tool ran, in milliseconds
+	DurationMs int64 `json:"duration_ms"`
+	// Timestamp is the time the tool call started, in UTC
+	Timestamp time.Time `json:"timestamp"`
+	// Error is the error of a failed tool call, empty on success
+	Error string `json:"error,omitempty"`
+}
+
+func newToolCallRecord(
+	start time.Time,
+	toolCall llm.LLMToolCall,
+	toolRes llm.LLMToolResult,
+	callErr error,
+) ToolCallRecord {
+	record := ToolCallRecord{
+		ToolName:   toolCall.ToolName,
+		CallID:     toolCall.ID,
+		Args:       toolCall.Args,
+		Result:     toolRes,
+		DurationMs: time.Since(start).Milliseconds(),
+		Timestamp:  start.UTC(),
+	}
+	if callErr != nil {
+		record.Error = callErr.Error()
+	}
+
+	return record
+}
+
+// ToolCallHistory returns every tool execution of the run so far, in the order the calls
+// were requested. Calls to unknown tools and calls rejected by a tool limit are not
+// executed and are not included.
+func (a *AgentState) ToolCallHistory() []ToolCallRecord {
+	history := make([]ToolCallRecord, len(a.toolCallHistory))
+	copy(history, a.toolCallHistory)
+
+	return history
+}
+
+func (a *AgentState) recordToolCall(record ToolCallRecord) {
+	a.toolCallHistory = append(a.toolCallHistory, record)
+}
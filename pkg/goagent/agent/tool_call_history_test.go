@@ -0,0 +1,81 @@
+package agent_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+func TestAgentState_ToolCallHistory(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		parallel bool
+	}{
+		{"sequential", false},
+		{"parallel", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			var state *agent.AgentState
+			historyAgent, err := agent.NewAgent(
+				agent.WithName[AddNumbersResult]("history_agent"),
+				agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+				agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+				agent.WithTool[AddNumbersResult]("add", createTestAddTool()),
+				agent.WithParallelToolCalls[AddNumbersResult](tt.parallel),
+				agent.WithMiddleware[AddNumbersResult](func(
+					_ context.Context, s *agent.AgentState, msg llm.LLMMessage,
+				) (llm.LLMMessage, error) {
+					state = s
+
+					return msg, nil
+				}),
+			)
+			require.NoError(t, err)
+
+			mockLLM := llmtest.NewMockLLM(`{"sum": 3}`,
+				toolCallMessage(
+					llm.LLMToolCall{ID: "call_1", ToolName: "add", Args: `{"num1": 1, "num2": 2}`},
+					llm.LLMToolCall{ID: "call_2", ToolName: "unknown", Args: `{}`},
+					llm.LLMToolCall{ID: "call_3", ToolName: "add", Args: `not json`},
+				),
+				endMessage("done"),
+			)
+
+			// when
+			_, err = historyAgent.UsingLLM(mockLLM).Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+
+			// then
+			require.NoError(t, err)
+			require.NotNil(t, state)
+
+			history := state.ToolCallHistory()
+			require.Len(t, history, 2, "the call to the unknown tool is not executed")
+
+			assert.Equal(t, "add", history[0].ToolName)
+			assert.Equal(t, "call_1", history[0].CallID)
+			assert.JSONEq(t, `{"num1": 1, "num2": 2}`, history[0].Args)
+			assert.Empty(t, history[0].Error)
+			assert.False(t, history[0].Timestamp.IsZero())
+			assert.GreaterOrEqual(t, history[0].DurationMs, int64(0))
+			addResult, ok := history[0].Result.(AddToolResult)
+			require.True(t, ok)
+			assert.InDelta(t, 3, addResult.Sum, 0)
+
+			assert.Equal(t, "call_3", history[1].CallID)
+			assert.NotEmpty(t, history[1].Error)
+			assert.Nil(t, history[1].Result)
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package agent
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+var toolCallLogHeader = []string{
+	"timestamp", "agent_name", "tool_name", "call_id", "args_json", "result_json", "duration_ms", "error",
+	"agent_version",
+}
+
+// toolCallLog writes one CSV row per tool call. It is safe for concurrent use,
+// so it can be shared by parallel tool calls and copies of the agent.
+type toolCallLog struct {
+	mu            sync.Mutex
+	buffer        *bufio.Writer
+	csv           *csv.Writer
+	headerWritten bool
+}
+
+// WithToolCallLog writes a CSV audit log row to w for every tool call, with the columns
+// timestamp, agent_name, tool_name, call_id, args_json, result_json, duration_ms, error and
+// agent_version, which is empty without WithAgentVersioning.
+// A header row is written before the first call. Rows are flushed as they are written.
+//
+// Example:
+//
+//	logFile, err := os.Create("tool_calls.csv")
+//	// ...
+//	agent.WithToolCallLog[Result](logFile)
+func WithToolCallLog[T any](w io.Writer) AgentOption[T] {
+	return func(a *Agent[T]) {
+		buffer := bufio.NewWriter(w)
+		a.toolCallLog = &toolCallLog{
+			buffer: buffer,
+			csv:    csv.NewWriter(buffer),
+		}
+	}
+}
+
+// runTool applies the tool call transformers, waits for the tool rate limit, invokes the tool,
+// applies the tool result transformers and records the call in the tool call log, if configured.
+// The returned record describes the execution. A result injected with WithInjectedToolResult
+// replaces all of the steps except the logging.
+func (a *Agent[T]) runTool(
+	ctx context.Context,
+	state *AgentState,
+	tool llm.LLMTool,
+	toolCall llm.LLMToolCall,
+) (llm.LLMToolResult, ToolCallRecord, error) {
+	start := time.Now()
+
+	toolRes, injected := state.injectedResults.take(toolCall.ID)
+	var err error
+	if !injected {
+		toolCall, err = a.transformToolCall(toolCall)
+		if err == nil {
+			toolRes, err = a.executeToolOnce(ctx, state, tool, toolCall)
+		}
+	}
+
+	if a.toolCallLog != nil {
+		a.toolCallLog.write(start, a.name, a.version, toolCall, toolRes, err)
+	}
+
+	return toolRes, newToolCallRecord(start, toolCall, toolRes, err), err
+}
+
+// executeTool waits for the rate limit of the tool, calls it and transforms its result
+func (a *Agent[T]) executeTool(
+	ctx context.Context,
+	tool llm.LLMTool,
+	toolCall llm.LLMToolCall,
+) (llm.LLMToolResult, error) {
+	if err := waitToolRateLimit(ctx, tool); err != nil {
+		return nil, err
+	}
+
+	toolRes, err := a.invokeTool(tool, toolCall)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.transformToolResult(toolCall.ToolName, toolRes)
+}
+
+func (l *toolCallLog) write(
+	start time.Time,
+	agentName string,
+	agentVersion string,
+	toolCall llm.LLMToolCall,
+	toolRes llm.LLMToolResult,
+	callErr error,
+) {
+	duration := time.Since(start)
+
+	resultJSON := ""
+	if toolRes != nil {
+		if data, err := llm.MarshalToolResult(toolRes); err == nil {
+			resultJSON = string(data)
+		} else {
+			resultJSON = fmt.Sprintf("failed to marshal result: %v", err)
+		}
+	}
+
+	errorText := ""
+	if callErr != nil {
+		errorText = callErr.Error()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.headerWritten {
+		l.headerWritten = true
+		if err := l.csv.Write(toolCallLogHeader); err != nil {
+			slog.Warn("failed to write tool call log header", "error", err)
+		}
+	}
+
+	if err := l.csv.Write([]string{
+		start.UTC().Format(time.RFC3339Nano),
+		agentName,
+		toolCall.ToolName,
+		toolCall.ID,
+		toolCall.Args,
+		resultJSON,
+		strconv.FormatInt(duration.Milliseconds(), 10),
+		errorText,
+		agentVersion,
+	}); err != nil {
+		slog.Warn("failed to write tool call log row", "tool", toolCall.ToolName, "error", err)
+	}
+
+	l.csv.Flush()
+	if err := l.buffer.Flush(); err != nil {
+		slog.Warn("failed to flush tool call log", "error", err)
+	}
+}
@@ -0,0 +1,61 @@
+package agent_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+func TestWithToolCallLog(t *testing.T) {
+	t.Parallel()
+
+	var logBuffer bytes.Buffer
+
+	logAgent, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("log_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithTool[AddNumbersResult]("add", createTestAddTool()),
+		agent.WithToolCallLog[AddNumbersResult](&logBuffer),
+	)
+	require.NoError(t, err)
+
+	agent.SetLLM(logAgent, llmtest.NewMockLLM(`{"sum": 3}`,
+		toolCallMessage(
+			llm.LLMToolCall{ID: "call_1", ToolName: "add", Args: `{"num1": 1, "num2": 2}`},
+			llm.LLMToolCall{ID: "call_2", ToolName: "add", Args: `not json`},
+		),
+		endMessage("done"),
+	))
+
+	_, err = logAgent.Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+	require.NoError(t, err)
+
+	rows, err := csv.NewReader(&logBuffer).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+
+	assert.Equal(t, []string{
+		"timestamp", "agent_name", "tool_name", "call_id", "args_json", "result_json", "duration_ms", "error",
+		"agent_version",
+	}, rows[0])
+
+	assert.Equal(t, "log_agent", rows[1][1])
+	assert.Equal(t, "add", rows[1][2])
+	assert.Equal(t, "call_1", rows[1][3])
+	assert.JSONEq(t, `{"num1": 1, "num2": 2}`, rows[1][4])
+	assert.JSONEq(t, `{"id": "call_1", "sum": 3}`, rows[1][5])
+	assert.Empty(t, rows[1][7])
+	assert.Empty(t, rows[1][8])
+
+	assert.Equal(t, "call_2", rows[2][3])
+	assert.Empty(t, rows[2][5])
+	assert.Contains(t, rows[2][7], llm.ErrInvalidArguments.Error())
+}
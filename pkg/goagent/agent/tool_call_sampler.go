@@ -0,0 +1,42 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+)
+
+type sampledKey struct{}
+
+// WithToolCallSampler traces only a fraction of runs, between 0 and 1, e.g. 0.01 traces 1%
+// of them. Unsampled runs get no "agent.run" span and no per-message debug logs, metrics are
+// kept for all runs. The decision is stored in AgentState.Sampled.
+func WithToolCallSampler[T any](sampleRate float64) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.sampleRate = &sampleRate
+	}
+}
+
+func (a *Agent[T]) validateToolCallSampler() error {
+	if a.sampleRate == nil || (*a.sampleRate >= 0 && *a.sampleRate <= 1) {
+		return nil
+	}
+
+	return fmt.Errorf("%w: sample rate %v must be between 0 and 1", validation.ErrValidationFailed, *a.sampleRate)
+}
+
+// withSamplingDecision decides whether the run of ctx is sampled and stores the decision in ctx
+func (a *Agent[T]) withSamplingDecision(ctx context.Context) context.Context {
+	sampled := a.sampleRate == nil || rand.Float64() < *a.sampleRate //nolint:gosec // sampling needs no crypto
+
+	return context.WithValue(ctx, sampledKey{}, sampled)
+}
+
+// isSampled reports whether the run of ctx is sampled. Runs without a decision are sampled.
+func isSampled(ctx context.Context) bool {
+	sampled, ok := ctx.Value(sampledKey{}).(bool)
+
+	return !ok || sampled
+}
@@ -0,0 +1,114 @@
+package agent_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+func createSampledAgent(
+	t *testing.T, cfg agent.ObservabilityConfig, options ...agent.AgentOption[AddNumbersResult],
+) (*agent.Agent[AddNumbersResult], *[]bool) {
+	t.Helper()
+
+	var sampled []bool
+	recordSampled := func(_ context.Context, state *agent.AgentState, msg llm.LLMMessage) (llm.LLMMessage, error) {
+		sampled = append(sampled, state.Sampled)
+
+		return msg, nil
+	}
+
+	sampledAgent, err := agent.NewAgent(append([]agent.AgentOption[AddNumbersResult]{
+		agent.WithName[AddNumbersResult]("sampled_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithObservabilityMiddleware[AddNumbersResult](cfg),
+		agent.WithMiddleware[AddNumbersResult](recordSampled),
+	}, options...)...)
+	require.NoError(t, err)
+
+	return sampledAgent, &sampled
+}
+
+func TestWithToolCallSampler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		options     []agent.AgentOption[AddNumbersResult]
+		wantSampled bool
+	}{
+		{name: "no sampler", options: nil, wantSampled: true},
+		{
+			name:        "always",
+			options:     []agent.AgentOption[AddNumbersResult]{agent.WithToolCallSampler[AddNumbersResult](1)},
+			wantSampled: true,
+		},
+		{
+			name:        "never",
+			options:     []agent.AgentOption[AddNumbersResult]{agent.WithToolCallSampler[AddNumbersResult](0)},
+			wantSampled: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			var logs bytes.Buffer
+			spans, tracerProvider := newSpanRecorder()
+			registry := prometheus.NewRegistry()
+
+			cfg := agent.NewDefaultObservabilityConfig("calculator_service")
+			cfg.Logger = slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+			cfg.TracerProvider = tracerProvider
+			cfg.MetricsRegisterer = registry
+
+			sampledAgent, sampled := createSampledAgent(t, cfg, tt.options...)
+			mockLLM := llmtest.NewMockLLM(`{"sum": 3}`, endMessage("done"))
+
+			// when
+			_, err := sampledAgent.UsingLLM(mockLLM).Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, []bool{tt.wantSampled}, *sampled)
+			assert.InDelta(t, 1, metricValue(t, registry, "goagent_runs_total"), 0)
+			assert.Contains(t, logs.String(), "agent run finished")
+
+			if tt.wantSampled {
+				assert.Len(t, spans.Ended(), 1)
+				assert.Contains(t, logs.String(), "LLM message received")
+			} else {
+				assert.Empty(t, spans.Ended())
+				assert.NotContains(t, logs.String(), "LLM message received")
+			}
+		})
+	}
+}
+
+func TestWithToolCallSampler_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, rate := range []float64{-0.1, 1.5} {
+		_, err := agent.NewAgent(
+			agent.WithName[AddNumbersResult]("sampled_agent"),
+			agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+			agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+			agent.WithToolCallSampler[AddNumbersResult](rate),
+		)
+
+		require.ErrorIs(t, err, validation.ErrValidationFailed, "sample rate %v", rate)
+		assert.Contains(t, err.Error(), "tool call sampler")
+	}
+}
@@ -0,0 +1,50 @@
+package agent
+
+import (
+	"fmt"
+
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+// ToolCallTransformer modifies a tool call before the tool is executed
+type ToolCallTransformer func(toolCall llm.LLMToolCall) (llm.LLMToolCall, error)
+
+// WithToolCallTransformer registers a function which normalizes the arguments of every call
+// to the named tool before the tool runs, e.g. to convert numbers sent as strings or to parse
+// dates into ISO 8601 format. Transformers of the same tool run in the order they were added.
+// The ID and tool name of the returned call are ignored. When fn returns an error, the tool
+// is not called and the error is reported to the LLM as a tool error.
+//
+// Example:
+//
+//	agent.WithToolCallTransformer[Result]("create_event", func(call llm.LLMToolCall) (llm.LLMToolCall, error) {
+//		args, err := call.ArgsMap()
+//		if err != nil {
+//			return call, err
+//		}
+//		// ... normalize args["date"]
+//		return llm.NewLLMToolCallFromMap(call.ID, call.ToolName, args)
+//	})
+func WithToolCallTransformer[T any](name string, fn ToolCallTransformer) AgentOption[T] {
+	return func(a *Agent[T]) {
+		if a.toolCallTransformers == nil {
+			a.toolCallTransformers = make(map[string][]ToolCallTransformer)
+		}
+		a.toolCallTransformers[name] = append(a.toolCallTransformers[name], fn)
+	}
+}
+
+func (a *Agent[T]) transformToolCall(toolCall llm.LLMToolCall) (llm.LLMToolCall, error) {
+	for _, transform := range a.toolCallTransformers[toolCall.ToolName] {
+		transformed, err := transform(toolCall)
+		if err != nil {
+			return toolCall, fmt.Errorf("failed to transform tool call: %w", err)
+		}
+
+		transformed.ID = toolCall.ID
+		transformed.ToolName = toolCall.ToolName
+		toolCall = transformed
+	}
+
+	return toolCall, nil
+}
@@ -0,0 +1,129 @@
+package agent_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+var errNotANumber = errors.New("not a number")
+
+// numbersFromStrings converts numeric arguments sent as strings into numbers
+func numbersFromStrings(toolCall llm.LLMToolCall) (llm.LLMToolCall, error) {
+	args, err := toolCall.ArgsMap()
+	if err != nil {
+		return toolCall, err
+	}
+
+	for name, value := range args {
+		if text, ok := value.(string); ok {
+			number, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				return toolCall, errNotANumber
+			}
+			args[name] = number
+		}
+	}
+
+	return llm.NewLLMToolCallFromMap("ignored", "ignored", args)
+}
+
+func TestWithToolCallTransformer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		args      string
+		expectSum float64
+		expectErr bool
+	}{
+		{"numbers as strings", `{"num1": "1", "num2": "2.5"}`, 3.5, false},
+		{"numbers unchanged", `{"num1": 1, "num2": 2}`, 3, false},
+		{"transformer error", `{"num1": "one", "num2": 2}`, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			transformAgent, err := agent.NewAgent(
+				agent.WithName[AddNumbersResult]("transform_agent"),
+				agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+				agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+				agent.WithTool[AddNumbersResult]("add", createTestAddTool()),
+				agent.WithToolCallTransformer[AddNumbersResult]("add", numbersFromStrings),
+			)
+			require.NoError(t, err)
+
+			mockLLM := llmtest.NewMockLLM(`{"sum": 3}`,
+				toolCallMessage(llm.LLMToolCall{ID: "call_1", ToolName: "add", Args: tt.args}),
+				endMessage("done"),
+			)
+
+			// when
+			result, err := transformAgent.UsingLLM(mockLLM).Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+
+			// then
+			require.NoError(t, err)
+			toolResult := result.Messages[2].ToolResults[0]
+			assert.Equal(t, "call_1", toolResult.GetID())
+			if tt.expectErr {
+				errorResult, ok := toolResult.(llm.ErrorLLMToolResult)
+				require.True(t, ok)
+				assert.Contains(t, errorResult.Error, agent.ErrToolError.Error())
+				assert.Contains(t, errorResult.Error, errNotANumber.Error())
+			} else {
+				addResult, ok := toolResult.(AddToolResult)
+				require.True(t, ok)
+				assert.InDelta(t, tt.expectSum, addResult.Sum, 0)
+			}
+		})
+	}
+}
+
+func TestWithToolCallTransformer_Chained(t *testing.T) {
+	t.Parallel()
+
+	// given
+	double := func(toolCall llm.LLMToolCall) (llm.LLMToolCall, error) {
+		args, err := toolCall.ArgsMap()
+		if err != nil {
+			return toolCall, err
+		}
+		num, _ := args["num1"].(float64)
+		args["num1"] = num * 2
+
+		return llm.NewLLMToolCallFromMap(toolCall.ID, toolCall.ToolName, args)
+	}
+	transformAgent, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("transform_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithTool[AddNumbersResult]("add", createTestAddTool()),
+		agent.WithToolCallTransformer[AddNumbersResult]("add", numbersFromStrings),
+		agent.WithToolCallTransformer[AddNumbersResult]("add", double),
+	)
+	require.NoError(t, err)
+
+	mockLLM := llmtest.NewMockLLM(`{"sum": 4}`,
+		toolCallMessage(llm.LLMToolCall{ID: "call_1", ToolName: "add", Args: `{"num1": "1", "num2": "2"}`}),
+		endMessage("done"),
+	)
+
+	// when
+	result, err := transformAgent.UsingLLM(mockLLM).Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+
+	// then
+	require.NoError(t, err)
+	addResult, ok := result.Messages[2].ToolResults[0].(AddToolResult)
+	require.True(t, ok)
+	assert.InDelta(t, 4, addResult.Sum, 0)
+}
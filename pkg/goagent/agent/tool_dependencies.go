@@ -0,0 +1,96 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+)
+
+// ErrToolDependencyNotMet is returned to the LLM when it calls a tool before the tools
+// it depends on, see WithToolDependencyGraph
+var ErrToolDependencyNotMet = errors.New("tool dependency not met")
+
+// WithToolDependencyGraph enforces an order of tool calls within a run. deps maps a tool name
+// to the tools which must have been called successfully at least once before it, e.g.
+// deps["query"] = []string{"authenticate"}. A call which violates the order is rejected without
+// running the tool, and the LLM gets an error naming the missing tools so it can call them first.
+//
+// With WithParallelToolCalls, tool calls of one turn run concurrently, so dependencies must be
+// called in an earlier turn. NewAgent fails when the graph has a cycle or names a tool which
+// is not registered.
+//
+// Example:
+//
+//	agent.WithToolDependencyGraph[Report](map[string][]string{
+//		"query": {"authenticate"},
+//	})
+func WithToolDependencyGraph[T any](deps map[string][]string) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.toolDependencies = deps
+	}
+}
+
+// checkToolDependencies returns an error naming the dependencies of the tool which were not
+// called yet in the run
+func (a *Agent[T]) checkToolDependencies(name string, usage map[string]int) error {
+	var missing []string
+	for _, dependency := range a.toolDependencies[name] {
+		if usage[dependency] == 0 {
+			missing = append(missing, dependency)
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%w: call %s before %s", ErrToolDependencyNotMet, strings.Join(missing, ", "), name)
+}
+
+func (a *Agent[T]) validateToolDependencies() error {
+	for _, name := range slices.Sorted(maps.Keys(a.toolDependencies)) {
+		for _, dependency := range a.toolDependencies[name] {
+			if _, ok := a.tools[dependency]; !ok {
+				return fmt.Errorf("%w: %s depends on unknown tool %s",
+					validation.ErrValidationFailed, name, dependency)
+			}
+		}
+	}
+
+	visited := make(map[string]bool)
+	inPath := make(map[string]bool)
+
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		if inPath[name] {
+			return fmt.Errorf("%w: dependency cycle %s", validation.ErrValidationFailed,
+				strings.Join(append(path, name), " -> "))
+		}
+		if visited[name] {
+			return nil
+		}
+
+		visited[name] = true
+		inPath[name] = true
+		for _, dependency := range a.toolDependencies[name] {
+			if err := visit(dependency, append(path, name)); err != nil {
+				return err
+			}
+		}
+		inPath[name] = false
+
+		return nil
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(a.toolDependencies)) {
+		if err := visit(name, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
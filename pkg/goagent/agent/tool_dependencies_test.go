@@ -0,0 +1,134 @@
+package agent_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+func createNamedAddTool(name string) llm.LLMTool {
+	tool := createTestAddTool()
+	tool.Name = name
+
+	return tool
+}
+
+func createDependencyAgent(
+	t *testing.T, deps map[string][]string, opts ...agent.AgentOption[AddNumbersResult],
+) (*agent.Agent[AddNumbersResult], error) {
+	t.Helper()
+
+	opts = append([]agent.AgentOption[AddNumbersResult]{
+		agent.WithName[AddNumbersResult]("dependency_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You query data."),
+		agent.WithTool[AddNumbersResult]("authenticate", createNamedAddTool("authenticate")),
+		agent.WithTool[AddNumbersResult]("query", createNamedAddTool("query")),
+		agent.WithToolDependencyGraph[AddNumbersResult](deps),
+	}, opts...)
+
+	return agent.NewAgent(opts...)
+}
+
+func toolCallTo(id, name string) llm.LLMToolCall {
+	return llm.LLMToolCall{ID: id, ToolName: name, Args: `{"num1": 1, "num2": 2}`}
+}
+
+func TestWithToolDependencyGraph(t *testing.T) {
+	t.Parallel()
+
+	// given
+	dependencyAgent, err := createDependencyAgent(t, map[string][]string{"query": {"authenticate"}})
+	require.NoError(t, err)
+
+	agent.SetLLM(dependencyAgent, llmtest.NewMockLLM(`{"sum": 3}`,
+		toolCallMessage(toolCallTo("call_1", "query")),
+		toolCallMessage(toolCallTo("call_2", "authenticate"), toolCallTo("call_3", "query")),
+		endMessage("done"),
+	))
+
+	// when
+	result, err := dependencyAgent.Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+
+	// then
+	require.NoError(t, err)
+
+	rejected, isOK := result.Messages[2].ToolResults[0].(llm.ErrorLLMToolResult)
+	require.True(t, isOK)
+	assert.Contains(t, rejected.Error, agent.ErrToolDependencyNotMet.Error())
+	assert.Contains(t, rejected.Error, "call authenticate before query")
+
+	secondTurn := result.Messages[3].ToolResults
+	require.Len(t, secondTurn, 2)
+	for _, toolResult := range secondTurn {
+		_, isError := toolResult.(llm.ErrorLLMToolResult)
+		assert.False(t, isError)
+	}
+}
+
+func TestWithToolDependencyGraph_ParallelToolCalls(t *testing.T) {
+	t.Parallel()
+
+	// given
+	dependencyAgent, err := createDependencyAgent(t, map[string][]string{"query": {"authenticate"}},
+		agent.WithParallelToolCalls[AddNumbersResult](true),
+	)
+	require.NoError(t, err)
+
+	agent.SetLLM(dependencyAgent, llmtest.NewMockLLM(`{"sum": 3}`,
+		toolCallMessage(toolCallTo("call_1", "authenticate"), toolCallTo("call_2", "query")),
+		endMessage("done"),
+	))
+
+	// when
+	result, err := dependencyAgent.Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+
+	// then
+	require.NoError(t, err)
+
+	toolResults := result.Messages[2].ToolResults
+	require.Len(t, toolResults, 2)
+	_, isError := toolResults[0].(llm.ErrorLLMToolResult)
+	assert.False(t, isError)
+	rejected, isOK := toolResults[1].(llm.ErrorLLMToolResult)
+	require.True(t, isOK)
+	assert.Contains(t, rejected.Error, agent.ErrToolDependencyNotMet.Error())
+}
+
+func TestWithToolDependencyGraph_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		deps    map[string][]string
+		wantErr string
+	}{
+		{
+			name:    "unknown tool",
+			deps:    map[string][]string{"query": {"login"}},
+			wantErr: "query depends on unknown tool login",
+		},
+		{
+			name:    "cycle",
+			deps:    map[string][]string{"query": {"authenticate"}, "authenticate": {"query"}},
+			wantErr: "dependency cycle authenticate -> query -> authenticate",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := createDependencyAgent(t, tt.deps)
+
+			require.ErrorIs(t, err, validation.ErrValidationFailed)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
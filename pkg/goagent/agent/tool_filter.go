@@ -0,0 +1,39 @@
+package agent
+
+import "github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+
+// WithToolFilter registers only the tools for which predicate returns true. Filtered
+// tools are invisible to the LLM. When several filters are set, a tool must pass all of them.
+// The filter applies to all tools, regardless of the order of the options.
+//
+// Example:
+//
+//	agent.WithToolFilter[Result](func(tool llm.LLMTool) bool {
+//		return tool.Tags["category"] != "web"
+//	})
+func WithToolFilter[T any](predicate func(tool llm.LLMTool) bool) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.toolFilters = append(a.toolFilters, predicate)
+	}
+}
+
+// TagFilter returns a predicate for WithToolFilter that keeps tools tagged with key=value
+func TagFilter(key, value string) func(tool llm.LLMTool) bool {
+	return func(tool llm.LLMTool) bool {
+		tagValue, ok := tool.Tags[key]
+
+		return ok && tagValue == value
+	}
+}
+
+func (a *Agent[T]) applyToolFilters() {
+	for name, tool := range a.tools {
+		for _, filter := range a.toolFilters {
+			if !filter(tool) {
+				delete(a.tools, name)
+
+				break
+			}
+		}
+	}
+}
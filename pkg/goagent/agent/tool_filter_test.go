@@ -0,0 +1,62 @@
+package agent_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+func createTaggedTool(t *testing.T, name string, tags map[string]string) llm.LLMTool {
+	t.Helper()
+
+	tool, err := llm.NewLLMTool(
+		llm.WithLLMToolName(name),
+		llm.WithLLMToolDescription("Tagged tool "+name),
+		llm.WithLLMToolTags(tags),
+		llm.WithLLMToolParametersSchema[AddToolParams](),
+		llm.WithLLMToolCall(func(callID string, _ AddToolParams) (AddToolResult, error) {
+			return AddToolResult{BaseLLMToolResult: llm.BaseLLMToolResult{ID: callID}}, nil
+		}),
+	)
+	require.NoError(t, err)
+
+	return tool
+}
+
+func TestWithToolFilter(t *testing.T) {
+	t.Parallel()
+
+	filteredAgent, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("filtered_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithToolFilter[AddNumbersResult](agent.TagFilter("category", "math")),
+		agent.WithTool[AddNumbersResult]("add", createTaggedTool(t, "add", map[string]string{"category": "math"})),
+		agent.WithTool[AddNumbersResult]("fetch", createTaggedTool(t, "fetch", map[string]string{"category": "web"})),
+		agent.WithTool[AddNumbersResult]("untagged", createTaggedTool(t, "untagged", nil)),
+	)
+	require.NoError(t, err)
+
+	mockLLM := llmtest.NewMockLLM(`{"sum": 0}`,
+		toolCallMessage(llm.LLMToolCall{ID: "call_1", ToolName: "fetch", Args: `{}`}),
+		endMessage("done"),
+	)
+	agent.SetLLM(filteredAgent, mockLLM)
+
+	result, err := filteredAgent.Run(context.Background(), AddNumbers{})
+	require.NoError(t, err)
+
+	systemPrompt := mockLLM.Calls()[0][0].Content
+	assert.Contains(t, systemPrompt, "Tagged tool add")
+	assert.NotContains(t, systemPrompt, "Tagged tool fetch")
+	assert.NotContains(t, systemPrompt, "Tagged tool untagged")
+
+	errorResult, ok := result.Messages[2].ToolResults[0].(llm.ErrorLLMToolResult)
+	require.True(t, ok)
+	assert.Contains(t, errorResult.Error, agent.ErrToolNotFound.Error())
+}
@@ -0,0 +1,166 @@
+package agent
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+// ErrDuplicateToolCall is reported to the LLM when a tool call repeats an idempotency key but
+// the stored result cannot be returned for the new call ID
+var ErrDuplicateToolCall = errors.New("duplicate tool call")
+
+// ToolCallIdempotencyKey computes the idempotency key of a tool call. Calls with an empty key
+// are never deduplicated.
+type ToolCallIdempotencyKey func(call llm.LLMToolCall) string
+
+type idempotentResultsKey struct{}
+
+// WithToolCallIdempotencyKey gives tool calls exactly-once semantics within a run: the first
+// successful result of every key is stored, and later calls with the same key return the
+// stored result, with the ID of the new call, instead of running the tool again. RunWithRetry
+// shares the stored results between its attempts, so a retry never repeats the side effects
+// of a tool. A nil fn uses DefaultToolCallIdempotencyKey.
+//
+// Results are returned again by setting the embedded llm.BaseLLMToolResult to the new call ID.
+// When the result has no such field, the LLM receives an ErrDuplicateToolCall error instead.
+// Tool calls of the same key requested in parallel in one turn may all run.
+//
+// Example:
+//
+//	agent.WithToolCallIdempotencyKey[Result](func(call llm.LLMToolCall) string {
+//		if call.ToolName != "send_email" {
+//			return ""
+//		}
+//		return agent.DefaultToolCallIdempotencyKey(call)
+//	})
+func WithToolCallIdempotencyKey[T any](fn ToolCallIdempotencyKey) AgentOption[T] {
+	return func(a *Agent[T]) {
+		if fn == nil {
+			fn = DefaultToolCallIdempotencyKey
+		}
+		a.idempotencyKey = fn
+	}
+}
+
+// DefaultToolCallIdempotencyKey returns the hex encoded SHA-256 of the tool name and the arguments
+func DefaultToolCallIdempotencyKey(call llm.LLMToolCall) string {
+	hash := sha256.Sum256([]byte(call.ToolName + "\x00" + call.Args))
+
+	return hex.EncodeToString(hash[:])
+}
+
+// idempotentResults stores the results of tool calls by idempotency key
+type idempotentResults struct {
+	mu      sync.Mutex
+	results map[string]llm.LLMToolResult
+}
+
+func newIdempotentResults() *idempotentResults {
+	return &idempotentResults{results: make(map[string]llm.LLMToolResult)}
+}
+
+// withIdempotentResults shares the stored results with all runs of the context
+func withIdempotentResults(ctx context.Context, results *idempotentResults) context.Context {
+	return context.WithValue(ctx, idempotentResultsKey{}, results)
+}
+
+// idempotentResultsFor returns the results shared through the context, or a new store
+func idempotentResultsFor(ctx context.Context) *idempotentResults {
+	if results, ok := ctx.Value(idempotentResultsKey{}).(*idempotentResults); ok {
+		return results
+	}
+
+	return newIdempotentResults()
+}
+
+func (r *idempotentResults) load(key string) (llm.LLMToolResult, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result, ok := r.results[key]
+
+	return result, ok
+}
+
+func (r *idempotentResults) store(key string, result llm.LLMToolResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.results[key]; !exists {
+		r.results[key] = result
+	}
+}
+
+// executeToolOnce executes the tool unless a call with the same idempotency key already succeeded
+func (a *Agent[T]) executeToolOnce(
+	ctx context.Context,
+	state *AgentState,
+	tool llm.LLMTool,
+	toolCall llm.LLMToolCall,
+) (llm.LLMToolResult, error) {
+	key := ""
+	if a.idempotencyKey != nil {
+		key = a.idempotencyKey(toolCall)
+	}
+	if key == "" || state.idempotentResults == nil {
+		return a.executeTool(ctx, tool, toolCall)
+	}
+
+	if stored, ok := state.idempotentResults.load(key); ok {
+		if replayed, ok := withToolResultID(stored, toolCall.ID); ok {
+			return replayed, nil
+		}
+
+		return nil, fmt.Errorf("%w: the call with key %s was already executed as %s",
+			ErrDuplicateToolCall, key, stored.GetID())
+	}
+
+	toolRes, err := a.executeTool(ctx, tool, toolCall)
+	if err == nil {
+		state.idempotentResults.store(key, toolRes)
+	}
+
+	return toolRes, err
+}
+
+// withToolResultID returns a copy of result with the ID of its embedded BaseLLMToolResult set to id
+func withToolResultID(result llm.LLMToolResult, id string) (llm.LLMToolResult, bool) {
+	value := reflect.ValueOf(result)
+
+	isPointer := value.Kind() == reflect.Pointer
+	if isPointer {
+		if value.IsNil() {
+			return nil, false
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	clone := reflect.New(value.Type())
+	clone.Elem().Set(value)
+
+	base := clone.Elem().FieldByName("BaseLLMToolResult")
+	if !base.IsValid() || base.Type() != reflect.TypeFor[llm.BaseLLMToolResult]() || !base.CanSet() {
+		return nil, false
+	}
+	base.Set(reflect.ValueOf(llm.BaseLLMToolResult{ID: id}))
+
+	if isPointer {
+		replayed, ok := clone.Interface().(llm.LLMToolResult)
+
+		return replayed, ok
+	}
+
+	replayed, ok := clone.Elem().Interface().(llm.LLMToolResult)
+
+	return replayed, ok
+}
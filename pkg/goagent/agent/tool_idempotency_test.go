@@ -0,0 +1,161 @@
+package agent_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+// receiptResult is a tool result without llm.BaseLLMToolResult, so its ID cannot be replaced
+type receiptResult string
+
+func (r receiptResult) GetID() string {
+	return string(r)
+}
+
+func createCountingAddTool(calls *atomic.Int32) llm.LLMTool {
+	return llm.MustNewLLMToolFromFunc("add", "Adds two numbers",
+		func(callID string, params AddToolParams) (AddToolResult, error) {
+			calls.Add(1)
+
+			return AddToolResult{
+				BaseLLMToolResult: llm.BaseLLMToolResult{ID: callID},
+				Sum:               params.Num1 + params.Num2,
+			}, nil
+		},
+	)
+}
+
+func createIdempotentAgent(
+	t *testing.T, tool llm.LLMTool, opts ...agent.AgentOption[AddNumbersResult],
+) *agent.Agent[AddNumbersResult] {
+	t.Helper()
+
+	opts = append([]agent.AgentOption[AddNumbersResult]{
+		agent.WithName[AddNumbersResult]("idempotent_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithTool[AddNumbersResult]("add", tool),
+	}, opts...)
+
+	idempotentAgent, err := agent.NewAgent(opts...)
+	require.NoError(t, err)
+
+	return idempotentAgent
+}
+
+func TestWithToolCallIdempotencyKey(t *testing.T) {
+	t.Parallel()
+
+	// given
+	var calls atomic.Int32
+	idempotentAgent := createIdempotentAgent(t, createCountingAddTool(&calls),
+		agent.WithToolCallIdempotencyKey[AddNumbersResult](nil),
+	)
+	mockLLM := llmtest.NewMockLLM(`{"sum": 3}`,
+		toolCallMessage(addCall("call_1")),
+		toolCallMessage(addCall("call_2")),
+		endMessage("done"),
+	)
+
+	// when
+	result, err := idempotentAgent.UsingLLM(mockLLM).Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+
+	replayed, ok := result.Messages[3].ToolResults[0].(AddToolResult)
+	require.True(t, ok)
+	assert.Equal(t, "call_2", replayed.GetID())
+	assert.InDelta(t, 3, replayed.Sum, 0)
+}
+
+func TestWithToolCallIdempotencyKey_SharedBetweenRetries(t *testing.T) {
+	t.Parallel()
+
+	// given
+	var calls atomic.Int32
+	idempotentAgent := createIdempotentAgent(t, createCountingAddTool(&calls),
+		agent.WithToolCallIdempotencyKey[AddNumbersResult](agent.DefaultToolCallIdempotencyKey),
+		agent.WithToolLimit[AddNumbersResult]("add", 1),
+	)
+	otherArgs := llm.LLMToolCall{ID: "call_2", ToolName: "add", Args: `{"num1": 2, "num2": 2}`}
+	mockLLM := llmtest.NewMockLLM(`{"sum": 3}`,
+		toolCallMessage(addCall("call_1")),
+		toolCallMessage(otherArgs),
+		toolCallMessage(addCall("call_3")),
+		endMessage("done"),
+	)
+
+	// when
+	result, err := idempotentAgent.UsingLLM(mockLLM).
+		RunWithRetry(context.Background(), AddNumbers{Num1: 1, Num2: 2}, 1)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Equal(t, "call_3", result.Messages[2].ToolResults[0].GetID())
+}
+
+func TestWithToolCallIdempotencyKey_EmptyKey(t *testing.T) {
+	t.Parallel()
+
+	// given
+	var calls atomic.Int32
+	idempotentAgent := createIdempotentAgent(t, createCountingAddTool(&calls),
+		agent.WithToolCallIdempotencyKey[AddNumbersResult](func(llm.LLMToolCall) string { return "" }),
+	)
+	mockLLM := llmtest.NewMockLLM(`{"sum": 3}`,
+		toolCallMessage(addCall("call_1")),
+		toolCallMessage(addCall("call_2")),
+		endMessage("done"),
+	)
+
+	// when
+	_, err := idempotentAgent.UsingLLM(mockLLM).Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestWithToolCallIdempotencyKey_ResultWithoutBase(t *testing.T) {
+	t.Parallel()
+
+	// given
+	var calls atomic.Int32
+	receiptTool := llm.MustNewLLMToolFromFunc("add", "Adds two numbers",
+		func(callID string, _ AddToolParams) (receiptResult, error) {
+			calls.Add(1)
+
+			return receiptResult(callID), nil
+		},
+	)
+	idempotentAgent := createIdempotentAgent(t, receiptTool,
+		agent.WithToolCallIdempotencyKey[AddNumbersResult](nil),
+	)
+	mockLLM := llmtest.NewMockLLM(`{"sum": 3}`,
+		toolCallMessage(addCall("call_1")),
+		toolCallMessage(addCall("call_2")),
+		endMessage("done"),
+	)
+
+	// when
+	result, err := idempotentAgent.UsingLLM(mockLLM).Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+
+	duplicate, ok := result.Messages[3].ToolResults[0].(llm.ErrorLLMToolResult)
+	require.True(t, ok)
+	assert.Equal(t, "call_2", duplicate.GetID())
+	assert.Contains(t, duplicate.Error, agent.ErrDuplicateToolCall.Error())
+}
@@ -0,0 +1,84 @@
+package agent
+
+import (
+	"strings"
+
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+// WithNamespace prefixes the names of all tools the LLM sees with ns and an underscore,
+// e.g. "add" becomes "billing_add", so agents of different teams sharing a runtime or a
+// tool catalog can't collide. The LLM calls the tools by their prefixed names; everything
+// else, e.g. WithToolLimit or WithToolTimeout, keeps using the names the tools were
+// registered with. ns must be a valid snake case name.
+func WithNamespace[T any](ns string) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.namespace = ns
+		a.namespaceErr = validation.NameIsValid(ns)
+	}
+}
+
+// namespaced returns the name of the tool as the LLM sees it
+func (a *Agent[T]) namespaced(name string) string {
+	if a.namespace == "" {
+		return name
+	}
+
+	return a.namespace + "_" + name
+}
+
+// llmTools returns the tools of the agent keyed and named as the LLM sees them
+func (a *Agent[T]) llmTools() map[string]llm.LLMTool {
+	if a.namespace == "" {
+		return a.tools
+	}
+
+	tools := make(map[string]llm.LLMTool, len(a.tools))
+	for name, tool := range a.tools {
+		tool.Name = a.namespaced(tool.Name)
+		tools[a.namespaced(name)] = tool
+	}
+
+	return tools
+}
+
+// namespacedKeys returns a copy of the map of tool names with the names the LLM sees
+func namespacedKeys[T any, V any](a *Agent[T], values map[string]V) map[string]V {
+	if a.namespace == "" {
+		return values
+	}
+
+	result := make(map[string]V, len(values))
+	for name, value := range values {
+		result[a.namespaced(name)] = value
+	}
+
+	return result
+}
+
+// stripNamespace returns a copy of the tool calls of the LLM with the names the tools were
+// registered with
+func (a *Agent[T]) stripNamespace(toolCalls []llm.LLMToolCall) []llm.LLMToolCall {
+	if a.namespace == "" {
+		return toolCalls
+	}
+
+	stripped := make([]llm.LLMToolCall, len(toolCalls))
+	for i, toolCall := range toolCalls {
+		toolCall.ToolName = strings.TrimPrefix(toolCall.ToolName, a.namespace+"_")
+		stripped[i] = toolCall
+	}
+
+	return stripped
+}
+
+// namespacedNames returns the tool names as the LLM sees them
+func (a *Agent[T]) namespacedNames(names []string) []string {
+	result := make([]string, 0, len(names))
+	for _, name := range names {
+		result = append(result, a.namespaced(name))
+	}
+
+	return result
+}
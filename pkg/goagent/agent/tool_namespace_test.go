@@ -0,0 +1,91 @@
+package agent_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+func createNamespacedAgent(
+	llmConfig llm.LLMConfig, options ...agent.AgentOption[AddNumbersResult],
+) (*agent.Agent[AddNumbersResult], error) {
+	return agent.NewAgent(append([]agent.AgentOption[AddNumbersResult]{
+		agent.WithName[AddNumbersResult]("namespaced_agent"),
+		agent.WithLLMConfig[AddNumbersResult](llmConfig),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithTool[AddNumbersResult]("add", createTestAddTool()),
+	}, options...)...)
+}
+
+func TestWithNamespace_ToolSchemas(t *testing.T) {
+	t.Parallel()
+
+	// given
+	server := &toolsServer{}
+	llmConfig := testLLMConfig()
+	llmConfig.HTTPClient = newTenantClient(t, server)
+
+	namespacedAgent, err := createNamespacedAgent(llmConfig, agent.WithNamespace[AddNumbersResult]("billing"))
+	require.NoError(t, err)
+
+	// when
+	_, err = namespacedAgent.Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, server.tools, 1)
+
+	function, ok := server.tools[0]["function"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "billing_add", function["name"])
+}
+
+func TestWithNamespace_ToolCalls(t *testing.T) {
+	t.Parallel()
+
+	// given
+	namespacedAgent, err := createNamespacedAgent(testLLMConfig(),
+		agent.WithNamespace[AddNumbersResult]("billing"),
+		agent.WithToolLimit[AddNumbersResult]("add", 1),
+	)
+	require.NoError(t, err)
+
+	mockLLM := llmtest.NewMockLLM(`{"sum": 3}`,
+		toolCallMessage(llm.LLMToolCall{ID: "call_1", ToolName: "billing_add", Args: `{"num1": 1, "num2": 2}`}),
+		endMessage("done"),
+	)
+
+	// when
+	result, err := namespacedAgent.UsingLLM(mockLLM).Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Data.Sum)
+
+	systemPrompt := mockLLM.Calls()[0][0].Content
+	assert.Contains(t, systemPrompt, `"billing_add"`)
+	assert.Contains(t, systemPrompt, `{"billing_add":1}`)
+
+	toolMessage := result.Messages[2]
+	assert.Equal(t, "billing_add", toolMessage.ToolCalls[0].ToolName)
+	require.Len(t, toolMessage.ToolResults, 1)
+	assert.Equal(t, AddToolResult{BaseLLMToolResult: llm.BaseLLMToolResult{ID: "call_1"}, Sum: 3},
+		toolMessage.ToolResults[0])
+}
+
+func TestWithNamespace_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, ns := range []string{"", "Billing-Team"} {
+		_, err := createNamespacedAgent(testLLMConfig(), agent.WithNamespace[AddNumbersResult](ns))
+
+		require.ErrorIs(t, err, validation.ErrValidationFailed, "namespace %q", ns)
+		assert.Contains(t, err.Error(), "namespace")
+	}
+}
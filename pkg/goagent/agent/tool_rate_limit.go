@@ -0,0 +1,27 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+// ErrRateLimitExceeded is returned by Run when the run context is done while waiting
+// for the rate limit of a tool, see llm.WithLLMToolRateLimit. It wraps the context error,
+// e.g. context.DeadlineExceeded.
+var ErrRateLimitExceeded = errors.New("tool rate limit exceeded")
+
+// waitToolRateLimit blocks until the rate limit of the tool allows the next call
+func waitToolRateLimit(ctx context.Context, tool llm.LLMTool) error {
+	if tool.RateLimit == nil {
+		return nil
+	}
+
+	if err := tool.RateLimit.Wait(ctx); err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrRateLimitExceeded, tool.Name, err)
+	}
+
+	return nil
+}
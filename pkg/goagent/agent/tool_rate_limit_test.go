@@ -0,0 +1,62 @@
+package agent_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+func TestToolRateLimit(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		parallel bool
+	}{
+		{name: "sequential", parallel: false},
+		{name: "parallel", parallel: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			tool := createTestAddTool()
+			llm.WithLLMToolRateLimit(1)(&tool)
+
+			rateLimitedAgent, err := agent.NewAgent(
+				agent.WithName[AddNumbersResult]("rate_limited_agent"),
+				agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+				agent.WithBehavior[AddNumbersResult]("You add numbers."),
+				agent.WithTool[AddNumbersResult]("add", tool),
+				agent.WithParallelToolCalls[AddNumbersResult](tt.parallel),
+			)
+			require.NoError(t, err)
+
+			agent.SetLLM(rateLimitedAgent, llmtest.NewMockLLM(`{"sum": 3}`,
+				toolCallMessage(
+					llm.LLMToolCall{ID: "call_1", ToolName: "add", Args: `{"num1": 1, "num2": 2}`},
+					llm.LLMToolCall{ID: "call_2", ToolName: "add", Args: `{"num1": 1, "num2": 2}`},
+				),
+				endMessage("done"),
+			))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+
+			// when
+			_, err = rateLimitedAgent.Run(ctx, AddNumbers{Num1: 1, Num2: 2})
+
+			// then
+			require.ErrorIs(t, err, agent.ErrRateLimitExceeded)
+			assert.ErrorIs(t, err, context.DeadlineExceeded)
+		})
+	}
+}
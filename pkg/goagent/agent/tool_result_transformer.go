@@ -0,0 +1,56 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+// ErrNilToolResult is returned when a tool result transformer returns neither a result nor an error
+var ErrNilToolResult = errors.New("tool result transformer returned nil result")
+
+// ToolResultTransformer modifies the result of a tool before it is sent to the LLM
+type ToolResultTransformer func(result llm.LLMToolResult) (llm.LLMToolResult, error)
+
+// WithToolResultTransformer registers a function which post-processes every result of the named
+// tool before it is added to the conversation, e.g. to decompress or decode a response or to
+// translate error codes into readable text. Transformers of the same tool run in the order they
+// were added. When fn returns an error, the error is reported to the LLM as a tool error.
+// Results injected with WithInjectedToolResult are not transformed.
+//
+// Example:
+//
+//	agent.WithToolResultTransformer[Result]("fetch", func(result llm.LLMToolResult) (llm.LLMToolResult, error) {
+//		fetched := result.(FetchResult)
+//		body, err := base64.StdEncoding.DecodeString(fetched.Body)
+//		if err != nil {
+//			return nil, err
+//		}
+//		fetched.Body = string(body)
+//		return fetched, nil
+//	})
+func WithToolResultTransformer[T any](name string, fn ToolResultTransformer) AgentOption[T] {
+	return func(a *Agent[T]) {
+		if a.toolResultTransformers == nil {
+			a.toolResultTransformers = make(map[string][]ToolResultTransformer)
+		}
+		a.toolResultTransformers[name] = append(a.toolResultTransformers[name], fn)
+	}
+}
+
+func (a *Agent[T]) transformToolResult(toolName string, result llm.LLMToolResult) (llm.LLMToolResult, error) {
+	for _, transform := range a.toolResultTransformers[toolName] {
+		transformed, err := transform(result)
+		if err != nil {
+			return nil, fmt.Errorf("failed to transform tool result: %w", err)
+		}
+		if transformed == nil {
+			return nil, ErrNilToolResult
+		}
+
+		result = transformed
+	}
+
+	return result, nil
+}
@@ -0,0 +1,111 @@
+package agent_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+var errNegativeSum = errors.New("negative sum")
+
+func addToSum(delta float64) agent.ToolResultTransformer {
+	return func(result llm.LLMToolResult) (llm.LLMToolResult, error) {
+		addResult, ok := result.(AddToolResult)
+		if !ok {
+			return nil, errors.New("unexpected result type")
+		}
+		addResult.Sum += delta
+
+		return addResult, nil
+	}
+}
+
+func rejectNegativeSum(result llm.LLMToolResult) (llm.LLMToolResult, error) {
+	if result.(AddToolResult).Sum < 0 {
+		return nil, errNegativeSum
+	}
+
+	return result, nil
+}
+
+func runResultTransformAgent(
+	t *testing.T, opts ...agent.AgentOption[AddNumbersResult],
+) llm.LLMToolResult {
+	t.Helper()
+
+	opts = append([]agent.AgentOption[AddNumbersResult]{
+		agent.WithName[AddNumbersResult]("result_transform_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithTool[AddNumbersResult]("add", createTestAddTool()),
+	}, opts...)
+
+	transformAgent, err := agent.NewAgent(opts...)
+	require.NoError(t, err)
+
+	mockLLM := llmtest.NewMockLLM(`{"sum": 3}`,
+		toolCallMessage(llm.LLMToolCall{ID: "call_1", ToolName: "add", Args: `{"num1": 1, "num2": 2}`}),
+		endMessage("done"),
+	)
+
+	result, err := transformAgent.UsingLLM(mockLLM).Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+	require.NoError(t, err)
+
+	return result.Messages[2].ToolResults[0]
+}
+
+func TestWithToolResultTransformer_Chained(t *testing.T) {
+	t.Parallel()
+
+	// when
+	toolResult := runResultTransformAgent(t,
+		agent.WithToolResultTransformer[AddNumbersResult]("add", addToSum(-10)),
+		agent.WithToolResultTransformer[AddNumbersResult]("add", addToSum(20)),
+		agent.WithToolResultTransformer[AddNumbersResult]("other", addToSum(100)),
+	)
+
+	// then
+	addResult, ok := toolResult.(AddToolResult)
+	require.True(t, ok)
+	assert.InDelta(t, 13, addResult.Sum, 0)
+}
+
+func TestWithToolResultTransformer_Error(t *testing.T) {
+	t.Parallel()
+
+	// when
+	toolResult := runResultTransformAgent(t,
+		agent.WithToolResultTransformer[AddNumbersResult]("add", addToSum(-10)),
+		agent.WithToolResultTransformer[AddNumbersResult]("add", rejectNegativeSum),
+		agent.WithToolResultTransformer[AddNumbersResult]("add", addToSum(20)),
+	)
+
+	// then
+	errorResult, ok := toolResult.(llm.ErrorLLMToolResult)
+	require.True(t, ok)
+	assert.Equal(t, "call_1", errorResult.GetID())
+	assert.Contains(t, errorResult.Error, agent.ErrToolError.Error())
+	assert.Contains(t, errorResult.Error, errNegativeSum.Error())
+}
+
+func TestWithToolResultTransformer_NilResult(t *testing.T) {
+	t.Parallel()
+
+	// when
+	toolResult := runResultTransformAgent(t,
+		agent.WithToolResultTransformer[AddNumbersResult]("add", func(llm.LLMToolResult) (llm.LLMToolResult, error) {
+			return nil, nil
+		}),
+	)
+
+	// then
+	errorResult, ok := toolResult.(llm.ErrorLLMToolResult)
+	require.True(t, ok)
+	assert.Contains(t, errorResult.Error, agent.ErrNilToolResult.Error())
+}
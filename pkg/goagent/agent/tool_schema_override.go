@@ -0,0 +1,60 @@
+package agent
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+)
+
+// WithToolSchemaOverride replaces the parameters schema of the tool that the LLM sees with
+// schema, e.g. to describe a union type which the schema generated from the parameters type
+// cannot express. The arguments are still decoded into the parameters type of the tool, so
+// the override must stay compatible with it. The override applies to the tool regardless of
+// the order of the options, and NewAgent fails when the tool is not registered.
+//
+// Example:
+//
+//	agent.WithToolSchemaOverride[Result]("search", map[string]any{
+//		"type": "object",
+//		"properties": map[string]any{
+//			"query": map[string]any{"type": []string{"string", "array"}},
+//		},
+//		"required": []string{"query"},
+//	})
+func WithToolSchemaOverride[T any](name string, schema map[string]any) AgentOption[T] {
+	return func(a *Agent[T]) {
+		if a.toolSchemaOverrides == nil {
+			a.toolSchemaOverrides = make(map[string]map[string]any)
+		}
+		a.toolSchemaOverrides[name] = maps.Clone(schema)
+	}
+}
+
+// applyToolSchemaOverrides sets the overridden schemas on the copies of the tools held by the
+// agent, which only affects the tool definitions sent to the LLM
+func (a *Agent[T]) applyToolSchemaOverrides() {
+	for name, schema := range a.toolSchemaOverrides {
+		tool, ok := a.tools[name]
+		if !ok || len(schema) == 0 {
+			continue
+		}
+
+		tool.ParametersSchema = schema
+		a.tools[name] = tool
+	}
+}
+
+func (a *Agent[T]) validateToolSchemaOverrides() error {
+	for _, name := range slices.Sorted(maps.Keys(a.toolSchemaOverrides)) {
+		if _, ok := a.tools[name]; !ok {
+			return fmt.Errorf("%w: unknown tool %s", validation.ErrValidationFailed, name)
+		}
+		if len(a.toolSchemaOverrides[name]) == 0 {
+			return fmt.Errorf("%w: schema of tool %s cannot be empty", validation.ErrValidationFailed, name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package agent_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+var addSchemaOverride = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"num1": map[string]any{"type": []any{"number", "string"}},
+		"num2": map[string]any{"type": []any{"number", "string"}},
+	},
+	"required": []any{"num1", "num2"},
+}
+
+// toolsServer is a fake OpenAI API which records the tool definitions of the first request
+type toolsServer struct {
+	mu    sync.Mutex
+	tools []map[string]any
+}
+
+func (s *toolsServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Tools []map[string]any `json:"tools"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&body)
+
+	s.mu.Lock()
+	if s.tools == nil {
+		s.tools = body.Tools
+	}
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(sumCompletion))
+}
+
+func TestWithToolSchemaOverride(t *testing.T) {
+	t.Parallel()
+
+	// given
+	server := &toolsServer{}
+	llmConfig := testLLMConfig()
+	llmConfig.HTTPClient = newTenantClient(t, server)
+
+	overrideAgent, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("schema_override_agent"),
+		agent.WithLLMConfig[AddNumbersResult](llmConfig),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithToolSchemaOverride[AddNumbersResult]("add", addSchemaOverride),
+		agent.WithTool[AddNumbersResult]("add", createTestAddTool()),
+	)
+	require.NoError(t, err)
+
+	// when
+	_, err = overrideAgent.Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, server.tools, 1)
+
+	function, ok := server.tools[0]["function"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "add", function["name"])
+	assert.Equal(t, addSchemaOverride, function["parameters"])
+}
+
+func TestWithToolSchemaOverride_KeepsArgumentDecoding(t *testing.T) {
+	t.Parallel()
+
+	// given
+	tool := createTestAddTool()
+	overrideAgent, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("schema_override_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithTool[AddNumbersResult]("add", tool),
+		agent.WithToolSchemaOverride[AddNumbersResult]("add", addSchemaOverride),
+	)
+	require.NoError(t, err)
+
+	mockLLM := llmtest.NewMockLLM(`{"sum": 3}`,
+		toolCallMessage(llm.LLMToolCall{ID: "call_1", ToolName: "add", Args: `{"num1": 1, "num2": 2}`}),
+		endMessage("done"),
+	)
+
+	// when
+	result, err := overrideAgent.UsingLLM(mockLLM).Run(context.Background(), AddNumbers{Num1: 1, Num2: 2})
+
+	// then
+	require.NoError(t, err)
+	toolResult, ok := result.Messages[2].ToolResults[0].(AddToolResult)
+	require.True(t, ok)
+	assert.InDelta(t, 3, toolResult.Sum, 0)
+	assert.IsType(t, &AddToolParams{}, tool.ParametersSchema)
+}
+
+func TestWithToolSchemaOverride_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		toolName string
+		schema   map[string]any
+	}{
+		{name: "unknown tool", toolName: "subtract", schema: addSchemaOverride},
+		{name: "empty schema", toolName: "add", schema: map[string]any{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// when
+			_, err := agent.NewAgent(
+				agent.WithName[AddNumbersResult]("schema_override_agent"),
+				agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+				agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+				agent.WithTool[AddNumbersResult]("add", createTestAddTool()),
+				agent.WithToolSchemaOverride[AddNumbersResult](tt.toolName, tt.schema),
+			)
+
+			// then
+			require.ErrorIs(t, err, validation.ErrValidationFailed)
+			assert.Contains(t, err.Error(), "tool schema overrides")
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+const partialResultsBufferSize = 16
+
+// ErrToolTimeout is returned when a tool exceeds its timeout without submitting a partial result
+var ErrToolTimeout = errors.New("tool call timed out")
+
+type toolCallOutcome struct {
+	result llm.LLMToolResult
+	err    error
+}
+
+// WithToolTimeout sets a deadline for every call of the named tool.
+//
+// If the tool exceeds the deadline and was created with llm.WithLLMToolPartialResults,
+// the last submitted partial result is returned to the LLM as an llm.PartialToolResult,
+// so the agent degrades gracefully instead of failing the tool call. Otherwise the LLM
+// receives an ErrToolTimeout tool error. The timed out call keeps running in the
+// background and its result is discarded.
+//
+// Every timed call of a tool with partial results gets its own partial result channel.
+// Such calls run one at a time until they finish or time out, so a hung call doesn't
+// block the later calls of the tool.
+func WithToolTimeout[T any](name string, timeout time.Duration) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.toolTimeouts[name] = timeout
+	}
+}
+
+func (a *Agent[T]) invokeTool(tool llm.LLMTool, toolCall llm.LLMToolCall) (llm.LLMToolResult, error) {
+	timeout, ok := a.toolTimeouts[toolCall.ToolName]
+	if !ok {
+		return a.callToolFunc(tool, toolCall)
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	partials := make(chan llm.LLMToolResult, partialResultsBufferSize)
+	started := make(chan struct{})
+	if tool.PartialResults != nil {
+		slot := a.partialResultSlots.slot(toolCall.ToolName)
+		select {
+		case slot <- struct{}{}:
+		case <-timer.C:
+			return nil, fmt.Errorf("%w: %s after %s waiting for its previous call",
+				ErrToolTimeout, toolCall.ToolName, timeout)
+		}
+		// the slot is freed once the call has taken its channel and then finished or timed out
+		defer func() {
+			<-started
+			<-slot
+		}()
+		tool.PartialResults.SubmitPartial(partials)
+	}
+
+	outcome := make(chan toolCallOutcome, 1)
+	go func() {
+		close(started)
+
+		result, err := a.callToolFunc(tool, toolCall)
+		outcome <- toolCallOutcome{result: result, err: err}
+	}()
+
+	var lastPartial llm.LLMToolResult
+	for {
+		select {
+		case res := <-outcome:
+			return res.result, res.err
+		case partial := <-partials:
+			lastPartial = partial
+		case <-timer.C:
+			return a.createTimeoutResult(toolCall, timeout, lastPartial, partials)
+		}
+	}
+}
+
+func (a *Agent[T]) createTimeoutResult(
+	toolCall llm.LLMToolCall,
+	timeout time.Duration,
+	lastPartial llm.LLMToolResult,
+	partials <-chan llm.LLMToolResult,
+) (llm.LLMToolResult, error) {
+	// pick up partial results submitted right before the deadline
+	for drained := false; !drained; {
+		select {
+		case partial := <-partials:
+			lastPartial = partial
+		default:
+			drained = true
+		}
+	}
+
+	if lastPartial == nil {
+		return nil, fmt.Errorf("%w: %s after %s", ErrToolTimeout, toolCall.ToolName, timeout)
+	}
+
+	return llm.PartialToolResult{
+		BaseLLMToolResult: llm.BaseLLMToolResult{ID: toolCall.ID},
+		Partial:           true,
+		Result:            lastPartial,
+	}, nil
+}
+
+// partialResultSlots lets one timed call per tool with partial results run at a time,
+// because SubmitPartial replaces the partial result channel of the shared tool. A slot is
+// held until the call finishes or times out.
+type partialResultSlots struct {
+	mu    sync.Mutex
+	slots map[string]chan struct{}
+}
+
+func newPartialResultSlots() *partialResultSlots {
+	return &partialResultSlots{slots: make(map[string]chan struct{})}
+}
+
+func (p *partialResultSlots) slot(toolName string) chan struct{} {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	slot, ok := p.slots[toolName]
+	if !ok {
+		slot = make(chan struct{}, 1)
+		p.slots[toolName] = slot
+	}
+
+	return slot
+}
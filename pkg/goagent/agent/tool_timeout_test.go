@@ -0,0 +1,227 @@
+package agent_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+// slowSearchTool submits a partial result and then blocks until released
+type slowSearchTool struct {
+	partial        chan<- llm.LLMToolResult
+	release        chan struct{}
+	submitsPartial bool
+}
+
+func (s *slowSearchTool) SubmitPartial(partial chan<- llm.LLMToolResult) {
+	s.partial = partial
+}
+
+func (s *slowSearchTool) call(callID string, _ AddToolParams) (AddToolResult, error) {
+	if s.submitsPartial {
+		s.partial <- AddToolResult{BaseLLMToolResult: llm.BaseLLMToolResult{ID: callID}, Sum: 1}
+	}
+	<-s.release
+
+	return AddToolResult{BaseLLMToolResult: llm.BaseLLMToolResult{ID: callID}, Sum: 2}, nil
+}
+
+func runSlowToolAgent(t *testing.T, submitsPartial bool) llm.LLMToolResult {
+	t.Helper()
+
+	slowTool := &slowSearchTool{release: make(chan struct{}), submitsPartial: submitsPartial}
+	t.Cleanup(func() { close(slowTool.release) })
+
+	tool, err := llm.NewLLMTool(
+		llm.WithLLMToolName("search"),
+		llm.WithLLMToolDescription("Slow search"),
+		llm.WithLLMToolParametersSchema[AddToolParams](),
+		llm.WithLLMToolCall(slowTool.call),
+		llm.WithLLMToolPartialResults(slowTool),
+	)
+	require.NoError(t, err)
+
+	timeoutAgent, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("timeout_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You search."),
+		agent.WithTool[AddNumbersResult]("search", tool),
+		agent.WithToolTimeout[AddNumbersResult]("search", 50*time.Millisecond),
+	)
+	require.NoError(t, err)
+
+	agent.SetLLM(timeoutAgent, llmtest.NewMockLLM(`{"sum": 1}`,
+		toolCallMessage(llm.LLMToolCall{ID: "call_1", ToolName: "search", Args: `{}`}),
+		endMessage("done"),
+	))
+
+	result, err := timeoutAgent.Run(context.Background(), AddNumbers{})
+	require.NoError(t, err)
+
+	toolResults := result.Messages[2].ToolResults
+	require.Len(t, toolResults, 1)
+
+	return toolResults[0]
+}
+
+func TestWithToolTimeout_PartialResult(t *testing.T) {
+	t.Parallel()
+
+	toolResult := runSlowToolAgent(t, true)
+
+	partial, isOK := toolResult.(llm.PartialToolResult)
+	require.True(t, isOK)
+	assert.True(t, partial.Partial)
+	assert.Equal(t, "call_1", partial.GetID())
+	assert.InDelta(t, 1.0, partial.Result.(AddToolResult).Sum, 0)
+}
+
+func TestWithToolTimeout_NoPartialResult(t *testing.T) {
+	t.Parallel()
+
+	toolResult := runSlowToolAgent(t, false)
+
+	errorResult, isOK := toolResult.(llm.ErrorLLMToolResult)
+	require.True(t, isOK)
+	assert.Contains(t, errorResult.Error, agent.ErrToolTimeout.Error())
+}
+
+// countingPartialTool records how many of its calls run at the same time
+type countingPartialTool struct {
+	partial   chan<- llm.LLMToolResult
+	active    atomic.Int32
+	maxActive atomic.Int32
+}
+
+func (c *countingPartialTool) SubmitPartial(partial chan<- llm.LLMToolResult) {
+	c.partial = partial
+}
+
+func (c *countingPartialTool) call(callID string, _ AddToolParams) (AddToolResult, error) {
+	active := c.active.Add(1)
+	defer c.active.Add(-1)
+	for current := c.maxActive.Load(); active > current; current = c.maxActive.Load() {
+		c.maxActive.CompareAndSwap(current, active)
+	}
+
+	c.partial <- AddToolResult{BaseLLMToolResult: llm.BaseLLMToolResult{ID: callID}, Sum: 1}
+	time.Sleep(10 * time.Millisecond)
+
+	return AddToolResult{BaseLLMToolResult: llm.BaseLLMToolResult{ID: callID}, Sum: 2}, nil
+}
+
+func TestWithToolTimeout_PartialResultsAreNotConcurrent(t *testing.T) {
+	t.Parallel()
+
+	// given
+	partialTool := &countingPartialTool{}
+	tool, err := llm.NewLLMTool(
+		llm.WithLLMToolName("search"),
+		llm.WithLLMToolDescription("Search"),
+		llm.WithLLMToolParametersSchema[AddToolParams](),
+		llm.WithLLMToolCall(partialTool.call),
+		llm.WithLLMToolPartialResults(partialTool),
+	)
+	require.NoError(t, err)
+
+	timeoutAgent, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("timeout_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You search."),
+		agent.WithTool[AddNumbersResult]("search", tool),
+		agent.WithToolTimeout[AddNumbersResult]("search", time.Second),
+		agent.WithParallelToolCalls[AddNumbersResult](true),
+	)
+	require.NoError(t, err)
+
+	agent.SetLLM(timeoutAgent, llmtest.NewMockLLM(`{"sum": 2}`,
+		toolCallMessage(
+			llm.LLMToolCall{ID: "call_1", ToolName: "search", Args: `{}`},
+			llm.LLMToolCall{ID: "call_2", ToolName: "search", Args: `{}`},
+			llm.LLMToolCall{ID: "call_3", ToolName: "search", Args: `{}`},
+		),
+		endMessage("done"),
+	))
+
+	// when
+	result, err := timeoutAgent.Run(context.Background(), AddNumbers{})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), partialTool.maxActive.Load())
+	assert.Equal(t, []llm.LLMToolResult{
+		AddToolResult{BaseLLMToolResult: llm.BaseLLMToolResult{ID: "call_1"}, Sum: 2},
+		AddToolResult{BaseLLMToolResult: llm.BaseLLMToolResult{ID: "call_2"}, Sum: 2},
+		AddToolResult{BaseLLMToolResult: llm.BaseLLMToolResult{ID: "call_3"}, Sum: 2},
+	}, result.Messages[2].ToolResults)
+}
+
+// hangingPartialTool hangs on its first call and answers the later ones right away
+type hangingPartialTool struct {
+	partial chan<- llm.LLMToolResult
+	calls   atomic.Int32
+	release chan struct{}
+}
+
+func (h *hangingPartialTool) SubmitPartial(partial chan<- llm.LLMToolResult) {
+	h.partial = partial
+}
+
+func (h *hangingPartialTool) call(callID string, _ AddToolParams) (AddToolResult, error) {
+	if h.calls.Add(1) == 1 {
+		<-h.release
+	}
+
+	return AddToolResult{BaseLLMToolResult: llm.BaseLLMToolResult{ID: callID}, Sum: 2}, nil
+}
+
+func TestWithToolTimeout_HungCallDoesNotBlockLaterCalls(t *testing.T) {
+	t.Parallel()
+
+	// given
+	hangingTool := &hangingPartialTool{release: make(chan struct{})}
+	t.Cleanup(func() { close(hangingTool.release) })
+
+	tool, err := llm.NewLLMTool(
+		llm.WithLLMToolName("search"),
+		llm.WithLLMToolDescription("Search"),
+		llm.WithLLMToolParametersSchema[AddToolParams](),
+		llm.WithLLMToolCall(hangingTool.call),
+		llm.WithLLMToolPartialResults(hangingTool),
+	)
+	require.NoError(t, err)
+
+	timeoutAgent, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("timeout_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You search."),
+		agent.WithTool[AddNumbersResult]("search", tool),
+		agent.WithToolLimit[AddNumbersResult]("search", 2),
+		agent.WithToolTimeout[AddNumbersResult]("search", 50*time.Millisecond),
+	)
+	require.NoError(t, err)
+
+	agent.SetLLM(timeoutAgent, llmtest.NewMockLLM(`{"sum": 2}`,
+		toolCallMessage(llm.LLMToolCall{ID: "call_1", ToolName: "search", Args: `{}`}),
+		toolCallMessage(llm.LLMToolCall{ID: "call_2", ToolName: "search", Args: `{}`}),
+		endMessage("done"),
+	))
+
+	// when
+	result, err := timeoutAgent.Run(context.Background(), AddNumbers{})
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, result.Messages[2].ToolResults, 1)
+	assert.IsType(t, llm.ErrorLLMToolResult{}, result.Messages[2].ToolResults[0])
+	assert.Equal(t, []llm.LLMToolResult{
+		AddToolResult{BaseLLMToolResult: llm.BaseLLMToolResult{ID: "call_2"}, Sum: 2},
+	}, result.Messages[3].ToolResults)
+}
@@ -0,0 +1,163 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/schema"
+)
+
+// unionTypeField is the discriminator property added to every branch of a union output schema
+const unionTypeField = "type"
+
+var (
+	// ErrInvalidUnionType is returned by NewUnionAgent when the branch types cannot form a union
+	ErrInvalidUnionType = errors.New("invalid union type")
+	// ErrUnknownUnionType is returned by UnionAgent.Run when the LLM emits a type which matches no branch
+	ErrUnknownUnionType = errors.New("unknown union type")
+)
+
+// UnionAgent is an agent whose output is one of two types, e.g. a success or an error result.
+// The output schema is a JSON oneOf of both types, each with a "type" discriminator field set
+// to the name of the Go type. Note that providers which require an object at the root of the
+// schema, such as OpenAI structured outputs in strict mode, may reject the schema.
+type UnionAgent[A any, B any] struct {
+	agent *Agent[any]
+	typeA string
+	typeB string
+}
+
+// NewUnionAgent creates an agent which returns either A or B, depending on the outcome of the task
+//
+// Example:
+//
+//	type SuccessResult struct {
+//		Answer string `json:"answer" jsonschema_description:"The answer to the question"`
+//	}
+//
+//	type ErrorResult struct {
+//		Reason string `json:"reason" jsonschema_description:"Why the question cannot be answered"`
+//	}
+//
+//	unionAgent, err := agent.NewUnionAgent[SuccessResult, ErrorResult](
+//		agent.WithName[any]("qa_agent"),
+//		agent.WithLLMConfig[any](llmConfig),
+//		agent.WithBehavior[any]("Answer the question or explain why you cannot."),
+//	)
+//
+//	success, failure, err := unionAgent.Run(ctx, question)
+func NewUnionAgent[A any, B any](opts ...AgentOption[any]) (*UnionAgent[A, B], error) {
+	typeA, typeB := reflect.TypeFor[A]().Name(), reflect.TypeFor[B]().Name()
+	if typeA == "" || typeB == "" {
+		return nil, fmt.Errorf("failed to create agent: %w: union types must be named", ErrInvalidUnionType)
+	}
+	if typeA == typeB {
+		return nil, fmt.Errorf("failed to create agent: %w: both union types are named %s", ErrInvalidUnionType, typeA)
+	}
+
+	branchA, err := createUnionBranchSchema(typeA, new(A))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create agent: %w", err)
+	}
+	branchB, err := createUnionBranchSchema(typeB, new(B))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create agent: %w", err)
+	}
+
+	opts = append([]AgentOption[any]{func(a *Agent[any]) {
+		a.outputSchemaMap = map[string]any{"oneOf": []any{branchA, branchB}}
+	}}, opts...)
+
+	unionAgent, err := NewAgent(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UnionAgent[A, B]{
+		agent: unionAgent,
+		typeA: typeA,
+		typeB: typeB,
+	}, nil
+}
+
+// Run runs the agent and returns its output. Exactly one of the returned A and B is non-nil,
+// selected by the "type" field the LLM emitted.
+func (u *UnionAgent[A, B]) Run(ctx context.Context, input any) (*A, *B, error) {
+	result, err := u.agent.Run(ctx, input)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := json.Marshal(result.Data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", llm.ErrStructuredOutput, err)
+	}
+
+	var discriminator struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &discriminator); err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", llm.ErrStructuredOutput, err)
+	}
+
+	switch discriminator.Type {
+	case u.typeA:
+		output, err := unmarshalUnionBranch[A](data)
+
+		return output, nil, err
+	case u.typeB:
+		output, err := unmarshalUnionBranch[B](data)
+
+		return nil, output, err
+	default:
+		return nil, nil, fmt.Errorf("%w: %w: %q", llm.ErrStructuredOutput, ErrUnknownUnionType, discriminator.Type)
+	}
+}
+
+func unmarshalUnionBranch[R any](data []byte) (*R, error) {
+	output := new(R)
+	if err := json.Unmarshal(data, output); err != nil {
+		return nil, fmt.Errorf("%w: %w", llm.ErrStructuredOutput, err)
+	}
+
+	return output, nil
+}
+
+// createUnionBranchSchema returns the object schema of schemaT extended with the type discriminator.
+// Generated schemas are cached and shared, so the schema is copied before it is modified.
+func createUnionBranchSchema(name string, schemaT any) (map[string]any, error) {
+	generated, err := schema.GenerateSchema(schemaT)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCannotCreateSchema, err)
+	}
+
+	properties, ok := generated["properties"].(map[string]any)
+	if !ok || generated["type"] != "object" {
+		return nil, fmt.Errorf("%w: %s must be a struct", ErrInvalidUnionType, name)
+	}
+	if _, exists := properties[unionTypeField]; exists {
+		return nil, fmt.Errorf("%w: %s already has a %q field", ErrInvalidUnionType, name, unionTypeField)
+	}
+
+	branch := maps.Clone(generated)
+	delete(branch, "$id")
+	delete(branch, "$schema")
+
+	branchProperties := maps.Clone(properties)
+	branchProperties[unionTypeField] = map[string]any{
+		"type": "string",
+		"enum": []any{name},
+	}
+	branch["properties"] = branchProperties
+
+	required, _ := branch["required"].([]any)
+	branch["required"] = append(slices.Clone(required), unionTypeField)
+
+	return branch, nil
+}
@@ -0,0 +1,158 @@
+package agent_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+type SuccessResult struct {
+	Answer string `json:"answer" jsonschema_description:"The answer to the question"`
+}
+
+type ErrorResult struct {
+	Reason string `json:"reason" jsonschema_description:"Why the question cannot be answered"`
+}
+
+type TypedResult struct {
+	Type string `json:"type"`
+}
+
+func createUnionAgent(t *testing.T) *agent.UnionAgent[SuccessResult, ErrorResult] {
+	t.Helper()
+
+	unionAgent, err := agent.NewUnionAgent[SuccessResult, ErrorResult](
+		agent.WithName[any]("qa_agent"),
+		agent.WithLLMConfig[any](testLLMConfig()),
+		agent.WithBehavior[any]("Answer the question or explain why you cannot."),
+	)
+	require.NoError(t, err)
+
+	return unionAgent
+}
+
+func TestUnionAgent_Run(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		output      string
+		wantSuccess *SuccessResult
+		wantError   *ErrorResult
+	}{
+		{
+			name:        "first type",
+			output:      `{"type": "SuccessResult", "answer": "42"}`,
+			wantSuccess: &SuccessResult{Answer: "42"},
+		},
+		{
+			name:      "second type",
+			output:    `{"type": "ErrorResult", "reason": "no data"}`,
+			wantError: &ErrorResult{Reason: "no data"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			unionAgent := createUnionAgent(t)
+			agent.SetUnionLLM(unionAgent, llmtest.NewMockLLM(tt.output, endMessage("done")))
+
+			// when
+			success, failure, err := unionAgent.Run(context.Background(), "What is the meaning of life?")
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantSuccess, success)
+			assert.Equal(t, tt.wantError, failure)
+		})
+	}
+}
+
+func TestUnionAgent_Schema(t *testing.T) {
+	t.Parallel()
+
+	// given
+	unionAgent := createUnionAgent(t)
+	mockLLM := llmtest.NewMockLLM(`{"type": "SuccessResult", "answer": "42"}`, endMessage("done"))
+	agent.SetUnionLLM(unionAgent, mockLLM)
+
+	// when
+	_, _, err := unionAgent.Run(context.Background(), "What is the meaning of life?")
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, mockLLM.Schemas(), 1)
+
+	schemaJSON, err := json.Marshal(mockLLM.Schemas()[0])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"oneOf": [
+			{
+				"type": "object",
+				"additionalProperties": false,
+				"properties": {
+					"answer": {"type": "string", "description": "The answer to the question"},
+					"type": {"type": "string", "enum": ["SuccessResult"]}
+				},
+				"required": ["answer", "type"]
+			},
+			{
+				"type": "object",
+				"additionalProperties": false,
+				"properties": {
+					"reason": {"type": "string", "description": "Why the question cannot be answered"},
+					"type": {"type": "string", "enum": ["ErrorResult"]}
+				},
+				"required": ["reason", "type"]
+			}
+		]
+	}`, string(schemaJSON))
+}
+
+func TestUnionAgent_UnknownType(t *testing.T) {
+	t.Parallel()
+
+	// given
+	unionAgent := createUnionAgent(t)
+	agent.SetUnionLLM(unionAgent, llmtest.NewMockLLM(`{"type": "Other"}`, endMessage("done")))
+
+	// when
+	success, failure, err := unionAgent.Run(context.Background(), "What is the meaning of life?")
+
+	// then
+	require.ErrorIs(t, err, agent.ErrUnknownUnionType)
+	require.ErrorIs(t, err, llm.ErrStructuredOutput)
+	assert.Nil(t, success)
+	assert.Nil(t, failure)
+}
+
+func TestNewUnionAgent_InvalidTypes(t *testing.T) {
+	t.Parallel()
+
+	opts := []agent.AgentOption[any]{
+		agent.WithName[any]("qa_agent"),
+		agent.WithLLMConfig[any](testLLMConfig()),
+		agent.WithBehavior[any]("Answer the question."),
+	}
+
+	_, err := agent.NewUnionAgent[SuccessResult, SuccessResult](opts...)
+	require.ErrorIs(t, err, agent.ErrInvalidUnionType)
+
+	_, err = agent.NewUnionAgent[SuccessResult, struct{ Reason string }](opts...)
+	require.ErrorIs(t, err, agent.ErrInvalidUnionType)
+
+	_, err = agent.NewUnionAgent[SuccessResult, TypedResult](opts...)
+	require.ErrorIs(t, err, agent.ErrInvalidUnionType)
+
+	_, err = agent.NewUnionAgent[SuccessResult, string](opts...)
+	require.ErrorIs(t, err, agent.ErrInvalidUnionType)
+}
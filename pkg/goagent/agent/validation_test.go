@@ -196,3 +196,64 @@ func TestNewAgent_TooLongName(t *testing.T) {
 	assert.Contains(t, err.Error(), "name")
 	assert.ErrorIs(t, err, validation.ErrValidationFailed)
 }
+
+func TestNewAgent_NonPositiveToolLimits(t *testing.T) {
+	t.Parallel()
+
+	_, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("limits_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithDefaultToolLimit[AddNumbersResult](0),
+	)
+	require.ErrorIs(t, err, validation.ErrValidationFailed)
+	assert.Contains(t, err.Error(), "default tool limit must be positive")
+
+	_, err = agent.NewAgent(
+		agent.WithName[AddNumbersResult]("limits_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithToolLimit[AddNumbersResult]("add", -1),
+	)
+	require.ErrorIs(t, err, validation.ErrValidationFailed)
+	assert.Contains(t, err.Error(), "limit of tool add must be positive")
+}
+
+func TestNewAgent_ReportsAllErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult](""),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithDefaultToolLimit[AddNumbersResult](0),
+	)
+
+	require.ErrorIs(t, err, validation.ErrMultiValidation)
+	assert.Contains(t, err.Error(), "name")
+	assert.Contains(t, err.Error(), "behavior")
+	assert.Contains(t, err.Error(), "default tool limit must be positive")
+}
+
+func TestNewAgent_DuplicateToolName(t *testing.T) {
+	t.Parallel()
+
+	_, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("duplicate_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithTool[AddNumbersResult]("add", createTestAddTool()),
+		agent.WithTool[AddNumbersResult]("add", createTestAddTool()),
+	)
+	require.ErrorIs(t, err, agent.ErrDuplicateToolName)
+	assert.Contains(t, err.Error(), "add")
+
+	overwriteAgent, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("overwrite_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithTool[AddNumbersResult]("add", createTestAddTool()),
+		agent.WithToolOverwrite[AddNumbersResult]("add", createTestAddTool()),
+	)
+	require.NoError(t, err)
+	assert.NotNil(t, overwriteAgent)
+}
@@ -0,0 +1,65 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+// ErrWarmUpFailed is returned when the warm-up probe to the LLM fails
+var ErrWarmUpFailed = errors.New("LLM warm-up failed")
+
+const (
+	// DefaultWarmUpTimeout bounds the warm-up probe made by WithWarmUpOnCreate
+	DefaultWarmUpTimeout = 30 * time.Second
+
+	warmUpPrompt = "Reply with the single word OK."
+)
+
+// WarmUp sends a minimal request to the agent's LLM to establish the HTTP connection
+// pool before the first Run. Call it at startup to detect misconfiguration, such as an
+// invalid API key or model name, early instead of on the first real request.
+func WarmUp[T any](ctx context.Context, a *Agent[T]) error {
+	_, err := a.llm.Call(ctx, []llm.LLMMessage{
+		llm.NewLLMMessage(llm.LLMMessageTypeUser, warmUpPrompt),
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrWarmUpFailed, err)
+	}
+
+	return nil
+}
+
+// WithWarmUpOnCreate makes NewAgent call WarmUp after the LLM client is created.
+// NewAgent returns ErrWarmUpFailed when the probe fails or takes longer than the
+// warm-up timeout, DefaultWarmUpTimeout unless set by WithWarmUpTimeout.
+func WithWarmUpOnCreate[T any](enabled bool) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.warmUp.onCreate = enabled
+	}
+}
+
+// WithWarmUpTimeout sets how long NewAgent waits for the probe of WithWarmUpOnCreate
+func WithWarmUpTimeout[T any](timeout time.Duration) AgentOption[T] {
+	return func(a *Agent[T]) {
+		a.warmUp.timeout = timeout
+	}
+}
+
+// warmUpConfig configures the warm-up probe made by NewAgent
+type warmUpConfig struct {
+	onCreate bool
+	timeout  time.Duration
+}
+
+func (c warmUpConfig) validate() error {
+	if c.timeout <= 0 {
+		return fmt.Errorf("%w: timeout must be positive, got %v", validation.ErrValidationFailed, c.timeout)
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package agent_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+func createWarmUpAgent(t *testing.T) *agent.Agent[AddNumbersResult] {
+	t.Helper()
+
+	warmUpAgent, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("warm_up_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+	)
+	require.NoError(t, err)
+
+	return warmUpAgent
+}
+
+func TestWarmUp(t *testing.T) {
+	t.Parallel()
+
+	warmUpAgent := createWarmUpAgent(t)
+	mockLLM := llmtest.NewMockLLM("", endMessage("OK"))
+	agent.SetLLM(warmUpAgent, mockLLM)
+
+	err := agent.WarmUp(context.Background(), warmUpAgent)
+
+	require.NoError(t, err)
+	require.Len(t, mockLLM.Calls(), 1)
+}
+
+func TestWarmUp_Failed(t *testing.T) {
+	t.Parallel()
+
+	warmUpAgent := createWarmUpAgent(t)
+	agent.SetLLM(warmUpAgent, llmtest.NewMockLLM(""))
+
+	err := agent.WarmUp(context.Background(), warmUpAgent)
+
+	require.ErrorIs(t, err, agent.ErrWarmUpFailed)
+	require.ErrorIs(t, err, llmtest.ErrNoMoreResponses)
+}
+
+func TestWithWarmUpTimeout(t *testing.T) {
+	t.Parallel()
+
+	// given
+	released := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		<-released
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(released) })
+
+	cfg := testLLMConfig()
+	cfg.BaseURL = server.URL
+
+	// when
+	_, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("warm_up_agent"),
+		agent.WithLLMConfig[AddNumbersResult](cfg),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithWarmUpOnCreate[AddNumbersResult](true),
+		agent.WithWarmUpTimeout[AddNumbersResult](50*time.Millisecond),
+	)
+
+	// then
+	require.ErrorIs(t, err, agent.ErrWarmUpFailed)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestWithWarmUpTimeout_NotPositive(t *testing.T) {
+	t.Parallel()
+
+	// when
+	_, err := agent.NewAgent(
+		agent.WithName[AddNumbersResult]("warm_up_agent"),
+		agent.WithLLMConfig[AddNumbersResult](testLLMConfig()),
+		agent.WithBehavior[AddNumbersResult]("You are a calculator."),
+		agent.WithWarmUpTimeout[AddNumbersResult](0),
+	)
+
+	// then
+	require.ErrorIs(t, err, validation.ErrValidationFailed)
+	require.ErrorContains(t, err, "warm-up")
+}
@@ -0,0 +1,193 @@
+// Package agenthttp exposes agent execution as a REST endpoint.
+//
+// Example:
+//
+//	handler := agenthttp.NewHandler[Question](answerAgent)
+//	log.Fatal(http.ListenAndServe(":8080", handler))
+//
+// The handler serves:
+//
+//	POST /run     runs the agent with the JSON request body as input and returns the AgentResult
+//	GET  /health  returns {"status":"ok"}
+package agenthttp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+)
+
+const (
+	// HeaderRequestID carries the request ID. It is generated when the client does not send one.
+	HeaderRequestID = "X-Request-ID"
+
+	// StatusClientClosedRequest is returned when the client canceled the request before the run finished
+	StatusClientClosedRequest = 499
+
+	// DefaultMaxRequestBodySize is the maximum size of a POST /run body, see WithMaxRequestBodySize
+	DefaultMaxRequestBodySize = 1 << 20
+
+	requestIDBytes = 16
+
+	internalServerErrorMessage = "internal server error"
+)
+
+// traceHeaders are propagated from the request to the response and the run context
+var traceHeaders = []string{"traceparent", "tracestate", "baggage"}
+
+type contextKey int
+
+const (
+	requestIDKey contextKey = iota
+	traceHeadersKey
+)
+
+type errorResponse struct {
+	Error     string `json:"error"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+type handlerConfig struct {
+	maxRequestBodySize int64
+}
+
+// HandlerOption configures the handler created by NewHandler
+type HandlerOption func(*handlerConfig)
+
+// WithMaxRequestBodySize sets the maximum size of a POST /run body in bytes. Larger bodies
+// are rejected with 413. Defaults to DefaultMaxRequestBodySize.
+func WithMaxRequestBodySize(size int64) HandlerOption {
+	return func(c *handlerConfig) {
+		c.maxRequestBodySize = size
+	}
+}
+
+// NewHandler creates an HTTP handler that decodes POST /run bodies as In, runs the agent
+// and encodes the AgentResult as JSON. Tool limit errors are returned as 422, client
+// cancellations as 499 and other errors as 500. The details of 500 errors are only
+// logged, the response carries a generic message and the request ID.
+func NewHandler[In any, T any](a *agent.Agent[T], options ...HandlerOption) http.Handler {
+	cfg := &handlerConfig{maxRequestBodySize: DefaultMaxRequestBodySize}
+	for _, opt := range options {
+		opt(cfg)
+	}
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+
+	mux.HandleFunc("POST /run", func(w http.ResponseWriter, r *http.Request) {
+		var input In
+		body := http.MaxBytesReader(w, r.Body, cfg.maxRequestBodySize)
+		if err := json.NewDecoder(body).Decode(&input); err != nil {
+			status := http.StatusBadRequest
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				status = http.StatusRequestEntityTooLarge
+			}
+			writeError(r.Context(), w, status, fmt.Errorf("invalid request body: %w", err))
+
+			return
+		}
+
+		result, err := a.Run(r.Context(), input)
+		if err != nil {
+			writeError(r.Context(), w, statusForError(r.Context(), err), err)
+
+			return
+		}
+
+		writeJSON(w, http.StatusOK, result)
+	})
+
+	return withTracing(mux)
+}
+
+// RequestIDFromContext returns the request ID of the HTTP request that started the run
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+
+	return requestID
+}
+
+// TraceHeadersFromContext returns the W3C trace context headers of the HTTP request that
+// started the run, so they can be forwarded to downstream calls made by tools
+func TraceHeadersFromContext(ctx context.Context) http.Header {
+	headers, _ := ctx.Value(traceHeadersKey).(http.Header)
+
+	return headers.Clone()
+}
+
+// withTracing assigns a request ID and propagates trace headers to the context and the response
+func withTracing(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(HeaderRequestID)
+		if requestID == "" {
+			requestID = newRequestID()
+		}
+		w.Header().Set(HeaderRequestID, requestID)
+
+		headers := http.Header{}
+		for _, name := range traceHeaders {
+			if value := r.Header.Get(name); value != "" {
+				headers.Set(name, value)
+				w.Header().Set(name, value)
+			}
+		}
+
+		ctx := context.WithValue(r.Context(), requestIDKey, requestID)
+		ctx = context.WithValue(ctx, traceHeadersKey, headers)
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func statusForError(ctx context.Context, err error) int {
+	switch {
+	case errors.Is(err, agent.ErrLimitReached):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, context.Canceled) || errors.Is(ctx.Err(), context.Canceled):
+		return StatusClientClosedRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeError(ctx context.Context, w http.ResponseWriter, status int, err error) {
+	requestID := RequestIDFromContext(ctx)
+	slog.ErrorContext(ctx, "agent request failed", "request_id", requestID, "status", status, "error", err)
+
+	message := err.Error()
+	if status == http.StatusInternalServerError {
+		message = internalServerErrorMessage
+	}
+
+	writeJSON(w, status, errorResponse{Error: message, RequestID: requestID})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		slog.Error("failed to encode response", "error", err)
+	}
+}
+
+func newRequestID() string {
+	id := make([]byte, requestIDBytes)
+	if _, err := rand.Read(id); err != nil {
+		return ""
+	}
+
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,187 @@
+package agenthttp_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agenthttp"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+type Question struct {
+	Text string `json:"text"`
+}
+
+type Answer struct {
+	Text string `json:"text" jsonschema_description:"The answer"`
+}
+
+func createHandler(t *testing.T, responses ...llm.LLMMessage) http.Handler {
+	t.Helper()
+
+	return createHandlerWithOptions(t, nil, responses...)
+}
+
+func createHandlerWithOptions(
+	t *testing.T, options []agenthttp.HandlerOption, responses ...llm.LLMMessage,
+) http.Handler {
+	t.Helper()
+
+	searchTool, err := llm.NewLLMTool(
+		llm.WithLLMToolName("search"),
+		llm.WithLLMToolDescription("Searches the web"),
+		llm.WithLLMToolParametersSchema[Question](),
+		llm.WithLLMToolCall(func(callID string, _ Question) (llm.BaseLLMToolResult, error) {
+			return llm.BaseLLMToolResult{ID: callID}, nil
+		}),
+	)
+	require.NoError(t, err)
+
+	answerAgent, err := agent.NewAgent(
+		agent.WithName[Answer]("answer_agent"),
+		agent.WithLLMConfig[Answer](llm.LLMConfig{
+			Type:   llm.LLMTypeOpenAI,
+			APIKey: "test-api-key",
+			Model:  "gpt-4",
+		}),
+		agent.WithBehavior[Answer]("You answer questions."),
+		agent.WithTool[Answer]("search", searchTool),
+		agent.WithToolLimit[Answer]("search", 1),
+	)
+	require.NoError(t, err)
+
+	return agenthttp.NewHandler[Question](
+		answerAgent.UsingLLM(llmtest.NewMockLLM(`{"text": "4"}`, responses...)), options...)
+}
+
+func TestHandler_Health(t *testing.T) {
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	createHandler(t).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, recorder.Body.String())
+	assert.NotEmpty(t, recorder.Header().Get(agenthttp.HeaderRequestID))
+}
+
+func TestHandler_Run(t *testing.T) {
+	t.Parallel()
+
+	handler := createHandler(t, llm.LLMMessage{Type: llm.LLMMessageTypeAssistant, End: true})
+	request := httptest.NewRequest(http.MethodPost, "/run", strings.NewReader(`{"text": "What is 2+2?"}`))
+	request.Header.Set(agenthttp.HeaderRequestID, "req-1")
+	request.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "req-1", recorder.Header().Get(agenthttp.HeaderRequestID))
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", recorder.Header().Get("traceparent"))
+
+	var result agent.AgentResult[Answer]
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &result))
+	assert.Equal(t, "4", result.Data.Text)
+}
+
+func TestHandler_Errors(t *testing.T) {
+	t.Parallel()
+
+	toolCall := llm.LLMToolCall{ID: "call_1", ToolName: "search", Args: `{}`}
+	tests := []struct {
+		name      string
+		body      string
+		responses []llm.LLMMessage
+		ctx       func() context.Context
+		status    int
+	}{
+		{
+			name:   "invalid body",
+			body:   `{`,
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "llm error",
+			body:   `{"text": "hi"}`,
+			status: http.StatusInternalServerError,
+		},
+		{
+			name: "limit reached",
+			body: `{"text": "hi"}`,
+			responses: []llm.LLMMessage{{
+				Type:      llm.LLMMessageTypeAssistant,
+				ToolCalls: []llm.LLMToolCall{toolCall, toolCall},
+			}},
+			status: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "client canceled",
+			body: `{"text": "hi"}`,
+			ctx: func() context.Context {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				return ctx
+			},
+			status: agenthttp.StatusClientClosedRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			request := httptest.NewRequest(http.MethodPost, "/run", strings.NewReader(tt.body))
+			if tt.ctx != nil {
+				request = request.WithContext(tt.ctx())
+			}
+
+			recorder := httptest.NewRecorder()
+			createHandler(t, tt.responses...).ServeHTTP(recorder, request)
+
+			assert.Equal(t, tt.status, recorder.Code)
+			assert.Contains(t, recorder.Body.String(), `"error"`)
+		})
+	}
+}
+
+func TestHandler_InternalErrorIsGeneric(t *testing.T) {
+	t.Parallel()
+
+	// given
+	request := httptest.NewRequest(http.MethodPost, "/run", strings.NewReader(`{"text": "hi"}`))
+	request.Header.Set(agenthttp.HeaderRequestID, "req-1")
+	recorder := httptest.NewRecorder()
+
+	// when
+	createHandler(t).ServeHTTP(recorder, request)
+
+	// then
+	require.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.JSONEq(t, `{"error": "internal server error", "request_id": "req-1"}`, recorder.Body.String())
+}
+
+func TestHandler_RequestBodyTooLarge(t *testing.T) {
+	t.Parallel()
+
+	// given
+	handler := createHandlerWithOptions(t, []agenthttp.HandlerOption{agenthttp.WithMaxRequestBodySize(16)},
+		llm.LLMMessage{Type: llm.LLMMessageTypeAssistant, End: true})
+	body := `{"text": "` + strings.Repeat("a", 32) + `"}`
+	recorder := httptest.NewRecorder()
+
+	// when
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/run", strings.NewReader(body)))
+
+	// then
+	assert.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+}
@@ -0,0 +1,149 @@
+// Package config loads the LLM configuration of agents from configuration files and the
+// environment through Viper.
+//
+// NewConfigFromViper reads these keys:
+//
+//	Viper key                       Environment variable            LLMConfig field
+//	openai.api_key                  OPENAI_API_KEY                  APIKey
+//	openai.model                    OPENAI_MODEL                    Model
+//	openai.temperature              OPENAI_TEMPERATURE              Temperature
+//	openai.max_tokens               OPENAI_MAX_TOKENS               MaxTokens
+//	openai.organization_id          OPENAI_ORGANIZATION_ID          OrganizationID
+//	openai.project_id               OPENAI_PROJECT_ID               ProjectID
+//	openai.base_url                 OPENAI_BASE_URL                 BaseURL
+//	openai.prompt_id                OPENAI_PROMPT_ID                PromptID
+//	openai.strict_function_calling  OPENAI_STRICT_FUNCTION_CALLING  StrictFunctionCalling
+//	openai.request_headers          -                               RequestHeaders
+//
+// Secret managers keep the API key out of the environment, see WithAWSSecretsManager and
+// WithGCPSecretManager.
+//
+// The environment variables apply when Viper is set up to replace dots with underscores,
+// so a config file and the environment can be mixed, e.g. to keep the API key out of the file:
+//
+//	v := viper.New()
+//	v.SetConfigFile("agent.toml")
+//	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+//	v.AutomaticEnv()
+//	if err := v.ReadInConfig(); err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	cfg, err := config.NewConfigFromViper(v)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	myAgent, err := agent.NewAgent(
+//		agent.WithName[Result]("my_agent"),
+//		agent.WithLLMConfig[Result](cfg.LLM),
+//		// ...
+//	)
+//
+// With a prefix set by v.SetEnvPrefix("APP"), the variables are APP_OPENAI_API_KEY and so on.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cast"
+	"github.com/spf13/viper"
+
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+// ErrInvalidConfigValue is returned when a configuration value has the wrong type
+var ErrInvalidConfigValue = errors.New("invalid config value")
+
+// Config is the configuration of an agent loaded from a configuration source
+type Config struct {
+	// LLM is the validated LLM configuration. The type is LLMTypeOpenAIPrompt when
+	// openai.prompt_id is set and LLMTypeOpenAI otherwise.
+	LLM llm.LLMConfig
+}
+
+// NewConfig reads the configuration from the environment variables listed in the package
+// documentation. Secrets fetched with options such as WithAWSSecretsManager override them.
+//
+// Example:
+//
+//	cfg, err := config.NewConfig(
+//		config.WithAWSSecretsManager("arn:aws:secretsmanager:eu-west-1:123456789012:secret:openai"),
+//	)
+func NewConfig(opts ...ConfigOption) (*Config, error) {
+	v := viper.New()
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+
+	return NewConfigFromViper(v, opts...)
+}
+
+// NewConfigFromViper reads the configuration from the merged configuration of v, see the
+// package documentation for the keys. Secrets fetched with options such as WithAWSSecretsManager
+// override the values of v. The LLM configuration is validated with LLMConfig.Validate, so an
+// API key written as ${ENV_VAR} is resolved from the environment too.
+func NewConfigFromViper(v *viper.Viper, opts ...ConfigOption) (*Config, error) {
+	if v == nil {
+		return nil, fmt.Errorf("failed to create config: %w: viper cannot be nil", ErrInvalidConfigValue)
+	}
+
+	llmConfig, err := readLLMConfig(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config: %w", err)
+	}
+
+	secretErr := newConfigOptions(opts).applySecrets(&llmConfig)
+
+	if err := llmConfig.Validate(); err != nil {
+		return nil, fmt.Errorf("failed to create config: llm config: %w", errors.Join(err, secretErr))
+	}
+
+	return &Config{LLM: llmConfig}, nil
+}
+
+func readLLMConfig(v *viper.Viper) (llm.LLMConfig, error) {
+	var (
+		cfg  llm.LLMConfig
+		errs []error
+	)
+
+	cfg.APIKey = read(v, "openai.api_key", cast.ToStringE, &errs)
+	cfg.Model = read(v, "openai.model", cast.ToStringE, &errs)
+	cfg.Temperature = read(v, "openai.temperature", cast.ToFloat64E, &errs)
+	cfg.MaxTokens = read(v, "openai.max_tokens", cast.ToIntE, &errs)
+	cfg.OrganizationID = read(v, "openai.organization_id", cast.ToStringE, &errs)
+	cfg.ProjectID = read(v, "openai.project_id", cast.ToStringE, &errs)
+	cfg.BaseURL = read(v, "openai.base_url", cast.ToStringE, &errs)
+	cfg.PromptID = read(v, "openai.prompt_id", cast.ToStringE, &errs)
+	cfg.StrictFunctionCalling = read(v, "openai.strict_function_calling", cast.ToBoolE, &errs)
+	cfg.RequestHeaders = read(v, "openai.request_headers", cast.ToStringMapStringE, &errs)
+
+	cfg.Type = llm.LLMTypeOpenAI
+	if cfg.PromptID != "" {
+		cfg.Type = llm.LLMTypeOpenAIPrompt
+	}
+
+	return cfg, errors.Join(errs...)
+}
+
+// read converts the value of key, collecting the conversion error in errs.
+// A missing key returns the zero value.
+func read[V any](v *viper.Viper, key string, convert func(any) (V, error), errs *[]error) V {
+	var zero V
+
+	raw := v.Get(key)
+	if raw == nil {
+		return zero
+	}
+
+	value, err := convert(raw)
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("%s: %w: %w", key, ErrInvalidConfigValue, err))
+
+		return zero
+	}
+
+	return value
+}
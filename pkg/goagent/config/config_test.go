@@ -0,0 +1,179 @@
+package config_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/config"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+func newViper(t *testing.T, configType, content string) *viper.Viper {
+	t.Helper()
+
+	v := viper.New()
+	v.SetConfigType(configType)
+	require.NoError(t, v.ReadConfig(strings.NewReader(content)))
+
+	return v
+}
+
+func TestNewConfigFromViper(t *testing.T) {
+	t.Parallel()
+
+	want := llm.LLMConfig{
+		Type:                  llm.LLMTypeOpenAI,
+		APIKey:                "sk-proj-test-key",
+		Model:                 "gpt-4o-mini",
+		Temperature:           0.2,
+		MaxTokens:             512,
+		OrganizationID:        "org-Abc123",
+		ProjectID:             "proj_Abc123",
+		BaseURL:               "http://localhost:4000",
+		StrictFunctionCalling: true,
+		RequestHeaders:        map[string]string{"x-route": "team-a"},
+	}
+
+	tests := []struct {
+		name       string
+		configType string
+		content    string
+	}{
+		{
+			name:       "yaml",
+			configType: "yaml",
+			content: `
+openai:
+  api_key: sk-proj-test-key
+  model: gpt-4o-mini
+  temperature: 0.2
+  max_tokens: 512
+  organization_id: org-Abc123
+  project_id: proj_Abc123
+  base_url: http://localhost:4000
+  strict_function_calling: true
+  request_headers:
+    X-Route: team-a
+`,
+		},
+		{
+			name:       "toml",
+			configType: "toml",
+			content: `
+[openai]
+api_key = "sk-proj-test-key"
+model = "gpt-4o-mini"
+temperature = 0.2
+max_tokens = 512
+organization_id = "org-Abc123"
+project_id = "proj_Abc123"
+base_url = "http://localhost:4000"
+strict_function_calling = true
+
+[openai.request_headers]
+X-Route = "team-a"
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			v := newViper(t, tt.configType, tt.content)
+
+			// when
+			cfg, err := config.NewConfigFromViper(v)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, want, cfg.LLM)
+		})
+	}
+}
+
+func TestNewConfigFromViper_Environment(t *testing.T) {
+	// given
+	t.Setenv("OPENAI_API_KEY", "sk-proj-env-key")
+	t.Setenv("OPENAI_MAX_TOKENS", "256")
+	t.Setenv("OPENAI_PROJECT_ID", "proj_env")
+
+	v := newViper(t, "yaml", `
+openai:
+  model: gpt-4o
+  max_tokens: 1024
+`)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+
+	// when
+	cfg, err := config.NewConfigFromViper(v)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "sk-proj-env-key", cfg.LLM.APIKey)
+	assert.Equal(t, "gpt-4o", cfg.LLM.Model)
+	assert.Equal(t, 256, cfg.LLM.MaxTokens)
+	assert.Equal(t, "proj_env", cfg.LLM.ProjectID)
+}
+
+func TestNewConfigFromViper_Prompt(t *testing.T) {
+	t.Parallel()
+
+	// given
+	v := newViper(t, "yaml", `
+openai:
+  api_key: test-key
+  prompt_id: pmpt_123
+`)
+
+	// when
+	cfg, err := config.NewConfigFromViper(v)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, llm.LLMTypeOpenAIPrompt, cfg.LLM.Type)
+	assert.Equal(t, "pmpt_123", cfg.LLM.PromptID)
+}
+
+func TestNewConfigFromViper_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr error
+	}{
+		{
+			name:    "missing api key",
+			content: "openai:\n  model: gpt-4o\n",
+			wantErr: validation.ErrValidationFailed,
+		},
+		{
+			name:    "negative max tokens",
+			content: "openai:\n  api_key: test-key\n  model: gpt-4o\n  max_tokens: -1\n",
+			wantErr: validation.ErrValidationFailed,
+		},
+		{
+			name:    "invalid max tokens",
+			content: "openai:\n  api_key: test-key\n  model: gpt-4o\n  max_tokens: many\n",
+			wantErr: config.ErrInvalidConfigValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := config.NewConfigFromViper(newViper(t, "yaml", tt.content))
+
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,9 @@
+package config
+
+import "net/http"
+
+// NewGCPSecretFetcher creates the GCP Secret Manager fetcher with a custom endpoint and
+// client, so tests can run against a fake server
+func NewGCPSecretFetcher(endpoint string, client *http.Client) SecretFetcher {
+	return gcpSecretManager{endpoint: endpoint, client: client}
+}
@@ -0,0 +1,259 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"golang.org/x/oauth2/google"
+
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+const (
+	defaultSecretCacheTTL = 5 * time.Minute
+	secretFetchTimeout    = 30 * time.Second
+
+	gcpSecretManagerEndpoint = "https://secretmanager.googleapis.com/v1/"
+	gcpCloudPlatformScope    = "https://www.googleapis.com/auth/cloud-platform"
+)
+
+// ErrSecretFetchFailed is returned when a secret cannot be fetched from a secret manager or
+// does not contain an API key
+var ErrSecretFetchFailed = errors.New("secret fetch failed")
+
+// SecretFetcher fetches the value of a secret from a secret manager
+type SecretFetcher interface {
+	FetchSecret(ctx context.Context, name string) ([]byte, error)
+}
+
+// ConfigOption configures how a Config is loaded
+type ConfigOption func(o *configOptions)
+
+type configOptions struct {
+	secrets  []secretSource
+	cacheTTL time.Duration
+}
+
+type secretSource struct {
+	fetcher SecretFetcher
+	name    string
+}
+
+// WithAWSSecretsManager merges the LLM config stored as a JSON secret in AWS Secrets Manager into
+// the config. The secret must contain "api_key" and may contain "model" and "temperature":
+//
+//	{"api_key": "sk-...", "model": "gpt-4o", "temperature": 0.2}
+//
+// The AWS client is configured from the environment like the AWS CLI, e.g. with AWS_REGION and
+// AWS_PROFILE. When the secret cannot be fetched, the failure is logged and the config keeps
+// the values from the environment or Viper.
+func WithAWSSecretsManager(secretARN string) ConfigOption {
+	return WithSecretFetcher(awsSecretsManager{}, secretARN)
+}
+
+// WithGCPSecretManager merges the LLM config stored as a JSON secret in GCP Secret Manager into
+// the config, like WithAWSSecretsManager. resourceName is the name of the secret version, e.g.
+// "projects/my-project/secrets/openai/versions/latest". The client uses Application Default
+// Credentials.
+func WithGCPSecretManager(resourceName string) ConfigOption {
+	return WithSecretFetcher(gcpSecretManager{endpoint: gcpSecretManagerEndpoint}, resourceName)
+}
+
+// WithSecretFetcher merges the LLM config stored as the JSON secret name in a custom secret
+// manager into the config, see WithAWSSecretsManager for the format and the fallback
+func WithSecretFetcher(fetcher SecretFetcher, name string) ConfigOption {
+	return func(o *configOptions) {
+		o.secrets = append(o.secrets, secretSource{fetcher: fetcher, name: name})
+	}
+}
+
+// WithSecretCacheTTL sets how long fetched secrets are reused by later calls in the process.
+// Defaults to 5 minutes, zero disables the cache.
+func WithSecretCacheTTL(ttl time.Duration) ConfigOption {
+	return func(o *configOptions) {
+		o.cacheTTL = ttl
+	}
+}
+
+func newConfigOptions(opts []ConfigOption) configOptions {
+	options := configOptions{cacheTTL: defaultSecretCacheTTL}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return options
+}
+
+// llmSecret is the JSON value of an LLM config secret
+type llmSecret struct {
+	APIKey      string   `json:"api_key"`
+	Model       string   `json:"model"`
+	Temperature *float64 `json:"temperature"`
+}
+
+// applySecrets merges the secrets into cfg in the order they were added. A secret which
+// cannot be fetched is skipped and its error returned, so callers can report it when the
+// config turns out to be invalid without it.
+func (o configOptions) applySecrets(cfg *llm.LLMConfig) error {
+	var errs []error
+	for _, source := range o.secrets {
+		secret, err := o.fetchSecret(source)
+		if err != nil {
+			slog.Warn("failed to fetch LLM config secret, falling back to the environment",
+				"secret", source.name, "error", err)
+			errs = append(errs, err)
+
+			continue
+		}
+
+		cfg.APIKey = secret.APIKey
+		if secret.Model != "" {
+			cfg.Model = secret.Model
+		}
+		if secret.Temperature != nil {
+			cfg.Temperature = *secret.Temperature
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (o configOptions) fetchSecret(source secretSource) (llmSecret, error) {
+	key := fmt.Sprintf("%T/%s", source.fetcher, source.name)
+	if secret, ok := secretCache.get(key, o.cacheTTL); ok {
+		return secret, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), secretFetchTimeout)
+	defer cancel()
+
+	value, err := source.fetcher.FetchSecret(ctx, source.name)
+	if err != nil {
+		return llmSecret{}, fmt.Errorf("%w: %s: %w", ErrSecretFetchFailed, source.name, err)
+	}
+
+	var secret llmSecret
+	if err := json.Unmarshal(value, &secret); err != nil {
+		return llmSecret{}, fmt.Errorf("%w: %s: invalid JSON: %w", ErrSecretFetchFailed, source.name, err)
+	}
+	if secret.APIKey == "" {
+		return llmSecret{}, fmt.Errorf("%w: %s: api_key is missing", ErrSecretFetchFailed, source.name)
+	}
+
+	if o.cacheTTL > 0 {
+		secretCache.put(key, secret)
+	}
+
+	return secret, nil
+}
+
+var secretCache = &fetchedSecrets{entries: make(map[string]fetchedSecret)}
+
+type fetchedSecret struct {
+	secret    llmSecret
+	fetchedAt time.Time
+}
+
+// fetchedSecrets caches fetched secrets by fetcher type and secret name
+type fetchedSecrets struct {
+	mu      sync.Mutex
+	entries map[string]fetchedSecret
+}
+
+func (c *fetchedSecrets) get(key string, ttl time.Duration) (llmSecret, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || time.Since(entry.fetchedAt) >= ttl {
+		return llmSecret{}, false
+	}
+
+	return entry.secret, true
+}
+
+func (c *fetchedSecrets) put(key string, secret llmSecret) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = fetchedSecret{secret: secret, fetchedAt: time.Now()}
+}
+
+type awsSecretsManager struct{}
+
+func (awsSecretsManager) FetchSecret(ctx context.Context, secretARN string) ([]byte, error) {
+	awsConfig, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	output, err := secretsmanager.NewFromConfig(awsConfig).GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: &secretARN,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if output.SecretString != nil {
+		return []byte(*output.SecretString), nil
+	}
+
+	return output.SecretBinary, nil
+}
+
+// gcpSecretManager accesses secret versions through the Secret Manager REST API
+type gcpSecretManager struct {
+	endpoint string
+	client   *http.Client
+}
+
+func (g gcpSecretManager) FetchSecret(ctx context.Context, resourceName string) ([]byte, error) {
+	client := g.client
+	if client == nil {
+		var err error
+		client, err = google.DefaultClient(ctx, gcpCloudPlatformScope)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find GCP credentials: %w", err)
+		}
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, g.endpoint+resourceName+":access", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d: %s", response.StatusCode, body)
+	}
+
+	var version struct {
+		Payload struct {
+			Data []byte `json:"data"`
+		} `json:"payload"`
+	}
+	if err := json.Unmarshal(body, &version); err != nil {
+		return nil, fmt.Errorf("invalid response: %w", err)
+	}
+
+	return version.Payload.Data, nil
+}
@@ -0,0 +1,238 @@
+package config_test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/config"
+)
+
+const baseConfig = `
+openai:
+  api_key: file-key
+  model: gpt-4o-mini
+  temperature: 0.5
+`
+
+var errSecretUnavailable = errors.New("secret unavailable")
+
+// stubSecretFetcher returns a fixed secret value and counts the fetches
+type stubSecretFetcher struct {
+	value   string
+	err     error
+	fetches atomic.Int32
+}
+
+func (s *stubSecretFetcher) FetchSecret(_ context.Context, _ string) ([]byte, error) {
+	s.fetches.Add(1)
+
+	return []byte(s.value), s.err
+}
+
+func TestWithSecretFetcher(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		value           string
+		wantAPIKey      string
+		wantModel       string
+		wantTemperature float64
+	}{
+		{
+			name:            "all fields",
+			value:           `{"api_key": "secret-key", "model": "gpt-4o", "temperature": 0}`,
+			wantAPIKey:      "secret-key",
+			wantModel:       "gpt-4o",
+			wantTemperature: 0,
+		},
+		{
+			name:            "api key only",
+			value:           `{"api_key": "secret-key"}`,
+			wantAPIKey:      "secret-key",
+			wantModel:       "gpt-4o-mini",
+			wantTemperature: 0.5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			fetcher := &stubSecretFetcher{value: tt.value}
+
+			// when
+			cfg, err := config.NewConfigFromViper(newViper(t, "yaml", baseConfig),
+				config.WithSecretFetcher(fetcher, "secret-"+t.Name()),
+			)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantAPIKey, cfg.LLM.APIKey)
+			assert.Equal(t, tt.wantModel, cfg.LLM.Model)
+			assert.InDelta(t, tt.wantTemperature, cfg.LLM.Temperature, 0)
+		})
+	}
+}
+
+func TestWithSecretFetcher_Fallback(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		fetcher *stubSecretFetcher
+	}{
+		{name: "fetch error", fetcher: &stubSecretFetcher{err: errSecretUnavailable}},
+		{name: "invalid JSON", fetcher: &stubSecretFetcher{value: `api_key`}},
+		{name: "missing api key", fetcher: &stubSecretFetcher{value: `{"model": "gpt-4o"}`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// when
+			cfg, err := config.NewConfigFromViper(newViper(t, "yaml", baseConfig),
+				config.WithSecretFetcher(tt.fetcher, "secret-"+t.Name()),
+			)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, "file-key", cfg.LLM.APIKey)
+			assert.Equal(t, "gpt-4o-mini", cfg.LLM.Model)
+		})
+	}
+}
+
+func TestWithSecretFetcher_FallbackInvalid(t *testing.T) {
+	t.Parallel()
+
+	// when
+	_, err := config.NewConfigFromViper(newViper(t, "yaml", "openai:\n  model: gpt-4o\n"),
+		config.WithSecretFetcher(&stubSecretFetcher{err: errSecretUnavailable}, "secret-"+t.Name()),
+	)
+
+	// then
+	require.ErrorIs(t, err, validation.ErrValidationFailed)
+	require.ErrorIs(t, err, config.ErrSecretFetchFailed)
+	require.ErrorIs(t, err, errSecretUnavailable)
+}
+
+func TestWithSecretCacheTTL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		opts        []config.ConfigOption
+		wantFetches int32
+	}{
+		{name: "default TTL", wantFetches: 1},
+		{name: "cache disabled", opts: []config.ConfigOption{config.WithSecretCacheTTL(0)}, wantFetches: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			fetcher := &stubSecretFetcher{value: `{"api_key": "secret-key"}`}
+			opts := append([]config.ConfigOption{config.WithSecretFetcher(fetcher, "secret-"+t.Name())}, tt.opts...)
+
+			// when
+			for range 2 {
+				cfg, err := config.NewConfigFromViper(newViper(t, "yaml", baseConfig), opts...)
+				require.NoError(t, err)
+				assert.Equal(t, "secret-key", cfg.LLM.APIKey)
+			}
+
+			// then
+			assert.Equal(t, tt.wantFetches, fetcher.fetches.Load())
+		})
+	}
+}
+
+func TestWithAWSSecretsManager(t *testing.T) {
+	// given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, "secretsmanager.GetSecretValue", r.Header.Get("X-Amz-Target"))
+		assert.JSONEq(t, `{"SecretId": "arn:aws:secretsmanager:eu-west-1:123:secret:openai"}`, string(body))
+
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		_, _ = w.Write([]byte(`{"SecretString": "{\"api_key\": \"aws-key\", \"model\": \"gpt-4o\"}"}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("AWS_REGION", "eu-west-1")
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+	t.Setenv("AWS_ENDPOINT_URL_SECRETS_MANAGER", server.URL)
+	t.Setenv("OPENAI_API_KEY", "env-key")
+	t.Setenv("OPENAI_MODEL", "gpt-4o-mini")
+
+	// when
+	cfg, err := config.NewConfig(
+		config.WithAWSSecretsManager("arn:aws:secretsmanager:eu-west-1:123:secret:openai"),
+		config.WithSecretCacheTTL(0),
+	)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "aws-key", cfg.LLM.APIKey)
+	assert.Equal(t, "gpt-4o", cfg.LLM.Model)
+}
+
+func TestGCPSecretManager(t *testing.T) {
+	t.Parallel()
+
+	// given
+	const resourceName = "projects/my-project/secrets/openai/versions/latest"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/v1/"+resourceName+":access", r.URL.Path)
+
+		data := base64.StdEncoding.EncodeToString([]byte(`{"api_key": "gcp-key"}`))
+		_, _ = w.Write([]byte(`{"name": "` + resourceName + `", "payload": {"data": "` + data + `"}}`))
+	}))
+	defer server.Close()
+
+	fetcher := config.NewGCPSecretFetcher(server.URL+"/v1/", server.Client())
+
+	// when
+	cfg, err := config.NewConfigFromViper(newViper(t, "yaml", baseConfig),
+		config.WithSecretFetcher(fetcher, resourceName),
+	)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "gcp-key", cfg.LLM.APIKey)
+}
+
+func TestGCPSecretManager_ErrorStatus(t *testing.T) {
+	t.Parallel()
+
+	// given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, `{"error": {"code": 403}}`, http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	fetcher := config.NewGCPSecretFetcher(server.URL+"/v1/", server.Client())
+
+	// when
+	_, err := fetcher.FetchSecret(context.Background(), "projects/p/secrets/s/versions/1")
+
+	// then
+	require.ErrorContains(t, err, "unexpected status 403")
+}
@@ -0,0 +1,151 @@
+// Package eval measures the quality of agents against golden datasets.
+//
+// Example:
+//
+//	evaluator := eval.NewEvaluator(eval.ExactMatchScorer[Result]())
+//	report, err := evaluator.Evaluate(ctx, calculatorAgent, []eval.EvalCase[Result]{
+//		{Name: "adds numbers", Input: AddNumbers{Num1: 3, Num2: 5}, Expected: Result{Sum: 8}},
+//		{Name: "adds negatives", Input: AddNumbers{Num1: -3, Num2: -5}, Expected: Result{Sum: -8}},
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Printf("mean %.2f, p95 %.2f\n", report.MeanScore, report.P95Score)
+package eval
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+const p95 = 0.95
+
+// ErrEmptyDataset is returned when Evaluate is called without cases
+var ErrEmptyDataset = errors.New("dataset is empty")
+
+// Scorer rates how close the actual output of an agent is to the expected one, from 0 for
+// a wrong output to 1 for a correct one
+type Scorer[T any] func(expected, actual *T) float64
+
+// EvalCase is an input of the agent together with the output expected for it
+type EvalCase[T any] struct {
+	// Name identifies the case in the report. Optional.
+	Name     string
+	Input    any
+	Expected T
+}
+
+// EvalCaseResult is the outcome of a single case
+type EvalCaseResult struct {
+	Name  string  `json:"name,omitempty"`
+	Input any     `json:"input"`
+	Score float64 `json:"score"`
+	// Output is the output of the agent, nil when the run failed
+	Output any `json:"output,omitempty"`
+	// Error is the error of the run. Failed runs score 0.
+	Error    string         `json:"error,omitempty"`
+	Usage    llm.TokenUsage `json:"usage"`
+	Duration time.Duration  `json:"duration"`
+}
+
+// EvalReport summarizes the evaluation of an agent on a dataset
+type EvalReport struct {
+	MeanScore float64 `json:"mean_score"`
+	// P95Score is the score which 95% of the cases reach or fall below, by the nearest-rank method
+	P95Score float64          `json:"p95_score"`
+	Cases    []EvalCaseResult `json:"cases"`
+	// FailedRuns is the number of cases whose run returned an error
+	FailedRuns int            `json:"failed_runs"`
+	TotalUsage llm.TokenUsage `json:"total_usage"`
+	Duration   time.Duration  `json:"duration"`
+}
+
+// Evaluator runs agents on datasets and scores their outputs
+type Evaluator[T any] struct {
+	scorer Scorer[T]
+}
+
+// NewEvaluator creates an evaluator which scores the outputs of agents with scorer
+func NewEvaluator[T any](scorer func(expected, actual *T) float64) *Evaluator[T] {
+	return &Evaluator[T]{scorer: scorer}
+}
+
+// Evaluate runs the agent on every case of the dataset in order and scores its outputs.
+// A failed run scores 0 and is reported in the case result instead of failing the
+// evaluation. Evaluate returns an error when the dataset is empty or ctx is done.
+func (e *Evaluator[T]) Evaluate(ctx context.Context, a *agent.Agent[T], dataset []EvalCase[T]) (*EvalReport, error) {
+	if e.scorer == nil {
+		return nil, fmt.Errorf("scorer: %w: value cannot be nil", validation.ErrValidationFailed)
+	}
+	if len(dataset) == 0 {
+		return nil, ErrEmptyDataset
+	}
+
+	start := time.Now()
+	report := &EvalReport{Cases: make([]EvalCaseResult, 0, len(dataset))}
+
+	for _, evalCase := range dataset {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("failed to evaluate agent: %w", err)
+		}
+
+		caseResult := e.evaluateCase(ctx, a, evalCase)
+		if caseResult.Error != "" {
+			report.FailedRuns++
+		}
+		report.TotalUsage = report.TotalUsage.Add(caseResult.Usage)
+		report.Cases = append(report.Cases, caseResult)
+	}
+
+	report.MeanScore, report.P95Score = summarize(report.Cases)
+	report.Duration = time.Since(start)
+
+	return report, nil
+}
+
+func (e *Evaluator[T]) evaluateCase(ctx context.Context, a *agent.Agent[T], evalCase EvalCase[T]) EvalCaseResult {
+	caseResult := EvalCaseResult{Name: evalCase.Name, Input: evalCase.Input}
+
+	start := time.Now()
+	result, err := a.Run(ctx, evalCase.Input)
+	caseResult.Duration = time.Since(start)
+
+	if result != nil {
+		for _, msg := range result.Messages {
+			caseResult.Usage = caseResult.Usage.Add(msg.Usage)
+		}
+	}
+
+	if err != nil {
+		caseResult.Error = err.Error()
+
+		return caseResult
+	}
+
+	caseResult.Output = result.Data
+	caseResult.Score = e.scorer(&evalCase.Expected, result.Data)
+
+	return caseResult
+}
+
+func summarize(cases []EvalCaseResult) (mean, p95Score float64) {
+	scores := make([]float64, len(cases))
+	var sum float64
+	for i, caseResult := range cases {
+		scores[i] = caseResult.Score
+		sum += caseResult.Score
+	}
+	slices.Sort(scores)
+
+	rank := int(math.Ceil(p95*float64(len(scores)))) - 1
+
+	return sum / float64(len(scores)), scores[max(rank, 0)]
+}
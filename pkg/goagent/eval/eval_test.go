@@ -0,0 +1,185 @@
+package eval_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/eval"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+type Answer struct {
+	Text string `json:"text" jsonschema_description:"The answer"`
+}
+
+var testLLMConfig = llm.LLMConfig{Type: llm.LLMTypeOpenAI, APIKey: "test-api-key", Model: "gpt-4"}
+
+func endMessage(usage llm.TokenUsage) llm.LLMMessage {
+	return llm.LLMMessage{Type: llm.LLMMessageTypeAssistant, Content: "done", End: true, Usage: usage}
+}
+
+func newAgent[T any](t *testing.T, name string, mockLLM llm.LLM) *agent.Agent[T] {
+	t.Helper()
+
+	a, err := agent.NewAgent(
+		agent.WithName[T](name),
+		agent.WithLLMConfig[T](testLLMConfig),
+		agent.WithBehavior[T]("You answer questions."),
+	)
+	require.NoError(t, err)
+
+	return a.UsingLLM(mockLLM)
+}
+
+func TestEvaluator_Evaluate(t *testing.T) {
+	t.Parallel()
+
+	// given
+	mockLLM := llmtest.NewMockLLM(`{"text": "4"}`,
+		endMessage(llm.TokenUsage{InputTokens: 10, OutputTokens: 2}),
+		endMessage(llm.TokenUsage{InputTokens: 20, OutputTokens: 4}),
+	)
+	answerAgent := newAgent[Answer](t, "answer_agent", mockLLM)
+	evaluator := eval.NewEvaluator(eval.ExactMatchScorer[Answer]())
+
+	// when
+	report, err := evaluator.Evaluate(context.Background(), answerAgent, []eval.EvalCase[Answer]{
+		{Name: "correct", Input: "What is 2+2?", Expected: Answer{Text: "4"}},
+		{Name: "wrong", Input: "What is 2+3?", Expected: Answer{Text: "5"}},
+		{Name: "failed", Input: "What is 2+4?", Expected: Answer{Text: "6"}},
+	})
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, report.Cases, 3)
+
+	assert.Equal(t, "correct", report.Cases[0].Name)
+	assert.InDelta(t, 1, report.Cases[0].Score, 0)
+	assert.Equal(t, &Answer{Text: "4"}, report.Cases[0].Output)
+	assert.Equal(t, llm.TokenUsage{InputTokens: 10, OutputTokens: 2}, report.Cases[0].Usage)
+	assert.InDelta(t, 0, report.Cases[1].Score, 0)
+	assert.InDelta(t, 0, report.Cases[2].Score, 0)
+	assert.Contains(t, report.Cases[2].Error, agent.ErrLLMCall.Error())
+	assert.Nil(t, report.Cases[2].Output)
+
+	assert.InDelta(t, 1.0/3, report.MeanScore, 1e-9)
+	assert.InDelta(t, 1, report.P95Score, 0)
+	assert.Equal(t, 1, report.FailedRuns)
+	assert.Equal(t, llm.TokenUsage{InputTokens: 30, OutputTokens: 6}, report.TotalUsage)
+	assert.Positive(t, report.Duration)
+}
+
+func TestEvaluator_Evaluate_EmptyDataset(t *testing.T) {
+	t.Parallel()
+
+	answerAgent := newAgent[Answer](t, "answer_agent", llmtest.NewMockLLM(`{"text": "4"}`))
+
+	_, err := eval.NewEvaluator(eval.ExactMatchScorer[Answer]()).Evaluate(context.Background(), answerAgent, nil)
+
+	require.ErrorIs(t, err, eval.ErrEmptyDataset)
+}
+
+func TestEvaluator_Evaluate_NilScorer(t *testing.T) {
+	t.Parallel()
+
+	answerAgent := newAgent[Answer](t, "answer_agent", llmtest.NewMockLLM(`{"text": "4"}`))
+
+	_, err := eval.NewEvaluator[Answer](nil).Evaluate(context.Background(), answerAgent, []eval.EvalCase[Answer]{
+		{Input: "What is 2+2?", Expected: Answer{Text: "4"}},
+	})
+
+	require.ErrorIs(t, err, validation.ErrValidationFailed)
+}
+
+func TestEvaluator_Evaluate_CancelledContext(t *testing.T) {
+	t.Parallel()
+
+	// given
+	answerAgent := newAgent[Answer](t, "answer_agent", llmtest.NewMockLLM(`{"text": "4"}`))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// when
+	_, err := eval.NewEvaluator(eval.ExactMatchScorer[Answer]()).Evaluate(ctx, answerAgent, []eval.EvalCase[Answer]{
+		{Input: "What is 2+2?", Expected: Answer{Text: "4"}},
+	})
+
+	// then
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestExactMatchScorer(t *testing.T) {
+	t.Parallel()
+
+	scorer := eval.ExactMatchScorer[Answer]()
+
+	assert.InDelta(t, 1, scorer(&Answer{Text: "4"}, &Answer{Text: "4"}), 0)
+	assert.InDelta(t, 0, scorer(&Answer{Text: "4"}, &Answer{Text: "four"}), 0)
+	assert.InDelta(t, 0, scorer(&Answer{Text: "4"}, nil), 0)
+}
+
+func TestLLMJudgeScorer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		judgement string
+		responses []llm.LLMMessage
+		want      float64
+	}{
+		{
+			name:      "score",
+			judgement: `{"score": 0.8, "reasoning": "same number, different wording"}`,
+			responses: []llm.LLMMessage{endMessage(llm.TokenUsage{})},
+			want:      0.8,
+		},
+		{
+			name:      "clamped",
+			judgement: `{"score": 7, "reasoning": "perfect"}`,
+			responses: []llm.LLMMessage{endMessage(llm.TokenUsage{})},
+			want:      1,
+		},
+		{
+			name:      "failed judge",
+			judgement: `{"score": 1, "reasoning": "perfect"}`,
+			responses: nil,
+			want:      0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			judgeLLM := llmtest.NewMockLLM(tt.judgement, tt.responses...)
+			scorer := eval.LLMJudgeScorer[Answer](newAgent[eval.EvalScore](t, "judge_agent", judgeLLM))
+
+			// when
+			score := scorer(&Answer{Text: "4"}, &Answer{Text: "four"})
+
+			// then
+			assert.InDelta(t, tt.want, score, 0)
+		})
+	}
+}
+
+func TestLLMJudgeScorer_SendsBothOutputs(t *testing.T) {
+	t.Parallel()
+
+	// given
+	judgeLLM := llmtest.NewMockLLM(`{"score": 1, "reasoning": "same"}`, endMessage(llm.TokenUsage{}))
+	scorer := eval.LLMJudgeScorer[Answer](newAgent[eval.EvalScore](t, "judge_agent", judgeLLM))
+
+	// when
+	scorer(&Answer{Text: "4"}, &Answer{Text: "four"})
+
+	// then
+	userMessage := judgeLLM.Calls()[0][1]
+	assert.JSONEq(t, `{"expected": {"text": "4"}, "actual": {"text": "four"}}`, userMessage.Content)
+}
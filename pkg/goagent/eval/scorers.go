@@ -0,0 +1,60 @@
+package eval
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+)
+
+// EvalScore is the output of the judge agent of LLMJudgeScorer
+type EvalScore struct {
+	Score     float64 `json:"score" jsonschema_description:"Match of actual and expected output, 0 to 1"`
+	Reasoning string  `json:"reasoning" jsonschema_description:"Short explanation of the score"`
+}
+
+// judgeInput is the input of the judge agent
+type judgeInput[T any] struct {
+	Expected *T `json:"expected"`
+	Actual   *T `json:"actual"`
+}
+
+// ExactMatchScorer scores 1 when the actual output deeply equals the expected one and 0 otherwise
+func ExactMatchScorer[T any]() func(expected, actual *T) float64 {
+	return func(expected, actual *T) float64 {
+		if actual == nil || !reflect.DeepEqual(expected, actual) {
+			return 0
+		}
+
+		return 1
+	}
+}
+
+// LLMJudgeScorer asks judgeAgent to rate the actual output against the expected one, for
+// outputs such as free text which are correct without matching exactly. The judge receives
+// both outputs as JSON with the fields "expected" and "actual", so its behavior should
+// describe what makes an output correct. Scores are clamped to [0, 1] and a failed judge
+// run scores 0.
+//
+// Example:
+//
+//	judge, err := agent.NewAgent(
+//		agent.WithName[eval.EvalScore]("summary_judge"),
+//		agent.WithLLMConfig[eval.EvalScore](llmConfig),
+//		agent.WithBehavior[eval.EvalScore]("Rate if the actual summary states the facts of the expected one."),
+//	)
+//	evaluator := eval.NewEvaluator(eval.LLMJudgeScorer[Summary](judge))
+func LLMJudgeScorer[T any](judgeAgent *agent.Agent[EvalScore]) func(expected, actual *T) float64 {
+	return func(expected, actual *T) float64 {
+		if actual == nil {
+			return 0
+		}
+
+		result, err := judgeAgent.Run(context.Background(), judgeInput[T]{Expected: expected, Actual: actual})
+		if err != nil || result.Data == nil {
+			return 0
+		}
+
+		return min(max(result.Data.Score, 0), 1)
+	}
+}
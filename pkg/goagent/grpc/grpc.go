@@ -0,0 +1,171 @@
+// Package grpc exposes agent execution as a gRPC service defined in agentpb/agent.proto.
+//
+// Example:
+//
+//	server := grpc.NewServer()
+//	agentpb.RegisterAgentServiceServer(server, agentgrpc.NewAgentServer(answerAgent))
+//
+//	listener, err := net.Listen("tcp", ":9090")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	log.Fatal(server.Serve(listener))
+//
+// The input of a run is sent as JSON in RunRequest.input. RunResponse carries the JSON encoded
+// result data and the messages of the run. RunStream sends every message as soon as it is
+// produced, followed by a chunk with the final RunResponse.
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/grpc/agentpb"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+// AgentServiceServer is the server API of the agent service
+type AgentServiceServer = agentpb.AgentServiceServer
+
+type agentServer[T any] struct {
+	agentpb.UnimplementedAgentServiceServer
+
+	agent *agent.Agent[T]
+}
+
+// NewAgentServer creates a gRPC service which runs the agent. Errors are returned with these
+// status codes: tool limit and budget errors as ResourceExhausted, run timeouts as
+// DeadlineExceeded, cancellations by the client as Canceled, invalid input as InvalidArgument
+// and other errors as Internal.
+func NewAgentServer[T any](a *agent.Agent[T]) AgentServiceServer {
+	return &agentServer[T]{agent: a}
+}
+
+func (s *agentServer[T]) Run(ctx context.Context, request *agentpb.RunRequest) (*agentpb.RunResponse, error) {
+	input, err := requestInput(request)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.agent.Run(ctx, input)
+	if err != nil {
+		return nil, statusForError(ctx, err)
+	}
+
+	return toRunResponse(result)
+}
+
+func (s *agentServer[T]) RunStream(request *agentpb.RunRequest, stream agentpb.AgentService_RunStreamServer) error {
+	input, err := requestInput(request)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	var sendErr error
+	ctx = agent.WithStreamCallback(ctx, func(_ context.Context, msg llm.LLMMessage) {
+		if sendErr != nil {
+			return
+		}
+
+		sendErr = stream.Send(&agentpb.RunChunk{Chunk: &agentpb.RunChunk_Message{Message: toMessage(msg)}})
+		if sendErr != nil {
+			cancel()
+		}
+	})
+
+	result, err := s.agent.Run(ctx, input)
+	if sendErr != nil {
+		return sendErr
+	}
+	if err != nil {
+		return statusForError(ctx, err)
+	}
+
+	response, err := toRunResponse(result)
+	if err != nil {
+		return err
+	}
+
+	return stream.Send(&agentpb.RunChunk{Chunk: &agentpb.RunChunk_Result{Result: response}})
+}
+
+func requestInput(request *agentpb.RunRequest) (json.RawMessage, error) {
+	if !json.Valid(request.GetInput()) {
+		return nil, status.Error(codes.InvalidArgument, "input must be valid JSON")
+	}
+
+	return json.RawMessage(request.GetInput()), nil
+}
+
+func toRunResponse[T any](result *agent.AgentResult[T]) (*agentpb.RunResponse, error) {
+	data, err := json.Marshal(result.Data)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to marshal result data: %v", err)
+	}
+
+	messages := make([]*agentpb.Message, 0, len(result.Messages))
+	for _, msg := range result.Messages {
+		messages = append(messages, toMessage(msg))
+	}
+
+	return &agentpb.RunResponse{Data: data, Messages: messages}, nil
+}
+
+func toMessage(msg llm.LLMMessage) *agentpb.Message {
+	message := &agentpb.Message{
+		Type:    string(msg.Type),
+		Content: msg.Content,
+		End:     msg.End,
+	}
+	if !msg.Timestamp.IsZero() {
+		message.TimestampUnixMs = msg.Timestamp.UnixMilli()
+	}
+
+	for _, toolCall := range msg.ToolCalls {
+		message.ToolCalls = append(message.ToolCalls, &agentpb.ToolCall{
+			Id:       toolCall.ID,
+			ToolName: toolCall.ToolName,
+			Args:     toolCall.Args,
+		})
+	}
+
+	for _, toolResult := range msg.ToolResults {
+		result, err := llm.MarshalToolResult(toolResult)
+		if err != nil {
+			result, _ = json.Marshal(map[string]string{"error": "failed to marshal tool result: " + err.Error()})
+		}
+
+		message.ToolResults = append(message.ToolResults, &agentpb.ToolResult{
+			Id:     toolResult.GetID(),
+			Result: string(result),
+		})
+	}
+
+	return message
+}
+
+func statusForError(ctx context.Context, err error) error {
+	code := codes.Internal
+
+	switch {
+	case ctx.Err() != nil:
+		code = status.FromContextError(ctx.Err()).Code()
+	case errors.Is(err, agent.ErrRunTimeout):
+		code = codes.DeadlineExceeded
+	case errors.Is(err, agent.ErrLimitReached), errors.Is(err, agent.ErrBudgetExceeded):
+		code = codes.ResourceExhausted
+	}
+
+	slog.ErrorContext(ctx, "agent gRPC request failed", "code", code.String(), "error", err)
+
+	return status.Error(code, err.Error())
+}
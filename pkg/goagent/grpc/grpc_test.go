@@ -0,0 +1,247 @@
+package grpc_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	agentgrpc "github.com/vitalii-honchar/go-agent/pkg/goagent/grpc"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/grpc/agentpb"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+type Question struct {
+	Text string `json:"text"`
+}
+
+type Answer struct {
+	Text string `json:"text" jsonschema_description:"The answer"`
+}
+
+const (
+	searchCompletion = `{
+	"id": "chatcmpl-%d",
+	"object": "chat.completion",
+	"created": 1700000000,
+	"model": "gpt-4o-mini",
+	"choices": [{"index": 0, "finish_reason": "tool_calls", "message": {"role": "assistant", "tool_calls": [
+		{"id": "call_%d", "type": "function", "function": {"name": "search", "arguments": "{\"text\": \"life\"}"}}
+	]}}]
+}`
+	stopCompletion = `{
+	"id": "chatcmpl-%d",
+	"object": "chat.completion",
+	"created": 1700000000,
+	"model": "gpt-4o-mini",
+	"choices": [{"index": 0, "finish_reason": "stop", "message": {
+		"role": "assistant", "content": "{\"text\": \"42\"}"
+	}}]
+}`
+)
+
+// openAIServer is a fake OpenAI API which requests the search tool the given number of
+// times and then answers with the final result
+type openAIServer struct {
+	mu       sync.Mutex
+	searches int
+	requests int
+}
+
+func (s *openAIServer) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	s.requests++
+	completion := fmt.Sprintf(stopCompletion, s.requests)
+	if s.requests <= s.searches {
+		completion = fmt.Sprintf(searchCompletion, s.requests, s.requests)
+	}
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(completion))
+}
+
+// serverTransport sends every request to the target server
+type serverTransport struct {
+	target *url.URL
+}
+
+func (s serverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = s.target.Scheme
+	req.URL.Host = s.target.Host
+
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func createClient(t *testing.T, searches int) agentpb.AgentServiceClient {
+	t.Helper()
+
+	server := httptest.NewServer(&openAIServer{searches: searches})
+	t.Cleanup(server.Close)
+
+	target, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	searchTool, err := llm.NewLLMTool(
+		llm.WithLLMToolName("search"),
+		llm.WithLLMToolDescription("Searches the web"),
+		llm.WithLLMToolParametersSchema[Question](),
+		llm.WithLLMToolCall(func(callID string, _ Question) (llm.BaseLLMToolResult, error) {
+			return llm.BaseLLMToolResult{ID: callID}, nil
+		}),
+	)
+	require.NoError(t, err)
+
+	answerAgent, err := agent.NewAgent(
+		agent.WithName[Answer]("answer_agent"),
+		agent.WithLLMConfig[Answer](llm.LLMConfig{
+			Type:       llm.LLMTypeOpenAI,
+			APIKey:     "test-key",
+			Model:      "gpt-4o-mini",
+			HTTPClient: &http.Client{Transport: serverTransport{target: target}},
+		}),
+		agent.WithBehavior[Answer]("You answer questions."),
+		agent.WithTool[Answer]("search", searchTool),
+		agent.WithToolLimit[Answer]("search", 1),
+	)
+	require.NoError(t, err)
+
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer()
+	agentpb.RegisterAgentServiceServer(grpcServer, agentgrpc.NewAgentServer(answerAgent))
+
+	go func() {
+		_ = grpcServer.Serve(listener)
+	}()
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return agentpb.NewAgentServiceClient(conn)
+}
+
+func TestAgentServer_Run(t *testing.T) {
+	t.Parallel()
+
+	// given
+	client := createClient(t, 1)
+
+	// when
+	response, err := client.Run(context.Background(), &agentpb.RunRequest{Input: []byte(`{"text": "question"}`)})
+
+	// then
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"text": "42"}`, string(response.GetData()))
+
+	messages := response.GetMessages()
+	require.GreaterOrEqual(t, len(messages), 4)
+	assert.Equal(t, "system", messages[0].GetType())
+	assert.Equal(t, "user", messages[1].GetType())
+	assert.JSONEq(t, `{"text": "question"}`, messages[1].GetContent())
+
+	toolMessage := messages[2]
+	require.Len(t, toolMessage.GetToolCalls(), 1)
+	assert.Equal(t, "search", toolMessage.GetToolCalls()[0].GetToolName())
+	require.Len(t, toolMessage.GetToolResults(), 1)
+	assert.Equal(t, "call_1", toolMessage.GetToolResults()[0].GetId())
+	assert.JSONEq(t, `{"id": "call_1"}`, toolMessage.GetToolResults()[0].GetResult())
+	assert.NotZero(t, toolMessage.GetTimestampUnixMs())
+}
+
+func TestAgentServer_RunStream(t *testing.T) {
+	t.Parallel()
+
+	// given
+	client := createClient(t, 1)
+
+	// when
+	stream, err := client.RunStream(context.Background(), &agentpb.RunRequest{Input: []byte(`{"text": "question"}`)})
+	require.NoError(t, err)
+
+	var chunks []*agentpb.RunChunk
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		chunks = append(chunks, chunk)
+	}
+
+	// then
+	require.Len(t, chunks, 3)
+	assert.Len(t, chunks[0].GetMessage().GetToolResults(), 1)
+	assert.True(t, chunks[1].GetMessage().GetEnd())
+
+	result := chunks[2].GetResult()
+	require.NotNil(t, result)
+	assert.JSONEq(t, `{"text": "42"}`, string(result.GetData()))
+}
+
+func TestAgentServer_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    string
+		wantCode codes.Code
+	}{
+		{name: "invalid input", input: `{`, wantCode: codes.InvalidArgument},
+		{name: "tool limit reached", input: `{"text": "question"}`, wantCode: codes.ResourceExhausted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			client := createClient(t, 2)
+
+			// when
+			_, err := client.Run(context.Background(), &agentpb.RunRequest{Input: []byte(tt.input)})
+
+			// then
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
+
+func TestAgentServer_Run_StringInput(t *testing.T) {
+	t.Parallel()
+
+	// given
+	client := createClient(t, 0)
+
+	// when
+	response, err := client.Run(context.Background(), &agentpb.RunRequest{Input: []byte(`"question"`)})
+
+	// then
+	require.NoError(t, err)
+
+	var answer Answer
+	require.NoError(t, json.Unmarshal(response.GetData(), &answer))
+	assert.Equal(t, "42", answer.Text)
+}
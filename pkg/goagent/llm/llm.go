@@ -69,11 +69,23 @@ type LLM interface {
 	CallWithStructuredOutput(ctx context.Context, msgs []LLMMessage, schemaT any) (string, error)
 }
 
+// ToolRestrictor is implemented by LLMs which can return a copy of themselves that offers
+// only some of their tools to the model. Agents use it to hide tools for a single run.
+type ToolRestrictor interface {
+	RestrictTools(names []string) LLM
+}
+
 // Call the LLM with structured output
 func CallWithStructuredOutput[T any](ctx context.Context, llm LLM, msgs []LLMMessage) (T, error) {
+	return CallWithStructuredOutputSchema[T](ctx, llm, msgs, new(T))
+}
+
+// CallWithStructuredOutputSchema calls the LLM with structured output described by schemaT,
+// which can be either a Go value or a pre-generated JSON schema map, and decodes the output into T
+func CallWithStructuredOutputSchema[T any](ctx context.Context, llm LLM, msgs []LLMMessage, schemaT any) (T, error) {
 	var result T
 
-	output, err := llm.CallWithStructuredOutput(ctx, msgs, new(T))
+	output, err := llm.CallWithStructuredOutput(ctx, msgs, schemaT)
 	if err != nil {
 		return result, fmt.Errorf("%w: %w", ErrStructuredOutput, err)
 	}
@@ -1,17 +1,41 @@
 package llm
 
 import (
+	"errors"
 	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
 
 	"github.com/vitalii-honchar/go-agent/internal/validation"
 )
 
+// ErrEnvVarNotSet is returned when an API key references an environment variable that is not set
+var ErrEnvVarNotSet = errors.New("environment variable is not set")
+
+const authorizationHeader = "Authorization"
+
+// organizationIDPattern matches OpenAI organization IDs such as org-AbC123
+const organizationIDPattern = `^org-[A-Za-z0-9]+$`
+
+// projectAPIKeyPrefix starts OpenAI project-scoped API keys
+const projectAPIKeyPrefix = "sk-proj-"
+
+var envVarReferencePattern = regexp.MustCompile(`^\$(?:\{([A-Za-z_][A-Za-z0-9_]*)\}|([A-Za-z_][A-Za-z0-9_]*))$`)
+
 // LLMType represents the type of LLM provider
 type LLMType string
 
 const (
 	// LLMTypeOpenAI represents the OpenAI LLM provider
 	LLMTypeOpenAI LLMType = "openai"
+	// LLMTypeOpenAIPrompt executes the conversation with a prompt stored in OpenAI
+	// through the Responses API, see LLMConfig.PromptID
+	LLMTypeOpenAIPrompt LLMType = "openai_prompt"
 )
 
 // LLMConfig contains configuration for LLM providers
@@ -20,18 +44,154 @@ type LLMConfig struct {
 	APIKey      string  `json:"api_key"`
 	Model       string  `json:"model"`
 	Temperature float64 `json:"temperature"`
+	// MaxTokens limits the number of tokens the LLM generates per call. Optional, zero
+	// leaves the limit to the provider.
+	MaxTokens int `json:"max_tokens,omitempty"`
+	// OrganizationID routes requests to a specific OpenAI organization for billing.
+	// Optional. Like APIKey, it can reference an environment variable, e.g. ${OPENAI_ORGANIZATION_ID}.
+	OrganizationID string `json:"organization_id,omitempty"`
+	// ProjectID routes requests to a specific OpenAI project. Optional, but required with
+	// project-scoped API keys, which start with sk-proj-.
+	ProjectID string `json:"project_id,omitempty"`
+	// BaseURL sends requests to an OpenAI-compatible API instead of api.openai.com, e.g.
+	// a LiteLLM or OpenRouter proxy. Optional, it must be an http or https URL.
+	BaseURL string `json:"base_url,omitempty"`
+	// StrictFunctionCalling makes the provider follow tool parameter schemas exactly.
+	// OpenAI strict mode requires every tool schema to set additionalProperties to false
+	// and to list all properties as required.
+	StrictFunctionCalling bool `json:"strict_function_calling,omitempty"`
+	// HTTPClient is used for requests to the provider instead of the default client.
+	// Use it to configure proxies, mutual TLS or request signing. Optional.
+	HTTPClient *http.Client `json:"-"`
+	// RequestHeaders are added to every request to the provider, e.g. for LLM proxies
+	// which route or authenticate requests with custom headers. For Helicone:
+	//
+	//	RequestHeaders: map[string]string{"Helicone-Auth": "Bearer " + heliconeKey}
+	//
+	// The Authorization header can't be set here, it is derived from APIKey.
+	RequestHeaders map[string]string `json:"request_headers,omitempty"`
+	// PromptID is the ID of the stored OpenAI prompt which executes the conversation.
+	// Required for LLMTypeOpenAIPrompt, where Model is optional and overrides the
+	// model of the prompt when set.
+	PromptID string `json:"prompt_id,omitempty"`
 }
 
+// Validate checks the configuration and reports all problems at once: the returned error
+// has an Errors() []error method listing them. An APIKey written as ${ENV_VAR} or $ENV_VAR
+// is resolved from the environment and replaced with its value.
 func (c *LLMConfig) Validate() error {
-	if err := validation.StringIsNotEmpty(string(c.Type)); err != nil {
-		return fmt.Errorf("type: %w", err)
+	return validation.ValidateAll(
+		validation.WithPrefix("type", func() error {
+			return validation.StringIsNotEmpty(string(c.Type))
+		}),
+		c.validateAPIKey,
+		c.validateModel,
+		func() error {
+			return validation.IntIsNotNegative(c.MaxTokens, "max tokens")
+		},
+		validation.WithPrefix("organization id", c.validateOrganizationID),
+		validation.WithPrefix("project id", c.validateProjectID),
+		validation.WithPrefix("base url", c.validateBaseURL),
+		validation.WithPrefix("request headers", c.validateRequestHeaders),
+	)
+}
+
+func (c *LLMConfig) validateAPIKey() error {
+	apiKey, err := ResolveAPIKey(c.APIKey)
+	if err != nil {
+		return fmt.Errorf("api key: %w", err)
 	}
+	c.APIKey = apiKey
 	if err := validation.StringIsNotEmpty(c.APIKey); err != nil {
 		return fmt.Errorf("api key: %w", err)
 	}
+
+	return nil
+}
+
+func (c *LLMConfig) validateModel() error {
+	if c.Type == LLMTypeOpenAIPrompt {
+		if err := validation.StringIsNotEmpty(c.PromptID); err != nil {
+			return fmt.Errorf("prompt id: %w", err)
+		}
+
+		return nil
+	}
 	if err := validation.StringIsNotEmpty(c.Model); err != nil {
 		return fmt.Errorf("model: %w", err)
 	}
 
 	return nil
 }
+
+func (c *LLMConfig) validateRequestHeaders() error {
+	for _, name := range slices.Sorted(maps.Keys(c.RequestHeaders)) {
+		if err := validation.StringIsNotEmpty(name); err != nil {
+			return fmt.Errorf("header name: %w", err)
+		}
+		if strings.EqualFold(name, authorizationHeader) {
+			return fmt.Errorf("%w: header %s is reserved, use api key instead", validation.ErrValidationFailed, name)
+		}
+	}
+
+	return nil
+}
+
+func (c *LLMConfig) validateOrganizationID() error {
+	organizationID, err := ResolveAPIKey(c.OrganizationID)
+	if err != nil {
+		return err
+	}
+	c.OrganizationID = organizationID
+	if c.OrganizationID == "" {
+		return nil
+	}
+
+	return validation.StringMatchesPattern(c.OrganizationID, organizationIDPattern)
+}
+
+func (c *LLMConfig) validateProjectID() error {
+	if c.ProjectID == "" || c.Type != LLMTypeOpenAI {
+		return nil
+	}
+	if !strings.HasPrefix(c.APIKey, projectAPIKeyPrefix) {
+		return fmt.Errorf("%w: api key must be a project-scoped key starting with %s",
+			validation.ErrValidationFailed, projectAPIKeyPrefix)
+	}
+
+	return nil
+}
+
+func (c *LLMConfig) validateBaseURL() error {
+	if c.BaseURL == "" {
+		return nil
+	}
+
+	baseURL, err := url.Parse(c.BaseURL)
+	if err != nil || (baseURL.Scheme != "http" && baseURL.Scheme != "https") || baseURL.Host == "" {
+		return fmt.Errorf("%w: %q must be an http or https URL", validation.ErrValidationFailed, c.BaseURL)
+	}
+
+	return nil
+}
+
+// ResolveAPIKey resolves an API key given as ${ENV_VAR} or $ENV_VAR from the environment.
+// Any other value is treated as a literal key and returned as is.
+func ResolveAPIKey(raw string) (string, error) {
+	matches := envVarReferencePattern.FindStringSubmatch(raw)
+	if matches == nil {
+		return raw, nil
+	}
+
+	name := matches[1]
+	if name == "" {
+		name = matches[2]
+	}
+
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrEnvVarNotSet, name)
+	}
+
+	return value, nil
+}
@@ -0,0 +1,74 @@
+package llm
+
+import "context"
+
+type contextKey int
+
+const llmConfigOverrideKey contextKey = iota
+
+// WithLLMConfigOverride returns a context which makes agents run with the given config
+// override, e.g. the API key or model of the tenant that sent the request. Fields left
+// at their zero value keep the value of the agent config, see MergeLLMConfig.
+//
+// Like in the agent config, an API key written as $ENV_VAR is resolved from the
+// environment, so don't pass API keys received from clients unchecked.
+//
+// Example:
+//
+//	ctx = llm.WithLLMConfigOverride(ctx, llm.LLMConfig{APIKey: tenant.OpenAIKey, Model: tenant.Model})
+//	result, err := myAgent.Run(ctx, input)
+func WithLLMConfigOverride(ctx context.Context, override LLMConfig) context.Context {
+	return context.WithValue(ctx, llmConfigOverrideKey, override)
+}
+
+// LLMConfigFromContext returns the config override stored by WithLLMConfigOverride
+func LLMConfigFromContext(ctx context.Context) (LLMConfig, bool) {
+	override, ok := ctx.Value(llmConfigOverrideKey).(LLMConfig)
+
+	return override, ok
+}
+
+// MergeLLMConfig returns base with every non-zero field of override applied to it.
+// Boolean fields can only be switched on and a zero temperature can't override a
+// non-zero one.
+func MergeLLMConfig(base, override LLMConfig) LLMConfig {
+	merged := base
+	if override.Type != "" {
+		merged.Type = override.Type
+	}
+	if override.APIKey != "" {
+		merged.APIKey = override.APIKey
+	}
+	if override.Model != "" {
+		merged.Model = override.Model
+	}
+	if override.Temperature != 0 {
+		merged.Temperature = override.Temperature
+	}
+	if override.MaxTokens != 0 {
+		merged.MaxTokens = override.MaxTokens
+	}
+	if override.OrganizationID != "" {
+		merged.OrganizationID = override.OrganizationID
+	}
+	if override.ProjectID != "" {
+		merged.ProjectID = override.ProjectID
+	}
+	if override.BaseURL != "" {
+		merged.BaseURL = override.BaseURL
+	}
+	if override.StrictFunctionCalling {
+		merged.StrictFunctionCalling = true
+	}
+	if override.HTTPClient != nil {
+		merged.HTTPClient = override.HTTPClient
+	}
+	if override.RequestHeaders != nil {
+		merged.RequestHeaders = override.RequestHeaders
+	}
+	if override.PromptID != "" {
+		merged.PromptID = override.PromptID
+	}
+
+	return merged
+}
@@ -0,0 +1,57 @@
+package llm_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+func TestLLMConfigFromContext(t *testing.T) {
+	t.Parallel()
+
+	_, ok := llm.LLMConfigFromContext(context.Background())
+	assert.False(t, ok)
+
+	override := llm.LLMConfig{APIKey: "tenant-key"}
+	ctx := llm.WithLLMConfigOverride(context.Background(), override)
+
+	fromCtx, ok := llm.LLMConfigFromContext(ctx)
+	require.True(t, ok)
+	assert.Equal(t, override, fromCtx)
+}
+
+func TestMergeLLMConfig(t *testing.T) {
+	t.Parallel()
+
+	client := &http.Client{}
+	base := llm.LLMConfig{
+		Type:        llm.LLMTypeOpenAI,
+		APIKey:      "base-key",
+		Model:       "gpt-4o",
+		Temperature: 0.2,
+	}
+
+	merged := llm.MergeLLMConfig(base, llm.LLMConfig{
+		APIKey:         "tenant-key",
+		ProjectID:      "proj_tenant",
+		BaseURL:        "https://openrouter.ai/api/v1",
+		HTTPClient:     client,
+		RequestHeaders: map[string]string{"X-Tenant": "acme"},
+	})
+
+	assert.Equal(t, llm.LLMConfig{
+		Type:           llm.LLMTypeOpenAI,
+		APIKey:         "tenant-key",
+		Model:          "gpt-4o",
+		Temperature:    0.2,
+		ProjectID:      "proj_tenant",
+		BaseURL:        "https://openrouter.ai/api/v1",
+		HTTPClient:     client,
+		RequestHeaders: map[string]string{"X-Tenant": "acme"},
+	}, merged)
+	assert.Equal(t, base, llm.MergeLLMConfig(base, llm.LLMConfig{}))
+}
@@ -91,3 +91,268 @@ func TestLLMConfig_Validate_AllFieldsEmpty(t *testing.T) {
 	assert.Contains(t, err.Error(), "type")
 	assert.ErrorIs(t, err, validation.ErrValidationFailed)
 }
+
+func TestLLMConfig_Validate_ReportsAllErrors(t *testing.T) {
+	t.Parallel()
+
+	config := llm.LLMConfig{
+		Type:           llm.LLMTypeOpenAI,
+		OrganizationID: "acme",
+		MaxTokens:      -1,
+	}
+
+	err := config.Validate()
+
+	require.ErrorIs(t, err, validation.ErrMultiValidation)
+	require.ErrorIs(t, err, validation.ErrValidationFailed)
+
+	var multiErr interface{ Errors() []error }
+	require.ErrorAs(t, err, &multiErr)
+	require.Len(t, multiErr.Errors(), 4)
+	assert.Contains(t, multiErr.Errors()[0].Error(), "api key")
+	assert.Contains(t, multiErr.Errors()[1].Error(), "model")
+	assert.Contains(t, multiErr.Errors()[2].Error(), "max tokens")
+	assert.Contains(t, multiErr.Errors()[3].Error(), "organization id")
+}
+
+//nolint:paralleltest // t.Setenv cannot be used in parallel tests
+func TestResolveAPIKey(t *testing.T) {
+	t.Setenv("GO_AGENT_TEST_API_KEY", "resolved-key")
+
+	tests := []struct {
+		name     string
+		raw      string
+		expected string
+	}{
+		{"braced env var", "${GO_AGENT_TEST_API_KEY}", "resolved-key"},
+		{"plain env var", "$GO_AGENT_TEST_API_KEY", "resolved-key"},
+		{"literal key", "sk-literal-key", "sk-literal-key"},
+		{"dollar inside literal", "sk-$GO_AGENT_TEST_API_KEY", "sk-$GO_AGENT_TEST_API_KEY"},
+		{"empty string", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := llm.ResolveAPIKey(tt.raw)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestResolveAPIKey_MissingEnvVar(t *testing.T) {
+	t.Parallel()
+
+	_, err := llm.ResolveAPIKey("${GO_AGENT_TEST_MISSING_API_KEY}")
+
+	require.ErrorIs(t, err, llm.ErrEnvVarNotSet)
+	assert.Contains(t, err.Error(), "GO_AGENT_TEST_MISSING_API_KEY")
+}
+
+//nolint:paralleltest // t.Setenv cannot be used in parallel tests
+func TestLLMConfig_Validate_ResolvesAPIKeyFromEnv(t *testing.T) {
+	t.Setenv("GO_AGENT_TEST_CONFIG_API_KEY", "env-api-key")
+
+	config := llm.LLMConfig{
+		Type:   llm.LLMTypeOpenAI,
+		APIKey: "${GO_AGENT_TEST_CONFIG_API_KEY}",
+		Model:  "gpt-4",
+	}
+
+	err := config.Validate()
+
+	require.NoError(t, err)
+	assert.Equal(t, "env-api-key", config.APIKey)
+}
+
+func TestLLMConfig_Validate_OrganizationID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		organizationID string
+		wantErr        bool
+	}{
+		{name: "empty", organizationID: "", wantErr: false},
+		{name: "valid", organizationID: "org-AbC123", wantErr: false},
+		{name: "missing prefix", organizationID: "AbC123", wantErr: true},
+		{name: "invalid characters", organizationID: "org-abc_123", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			config := llm.LLMConfig{
+				Type:           llm.LLMTypeOpenAI,
+				APIKey:         "test-api-key",
+				Model:          "gpt-4",
+				OrganizationID: tt.organizationID,
+			}
+
+			err := config.Validate()
+
+			if tt.wantErr {
+				require.ErrorIs(t, err, validation.ErrValidationFailed)
+				assert.Contains(t, err.Error(), "organization id")
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLLMConfig_Validate_RequestHeaders(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		wantErr bool
+	}{
+		{name: "none", headers: nil, wantErr: false},
+		{name: "custom headers", headers: map[string]string{"Helicone-Auth": "Bearer key"}, wantErr: false},
+		{name: "authorization", headers: map[string]string{"Authorization": "Bearer other"}, wantErr: true},
+		{name: "authorization lowercase", headers: map[string]string{"authorization": "Bearer other"}, wantErr: true},
+		{name: "empty name", headers: map[string]string{"": "value"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			config := llm.LLMConfig{
+				Type:           llm.LLMTypeOpenAI,
+				APIKey:         "test-api-key",
+				Model:          "gpt-4",
+				RequestHeaders: tt.headers,
+			}
+
+			err := config.Validate()
+
+			if tt.wantErr {
+				require.ErrorIs(t, err, validation.ErrValidationFailed)
+				assert.Contains(t, err.Error(), "request headers")
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLLMConfig_Validate_BaseURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		baseURL string
+		wantErr bool
+	}{
+		{name: "none", baseURL: "", wantErr: false},
+		{name: "openrouter", baseURL: "https://openrouter.ai/api/v1", wantErr: false},
+		{name: "litellm", baseURL: "http://localhost:4000", wantErr: false},
+		{name: "no scheme", baseURL: "localhost:4000", wantErr: true},
+		{name: "unsupported scheme", baseURL: "ftp://proxy.example.com", wantErr: true},
+		{name: "no host", baseURL: "https://", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			config := llm.LLMConfig{
+				Type:    llm.LLMTypeOpenAI,
+				APIKey:  "test-api-key",
+				Model:   "gpt-4",
+				BaseURL: tt.baseURL,
+			}
+
+			err := config.Validate()
+
+			if tt.wantErr {
+				require.ErrorIs(t, err, validation.ErrValidationFailed)
+				assert.Contains(t, err.Error(), "base url")
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLLMConfig_Validate_ProjectID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		llmType   llm.LLMType
+		apiKey    string
+		projectID string
+		wantErr   bool
+	}{
+		{name: "none", llmType: llm.LLMTypeOpenAI, apiKey: "sk-test", projectID: ""},
+		{name: "project key", llmType: llm.LLMTypeOpenAI, apiKey: "sk-proj-test", projectID: "proj_1"},
+		{name: "legacy key", llmType: llm.LLMTypeOpenAI, apiKey: "sk-test", projectID: "proj_1", wantErr: true},
+		{name: "prompt", llmType: llm.LLMTypeOpenAIPrompt, apiKey: "sk-test", projectID: "proj_1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			config := llm.LLMConfig{
+				Type:      tt.llmType,
+				APIKey:    tt.apiKey,
+				Model:     "gpt-4",
+				PromptID:  "pmpt_123",
+				ProjectID: tt.projectID,
+			}
+
+			err := config.Validate()
+
+			if tt.wantErr {
+				require.ErrorIs(t, err, validation.ErrValidationFailed)
+				assert.Contains(t, err.Error(), "project id")
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLLMConfig_Validate_OpenAIPrompt(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		promptID string
+		model    string
+		wantErr  bool
+	}{
+		{name: "prompt without model", promptID: "pmpt_123", model: "", wantErr: false},
+		{name: "prompt with model override", promptID: "pmpt_123", model: "gpt-4o", wantErr: false},
+		{name: "missing prompt id", promptID: "", model: "gpt-4o", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			config := llm.LLMConfig{
+				Type:     llm.LLMTypeOpenAIPrompt,
+				APIKey:   "test-api-key",
+				Model:    tt.model,
+				PromptID: tt.promptID,
+			}
+
+			err := config.Validate()
+
+			if tt.wantErr {
+				require.ErrorIs(t, err, validation.ErrValidationFailed)
+				assert.Contains(t, err.Error(), "prompt id")
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,8 @@
+package llm
+
+import "context"
+
+// Embedder converts texts into embedding vectors
+type Embedder interface {
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
@@ -1,5 +1,16 @@
 package llm
 
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ErrInvalidMessagePart is returned when a multimodal message part is malformed
+var ErrInvalidMessagePart = errors.New("invalid message part")
+
 // LLMMessageType represents the type of LLM message
 type LLMMessageType string
 
@@ -16,15 +27,128 @@ const (
 type LLMMessage struct {
 	Type        LLMMessageType  `json:"type"`
 	Content     string          `json:"content"`
+	Parts       []MessagePart   `json:"parts,omitempty"`
 	ToolCalls   []LLMToolCall   `json:"tool_call,omitempty"`
 	ToolResults []LLMToolResult `json:"tool_result,omitempty"`
 	End         bool            `json:"end,omitempty"`
+	// Timestamp is the time the message was created, in UTC
+	Timestamp time.Time `json:"timestamp,omitzero"`
+	// Usage is the number of tokens the LLM call which produced the message consumed,
+	// if the provider reports it
+	Usage TokenUsage `json:"usage,omitzero"`
+	// CacheControl asks providers with explicit prompt caching to cache the conversation up
+	// to and including this message. Providers which cache automatically ignore it.
+	CacheControl CacheControl `json:"cache_control,omitempty"`
 }
 
+// CacheControl describes how a provider caches the prompt prefix ending with a message
+type CacheControl string
+
+const (
+	// CacheControlEphemeral caches the prompt prefix for a few minutes, refreshed on every hit.
+	// It corresponds to the ephemeral cache_control type of Anthropic.
+	CacheControlEphemeral CacheControl = "ephemeral"
+)
+
 // NewLLMMessage creates a new LLM message with the given type and content
 func NewLLMMessage(msgType LLMMessageType, content string) LLMMessage {
 	return LLMMessage{
-		Type:    msgType,
-		Content: content,
+		Type:      msgType,
+		Content:   content,
+		Timestamp: time.Now().UTC(),
+	}
+}
+
+// MessagePart represents a single part of a multimodal message.
+// Exactly one of Text or Image must be set.
+type MessagePart struct {
+	Text  *TextPart  `json:"text,omitempty"`
+	Image *ImagePart `json:"image,omitempty"`
+}
+
+// TextPart represents a text part of a multimodal message
+type TextPart struct {
+	Text string `json:"text"`
+}
+
+// ImagePart represents an image part of a multimodal message
+type ImagePart struct {
+	Image ImageContent `json:"image"`
+}
+
+// ImageContent describes an image either by URL or by base64 encoded data.
+// URL and Base64 are mutually exclusive.
+type ImageContent struct {
+	URL    string `json:"url,omitempty"`
+	Base64 string `json:"base64,omitempty"`
+}
+
+// NewTextPart creates a text message part
+func NewTextPart(text string) MessagePart {
+	return MessagePart{Text: &TextPart{Text: text}}
+}
+
+// NewImagePart creates an image message part
+func NewImagePart(image ImageContent) MessagePart {
+	return MessagePart{Image: &ImagePart{Image: image}}
+}
+
+// NewMultimodalLLMMessage creates a new LLM message with the given type and parts
+func NewMultimodalLLMMessage(msgType LLMMessageType, parts ...MessagePart) LLMMessage {
+	return LLMMessage{
+		Type:      msgType,
+		Parts:     parts,
+		Timestamp: time.Now().UTC(),
 	}
 }
+
+// HasImages reports whether the message contains at least one image part
+func (m LLMMessage) HasImages() bool {
+	for _, part := range m.Parts {
+		if part.Image != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Validate checks that exactly one of Text or Image is set
+func (p MessagePart) Validate() error {
+	if (p.Text == nil) == (p.Image == nil) {
+		return fmt.Errorf("%w: exactly one of text or image must be set", ErrInvalidMessagePart)
+	}
+
+	if p.Image != nil {
+		return p.Image.Image.Validate()
+	}
+
+	return nil
+}
+
+// Validate checks that exactly one of URL or Base64 is set
+func (c ImageContent) Validate() error {
+	if (c.URL == "") == (c.Base64 == "") {
+		return fmt.Errorf("%w: exactly one of url or base64 must be set", ErrInvalidMessagePart)
+	}
+
+	return nil
+}
+
+// ImageURL returns the image URL, or a data URL when the image is base64 encoded
+func (c ImageContent) ImageURL() (string, error) {
+	if err := c.Validate(); err != nil {
+		return "", err
+	}
+
+	if c.URL != "" {
+		return c.URL, nil
+	}
+
+	data, err := base64.StdEncoding.DecodeString(c.Base64)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid base64 image data: %w", ErrInvalidMessagePart, err)
+	}
+
+	return "data:" + http.DetectContentType(data) + ";base64," + c.Base64, nil
+}
@@ -2,6 +2,7 @@ package llm_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -57,7 +58,13 @@ func TestNewLLMMessage(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
+			before := time.Now().UTC()
 			result := llm.NewLLMMessage(tt.msgType, tt.content)
+
+			assert.False(t, result.Timestamp.Before(before))
+			assert.Equal(t, time.UTC, result.Timestamp.Location())
+
+			result.Timestamp = time.Time{}
 			assert.Equal(t, tt.expected, result)
 		})
 	}
@@ -261,3 +268,65 @@ func TestLLMMessageTypes(t *testing.T) {
 	assert.Equal(t, "assistant", string(llm.LLMMessageTypeAssistant))
 	assert.Equal(t, "system", string(llm.LLMMessageTypeSystem))
 }
+
+func TestMessagePart_Validate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		part    llm.MessagePart
+		wantErr bool
+	}{
+		{"text part", llm.NewTextPart("hello"), false},
+		{"image url part", llm.NewImagePart(llm.ImageContent{URL: "https://example.com/a.png"}), false},
+		{"image base64 part", llm.NewImagePart(llm.ImageContent{Base64: "iVBORw0KGgo="}), false},
+		{"empty part", llm.MessagePart{}, true},
+		{"image without source", llm.NewImagePart(llm.ImageContent{}), true},
+		{
+			"image with url and base64",
+			llm.NewImagePart(llm.ImageContent{URL: "https://example.com/a.png", Base64: "iVBORw0KGgo="}),
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.part.Validate()
+			if tt.wantErr {
+				require.ErrorIs(t, err, llm.ErrInvalidMessagePart)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestImageContent_ImageURL(t *testing.T) {
+	t.Parallel()
+
+	url, err := llm.ImageContent{URL: "https://example.com/a.png"}.ImageURL()
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/a.png", url)
+
+	// base64 of the PNG file signature
+	dataURL, err := llm.ImageContent{Base64: "iVBORw0KGgo="}.ImageURL()
+	require.NoError(t, err)
+	assert.Equal(t, "data:image/png;base64,iVBORw0KGgo=", dataURL)
+
+	_, err = llm.ImageContent{Base64: "not base64!"}.ImageURL()
+	require.ErrorIs(t, err, llm.ErrInvalidMessagePart)
+}
+
+func TestLLMMessage_HasImages(t *testing.T) {
+	t.Parallel()
+
+	textOnly := llm.NewMultimodalLLMMessage(llm.LLMMessageTypeUser, llm.NewTextPart("hello"))
+	withImage := llm.NewMultimodalLLMMessage(
+		llm.LLMMessageTypeUser,
+		llm.NewTextPart("describe"),
+		llm.NewImagePart(llm.ImageContent{URL: "https://example.com/a.png"}),
+	)
+
+	assert.False(t, textOnly.HasImages())
+	assert.True(t, withImage.HasImages())
+}
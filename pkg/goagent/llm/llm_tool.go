@@ -19,6 +19,18 @@ type LLMTool struct {
 	ParametersSchema any                                                 `json:"parameters_schema"`
 	Description      string                                              `json:"description"`
 	Call             func(id string, args string) (LLMToolResult, error) `json:"-"`
+	PartialResults   PartialResultTool                                   `json:"-"`
+	Tags             map[string]string                                   `json:"tags,omitempty"`
+	// RateLimit limits how often the agent calls the tool, see WithLLMToolRateLimit
+	RateLimit RateLimiter `json:"-"`
+	// ParametersExample is a JSON encoded example of the tool arguments, see
+	// WithLLMToolParametersExample
+	ParametersExample json.RawMessage `json:"parameters_example,omitempty"`
+	// Batch executes several calls of the tool at once when the agent batches tool calls,
+	// see WithLLMToolBatchCall
+	Batch BatchableTool `json:"-"`
+
+	parametersExampleErr error
 }
 
 // LLMToolOption is a function that configures an LLMTool
@@ -39,21 +51,74 @@ func NewLLMTool(options ...LLMToolOption) (LLMTool, error) {
 	return *tool, nil
 }
 
-func (t *LLMTool) validate() error {
-	if err := validation.NameIsValid(t.Name); err != nil {
-		return fmt.Errorf("tool: %w", err)
-	}
-	if err := validation.DescriptionIsValid(t.Description); err != nil {
-		return fmt.Errorf("description: %w", err)
-	}
-	if err := validation.NotNil(t.ParametersSchema); err != nil {
-		return fmt.Errorf("parameters schema: %w", err)
-	}
-	if t.Call == nil {
-		return fmt.Errorf("call: %w: value cannot be nil", validation.ErrValidationFailed)
+// NewLLMToolFromFunc creates a tool which calls fn, with the parameters schema derived from P.
+// It is a shorthand for NewLLMTool with the name, description, parameters schema and call options.
+//
+// Example:
+//
+//	tool, err := llm.NewLLMToolFromFunc("add", "Adds two numbers",
+//		func(callID string, params AddParams) (AddResult, error) {
+//			return AddResult{
+//				BaseLLMToolResult: llm.BaseLLMToolResult{ID: callID},
+//				Sum:               params.Num1 + params.Num2,
+//			}, nil
+//		},
+//	)
+func NewLLMToolFromFunc[P any, R LLMToolResult](
+	name, description string,
+	fn func(string, P) (R, error),
+) (LLMTool, error) {
+	return NewLLMTool(
+		WithLLMToolName(name),
+		WithLLMToolDescription(description),
+		WithLLMToolParametersSchema[P](),
+		WithLLMToolCall(fn),
+	)
+}
+
+// MustNewLLMToolFromFunc is like NewLLMToolFromFunc but panics when the tool is invalid.
+// It simplifies the declaration of tools in package level variables and tests.
+func MustNewLLMToolFromFunc[P any, R LLMToolResult](
+	name, description string,
+	fn func(string, P) (R, error),
+) LLMTool {
+	tool, err := NewLLMToolFromFunc(name, description, fn)
+	if err != nil {
+		panic(err)
 	}
 
-	return nil
+	return tool
+}
+
+func (t *LLMTool) validate() error {
+	return validation.ValidateAll(
+		validation.WithPrefix("tool", func() error {
+			return validation.NameIsValid(t.Name)
+		}),
+		validation.WithPrefix("description", func() error {
+			return validation.DescriptionIsValid(t.Description)
+		}),
+		validation.WithPrefix("parameters schema", func() error {
+			return validation.NotNil(t.ParametersSchema)
+		}),
+		func() error {
+			if t.Call == nil {
+				return fmt.Errorf("call: %w: value cannot be nil", validation.ErrValidationFailed)
+			}
+
+			return nil
+		},
+		validation.WithPrefix("parameters example", func() error {
+			return t.parametersExampleErr
+		}),
+		validation.WithPrefix("rate limit", func() error {
+			if limiter, ok := t.RateLimit.(*intervalRateLimiter); ok {
+				return limiter.validate()
+			}
+
+			return nil
+		}),
+	)
 }
 
 // WithLLMToolName sets the name of the tool
@@ -70,12 +135,35 @@ func WithLLMToolDescription(description string) LLMToolOption {
 	}
 }
 
+// WithLLMToolTags sets key-value tags used to categorize the tool, e.g. {"category": "web"}
+func WithLLMToolTags(tags map[string]string) LLMToolOption {
+	return func(tool *LLMTool) {
+		tool.Tags = tags
+	}
+}
+
 func WithLLMToolParametersSchema[T any]() LLMToolOption {
 	return func(tool *LLMTool) {
 		tool.ParametersSchema = new(T)
 	}
 }
 
+// WithLLMToolParametersExample adds an example of the tool arguments to the tool definition,
+// which helps some models to fill in the parameters. LLM providers without a native field
+// for examples add it to the tool description.
+//
+// Example:
+//
+//	llm.WithLLMToolParametersExample(SearchParams{Query: "golang generics", Limit: 5})
+func WithLLMToolParametersExample[T any](example T) LLMToolOption {
+	return func(tool *LLMTool) {
+		tool.ParametersExample, tool.parametersExampleErr = json.Marshal(example)
+		if tool.parametersExampleErr != nil {
+			tool.parametersExampleErr = fmt.Errorf("%w: %w", validation.ErrValidationFailed, tool.parametersExampleErr)
+		}
+	}
+}
+
 // WithLLMToolCall sets the call function for the tool
 func WithLLMToolCall[P any, T LLMToolResult](callFunc func(callID string, args P) (T, error)) LLMToolOption {
 	return func(tool *LLMTool) {
@@ -110,6 +198,25 @@ func (r BaseLLMToolResult) GetID() string {
 	return r.ID
 }
 
+// LLMToolResultMarshaler is implemented by tool results that control how they are presented
+// to the LLM, for example to hide internal fields or to send plain text instead of JSON.
+//
+// BaseLLMToolResult deliberately does not implement it: the method would be promoted to every
+// result embedding BaseLLMToolResult and marshal only the ID.
+type LLMToolResultMarshaler interface {
+	MarshalForLLM() ([]byte, error)
+}
+
+// MarshalToolResult returns the content sent to the LLM for the tool result. It uses
+// MarshalForLLM when the result implements LLMToolResultMarshaler and json.Marshal otherwise.
+func MarshalToolResult(result LLMToolResult) ([]byte, error) {
+	if marshaler, ok := result.(LLMToolResultMarshaler); ok {
+		return marshaler.MarshalForLLM()
+	}
+
+	return json.Marshal(result)
+}
+
 type ErrorLLMToolResult struct {
 	BaseLLMToolResult
 	Error string `json:"error"`
@@ -138,6 +245,31 @@ func NewLLMToolCall(id string, toolName string, args string) (LLMToolCall, error
 	return toolCall, nil
 }
 
+// NewLLMToolCallFromMap creates a new LLM tool call with the arguments given as a map,
+// e.g. for tool calls made programmatically. A nil map is encoded as an empty object.
+func NewLLMToolCallFromMap(id string, toolName string, args map[string]any) (LLMToolCall, error) {
+	if args == nil {
+		args = map[string]any{}
+	}
+
+	argsJSON, err := json.Marshal(args)
+	if err != nil {
+		return LLMToolCall{}, fmt.Errorf("failed to create LLM tool call: %w: %w", ErrInvalidArguments, err)
+	}
+
+	return NewLLMToolCall(id, toolName, string(argsJSON))
+}
+
+// ArgsMap decodes the JSON encoded arguments of the tool call into a map
+func (tc *LLMToolCall) ArgsMap() (map[string]any, error) {
+	var args map[string]any
+	if err := json.Unmarshal([]byte(tc.Args), &args); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidArguments, err)
+	}
+
+	return args, nil
+}
+
 func (tc *LLMToolCall) validate() error {
 	if err := validation.StringIsNotEmpty(tc.ID); err != nil {
 		return fmt.Errorf("id: %w", err)
@@ -151,3 +283,28 @@ func (tc *LLMToolCall) validate() error {
 
 	return nil
 }
+
+// PartialResultTool is implemented by tools that can report intermediate results while they run.
+// Before each call the agent hands over a new channel via SubmitPartial; the tool sends its latest
+// partial result to it during Call. Sends must not block: use a select with a default case.
+// Call must take the channel when it starts and keep sending to that one: a call which
+// exceeds its timeout keeps running next to the following call of the tool.
+type PartialResultTool interface {
+	SubmitPartial(partial chan<- LLMToolResult)
+}
+
+// WithLLMToolPartialResults registers a source of partial results for the tool.
+// When the tool exceeds its timeout, the last submitted partial result is returned to the LLM.
+func WithLLMToolPartialResults(source PartialResultTool) LLMToolOption {
+	return func(tool *LLMTool) {
+		tool.PartialResults = source
+	}
+}
+
+// PartialToolResult is returned instead of a tool's result when the tool timed out
+// after submitting a partial result
+type PartialToolResult struct {
+	BaseLLMToolResult
+	Partial bool          `json:"partial"`
+	Result  LLMToolResult `json:"result"`
+}
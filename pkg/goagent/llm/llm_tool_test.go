@@ -187,6 +187,40 @@ func TestNewLLMTool_MissingCallFunction(t *testing.T) {
 	assert.ErrorIs(t, err, validation.ErrValidationFailed)
 }
 
+func TestNewLLMTool_ParametersExample(t *testing.T) {
+	t.Parallel()
+
+	tool, err := llm.NewLLMTool(
+		llm.WithLLMToolName("test_tool"),
+		llm.WithLLMToolDescription("A test tool"),
+		llm.WithLLMToolParametersSchema[TestParams](),
+		llm.WithLLMToolParametersExample(TestParams{Input: "hello"}),
+		llm.WithLLMToolCall(func(callID string, _ TestParams) (TestResult, error) {
+			return TestResult{BaseLLMToolResult: llm.BaseLLMToolResult{ID: callID}}, nil
+		}),
+	)
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"input": "hello"}`, string(tool.ParametersExample))
+}
+
+func TestNewLLMTool_InvalidParametersExample(t *testing.T) {
+	t.Parallel()
+
+	_, err := llm.NewLLMTool(
+		llm.WithLLMToolName("test_tool"),
+		llm.WithLLMToolDescription("A test tool"),
+		llm.WithLLMToolParametersSchema[TestParams](),
+		llm.WithLLMToolParametersExample(make(chan int)),
+		llm.WithLLMToolCall(func(callID string, _ TestParams) (TestResult, error) {
+			return TestResult{BaseLLMToolResult: llm.BaseLLMToolResult{ID: callID}}, nil
+		}),
+	)
+
+	require.ErrorIs(t, err, validation.ErrValidationFailed)
+	assert.Contains(t, err.Error(), "parameters example")
+}
+
 func TestLLMTool_CallWithValidArgs(t *testing.T) {
 	t.Parallel()
 
@@ -280,6 +314,52 @@ func TestNewLLMToolCall_InvalidToolName(t *testing.T) {
 	assert.ErrorIs(t, err, validation.ErrValidationFailed)
 }
 
+func TestNewLLMToolCallFromMap(t *testing.T) {
+	t.Parallel()
+
+	toolCall, err := llm.NewLLMToolCallFromMap("call-123", "test_tool", map[string]any{"param": "value", "count": 2})
+
+	require.NoError(t, err)
+	assert.Equal(t, "call-123", toolCall.ID)
+	assert.Equal(t, "test_tool", toolCall.ToolName)
+	assert.JSONEq(t, `{"param": "value", "count": 2}`, toolCall.Args)
+}
+
+func TestNewLLMToolCallFromMap_NilArgs(t *testing.T) {
+	t.Parallel()
+
+	toolCall, err := llm.NewLLMToolCallFromMap("call-123", "test_tool", nil)
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{}`, toolCall.Args)
+}
+
+func TestNewLLMToolCallFromMap_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := llm.NewLLMToolCallFromMap("call-123", "test_tool", map[string]any{"callback": func() {}})
+	require.ErrorIs(t, err, llm.ErrInvalidArguments)
+
+	_, err = llm.NewLLMToolCallFromMap("", "test_tool", map[string]any{"param": "value"})
+	require.ErrorIs(t, err, validation.ErrValidationFailed)
+	assert.Contains(t, err.Error(), "id:")
+}
+
+func TestLLMToolCall_ArgsMap(t *testing.T) {
+	t.Parallel()
+
+	toolCall := llm.LLMToolCall{ID: "call-123", ToolName: "test_tool", Args: `{"param": "value", "count": 2}`}
+
+	args, err := toolCall.ArgsMap()
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"param": "value", "count": float64(2)}, args)
+
+	invalid := llm.LLMToolCall{Args: "not json"}
+	_, err = invalid.ArgsMap()
+	require.ErrorIs(t, err, llm.ErrInvalidArguments)
+}
+
 func TestNewLLMToolCall_EmptyArgs(t *testing.T) {
 	t.Parallel()
 
@@ -290,3 +370,72 @@ func TestNewLLMToolCall_EmptyArgs(t *testing.T) {
 	assert.Contains(t, err.Error(), "args:")
 	assert.ErrorIs(t, err, validation.ErrValidationFailed)
 }
+
+type plainTextToolResult struct {
+	llm.BaseLLMToolResult
+	Text string
+}
+
+func (r plainTextToolResult) MarshalForLLM() ([]byte, error) {
+	return []byte(r.Text), nil
+}
+
+func TestMarshalToolResult(t *testing.T) {
+	t.Parallel()
+
+	custom, err := llm.MarshalToolResult(plainTextToolResult{
+		BaseLLMToolResult: llm.BaseLLMToolResult{ID: "call-123"},
+		Text:              "42 degrees",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "42 degrees", string(custom))
+
+	fallback, err := llm.MarshalToolResult(llm.ErrorLLMToolResult{
+		BaseLLMToolResult: llm.BaseLLMToolResult{ID: "call-123"},
+		Error:             "boom",
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id": "call-123", "error": "boom"}`, string(fallback))
+}
+
+func echoTool(callID string, params TestParams) (TestResult, error) {
+	return TestResult{
+		BaseLLMToolResult: llm.BaseLLMToolResult{ID: callID},
+		Output:            params.Input,
+	}, nil
+}
+
+func TestNewLLMToolFromFunc(t *testing.T) {
+	t.Parallel()
+
+	tool, err := llm.NewLLMToolFromFunc("echo", "Echoes the input", echoTool)
+
+	require.NoError(t, err)
+	assert.Equal(t, "echo", tool.Name)
+	assert.Equal(t, "Echoes the input", tool.Description)
+	assert.IsType(t, &TestParams{}, tool.ParametersSchema)
+
+	result, err := tool.Call("call_1", `{"input": "hello"}`)
+	require.NoError(t, err)
+	assert.Equal(t, TestResult{BaseLLMToolResult: llm.BaseLLMToolResult{ID: "call_1"}, Output: "hello"}, result)
+}
+
+func TestNewLLMToolFromFunc_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := llm.NewLLMToolFromFunc("Invalid-Name", "Echoes the input", echoTool)
+
+	require.ErrorIs(t, err, validation.ErrValidationFailed)
+	assert.Contains(t, err.Error(), "tool:")
+}
+
+func TestMustNewLLMToolFromFunc(t *testing.T) {
+	t.Parallel()
+
+	tool := llm.MustNewLLMToolFromFunc("echo", "Echoes the input", echoTool)
+	assert.Equal(t, "echo", tool.Name)
+
+	assert.Panics(t, func() {
+		llm.MustNewLLMToolFromFunc("echo", "", echoTool)
+	})
+}
@@ -0,0 +1,121 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+)
+
+// loggingLLM logs every request to the wrapped LLM and its response
+type loggingLLM struct {
+	inner    LLM
+	logger   *slog.Logger
+	logLevel slog.Level
+}
+
+// NewLoggingLLM wraps inner so that every Call and CallWithStructuredOutput logs the
+// serialized messages sent to the model and the raw response, or the error, together with
+// the call duration. A nil logger logs to slog.Default().
+//
+// The messages are logged as they are, so they may contain personal data or secrets:
+// prefer a debug level and a logger which is disabled in production.
+//
+// Example:
+//
+//	logged := llm.NewLoggingLLM(openaiLLM, slog.New(slog.NewJSONHandler(os.Stderr, nil)), slog.LevelDebug)
+func NewLoggingLLM(inner LLM, logger *slog.Logger, logLevel slog.Level) LLM {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &loggingLLM{inner: inner, logger: logger, logLevel: logLevel}
+}
+
+func (l *loggingLLM) Call(ctx context.Context, msgs []LLMMessage) (LLMMessage, error) {
+	l.logRequest(ctx, "Call", msgs)
+
+	start := time.Now()
+	response, err := l.inner.Call(ctx, msgs)
+	if err != nil {
+		l.logError(ctx, "Call", start, err)
+
+		return response, err
+	}
+
+	l.logResponse(ctx, "Call", start, response)
+
+	return response, nil
+}
+
+func (l *loggingLLM) CallWithStructuredOutput(ctx context.Context, msgs []LLMMessage, schemaT any) (string, error) {
+	l.logRequest(ctx, "CallWithStructuredOutput", msgs)
+
+	start := time.Now()
+	response, err := l.inner.CallWithStructuredOutput(ctx, msgs, schemaT)
+	if err != nil {
+		l.logError(ctx, "CallWithStructuredOutput", start, err)
+
+		return response, err
+	}
+
+	l.logResponse(ctx, "CallWithStructuredOutput", start, response)
+
+	return response, nil
+}
+
+// RestrictTools restricts the tools of the wrapped LLM when it supports it and keeps the logging
+func (l *loggingLLM) RestrictTools(names []string) LLM {
+	restrictor, ok := l.inner.(ToolRestrictor)
+	if !ok {
+		return l
+	}
+
+	clone := *l
+	clone.inner = restrictor.RestrictTools(names)
+
+	return &clone
+}
+
+func (l *loggingLLM) logRequest(ctx context.Context, method string, msgs []LLMMessage) {
+	if !l.logger.Enabled(ctx, l.logLevel) {
+		return
+	}
+
+	l.logger.Log(ctx, l.logLevel, "LLM request", "method", method, "request", toJSON(msgs))
+}
+
+// logResponse logs a raw structured output as is and serializes any other response
+func (l *loggingLLM) logResponse(ctx context.Context, method string, start time.Time, response any) {
+	if !l.logger.Enabled(ctx, l.logLevel) {
+		return
+	}
+
+	raw, ok := response.(string)
+	if !ok {
+		raw = toJSON(response)
+	}
+
+	l.logger.Log(ctx, l.logLevel, "LLM response",
+		"method", method,
+		"duration", time.Since(start),
+		"response", raw,
+	)
+}
+
+func (l *loggingLLM) logError(ctx context.Context, method string, start time.Time, err error) {
+	l.logger.Log(ctx, l.logLevel, "LLM request failed",
+		"method", method,
+		"duration", time.Since(start),
+		"error", err,
+	)
+}
+
+func toJSON(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err.Error()
+	}
+
+	return string(data)
+}
@@ -0,0 +1,75 @@
+package llm_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+func TestLoggingLLM_Call(t *testing.T) {
+	t.Parallel()
+
+	// given
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	inner := llmtest.NewMockLLM(`{"answer": 42}`, llm.NewLLMMessage(llm.LLMMessageTypeAssistant, "pong"))
+	logged := llm.NewLoggingLLM(inner, logger, slog.LevelDebug)
+	msgs := []llm.LLMMessage{llm.NewLLMMessage(llm.LLMMessageTypeUser, "ping")}
+
+	// when
+	response, err := logged.Call(context.Background(), msgs)
+	require.NoError(t, err)
+	output, err := logged.CallWithStructuredOutput(context.Background(), msgs, struct{}{})
+	require.NoError(t, err)
+
+	// then
+	assert.Equal(t, "pong", response.Content)
+	assert.JSONEq(t, `{"answer": 42}`, output)
+
+	logs := buf.String()
+	assert.Contains(t, logs, `level=DEBUG msg="LLM request" method=Call`)
+	assert.Contains(t, logs, `\"content\":\"ping\"`)
+	assert.Contains(t, logs, `msg="LLM response" method=Call`)
+	assert.Contains(t, logs, `\"content\":\"pong\"`)
+	assert.Contains(t, logs, `msg="LLM response" method=CallWithStructuredOutput`)
+	assert.Contains(t, logs, `response="{\"answer\": 42}"`)
+}
+
+func TestLoggingLLM_Error(t *testing.T) {
+	t.Parallel()
+
+	// given
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	logged := llm.NewLoggingLLM(llmtest.NewMockLLM(""), logger, slog.LevelWarn)
+
+	// when
+	_, err := logged.Call(context.Background(), nil)
+
+	// then
+	require.ErrorIs(t, err, llmtest.ErrNoMoreResponses)
+	assert.Contains(t, buf.String(), `level=WARN msg="LLM request failed" method=Call`)
+}
+
+func TestLoggingLLM_LevelDisabled(t *testing.T) {
+	t.Parallel()
+
+	// given
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	inner := llmtest.NewMockLLM("", llm.NewLLMMessage(llm.LLMMessageTypeAssistant, "pong"))
+	logged := llm.NewLoggingLLM(inner, logger, slog.LevelDebug)
+
+	// when
+	_, err := logged.Call(context.Background(), nil)
+
+	// then
+	require.NoError(t, err)
+	assert.Empty(t, buf.String())
+}
@@ -0,0 +1,385 @@
+package llm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// ErrInvalidOpenAPISpec is returned when an OpenAPI spec cannot be read or is malformed
+	ErrInvalidOpenAPISpec = errors.New("invalid OpenAPI spec")
+	// ErrOperationNotFound is returned when a requested operation ID is not present in the spec
+	ErrOperationNotFound = errors.New("operation not found in OpenAPI spec")
+)
+
+const (
+	openAPIRequestTimeout = 30 * time.Second
+	openAPIMaxRefDepth    = 32
+	openAPIBodyProperty   = "body"
+)
+
+var (
+	openAPIMethods     = []string{"get", "put", "post", "delete", "patch", "head", "options"}
+	camelCaseBoundary  = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+	nonToolNameSymbols = regexp.MustCompile(`[^a-z0-9_]+`)
+)
+
+// OpenAPIToolResult is the result of a tool created from an OpenAPI operation
+type OpenAPIToolResult struct {
+	BaseLLMToolResult
+	StatusCode int    `json:"status_code"`
+	Body       string `json:"body"`
+}
+
+type openAPISpec struct {
+	Servers    []openAPIServer                        `json:"servers"    yaml:"servers"`
+	Paths      map[string]map[string]openAPIOperation `json:"paths"      yaml:"paths"`
+	Components map[string]any                         `json:"components" yaml:"components"`
+}
+
+type openAPIServer struct {
+	URL string `json:"url" yaml:"url"`
+}
+
+type openAPIOperation struct {
+	OperationID string             `json:"operationId" yaml:"operationId"`
+	Summary     string             `json:"summary"     yaml:"summary"`
+	Description string             `json:"description" yaml:"description"`
+	Parameters  []openAPIParameter `json:"parameters"  yaml:"parameters"`
+	RequestBody *openAPIBody       `json:"requestBody" yaml:"requestBody"`
+}
+
+type openAPIParameter struct {
+	Name        string         `json:"name"        yaml:"name"`
+	In          string         `json:"in"          yaml:"in"`
+	Description string         `json:"description" yaml:"description"`
+	Required    bool           `json:"required"    yaml:"required"`
+	Schema      map[string]any `json:"schema"      yaml:"schema"`
+}
+
+type openAPIBody struct {
+	Required bool                        `json:"required" yaml:"required"`
+	Content  map[string]openAPIMediaType `json:"content"  yaml:"content"`
+}
+
+type openAPIMediaType struct {
+	Schema map[string]any `json:"schema" yaml:"schema"`
+}
+
+type openAPIEndpoint struct {
+	method    string
+	path      string
+	operation openAPIOperation
+}
+
+// NewToolsFromOpenAPISpec creates one tool per operation ID from an OpenAPI 3.0 spec in JSON
+// or YAML format. Path and query parameters become top-level tool parameters and a JSON
+// request body becomes the "body" parameter. Calling a tool sends the HTTP request to the
+// first server listed in the spec and returns the response status and body.
+//
+// Example:
+//
+//	tools, err := llm.NewToolsFromOpenAPISpec("specs/orders.yaml", []string{"getOrder", "createOrder"})
+func NewToolsFromOpenAPISpec(specPath string, operationIDs []string) ([]LLMTool, error) {
+	spec, err := readOpenAPISpec(specPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(spec.Servers) == 0 || spec.Servers[0].URL == "" {
+		return nil, fmt.Errorf("%w: no servers defined", ErrInvalidOpenAPISpec)
+	}
+
+	endpoints := spec.endpointsByOperationID()
+	tools := make([]LLMTool, 0, len(operationIDs))
+
+	for _, operationID := range operationIDs {
+		endpoint, ok := endpoints[operationID]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrOperationNotFound, operationID)
+		}
+
+		tool, err := spec.createTool(spec.Servers[0].URL, endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("operation %s: %w", operationID, err)
+		}
+
+		tools = append(tools, tool)
+	}
+
+	return tools, nil
+}
+
+func readOpenAPISpec(specPath string) (*openAPISpec, error) {
+	data, err := os.ReadFile(specPath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidOpenAPISpec, err)
+	}
+
+	var spec openAPISpec
+	switch strings.ToLower(filepath.Ext(specPath)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &spec)
+	default:
+		err = json.Unmarshal(data, &spec)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidOpenAPISpec, err)
+	}
+
+	return &spec, nil
+}
+
+func (s *openAPISpec) endpointsByOperationID() map[string]openAPIEndpoint {
+	endpoints := make(map[string]openAPIEndpoint)
+
+	for path, operations := range s.Paths {
+		for method, operation := range operations {
+			if operation.OperationID == "" || !slices.Contains(openAPIMethods, strings.ToLower(method)) {
+				continue
+			}
+
+			endpoints[operation.OperationID] = openAPIEndpoint{
+				method:    strings.ToUpper(method),
+				path:      path,
+				operation: operation,
+			}
+		}
+	}
+
+	return endpoints
+}
+
+func (s *openAPISpec) createTool(serverURL string, endpoint openAPIEndpoint) (LLMTool, error) {
+	parametersSchema, err := s.createParametersSchema(endpoint.operation)
+	if err != nil {
+		return LLMTool{}, err
+	}
+
+	description := endpoint.operation.Summary
+	if endpoint.operation.Description != "" {
+		description = strings.TrimSpace(description + "\n" + endpoint.operation.Description)
+	}
+	if description == "" {
+		description = endpoint.method + " " + endpoint.path
+	}
+
+	return NewLLMTool(
+		WithLLMToolName(toToolName(endpoint.operation.OperationID)),
+		WithLLMToolDescription(description),
+		func(tool *LLMTool) {
+			tool.ParametersSchema = parametersSchema
+			tool.Call = func(id string, args string) (LLMToolResult, error) {
+				return callOpenAPIEndpoint(id, serverURL, endpoint, args)
+			}
+		},
+	)
+}
+
+func (s *openAPISpec) createParametersSchema(operation openAPIOperation) (map[string]any, error) {
+	properties := make(map[string]any)
+	required := make([]string, 0)
+
+	for _, param := range operation.Parameters {
+		if param.In != "path" && param.In != "query" {
+			continue
+		}
+
+		paramSchema, err := s.resolveRefs(param.Schema, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		property, _ := paramSchema.(map[string]any)
+		if property == nil {
+			property = map[string]any{"type": "string"}
+		}
+		if param.Description != "" {
+			property["description"] = param.Description
+		}
+
+		properties[param.Name] = property
+		if param.Required || param.In == "path" {
+			required = append(required, param.Name)
+		}
+	}
+
+	if operation.RequestBody != nil {
+		if media, ok := operation.RequestBody.Content["application/json"]; ok && media.Schema != nil {
+			bodySchema, err := s.resolveRefs(media.Schema, 0)
+			if err != nil {
+				return nil, err
+			}
+
+			properties[openAPIBodyProperty] = bodySchema
+			if operation.RequestBody.Required {
+				required = append(required, openAPIBodyProperty)
+			}
+		}
+	}
+
+	return map[string]any{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}, nil
+}
+
+// resolveRefs returns a copy of value with local "#/components/..." references replaced by their targets
+func (s *openAPISpec) resolveRefs(value any, depth int) (any, error) {
+	if depth > openAPIMaxRefDepth {
+		return nil, fmt.Errorf("%w: schema references are nested too deeply", ErrInvalidOpenAPISpec)
+	}
+
+	switch typed := value.(type) {
+	case map[string]any:
+		if ref, ok := typed["$ref"].(string); ok {
+			target, err := s.lookupRef(ref)
+			if err != nil {
+				return nil, err
+			}
+
+			return s.resolveRefs(target, depth+1)
+		}
+
+		resolved := make(map[string]any, len(typed))
+		for key, item := range typed {
+			resolvedItem, err := s.resolveRefs(item, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			resolved[key] = resolvedItem
+		}
+
+		return resolved, nil
+	case []any:
+		resolved := make([]any, 0, len(typed))
+		for _, item := range typed {
+			resolvedItem, err := s.resolveRefs(item, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			resolved = append(resolved, resolvedItem)
+		}
+
+		return resolved, nil
+	default:
+		return value, nil
+	}
+}
+
+func (s *openAPISpec) lookupRef(ref string) (any, error) {
+	const componentsPrefix = "#/components/"
+	if !strings.HasPrefix(ref, componentsPrefix) {
+		return nil, fmt.Errorf("%w: unsupported reference %s", ErrInvalidOpenAPISpec, ref)
+	}
+
+	var current any = s.Components
+	for _, segment := range strings.Split(strings.TrimPrefix(ref, componentsPrefix), "/") {
+		node, ok := current.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%w: unresolved reference %s", ErrInvalidOpenAPISpec, ref)
+		}
+		if current, ok = node[segment]; !ok {
+			return nil, fmt.Errorf("%w: unresolved reference %s", ErrInvalidOpenAPISpec, ref)
+		}
+	}
+
+	return current, nil
+}
+
+func callOpenAPIEndpoint(id, serverURL string, endpoint openAPIEndpoint, args string) (LLMToolResult, error) {
+	var params map[string]any
+	if err := json.Unmarshal([]byte(args), &params); err != nil {
+		return nil, fmt.Errorf("%w: failed to unmarshal arguments: %v", ErrInvalidArguments, err)
+	}
+
+	request, err := createOpenAPIRequest(serverURL, endpoint, params)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{Timeout: openAPIRequestTimeout}
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call %s %s: %w", endpoint.method, endpoint.path, err)
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	return OpenAPIToolResult{
+		BaseLLMToolResult: BaseLLMToolResult{ID: id},
+		StatusCode:        response.StatusCode,
+		Body:              string(body),
+	}, nil
+}
+
+func createOpenAPIRequest(serverURL string, endpoint openAPIEndpoint, params map[string]any) (*http.Request, error) {
+	path := endpoint.path
+	query := url.Values{}
+
+	for _, param := range endpoint.operation.Parameters {
+		value, ok := params[param.Name]
+		if !ok {
+			continue
+		}
+
+		switch param.In {
+		case "path":
+			path = strings.ReplaceAll(path, "{"+param.Name+"}", url.PathEscape(fmt.Sprint(value)))
+		case "query":
+			query.Set(param.Name, fmt.Sprint(value))
+		}
+	}
+
+	requestURL := strings.TrimSuffix(serverURL, "/") + path
+	if len(query) > 0 {
+		requestURL += "?" + query.Encode()
+	}
+
+	var body io.Reader
+	if bodyValue, ok := params[openAPIBodyProperty]; ok && endpoint.operation.RequestBody != nil {
+		bodyJSON, err := json.Marshal(bodyValue)
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to marshal request body: %v", ErrInvalidArguments, err)
+		}
+		body = bytes.NewReader(bodyJSON)
+	}
+
+	//nolint:noctx // LLMTool.Call has no context, the client timeout bounds the request
+	request, err := http.NewRequest(endpoint.method, requestURL, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	request.Header.Set("Accept", "application/json")
+
+	return request, nil
+}
+
+// toToolName converts an operation ID such as getOrderById into a valid tool name get_order_by_id
+func toToolName(operationID string) string {
+	name := camelCaseBoundary.ReplaceAllString(operationID, "${1}_${2}")
+	name = nonToolNameSymbols.ReplaceAllString(strings.ToLower(name), "_")
+
+	return strings.Trim(name, "_")
+}
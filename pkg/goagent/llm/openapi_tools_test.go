@@ -0,0 +1,131 @@
+package llm_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+const ordersSpec = `
+openapi: 3.0.0
+servers:
+  - url: {{server}}
+paths:
+  /orders/{orderId}:
+    get:
+      operationId: getOrderById
+      summary: Get an order by ID
+      parameters:
+        - name: orderId
+          in: path
+          required: true
+          schema:
+            type: string
+        - name: expand
+          in: query
+          description: Related objects to include
+          schema:
+            type: string
+  /orders:
+    post:
+      operationId: createOrder
+      summary: Create an order
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Order'
+components:
+  schemas:
+    Order:
+      type: object
+      properties:
+        item:
+          type: string
+        quantity:
+          type: integer
+`
+
+func writeOrdersSpec(t *testing.T, serverURL string) string {
+	t.Helper()
+
+	specPath := filepath.Join(t.TempDir(), "orders.yaml")
+	spec := strings.ReplaceAll(ordersSpec, "{{server}}", serverURL)
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0o600))
+
+	return specPath
+}
+
+func TestNewToolsFromOpenAPISpec(t *testing.T) {
+	t.Parallel()
+
+	var requests []string
+	var requestBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.RequestURI())
+		requestBody = string(body)
+		_, _ = w.Write([]byte(`{"ok": true}`))
+	}))
+	defer server.Close()
+
+	tools, err := llm.NewToolsFromOpenAPISpec(writeOrdersSpec(t, server.URL), []string{"getOrderById", "createOrder"})
+	require.NoError(t, err)
+	require.Len(t, tools, 2)
+
+	getOrder := tools[0]
+	assert.Equal(t, "get_order_by_id", getOrder.Name)
+	assert.Equal(t, "Get an order by ID", getOrder.Description)
+
+	params, err := json.Marshal(getOrder.ParametersSchema)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"orderId": {"type": "string"},
+			"expand": {"type": "string", "description": "Related objects to include"}
+		},
+		"required": ["orderId"]
+	}`, string(params))
+
+	result, err := getOrder.Call("call_1", `{"orderId": "42", "expand": "items"}`)
+	require.NoError(t, err)
+	assert.Equal(t, llm.OpenAPIToolResult{
+		BaseLLMToolResult: llm.BaseLLMToolResult{ID: "call_1"},
+		StatusCode:        http.StatusOK,
+		Body:              `{"ok": true}`,
+	}, result)
+
+	createOrder := tools[1]
+	_, err = createOrder.Call("call_2", `{"body": {"item": "book", "quantity": 2}}`)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"GET /orders/42?expand=items", "POST /orders"}, requests)
+	assert.JSONEq(t, `{"item": "book", "quantity": 2}`, requestBody)
+}
+
+func TestNewToolsFromOpenAPISpec_OperationNotFound(t *testing.T) {
+	t.Parallel()
+
+	_, err := llm.NewToolsFromOpenAPISpec(writeOrdersSpec(t, "http://localhost"), []string{"deleteOrder"})
+
+	require.ErrorIs(t, err, llm.ErrOperationNotFound)
+}
+
+func TestNewToolsFromOpenAPISpec_MissingFile(t *testing.T) {
+	t.Parallel()
+
+	_, err := llm.NewToolsFromOpenAPISpec(filepath.Join(t.TempDir(), "missing.json"), []string{"getOrderById"})
+
+	require.ErrorIs(t, err, llm.ErrInvalidOpenAPISpec)
+}
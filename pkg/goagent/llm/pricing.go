@@ -0,0 +1,65 @@
+package llm
+
+import "strings"
+
+// TokenUsage is the number of tokens consumed by one or more LLM calls
+type TokenUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+// Add returns the sum of both usages
+func (u TokenUsage) Add(other TokenUsage) TokenUsage {
+	return TokenUsage{
+		InputTokens:  u.InputTokens + other.InputTokens,
+		OutputTokens: u.OutputTokens + other.OutputTokens,
+	}
+}
+
+// ModelPrice is the price of a model in USD per million tokens
+type ModelPrice struct {
+	InputPerMillion  float64 `json:"input_per_million"`
+	OutputPerMillion float64 `json:"output_per_million"`
+}
+
+// Cost returns the estimated cost of the usage in USD
+func (p ModelPrice) Cost(usage TokenUsage) float64 {
+	return (float64(usage.InputTokens)*p.InputPerMillion + float64(usage.OutputTokens)*p.OutputPerMillion) / 1_000_000
+}
+
+// modelPrices are the list prices of known models. Snapshot models, e.g. "gpt-4o-2024-08-06",
+// are matched by the longest model name they start with.
+var modelPrices = map[string]ModelPrice{
+	"gpt-4.1":       {InputPerMillion: 2, OutputPerMillion: 8},
+	"gpt-4.1-mini":  {InputPerMillion: 0.4, OutputPerMillion: 1.6},
+	"gpt-4.1-nano":  {InputPerMillion: 0.1, OutputPerMillion: 0.4},
+	"gpt-4o":        {InputPerMillion: 2.5, OutputPerMillion: 10},
+	"gpt-4o-mini":   {InputPerMillion: 0.15, OutputPerMillion: 0.6},
+	"gpt-4-turbo":   {InputPerMillion: 10, OutputPerMillion: 30},
+	"gpt-4":         {InputPerMillion: 30, OutputPerMillion: 60},
+	"gpt-3.5-turbo": {InputPerMillion: 0.5, OutputPerMillion: 1.5},
+	"o1":            {InputPerMillion: 15, OutputPerMillion: 60},
+	"o3":            {InputPerMillion: 2, OutputPerMillion: 8},
+	"o3-mini":       {InputPerMillion: 1.1, OutputPerMillion: 4.4},
+	"o4-mini":       {InputPerMillion: 1.1, OutputPerMillion: 4.4},
+}
+
+// LookupModelPrice returns the price of the model from the built-in price table
+func LookupModelPrice(model string) (ModelPrice, bool) {
+	var (
+		price   ModelPrice
+		matched string
+	)
+	for name, candidate := range modelPrices {
+		if len(name) <= len(matched) || !matchesModel(model, name) {
+			continue
+		}
+		price, matched = candidate, name
+	}
+
+	return price, matched != ""
+}
+
+func matchesModel(model, name string) bool {
+	return model == name || strings.HasPrefix(model, name+"-")
+}
@@ -0,0 +1,53 @@
+package llm_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+func TestLookupModelPrice(t *testing.T) {
+	t.Parallel()
+
+	gpt4o := llm.ModelPrice{InputPerMillion: 2.5, OutputPerMillion: 10}
+
+	tests := []struct {
+		model     string
+		wantPrice llm.ModelPrice
+		wantOK    bool
+	}{
+		{model: "gpt-4o", wantPrice: gpt4o, wantOK: true},
+		{model: "gpt-4o-mini", wantPrice: llm.ModelPrice{InputPerMillion: 0.15, OutputPerMillion: 0.6}, wantOK: true},
+		{model: "gpt-4o-2024-08-06", wantPrice: gpt4o, wantOK: true},
+		{model: "gpt-4", wantPrice: llm.ModelPrice{InputPerMillion: 30, OutputPerMillion: 60}, wantOK: true},
+		{
+			model:     "gpt-4-turbo-preview",
+			wantPrice: llm.ModelPrice{InputPerMillion: 10, OutputPerMillion: 30},
+			wantOK:    true,
+		},
+		{model: "gpt-4ox", wantOK: false},
+		{model: "llama-3", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			t.Parallel()
+
+			price, ok := llm.LookupModelPrice(tt.model)
+
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantPrice, price)
+		})
+	}
+}
+
+func TestModelPrice_Cost(t *testing.T) {
+	t.Parallel()
+
+	price := llm.ModelPrice{InputPerMillion: 2, OutputPerMillion: 8}
+	usage := llm.TokenUsage{InputTokens: 1000, OutputTokens: 500}.
+		Add(llm.TokenUsage{InputTokens: 500, OutputTokens: 500})
+
+	assert.InDelta(t, 0.011, price.Cost(usage), 1e-9)
+}
@@ -0,0 +1,106 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+)
+
+// RateLimiter limits how often a tool is called. Wait blocks until the next call is allowed
+// and returns an error when ctx is done first. *rate.Limiter from golang.org/x/time/rate
+// implements it, so it can be assigned to LLMTool.RateLimit directly.
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+}
+
+// WithLLMToolRateLimit limits the tool to callsPerSecond calls, independently of other tools.
+// The agent waits for the limiter before every call of the tool, e.g. to stay within
+// the quota of a paid API.
+func WithLLMToolRateLimit(callsPerSecond float64) LLMToolOption {
+	return func(tool *LLMTool) {
+		tool.RateLimit = newIntervalRateLimiter(callsPerSecond)
+	}
+}
+
+// intervalRateLimiter allows one call per interval without bursts
+type intervalRateLimiter struct {
+	callsPerSecond float64
+	interval       time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+func newIntervalRateLimiter(callsPerSecond float64) *intervalRateLimiter {
+	limiter := &intervalRateLimiter{callsPerSecond: callsPerSecond}
+	if callsPerSecond > 0 {
+		limiter.interval = time.Duration(float64(time.Second) / callsPerSecond)
+	}
+
+	return limiter
+}
+
+func (l *intervalRateLimiter) validate() error {
+	if l.callsPerSecond <= 0 {
+		return fmt.Errorf("%w: calls per second must be positive, got %v",
+			validation.ErrValidationFailed, l.callsPerSecond)
+	}
+
+	return nil
+}
+
+// Wait reserves the next free slot and sleeps until it. When the slot is after the ctx
+// deadline, Wait returns context.DeadlineExceeded immediately without reserving it.
+// When ctx is done while waiting, the slot is released unless a later one is reserved.
+func (l *intervalRateLimiter) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	slot := now
+	if l.next.After(now) {
+		slot = l.next
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && slot.After(deadline) {
+		l.mu.Unlock()
+
+		return context.DeadlineExceeded
+	}
+
+	l.next = slot.Add(l.interval)
+	l.mu.Unlock()
+
+	delay := slot.Sub(now)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.release(slot)
+
+		return ctx.Err()
+	}
+}
+
+// release gives the slot back when it is the last reserved one. Later reservations keep
+// their slots, so the released one can't be reused without a burst.
+func (l *intervalRateLimiter) release(slot time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.next.Equal(slot.Add(l.interval)) {
+		l.next = slot
+	}
+}
@@ -0,0 +1,97 @@
+package llm_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+func createRateLimitedTool(t *testing.T, callsPerSecond float64) (llm.LLMTool, error) {
+	t.Helper()
+
+	return llm.NewLLMTool(
+		llm.WithLLMToolName("paid_api"),
+		llm.WithLLMToolDescription("Calls a paid API"),
+		llm.WithLLMToolParametersSchema[TestParams](),
+		llm.WithLLMToolCall(func(callID string, _ TestParams) (TestResult, error) {
+			return TestResult{BaseLLMToolResult: llm.BaseLLMToolResult{ID: callID}}, nil
+		}),
+		llm.WithLLMToolRateLimit(callsPerSecond),
+	)
+}
+
+func TestWithLLMToolRateLimit(t *testing.T) {
+	t.Parallel()
+
+	// given
+	tool, err := createRateLimitedTool(t, 20)
+	require.NoError(t, err)
+	require.NotNil(t, tool.RateLimit)
+
+	// when
+	start := time.Now()
+	for range 3 {
+		require.NoError(t, tool.RateLimit.Wait(context.Background()))
+	}
+
+	// then
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+}
+
+func TestWithLLMToolRateLimit_ContextDeadline(t *testing.T) {
+	t.Parallel()
+
+	// given
+	tool, err := createRateLimitedTool(t, 1)
+	require.NoError(t, err)
+	require.NoError(t, tool.RateLimit.Wait(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// when
+	start := time.Now()
+	err = tool.RateLimit.Wait(ctx)
+
+	// then
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 50*time.Millisecond)
+}
+
+func TestWithLLMToolRateLimit_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := createRateLimitedTool(t, 0)
+
+	require.ErrorIs(t, err, validation.ErrValidationFailed)
+	assert.Contains(t, err.Error(), "rate limit")
+}
+
+func TestWithLLMToolRateLimit_CanceledWaitReleasesSlot(t *testing.T) {
+	t.Parallel()
+
+	// given
+	tool, err := createRateLimitedTool(t, 5)
+	require.NoError(t, err)
+	require.NoError(t, tool.RateLimit.Wait(context.Background()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+	require.ErrorIs(t, tool.RateLimit.Wait(ctx), context.Canceled)
+
+	// when
+	start := time.Now()
+	err = tool.RateLimit.Wait(context.Background())
+
+	// then
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), 250*time.Millisecond)
+}
@@ -0,0 +1,23 @@
+package llm
+
+// ToolCallBatch is one of the tool calls dispatched together to BatchableTool.BatchCall
+type ToolCallBatch struct {
+	ID   string
+	Args string
+}
+
+// BatchableTool is implemented by tools that can execute several calls at once, e.g. a
+// database lookup that fetches many rows with a single query. BatchCall returns one result
+// per call, in the order of calls, each with the ID of its call. An error fails every call
+// of the batch.
+type BatchableTool interface {
+	BatchCall(calls []ToolCallBatch) ([]LLMToolResult, error)
+}
+
+// WithLLMToolBatchCall registers the batch implementation of the tool. The agent uses it
+// instead of Call only when tool call batching is enabled with agent.WithToolCallBatch.
+func WithLLMToolBatchCall(batch BatchableTool) LLMToolOption {
+	return func(tool *LLMTool) {
+		tool.Batch = batch
+	}
+}
@@ -0,0 +1,137 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/schema"
+)
+
+// ToolCatalog renders documentation for a set of tools. It is useful for debugging,
+// writing system prompts manually or generating documentation for tool-using agents.
+type ToolCatalog struct {
+	tools []LLMTool
+}
+
+type catalogEntry struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Parameters  map[string]any `json:"parameters"`
+}
+
+// NewToolCatalog creates a catalog of the given tools, keeping their order
+func NewToolCatalog(tools []LLMTool) *ToolCatalog {
+	return &ToolCatalog{tools: tools}
+}
+
+// MarshalJSON emits every tool with its name, description and parameters JSON schema
+func (c *ToolCatalog) MarshalJSON() ([]byte, error) {
+	entries := make([]catalogEntry, 0, len(c.tools))
+	for _, tool := range c.tools {
+		params, err := schema.GenerateSchema(tool.ParametersSchema)
+		if err != nil {
+			return nil, fmt.Errorf("tool %s: %w", tool.Name, err)
+		}
+
+		entries = append(entries, catalogEntry{
+			Name:        tool.Name,
+			Description: tool.Description,
+			Parameters:  params,
+		})
+	}
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal tool catalog: %w", err)
+	}
+
+	return data, nil
+}
+
+// Markdown renders the tools as a Markdown table with Name, Description and Parameters columns.
+// Each parameter is listed as `name` (type, required): description.
+//
+// Example output:
+//
+//	| Name | Description | Parameters |
+//	| --- | --- | --- |
+//	| add | Adds two numbers | `num1` (integer, required): First number<br>`num2` (integer, required): Second number |
+func (c *ToolCatalog) Markdown() string {
+	var builder strings.Builder
+
+	builder.WriteString("| Name | Description | Parameters |\n")
+	builder.WriteString("| --- | --- | --- |\n")
+
+	for _, tool := range c.tools {
+		fmt.Fprintf(&builder, "| %s | %s | %s |\n",
+			escapeMarkdownCell(tool.Name),
+			escapeMarkdownCell(tool.Description),
+			describeParameters(tool.ParametersSchema),
+		)
+	}
+
+	return builder.String()
+}
+
+func describeParameters(parametersSchema any) string {
+	params, err := schema.GenerateSchema(parametersSchema)
+	if err != nil {
+		return "_unavailable_"
+	}
+
+	properties, _ := params["properties"].(map[string]any)
+	if len(properties) == 0 {
+		return "_none_"
+	}
+
+	required := make(map[string]bool)
+	if requiredList, ok := params["required"].([]any); ok {
+		for _, name := range requiredList {
+			if nameStr, ok := name.(string); ok {
+				required[nameStr] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	lines := make([]string, 0, len(names))
+	for _, name := range names {
+		property, _ := properties[name].(map[string]any)
+		lines = append(lines, describeParameter(name, property, required[name]))
+	}
+
+	return strings.Join(lines, "<br>")
+}
+
+func describeParameter(name string, property map[string]any, required bool) string {
+	attributes := make([]string, 0, 2)
+	if propertyType, ok := property["type"].(string); ok {
+		attributes = append(attributes, propertyType)
+	}
+	if required {
+		attributes = append(attributes, "required")
+	}
+
+	line := "`" + name + "`"
+	if len(attributes) > 0 {
+		line += " (" + strings.Join(attributes, ", ") + ")"
+	}
+	if description, ok := property["description"].(string); ok && description != "" {
+		line += ": " + escapeMarkdownCell(description)
+	}
+
+	return line
+}
+
+func escapeMarkdownCell(value string) string {
+	value = strings.ReplaceAll(value, "|", `\|`)
+
+	return strings.ReplaceAll(value, "\n", "<br>")
+}
@@ -0,0 +1,62 @@
+package llm_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+type CatalogParams struct {
+	Query string `json:"query" jsonschema_description:"Search query"`
+	Limit int    `json:"limit,omitempty" jsonschema_description:"Maximum number of results"`
+}
+
+func createCatalogTool(t *testing.T) llm.LLMTool {
+	t.Helper()
+
+	tool, err := llm.NewLLMTool(
+		llm.WithLLMToolName("search"),
+		llm.WithLLMToolDescription("Searches documents | returns matches"),
+		llm.WithLLMToolParametersSchema[CatalogParams](),
+		llm.WithLLMToolCall(func(callID string, _ CatalogParams) (TestResult, error) {
+			return TestResult{BaseLLMToolResult: llm.BaseLLMToolResult{ID: callID}}, nil
+		}),
+	)
+	require.NoError(t, err)
+
+	return tool
+}
+
+func TestToolCatalog_Markdown(t *testing.T) {
+	t.Parallel()
+
+	catalog := llm.NewToolCatalog([]llm.LLMTool{createCatalogTool(t)})
+
+	expected := "| Name | Description | Parameters |\n" +
+		"| --- | --- | --- |\n" +
+		"| search | Searches documents \\| returns matches | " +
+		"`limit` (integer): Maximum number of results<br>`query` (string, required): Search query |\n"
+	assert.Equal(t, expected, catalog.Markdown())
+}
+
+func TestToolCatalog_MarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	catalog := llm.NewToolCatalog([]llm.LLMTool{createCatalogTool(t)})
+
+	data, err := json.Marshal(catalog)
+	require.NoError(t, err)
+
+	var entries []map[string]any
+	require.NoError(t, json.Unmarshal(data, &entries))
+	require.Len(t, entries, 1)
+	assert.Equal(t, "search", entries[0]["name"])
+	assert.Equal(t, "Searches documents | returns matches", entries[0]["description"])
+
+	params, ok := entries[0]["parameters"].(map[string]any)
+	require.True(t, ok)
+	assert.Contains(t, params["properties"], "query")
+}
@@ -12,8 +12,30 @@ func CreateLLM(cfg llm.LLMConfig, tools map[string]llm.LLMTool) (llm.LLM, error)
 	case llm.LLMTypeOpenAI:
 		return openai.NewOpenAILLM(
 			openai.WithAPIKey(cfg.APIKey),
+			openai.WithOrganizationID(cfg.OrganizationID),
+			openai.WithProject(cfg.ProjectID),
+			openai.WithBaseURL(cfg.BaseURL),
+			openai.WithHTTPClient(cfg.HTTPClient),
+			openai.WithRequestHeaders(cfg.RequestHeaders),
+			openai.WithStrictFunctionCalling(cfg.StrictFunctionCalling),
 			openai.WithModel(cfg.Model),
 			openai.WithTemperature(cfg.Temperature),
+			openai.WithMaxTokens(cfg.MaxTokens),
+			openai.WithTools(toSlice(tools)),
+		), nil
+	case llm.LLMTypeOpenAIPrompt:
+		return openai.NewOpenAIPromptLLM(
+			cfg.PromptID,
+			openai.WithAPIKey(cfg.APIKey),
+			openai.WithOrganizationID(cfg.OrganizationID),
+			openai.WithProject(cfg.ProjectID),
+			openai.WithBaseURL(cfg.BaseURL),
+			openai.WithHTTPClient(cfg.HTTPClient),
+			openai.WithRequestHeaders(cfg.RequestHeaders),
+			openai.WithStrictFunctionCalling(cfg.StrictFunctionCalling),
+			openai.WithModel(cfg.Model),
+			openai.WithTemperature(cfg.Temperature),
+			openai.WithMaxTokens(cfg.MaxTokens),
 			openai.WithTools(toSlice(tools)),
 		), nil
 	default:
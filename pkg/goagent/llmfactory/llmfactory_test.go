@@ -1,12 +1,16 @@
 package llmfactory_test
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
 	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmfactory"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/openai"
 )
 
 func TestCreateLLM_OpenAI(t *testing.T) {
@@ -60,6 +64,40 @@ func TestCreateLLM_OpenAI_EmptyTools(t *testing.T) {
 	assert.NotNil(t, result)
 }
 
+func TestCreateLLM_OpenAI_BaseURLAndProject(t *testing.T) {
+	t.Parallel()
+
+	// given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		assert.Equal(t, "proj_abc", r.Header.Get("OpenAI-Project"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices": [{"finish_reason": "stop", "message": {"content": "Hello from proxy"}}]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	cfg := llm.LLMConfig{
+		Type:      llm.LLMTypeOpenAI,
+		APIKey:    "sk-proj-test-key",
+		Model:     "gpt-4o-mini",
+		ProjectID: "proj_abc",
+		BaseURL:   server.URL + "/v1",
+	}
+
+	// when
+	result, err := llmfactory.CreateLLM(cfg, nil)
+	require.NoError(t, err)
+
+	response, err := result.Call(context.Background(), []llm.LLMMessage{
+		llm.NewLLMMessage(llm.LLMMessageTypeUser, "Say hello"),
+	})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "Hello from proxy", response.Content)
+}
+
 func TestCreateLLM_UnsupportedType(t *testing.T) {
 	t.Parallel()
 	cfg := llm.LLMConfig{
@@ -124,3 +162,17 @@ func createTestTool() llm.LLMTool {
 
 	return tool
 }
+
+func TestCreateLLM_OpenAIPrompt(t *testing.T) {
+	t.Parallel()
+	cfg := llm.LLMConfig{
+		Type:     llm.LLMTypeOpenAIPrompt,
+		APIKey:   "test-key",
+		PromptID: "pmpt_123",
+	}
+
+	result, err := llmfactory.CreateLLM(cfg, map[string]llm.LLMTool{"test": createTestTool()})
+
+	require.NoError(t, err)
+	assert.IsType(t, &openai.OpenAIPromptLLM{}, result)
+}
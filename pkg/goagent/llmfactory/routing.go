@@ -0,0 +1,57 @@
+package llmfactory
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+// DefaultRoute is the key of the route used for agents without a route of their own.
+// Agent names are snake case, so it never collides with an agent name.
+const DefaultRoute = "*"
+
+// ErrNoRoute is returned when neither the agent nor DefaultRoute has a route
+var ErrNoRoute = errors.New("no LLM route for agent")
+
+// RoutingFactory creates LLMs with a config selected by the agent name, e.g. to send the
+// requests of different agents to different providers or API keys for cost isolation
+type RoutingFactory struct {
+	routes map[string]llm.LLMConfig
+}
+
+// NewRoutingFactory creates a factory which routes every agent to the config stored under
+// its name, or under DefaultRoute when the agent has no route of its own.
+//
+// Example:
+//
+//	factory := llmfactory.NewRoutingFactory(map[string]llm.LLMConfig{
+//		"support_agent":        supportConfig,
+//		llmfactory.DefaultRoute: sharedConfig,
+//	})
+func NewRoutingFactory(routes map[string]llm.LLMConfig) *RoutingFactory {
+	return &RoutingFactory{routes: maps.Clone(routes)}
+}
+
+// Config returns the config of the route selected for the agent
+func (f *RoutingFactory) Config(agentName string) (llm.LLMConfig, error) {
+	if cfg, ok := f.routes[agentName]; ok {
+		return cfg, nil
+	}
+	if cfg, ok := f.routes[DefaultRoute]; ok {
+		return cfg, nil
+	}
+
+	return llm.LLMConfig{}, fmt.Errorf("%w: %s", ErrNoRoute, agentName)
+}
+
+// CreateLLM creates an LLM with the config of the route selected for the agent
+func (f *RoutingFactory) CreateLLM(agentName string, tools map[string]llm.LLMTool) (llm.LLM, error) {
+	cfg, err := f.Config(agentName)
+	if err != nil {
+		return nil, err
+	}
+
+	return CreateLLM(cfg, tools)
+}
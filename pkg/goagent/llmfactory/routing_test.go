@@ -0,0 +1,60 @@
+package llmfactory_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmfactory"
+)
+
+func routeConfig(apiKey string) llm.LLMConfig {
+	return llm.LLMConfig{Type: llm.LLMTypeOpenAI, APIKey: apiKey, Model: "gpt-4o-mini"}
+}
+
+func TestRoutingFactory_Config(t *testing.T) {
+	t.Parallel()
+
+	// given
+	routes := map[string]llm.LLMConfig{
+		"billing_agent":         routeConfig("billing-key"),
+		llmfactory.DefaultRoute: routeConfig("shared-key"),
+	}
+	factory := llmfactory.NewRoutingFactory(routes)
+	delete(routes, "billing_agent")
+
+	// when
+	billingConfig, billingErr := factory.Config("billing_agent")
+	supportConfig, supportErr := factory.Config("support_agent")
+
+	// then
+	require.NoError(t, billingErr)
+	assert.Equal(t, "billing-key", billingConfig.APIKey)
+	require.NoError(t, supportErr)
+	assert.Equal(t, "shared-key", supportConfig.APIKey)
+}
+
+func TestRoutingFactory_NoRoute(t *testing.T) {
+	t.Parallel()
+
+	factory := llmfactory.NewRoutingFactory(map[string]llm.LLMConfig{"billing_agent": routeConfig("billing-key")})
+
+	_, err := factory.CreateLLM("support_agent", nil)
+
+	require.ErrorIs(t, err, llmfactory.ErrNoRoute)
+	assert.Contains(t, err.Error(), "support_agent")
+}
+
+func TestRoutingFactory_CreateLLM(t *testing.T) {
+	t.Parallel()
+
+	factory := llmfactory.NewRoutingFactory(map[string]llm.LLMConfig{
+		llmfactory.DefaultRoute: routeConfig("shared-key"),
+	})
+
+	result, err := factory.CreateLLM("support_agent", map[string]llm.LLMTool{"test": createTestTool()})
+
+	require.NoError(t, err)
+	assert.NotNil(t, result)
+}
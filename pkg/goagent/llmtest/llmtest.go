@@ -0,0 +1,74 @@
+// Package llmtest provides test doubles for the llm.LLM interface, so agents
+// can be exercised without calling a real LLM provider.
+package llmtest
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+// ErrNoMoreResponses is returned when MockLLM is called more times than it has responses
+var ErrNoMoreResponses = errors.New("mock LLM has no more responses")
+
+// MockLLM is an llm.LLM that returns predefined responses in order and records
+// every call. It is safe for concurrent use.
+type MockLLM struct {
+	mu               sync.Mutex
+	responses        []llm.LLMMessage
+	structuredOutput string
+	calls            [][]llm.LLMMessage
+	schemas          []any
+}
+
+// NewMockLLM creates a MockLLM that returns responses from Call in order and
+// structuredOutput from every CallWithStructuredOutput
+func NewMockLLM(structuredOutput string, responses ...llm.LLMMessage) *MockLLM {
+	return &MockLLM{
+		responses:        responses,
+		structuredOutput: structuredOutput,
+	}
+}
+
+func (m *MockLLM) Call(_ context.Context, msgs []llm.LLMMessage) (llm.LLMMessage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls = append(m.calls, append([]llm.LLMMessage(nil), msgs...))
+	if len(m.responses) == 0 {
+		return llm.LLMMessage{}, ErrNoMoreResponses
+	}
+
+	response := m.responses[0]
+	m.responses = m.responses[1:]
+
+	return response, nil
+}
+
+func (m *MockLLM) CallWithStructuredOutput(_ context.Context, msgs []llm.LLMMessage, schemaT any) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls = append(m.calls, append([]llm.LLMMessage(nil), msgs...))
+	m.schemas = append(m.schemas, schemaT)
+
+	return m.structuredOutput, nil
+}
+
+// Calls returns the messages passed to every call, in order
+func (m *MockLLM) Calls() [][]llm.LLMMessage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([][]llm.LLMMessage(nil), m.calls...)
+}
+
+// Schemas returns the schemas passed to every CallWithStructuredOutput, in order
+func (m *MockLLM) Schemas() []any {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]any(nil), m.schemas...)
+}
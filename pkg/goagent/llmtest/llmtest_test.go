@@ -0,0 +1,51 @@
+package llmtest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+func TestMockLLM(t *testing.T) {
+	t.Parallel()
+
+	first := llm.NewLLMMessage(llm.LLMMessageTypeAssistant, "first")
+	mockLLM := llmtest.NewMockLLM(`{"answer": 42}`, first)
+	msgs := []llm.LLMMessage{llm.NewLLMMessage(llm.LLMMessageTypeUser, "hello")}
+
+	response, err := mockLLM.Call(context.Background(), msgs)
+	require.NoError(t, err)
+	assert.Equal(t, first, response)
+
+	_, err = mockLLM.Call(context.Background(), msgs)
+	require.ErrorIs(t, err, llmtest.ErrNoMoreResponses)
+
+	output, err := mockLLM.CallWithStructuredOutput(context.Background(), msgs, "schema")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"answer": 42}`, output)
+
+	assert.Len(t, mockLLM.Calls(), 3)
+	assert.Equal(t, []any{"schema"}, mockLLM.Schemas())
+}
+
+func TestAssertMessageRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	// given
+	mockLLM := llmtest.NewMockLLM("", llm.LLMMessage{
+		Type:      llm.LLMMessageTypeAssistant,
+		ToolCalls: []llm.LLMToolCall{{ID: "call_3", ToolName: "add", Args: `{"num1": 10, "num2": 15}`}},
+	})
+	messages := llmtest.RoundTripMessages()
+
+	// when
+	llmtest.AssertMessageRoundTrip(t, mockLLM, messages)
+
+	// then
+	require.Len(t, mockLLM.Calls(), 1)
+	assert.Equal(t, messages, mockLLM.Calls()[0])
+}
@@ -0,0 +1,72 @@
+package llmtest
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+// RoundTripToolResult is the tool result used in RoundTripMessages
+type RoundTripToolResult struct {
+	llm.BaseLLMToolResult
+	Sum float64 `json:"sum"`
+}
+
+// RoundTripMessages returns a conversation which uses every part of llm.LLMMessage an
+// adapter has to serialize: system and user messages, a multimodal user message, an
+// assistant message with tool calls and their results, and a follow-up question
+func RoundTripMessages() []llm.LLMMessage {
+	return []llm.LLMMessage{
+		llm.NewLLMMessage(llm.LLMMessageTypeSystem, "You are a calculator."),
+		llm.NewLLMMessage(llm.LLMMessageTypeUser, "What is 2 + 3?"),
+		llm.NewMultimodalLLMMessage(llm.LLMMessageTypeUser,
+			llm.NewTextPart("And what is the sum on this receipt?"),
+			llm.NewImagePart(llm.ImageContent{URL: "https://example.com/receipt.png"}),
+		),
+		{
+			Type:    llm.LLMMessageTypeAssistant,
+			Content: "I'll calculate both sums.",
+			ToolCalls: []llm.LLMToolCall{
+				{ID: "call_1", ToolName: "add", Args: `{"num1": 2, "num2": 3}`},
+				{ID: "call_2", ToolName: "add", Args: `{"num1": 12.5, "num2": 7.5}`},
+			},
+			ToolResults: []llm.LLMToolResult{
+				RoundTripToolResult{BaseLLMToolResult: llm.BaseLLMToolResult{ID: "call_1"}, Sum: 5},
+				RoundTripToolResult{BaseLLMToolResult: llm.BaseLLMToolResult{ID: "call_2"}, Sum: 20},
+			},
+		},
+		{Type: llm.LLMMessageTypeAssistant, Content: "2 + 3 is 5 and the receipt sums up to 20."},
+		llm.NewLLMMessage(llm.LLMMessageTypeUser, "Thank you! Now what is 10 + 15?"),
+	}
+}
+
+// AssertMessageRoundTrip sends messages to adapter and asserts that the adapter serializes
+// them without an error and returns a valid assistant message: its tool calls have IDs,
+// tool names and JSON arguments, and the message itself can be marshaled to JSON. Use it
+// with RoundTripMessages in the tests of every llm.LLM implementation.
+//
+// Example:
+//
+//	llmtest.AssertMessageRoundTrip(t, adapter, llmtest.RoundTripMessages())
+func AssertMessageRoundTrip(t *testing.T, adapter llm.LLM, messages []llm.LLMMessage) {
+	t.Helper()
+
+	response, err := adapter.Call(context.Background(), messages)
+	require.NoError(t, err, "adapter failed to handle the message history")
+
+	assert.Equal(t, llm.LLMMessageTypeAssistant, response.Type)
+	assert.True(t, response.Content != "" || len(response.ToolCalls) > 0, "response has no content and no tool calls")
+
+	for _, toolCall := range response.ToolCalls {
+		assert.NotEmpty(t, toolCall.ID, "tool call without id")
+		assert.NotEmpty(t, toolCall.ToolName, "tool call %s without tool name", toolCall.ID)
+		assert.True(t, json.Valid([]byte(toolCall.Args)), "tool call %s has invalid JSON arguments", toolCall.ID)
+	}
+
+	_, err = json.Marshal(response)
+	assert.NoError(t, err, "response can't be marshaled to JSON")
+}
@@ -0,0 +1,293 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+const clientName = "go-agent"
+
+// client sends JSON-RPC messages to an MCP server over the Streamable HTTP transport
+type client struct {
+	serverURL  string
+	httpClient *http.Client
+
+	mu        sync.Mutex
+	nextID    int
+	sessionID string
+}
+
+// NewToolFromMCPServer creates a tool which is executed by an MCP server. The tool description
+// and parameters schema are discovered with the tools/list method and every call of the tool
+// is sent to the server with the tools/call method. The result is a ToolResult, or an error
+// wrapping ErrMCPToolCallFailed when the server reports a failed call.
+//
+// Example:
+//
+//	weatherTool, err := mcp.NewToolFromMCPServer("http://localhost:8000/mcp", "get_weather")
+func NewToolFromMCPServer(serverURL string, toolName string) (llm.LLMTool, error) {
+	c := &client{
+		serverURL:  serverURL,
+		httpClient: &http.Client{Timeout: requestTimeout},
+	}
+
+	if err := c.initialize(); err != nil {
+		return llm.LLMTool{}, err
+	}
+
+	definition, err := c.findTool(toolName)
+	if err != nil {
+		return llm.LLMTool{}, err
+	}
+
+	description := definition.Description
+	if description == "" {
+		description = "MCP tool " + definition.Name
+	}
+
+	inputSchema := definition.InputSchema
+	if inputSchema == nil {
+		inputSchema = map[string]any{"type": "object"}
+	}
+
+	return llm.NewLLMTool(
+		llm.WithLLMToolName(definition.Name),
+		llm.WithLLMToolDescription(description),
+		func(tool *llm.LLMTool) {
+			tool.ParametersSchema = inputSchema
+			tool.Call = func(id string, args string) (llm.LLMToolResult, error) {
+				return c.callTool(id, definition.Name, args)
+			}
+		},
+	)
+}
+
+func (c *client) initialize() error {
+	params := map[string]any{
+		"protocolVersion": ProtocolVersion,
+		"capabilities":    map[string]any{},
+		"clientInfo":      map[string]any{"name": clientName},
+	}
+
+	if _, err := c.request("initialize", params); err != nil {
+		return err
+	}
+
+	return c.notify("notifications/initialized")
+}
+
+func (c *client) findTool(name string) (toolDefinition, error) {
+	cursor := ""
+	for {
+		params := map[string]any{}
+		if cursor != "" {
+			params["cursor"] = cursor
+		}
+
+		result, err := c.request("tools/list", params)
+		if err != nil {
+			return toolDefinition{}, err
+		}
+
+		var list listToolsResult
+		if err := json.Unmarshal(result, &list); err != nil {
+			return toolDefinition{}, fmt.Errorf("%w: invalid tools/list result: %w", ErrMCPRequest, err)
+		}
+
+		for _, definition := range list.Tools {
+			if definition.Name == name {
+				return definition, nil
+			}
+		}
+
+		if list.NextCursor == "" {
+			return toolDefinition{}, fmt.Errorf("%w: %s", ErrMCPToolNotFound, name)
+		}
+		cursor = list.NextCursor
+	}
+}
+
+func (c *client) callTool(id string, name string, args string) (llm.LLMToolResult, error) {
+	result, err := c.request("tools/call", callToolParams{Name: name, Arguments: json.RawMessage(args)})
+	if err != nil {
+		return nil, err
+	}
+
+	var callResult callToolResult
+	if err := json.Unmarshal(result, &callResult); err != nil {
+		return nil, fmt.Errorf("%w: invalid tools/call result: %w", ErrMCPRequest, err)
+	}
+
+	if callResult.IsError {
+		return nil, fmt.Errorf("%w: %s", ErrMCPToolCallFailed, contentText(callResult.Content))
+	}
+
+	return ToolResult{
+		BaseLLMToolResult: llm.BaseLLMToolResult{ID: id},
+		Content:           callResult.Content,
+		StructuredContent: callResult.StructuredContent,
+	}, nil
+}
+
+// request sends a JSON-RPC request and returns the result of the matching response
+func (c *client) request(method string, params any) (json.RawMessage, error) {
+	c.mu.Lock()
+	c.nextID++
+	id := json.RawMessage(strconv.Itoa(c.nextID))
+	c.mu.Unlock()
+
+	response, err := c.post(method, id, params)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if sessionID := response.Header.Get(HeaderSessionID); sessionID != "" {
+		c.mu.Lock()
+		c.sessionID = sessionID
+		c.mu.Unlock()
+	}
+
+	rpcResp, err := readResponse(response, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrMCPRequest, method, err)
+	}
+
+	if rpcResp.Error != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrMCPRequest, method, rpcResp.Error)
+	}
+
+	return rpcResp.Result, nil
+}
+
+// notify sends a JSON-RPC notification, which the server acknowledges without a response
+func (c *client) notify(method string) error {
+	response, err := c.post(method, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	_, _ = io.Copy(io.Discard, response.Body)
+
+	return nil
+}
+
+func (c *client) post(method string, id json.RawMessage, params any) (*http.Response, error) {
+	message := rpcRequest{JSONRPC: jsonRPCVersion, ID: id, Method: method}
+	if params != nil {
+		paramsJSON, err := json.Marshal(params)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: failed to marshal params: %w", ErrMCPRequest, method, err)
+		}
+		message.Params = paramsJSON
+	}
+
+	body, err := json.Marshal(message)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrMCPRequest, method, err)
+	}
+
+	//nolint:noctx // LLMTool.Call has no context, the client timeout bounds the request
+	request, err := http.NewRequest(http.MethodPost, c.serverURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrMCPRequest, method, err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json, "+contentTypeSSE)
+
+	c.mu.Lock()
+	if c.sessionID != "" {
+		request.Header.Set(HeaderSessionID, c.sessionID)
+	}
+	c.mu.Unlock()
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrMCPRequest, method, err)
+	}
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		response.Body.Close()
+
+		return nil, fmt.Errorf("%w: %s: unexpected status %s", ErrMCPRequest, method, response.Status)
+	}
+
+	return response, nil
+}
+
+// readResponse reads the JSON-RPC response with the given ID from a JSON body or an SSE stream
+func readResponse(response *http.Response, id json.RawMessage) (*rpcResponse, error) {
+	mediaType, _, _ := mime.ParseMediaType(response.Header.Get("Content-Type"))
+	if mediaType != contentTypeSSE {
+		var rpcResp rpcResponse
+		if err := json.NewDecoder(response.Body).Decode(&rpcResp); err != nil {
+			return nil, fmt.Errorf("invalid response: %w", err)
+		}
+
+		return &rpcResp, nil
+	}
+
+	return readSSEResponse(response.Body, id)
+}
+
+// readSSEResponse reads Server-Sent Events until the response with the given ID arrives.
+// Other messages on the stream, e.g. progress notifications, are skipped.
+func readSSEResponse(body io.Reader, id json.RawMessage) (*rpcResponse, error) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), 1<<24)
+
+	var data strings.Builder
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if value, ok := strings.CutPrefix(line, "data:"); ok {
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(value, " "))
+
+			continue
+		}
+
+		if line != "" || data.Len() == 0 {
+			continue
+		}
+
+		if rpcResp := matchEvent(data.String(), id); rpcResp != nil {
+			return rpcResp, nil
+		}
+		data.Reset()
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read event stream: %w", err)
+	}
+
+	// the last event may end without a blank line when the server closes the stream
+	if rpcResp := matchEvent(data.String(), id); rpcResp != nil {
+		return rpcResp, nil
+	}
+
+	return nil, fmt.Errorf("event stream ended without response %s", id)
+}
+
+// matchEvent returns the JSON-RPC response carried by the event data if it has the given ID
+func matchEvent(data string, id json.RawMessage) *rpcResponse {
+	var rpcResp rpcResponse
+	if err := json.Unmarshal([]byte(data), &rpcResp); err != nil || !bytes.Equal(rpcResp.ID, id) {
+		return nil
+	}
+
+	return &rpcResp
+}
@@ -0,0 +1,134 @@
+// Package mcp connects agent tools with the Model Context Protocol (MCP), a standard for
+// exposing tools to LLM applications.
+//
+// Use a tool served by an MCP server in an agent:
+//
+//	weatherTool, err := mcp.NewToolFromMCPServer("http://localhost:8000/mcp", "get_weather")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	weatherAgent, err := agent.NewAgent(
+//		// ... other options
+//		agent.WithTool[Forecast]("get_weather", weatherTool),
+//	)
+//
+// Expose agent tools to other MCP clients:
+//
+//	log.Fatal(http.ListenAndServe(":8000", mcp.NewMCPServer([]llm.LLMTool{addTool})))
+//
+// Both sides use the Streamable HTTP transport: JSON-RPC 2.0 messages are sent with POST
+// requests and the responses arrive as JSON or as a Server-Sent Events stream.
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+var (
+	// ErrMCPRequest is returned when an MCP server can't be reached or returns an invalid response
+	ErrMCPRequest = errors.New("MCP request failed")
+	// ErrMCPToolNotFound is returned when the MCP server does not provide the requested tool
+	ErrMCPToolNotFound = errors.New("MCP tool not found")
+	// ErrMCPToolCallFailed is returned when the MCP server reports that the tool call failed
+	ErrMCPToolCallFailed = errors.New("MCP tool call failed")
+)
+
+const (
+	// ProtocolVersion is the MCP revision implemented by this package
+	ProtocolVersion = "2025-03-26"
+
+	// HeaderSessionID carries the session ID assigned by the server during initialization
+	HeaderSessionID = "Mcp-Session-Id"
+
+	jsonRPCVersion = "2.0"
+	requestTimeout = 30 * time.Second
+	contentTypeSSE = "text/event-stream"
+)
+
+// JSON-RPC error codes used by MCP
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
+
+// Content is a single content item of an MCP tool result
+type Content struct {
+	Type     string `json:"type"`
+	Text     string `json:"text,omitempty"`
+	Data     string `json:"data,omitempty"`
+	MimeType string `json:"mimeType,omitempty"`
+}
+
+// ToolResult is the result of a tool created by NewToolFromMCPServer
+type ToolResult struct {
+	llm.BaseLLMToolResult
+	Content           []Content `json:"content"`
+	StructuredContent any       `json:"structured_content,omitempty"`
+}
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return fmt.Sprintf("%s (code %d)", e.Message, e.Code)
+}
+
+type toolDefinition struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	InputSchema map[string]any `json:"inputSchema"`
+}
+
+type listToolsResult struct {
+	Tools      []toolDefinition `json:"tools"`
+	NextCursor string           `json:"nextCursor,omitempty"`
+}
+
+type callToolParams struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+}
+
+type callToolResult struct {
+	Content           []Content `json:"content"`
+	StructuredContent any       `json:"structuredContent,omitempty"`
+	IsError           bool      `json:"isError,omitempty"`
+}
+
+// contentText joins the text content items, e.g. to describe a failed tool call
+func contentText(content []Content) string {
+	texts := make([]string, 0, len(content))
+	for _, item := range content {
+		if item.Type == "text" {
+			texts = append(texts, item.Text)
+		}
+	}
+
+	return strings.Join(texts, "\n")
+}
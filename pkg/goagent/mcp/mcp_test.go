@@ -0,0 +1,240 @@
+package mcp_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/mcp"
+)
+
+type AddParams struct {
+	Num1 int `json:"num1" jsonschema_description:"First number"`
+	Num2 int `json:"num2" jsonschema_description:"Second number"`
+}
+
+type AddResult struct {
+	llm.BaseLLMToolResult
+	Sum int `json:"sum"`
+}
+
+func createAddTool(t *testing.T) llm.LLMTool {
+	t.Helper()
+
+	tool, err := llm.NewLLMTool(
+		llm.WithLLMToolName("add"),
+		llm.WithLLMToolDescription("Adds two numbers"),
+		llm.WithLLMToolParametersSchema[AddParams](),
+		llm.WithLLMToolCall(func(callID string, params AddParams) (AddResult, error) {
+			if params.Num1 < 0 {
+				return AddResult{}, errors.New("negative numbers are not supported")
+			}
+
+			return AddResult{BaseLLMToolResult: llm.BaseLLMToolResult{ID: callID}, Sum: params.Num1 + params.Num2}, nil
+		}),
+	)
+	require.NoError(t, err)
+
+	return tool
+}
+
+func startMCPServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(mcp.NewMCPServer([]llm.LLMTool{createAddTool(t)}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestNewToolFromMCPServer(t *testing.T) {
+	t.Parallel()
+
+	// given
+	server := startMCPServer(t)
+
+	// when
+	tool, err := mcp.NewToolFromMCPServer(server.URL, "add")
+	require.NoError(t, err)
+
+	result, err := tool.Call("call_1", `{"num1": 1, "num2": 2}`)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "add", tool.Name)
+	assert.Equal(t, "Adds two numbers", tool.Description)
+
+	parametersSchema, isOK := tool.ParametersSchema.(map[string]any)
+	require.True(t, isOK)
+	assert.Contains(t, parametersSchema["properties"], "num1")
+
+	toolResult, isOK := result.(mcp.ToolResult)
+	require.True(t, isOK)
+	assert.Equal(t, "call_1", toolResult.GetID())
+	require.Len(t, toolResult.Content, 1)
+	assert.Equal(t, "text", toolResult.Content[0].Type)
+	assert.JSONEq(t, `{"id": "mcp_call_1", "sum": 3}`, toolResult.Content[0].Text)
+}
+
+func TestNewToolFromMCPServer_ToolError(t *testing.T) {
+	t.Parallel()
+
+	// given
+	tool, err := mcp.NewToolFromMCPServer(startMCPServer(t).URL, "add")
+	require.NoError(t, err)
+
+	// when
+	_, err = tool.Call("call_1", `{"num1": -1, "num2": 2}`)
+
+	// then
+	require.ErrorIs(t, err, mcp.ErrMCPToolCallFailed)
+	assert.Contains(t, err.Error(), "negative numbers are not supported")
+}
+
+func TestNewToolFromMCPServer_ToolNotFound(t *testing.T) {
+	t.Parallel()
+
+	_, err := mcp.NewToolFromMCPServer(startMCPServer(t).URL, "multiply")
+
+	require.ErrorIs(t, err, mcp.ErrMCPToolNotFound)
+}
+
+// sseMCPServer answers every request with an event stream which starts with a progress
+// notification, and expects the session ID assigned during initialization
+func sseMCPServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil || json.Unmarshal(body, &request) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		if request.Method == "initialize" {
+			w.Header().Set(mcp.HeaderSessionID, "session-1")
+		} else if r.Header.Get(mcp.HeaderSessionID) != "session-1" {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		if len(request.ID) == 0 {
+			w.WriteHeader(http.StatusAccepted)
+
+			return
+		}
+
+		var result string
+		switch request.Method {
+		case "tools/list":
+			result = `{"tools": [{"name": "echo", "inputSchema": {"type": "object"}}]}`
+		case "tools/call":
+			result = `{"content": [{"type": "text", "text": "hello"}], "structuredContent": {"text": "hello"}}`
+		default:
+			result = `{}`
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = fmt.Fprint(w, "event: message\ndata: {\"jsonrpc\": \"2.0\", \"method\": \"notifications/progress\"}\n\n")
+		_, _ = fmt.Fprintf(w, "event: message\ndata: {\"jsonrpc\": \"2.0\", \"id\": %s,\ndata: \"result\": %s}\n\n",
+			request.ID, result)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestNewToolFromMCPServer_EventStream(t *testing.T) {
+	t.Parallel()
+
+	// given
+	tool, err := mcp.NewToolFromMCPServer(sseMCPServer(t).URL, "echo")
+	require.NoError(t, err)
+
+	// when
+	result, err := tool.Call("call_1", `{}`)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "MCP tool echo", tool.Description)
+
+	toolResult, isOK := result.(mcp.ToolResult)
+	require.True(t, isOK)
+	assert.Equal(t, []mcp.Content{{Type: "text", Text: "hello"}}, toolResult.Content)
+	assert.Equal(t, map[string]any{"text": "hello"}, toolResult.StructuredContent)
+}
+
+func TestNewMCPServer_ProtocolErrors(t *testing.T) {
+	t.Parallel()
+
+	server := startMCPServer(t)
+
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+	}{
+		{name: "invalid JSON", body: `{`, wantCode: -32700},
+		{name: "unknown method", body: `{"jsonrpc": "2.0", "id": 1, "method": "prompts/list"}`, wantCode: -32601},
+		{
+			name:     "unknown tool",
+			body:     `{"jsonrpc": "2.0", "id": 1, "method": "tools/call", "params": {"name": "multiply"}}`,
+			wantCode: -32602,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// when
+			response, err := http.Post(server.URL, "application/json", strings.NewReader(tt.body))
+			require.NoError(t, err)
+			defer response.Body.Close()
+
+			// then
+			var rpcResponse struct {
+				Error struct {
+					Code int `json:"code"`
+				} `json:"error"`
+			}
+			require.NoError(t, json.NewDecoder(response.Body).Decode(&rpcResponse))
+			assert.Equal(t, tt.wantCode, rpcResponse.Error.Code)
+		})
+	}
+}
+
+func TestNewMCPServer_Notification(t *testing.T) {
+	t.Parallel()
+
+	response, err := http.Post(startMCPServer(t).URL, "application/json",
+		strings.NewReader(`{"jsonrpc": "2.0", "method": "notifications/initialized"}`))
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	assert.Equal(t, http.StatusAccepted, response.StatusCode)
+}
+
+func TestNewMCPServer_GetNotAllowed(t *testing.T) {
+	t.Parallel()
+
+	response, err := http.Get(startMCPServer(t).URL)
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	assert.Equal(t, http.StatusMethodNotAllowed, response.StatusCode)
+}
@@ -0,0 +1,179 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/schema"
+)
+
+const serverName = "go-agent"
+
+type server struct {
+	tools       map[string]llm.LLMTool
+	definitions []toolDefinition
+	schemaErr   error
+	callCounter atomic.Int64
+}
+
+// NewMCPServer returns an HTTP handler which serves the tools over MCP, so that any MCP client
+// can discover and call them. It implements the initialize, ping, tools/list and tools/call
+// methods of the Streamable HTTP transport with JSON responses; sessions and server-initiated
+// streams are not used. A tool error is returned to the client as a tool result with isError
+// set, like MCP requires, so the calling model can react to it.
+//
+// Example:
+//
+//	http.Handle("/mcp", mcp.NewMCPServer([]llm.LLMTool{addTool}))
+func NewMCPServer(tools []llm.LLMTool) http.Handler {
+	s := &server{
+		tools:       make(map[string]llm.LLMTool, len(tools)),
+		definitions: make([]toolDefinition, 0, len(tools)),
+	}
+
+	for _, tool := range tools {
+		inputSchema, err := schema.GenerateSchema(tool.ParametersSchema)
+		if err != nil {
+			s.schemaErr = fmt.Errorf("tool %s: %w", tool.Name, err)
+
+			break
+		}
+
+		s.tools[tool.Name] = tool
+		s.definitions = append(s.definitions, toolDefinition{
+			Name:        tool.Name,
+			Description: tool.Description,
+			InputSchema: inputSchema,
+		})
+	}
+
+	return s
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	var request rpcRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		s.writeError(w, nil, codeParseError, "invalid JSON-RPC message: "+err.Error())
+
+		return
+	}
+
+	if request.JSONRPC != jsonRPCVersion || request.Method == "" {
+		s.writeError(w, request.ID, codeInvalidRequest, "invalid JSON-RPC request")
+
+		return
+	}
+
+	// notifications and responses to server requests are only acknowledged
+	if len(request.ID) == 0 || bytes.Equal(request.ID, []byte("null")) {
+		w.WriteHeader(http.StatusAccepted)
+
+		return
+	}
+
+	result, rpcErr := s.handle(request)
+	if rpcErr != nil {
+		s.writeError(w, request.ID, rpcErr.Code, rpcErr.Message)
+
+		return
+	}
+
+	s.write(w, rpcResponse{JSONRPC: jsonRPCVersion, ID: request.ID, Result: result})
+}
+
+func (s *server) handle(request rpcRequest) (json.RawMessage, *rpcError) {
+	switch request.Method {
+	case "initialize":
+		return marshalResult(map[string]any{
+			"protocolVersion": ProtocolVersion,
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+			"serverInfo":      map[string]any{"name": serverName},
+		})
+	case "ping":
+		return marshalResult(map[string]any{})
+	case "tools/list":
+		if s.schemaErr != nil {
+			return nil, &rpcError{Code: codeInternalError, Message: s.schemaErr.Error()}
+		}
+
+		return marshalResult(listToolsResult{Tools: s.definitions})
+	case "tools/call":
+		return s.callTool(request.Params)
+	default:
+		return nil, &rpcError{Code: codeMethodNotFound, Message: "method not found: " + request.Method}
+	}
+}
+
+func (s *server) callTool(rawParams json.RawMessage) (json.RawMessage, *rpcError) {
+	var params callToolParams
+	if err := json.Unmarshal(rawParams, &params); err != nil {
+		return nil, &rpcError{Code: codeInvalidParams, Message: "invalid tools/call params: " + err.Error()}
+	}
+
+	tool, ok := s.tools[params.Name]
+	if !ok {
+		return nil, &rpcError{Code: codeInvalidParams, Message: "unknown tool: " + params.Name}
+	}
+
+	args := string(params.Arguments)
+	if len(params.Arguments) == 0 || bytes.Equal(params.Arguments, []byte("null")) {
+		args = "{}"
+	}
+
+	callID := "mcp_call_" + strconv.FormatInt(s.callCounter.Add(1), 10)
+
+	toolRes, err := tool.Call(callID, args)
+	if err != nil {
+		return marshalResult(callToolResult{
+			Content: []Content{{Type: "text", Text: err.Error()}},
+			IsError: true,
+		})
+	}
+
+	resultJSON, err := llm.MarshalToolResult(toolRes)
+	if err != nil {
+		return marshalResult(callToolResult{
+			Content: []Content{{Type: "text", Text: "failed to marshal tool result: " + err.Error()}},
+			IsError: true,
+		})
+	}
+
+	return marshalResult(callToolResult{Content: []Content{{Type: "text", Text: string(resultJSON)}}})
+}
+
+func marshalResult(result any) (json.RawMessage, *rpcError) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, &rpcError{Code: codeInternalError, Message: "failed to marshal result: " + err.Error()}
+	}
+
+	return data, nil
+}
+
+func (s *server) writeError(w http.ResponseWriter, id json.RawMessage, code int, message string) {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+
+	s.write(w, rpcResponse{JSONRPC: jsonRPCVersion, ID: id, Error: &rpcError{Code: code, Message: message}})
+}
+
+func (s *server) write(w http.ResponseWriter, response rpcResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		slog.Warn("failed to write MCP response", "error", err)
+	}
+}
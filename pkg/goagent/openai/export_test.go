@@ -0,0 +1,10 @@
+package openai
+
+import (
+	"github.com/openai/openai-go"
+)
+
+// CreateToolParams exposes the tool definitions sent to OpenAI for tests
+func (o *OpenAILLM) CreateToolParams() ([]openai.ChatCompletionToolParam, error) {
+	return o.createToolParams()
+}
@@ -5,6 +5,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"slices"
+	"time"
 
 	"github.com/openai/openai-go"
 	"github.com/openai/openai-go/option"
@@ -16,6 +19,11 @@ import (
 const (
 	openAIFinishReasonStop   = "stop"
 	openAIFinishReasonLength = "length"
+
+	projectHeader = "OpenAI-Project"
+
+	responseSchemaName        = "response_schema"
+	responseSchemaDescription = "Response schema for structured output of a conversation"
 )
 
 var (
@@ -28,11 +36,18 @@ var (
 )
 
 type OpenAILLM struct {
-	client      openai.Client
-	apiKey      string
-	temperature float64
-	model       openai.ChatModel
-	tools       []llm.LLMTool
+	client         openai.Client
+	apiKey         string
+	organizationID string
+	projectID      string
+	baseURL        string
+	httpClient     *http.Client
+	headers        map[string]string
+	strict         bool
+	temperature    float64
+	maxTokens      int
+	model          openai.ChatModel
+	tools          []llm.LLMTool
 }
 
 type OpenAILLMOption func(o *OpenAILLM)
@@ -43,6 +58,13 @@ func WithTemperature(temperature float64) OpenAILLMOption {
 	}
 }
 
+// WithMaxTokens limits the number of tokens generated per call. Zero leaves the limit to the API.
+func WithMaxTokens(maxTokens int) OpenAILLMOption {
+	return func(o *OpenAILLM) {
+		o.maxTokens = maxTokens
+	}
+}
+
 func WithModel(model string) OpenAILLMOption {
 	return func(o *OpenAILLM) {
 		o.model = model
@@ -52,7 +74,53 @@ func WithModel(model string) OpenAILLMOption {
 func WithAPIKey(apiKey string) OpenAILLMOption {
 	return func(o *OpenAILLM) {
 		o.apiKey = apiKey
-		o.client = openai.NewClient(option.WithAPIKey(apiKey))
+	}
+}
+
+// WithOrganizationID routes requests to the given OpenAI organization
+func WithOrganizationID(organizationID string) OpenAILLMOption {
+	return func(o *OpenAILLM) {
+		o.organizationID = organizationID
+	}
+}
+
+// WithProject sends the OpenAI-Project header with every request, which routes requests
+// to the given OpenAI project. Project-scoped API keys (sk-proj-...) require it.
+func WithProject(projectID string) OpenAILLMOption {
+	return func(o *OpenAILLM) {
+		o.projectID = projectID
+	}
+}
+
+// WithBaseURL sends requests to an OpenAI-compatible API at the given URL instead of
+// api.openai.com, e.g. a LiteLLM or OpenRouter proxy such as https://openrouter.ai/api/v1
+func WithBaseURL(url string) OpenAILLMOption {
+	return func(o *OpenAILLM) {
+		o.baseURL = url
+	}
+}
+
+// WithHTTPClient makes the client send requests with the given HTTP client,
+// e.g. one configured with a proxy, mutual TLS or a request signing transport
+func WithHTTPClient(client *http.Client) OpenAILLMOption {
+	return func(o *OpenAILLM) {
+		o.httpClient = client
+	}
+}
+
+// WithRequestHeaders adds the given headers to every request, e.g. for LLM proxies
+// such as LiteLLM or Helicone
+func WithRequestHeaders(headers map[string]string) OpenAILLMOption {
+	return func(o *OpenAILLM) {
+		o.headers = headers
+	}
+}
+
+// WithStrictFunctionCalling sets strict: true on every function definition, so the model
+// cannot produce arguments that do not match the tool parameter schema
+func WithStrictFunctionCalling(enabled bool) OpenAILLMOption {
+	return func(o *OpenAILLM) {
+		o.strict = enabled
 	}
 }
 
@@ -68,48 +136,86 @@ func NewOpenAILLM(options ...OpenAILLMOption) *OpenAILLM {
 		opt(llm)
 	}
 
+	llm.client = openai.NewClient(llm.clientOptions()...)
+
 	return llm
 }
 
+func (o *OpenAILLM) clientOptions() []option.RequestOption {
+	opts := []option.RequestOption{option.WithAPIKey(o.apiKey)}
+	if o.organizationID != "" {
+		opts = append(opts, option.WithOrganization(o.organizationID))
+	}
+	if o.projectID != "" {
+		opts = append(opts, option.WithHeader(projectHeader, o.projectID))
+	}
+	if o.baseURL != "" {
+		opts = append(opts, option.WithBaseURL(o.baseURL))
+	}
+	if o.httpClient != nil {
+		opts = append(opts, option.WithHTTPClient(o.httpClient))
+	}
+	for name, value := range o.headers {
+		opts = append(opts, option.WithHeader(name, value))
+	}
+
+	return opts
+}
+
+// RestrictTools returns a copy of the LLM which offers only the tools with the given names
+func (o *OpenAILLM) RestrictTools(names []string) llm.LLM {
+	clone := *o
+	clone.tools = make([]llm.LLMTool, 0, len(names))
+	for _, tool := range o.tools {
+		if slices.Contains(names, tool.Name) {
+			clone.tools = append(clone.tools, tool)
+		}
+	}
+
+	return &clone
+}
+
 func (o *OpenAILLM) Call(ctx context.Context, msgs []llm.LLMMessage) (llm.LLMMessage, error) {
-	choice, err := o.callLLM(ctx, msgs, nil)
+	completion, err := o.callLLM(ctx, msgs, nil)
 	if err != nil {
 		return llm.LLMMessage{}, err
 	}
 
-	return o.newLLMMessage(choice)
+	return o.newLLMMessage(completion)
 }
 
 func (o *OpenAILLM) CallWithStructuredOutput(ctx context.Context, msgs []llm.LLMMessage, schemaT any) (string, error) {
-	choice, err := o.callLLM(ctx, msgs, schemaT)
+	completion, err := o.callLLM(ctx, msgs, schemaT)
 	if err != nil {
 		return "", err
 	}
 
-	return choice.Message.Content, nil
+	return completion.Choices[0].Message.Content, nil
 }
 
+// callLLM sends the chat completion request. The returned completion has at least one choice.
 func (o *OpenAILLM) callLLM(
 	ctx context.Context, msgs []llm.LLMMessage, schemaT any,
-) (openai.ChatCompletionChoice, error) {
+) (*openai.ChatCompletion, error) {
 	params, err := o.createParameters(msgs, schemaT)
 	if err != nil {
-		return openai.ChatCompletionChoice{}, fmt.Errorf("failed to create OpenAI parameters: %w", err)
+		return nil, fmt.Errorf("failed to create OpenAI parameters: %w", err)
 	}
 
 	completion, err := o.client.Chat.Completions.New(ctx, params)
 	if err != nil {
-		return openai.ChatCompletionChoice{}, fmt.Errorf("OpenAI API call failed: %w", err)
+		return nil, fmt.Errorf("OpenAI API call failed: %w", err)
 	}
 
 	if len(completion.Choices) == 0 {
-		return openai.ChatCompletionChoice{}, ErrNoResponseFromOpenAI
+		return nil, ErrNoResponseFromOpenAI
 	}
 
-	return completion.Choices[0], nil
+	return completion, nil
 }
 
-func (o *OpenAILLM) newLLMMessage(choice openai.ChatCompletionChoice) (llm.LLMMessage, error) {
+func (o *OpenAILLM) newLLMMessage(completion *openai.ChatCompletion) (llm.LLMMessage, error) {
+	choice := completion.Choices[0]
 	toolCalls, err := o.createLLMToolCalls(choice)
 	if err != nil {
 		return llm.LLMMessage{}, fmt.Errorf("failed to create tool calls: %w", err)
@@ -120,6 +226,11 @@ func (o *OpenAILLM) newLLMMessage(choice openai.ChatCompletionChoice) (llm.LLMMe
 		Content:   choice.Message.Content,
 		ToolCalls: toolCalls,
 		End:       choice.FinishReason == openAIFinishReasonStop || choice.FinishReason == openAIFinishReasonLength,
+		Timestamp: time.Now().UTC(),
+		Usage: llm.TokenUsage{
+			InputTokens:  int(completion.Usage.PromptTokens),
+			OutputTokens: int(completion.Usage.CompletionTokens),
+		},
 	}, nil
 }
 
@@ -153,47 +264,99 @@ func (o *OpenAILLM) createParameters(messages []llm.LLMMessage, schemaT any) (op
 		Temperature: openai.Float(o.temperature),
 		Tools:       tools,
 	}
+	if o.maxTokens > 0 {
+		params.MaxCompletionTokens = openai.Int(int64(o.maxTokens))
+	}
 
 	if schemaT != nil {
-		schemaMap, err := schema.GenerateSchema(schemaT)
+		responseFormat, err := createResponseFormat(schemaT)
 		if err != nil {
-			return openai.ChatCompletionNewParams{}, fmt.Errorf("failed to convert schema to map: %w", err)
+			return openai.ChatCompletionNewParams{}, err
 		}
 
-		params.ResponseFormat = openai.ChatCompletionNewParamsResponseFormatUnion{
-			OfJSONSchema: &shared.ResponseFormatJSONSchemaParam{
-				JSONSchema: shared.ResponseFormatJSONSchemaJSONSchemaParam{
-					Name:        "response_schema",
-					Description: openai.String("Response schema for structured output of a conversation"),
-					Schema:      schemaMap,
-					Strict:      openai.Bool(true),
-				},
-			},
-		}
+		params.ResponseFormat = openai.ChatCompletionNewParamsResponseFormatUnion{OfJSONSchema: responseFormat}
 	}
 
 	return params, nil
 }
 
+func createResponseFormat(schemaT any) (*shared.ResponseFormatJSONSchemaParam, error) {
+	schemaMap, err := createResponseSchema(schemaT)
+	if err != nil {
+		return nil, err
+	}
+
+	return &shared.ResponseFormatJSONSchemaParam{
+		JSONSchema: shared.ResponseFormatJSONSchemaJSONSchemaParam{
+			Name:        responseSchemaName,
+			Description: openai.String(responseSchemaDescription),
+			Schema:      schemaMap,
+			Strict:      openai.Bool(true),
+		},
+	}, nil
+}
+
+func createResponseSchema(schemaT any) (map[string]any, error) {
+	// The response format is always strict, so every object must disallow additional properties
+	schemaMap, err := schema.GenerateSchemaWithOptions(schemaT, schema.WithNoAdditionalProperties())
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert schema to map: %w", err)
+	}
+
+	return schemaMap, nil
+}
+
 func (o *OpenAILLM) createToolParams() ([]openai.ChatCompletionToolParam, error) {
-	toolParams := make([]openai.ChatCompletionToolParam, 0, len(o.tools))
+	functions, err := o.createFunctionDefinitions()
+	if err != nil {
+		return nil, err
+	}
+
+	toolParams := make([]openai.ChatCompletionToolParam, 0, len(functions))
+	for _, function := range functions {
+		toolParams = append(toolParams, openai.ChatCompletionToolParam{Function: function})
+	}
+
+	return toolParams, nil
+}
+
+func (o *OpenAILLM) createFunctionDefinitions() ([]openai.FunctionDefinitionParam, error) {
+	functions := make([]openai.FunctionDefinitionParam, 0, len(o.tools))
+
+	var schemaOptions []schema.SchemaOption
+	if o.strict {
+		schemaOptions = append(schemaOptions, schema.WithNoAdditionalProperties())
+	}
 
 	for _, tool := range o.tools {
-		parameterSchema, err := schema.GenerateSchema(tool.ParametersSchema)
+		parameterSchema, err := schema.GenerateSchemaWithOptions(tool.ParametersSchema, schemaOptions...)
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate schema for tool %s: %w", tool.Name, err)
 		}
 
-		toolParams = append(toolParams, openai.ChatCompletionToolParam{
-			Function: openai.FunctionDefinitionParam{
-				Name:        tool.Name,
-				Description: openai.String(tool.Description),
-				Parameters:  parameterSchema,
-			},
-		})
+		function := openai.FunctionDefinitionParam{
+			Name:        tool.Name,
+			Description: openai.String(toolDescription(tool)),
+			Parameters:  parameterSchema,
+		}
+		if o.strict {
+			function.Strict = openai.Bool(true)
+		}
+
+		functions = append(functions, function)
 	}
 
-	return toolParams, nil
+	return functions, nil
+}
+
+// toolDescription returns the description of the tool, followed by the example of its
+// arguments when the tool has one, as OpenAI function definitions have no examples field
+func toolDescription(tool llm.LLMTool) string {
+	if len(tool.ParametersExample) == 0 {
+		return tool.Description
+	}
+
+	return tool.Description + "\n\nExample arguments:\n" + string(tool.ParametersExample)
 }
 
 func (o *OpenAILLM) createMessages(msgs []llm.LLMMessage) ([]openai.ChatCompletionMessageParamUnion, error) {
@@ -204,7 +367,12 @@ func (o *OpenAILLM) createMessages(msgs []llm.LLMMessage) ([]openai.ChatCompleti
 		case llm.LLMMessageTypeSystem:
 			openAIMessages = append(openAIMessages, openai.SystemMessage(msg.Content))
 		case llm.LLMMessageTypeUser:
-			openAIMessages = append(openAIMessages, openai.UserMessage(msg.Content))
+			userMsg, err := o.createUserMessage(msg)
+			if err != nil {
+				return nil, err
+			}
+
+			openAIMessages = append(openAIMessages, userMsg)
 		case llm.LLMMessageTypeAssistant:
 			messages, err := o.handleAssistantMessage(openAIMessages, msg)
 			if err != nil {
@@ -218,6 +386,40 @@ func (o *OpenAILLM) createMessages(msgs []llm.LLMMessage) ([]openai.ChatCompleti
 	return openAIMessages, nil
 }
 
+func (o *OpenAILLM) createUserMessage(msg llm.LLMMessage) (openai.ChatCompletionMessageParamUnion, error) {
+	if len(msg.Parts) == 0 {
+		return openai.UserMessage(msg.Content), nil
+	}
+
+	parts := make([]openai.ChatCompletionContentPartUnionParam, 0, len(msg.Parts)+1)
+	if msg.Content != "" {
+		parts = append(parts, openai.TextContentPart(msg.Content))
+	}
+
+	for _, part := range msg.Parts {
+		if err := part.Validate(); err != nil {
+			return openai.ChatCompletionMessageParamUnion{}, fmt.Errorf("failed to create user message: %w", err)
+		}
+
+		if part.Text != nil {
+			parts = append(parts, openai.TextContentPart(part.Text.Text))
+
+			continue
+		}
+
+		imageURL, err := part.Image.Image.ImageURL()
+		if err != nil {
+			return openai.ChatCompletionMessageParamUnion{}, fmt.Errorf("failed to create user message: %w", err)
+		}
+
+		parts = append(parts, openai.ImageContentPart(openai.ChatCompletionContentPartImageImageURLParam{
+			URL: imageURL,
+		}))
+	}
+
+	return openai.UserMessage(parts), nil
+}
+
 func (o *OpenAILLM) handleAssistantMessage(openAIMessages []openai.ChatCompletionMessageParamUnion,
 	msg llm.LLMMessage) ([]openai.ChatCompletionMessageParamUnion, error) {
 	if len(msg.ToolCalls) == 0 {
@@ -271,7 +473,7 @@ func (o *OpenAILLM) addToolCalls(openAIMessages []openai.ChatCompletionMessagePa
 func (o *OpenAILLM) addToolResults(openAIMessages []openai.ChatCompletionMessageParamUnion,
 	msg llm.LLMMessage) ([]openai.ChatCompletionMessageParamUnion, error) {
 	for _, toolRes := range msg.ToolResults {
-		toolResJSON, err := json.Marshal(toolRes)
+		toolResJSON, err := llm.MarshalToolResult(toolRes)
 		if err != nil {
 			return nil, fmt.Errorf("%w: %w", ErrFailedToMarshalToolResult, err)
 		}
@@ -0,0 +1,74 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+)
+
+// ErrUnexpectedEmbeddingsCount is returned when OpenAI returns a different number of embeddings than requested
+var ErrUnexpectedEmbeddingsCount = errors.New("unexpected number of embeddings")
+
+// OpenAIEmbedder implements llm.Embedder using the OpenAI embeddings API
+type OpenAIEmbedder struct {
+	client openai.Client
+	model  openai.EmbeddingModel
+}
+
+type OpenAIEmbedderOption func(e *OpenAIEmbedder)
+
+// WithEmbeddingModel overrides the default text-embedding-3-small model
+func WithEmbeddingModel(model string) OpenAIEmbedderOption {
+	return func(e *OpenAIEmbedder) {
+		e.model = model
+	}
+}
+
+// NewOpenAIEmbedder creates an embedder that uses text-embedding-3-small by default
+func NewOpenAIEmbedder(apiKey string, options ...OpenAIEmbedderOption) *OpenAIEmbedder {
+	embedder := &OpenAIEmbedder{
+		client: openai.NewClient(option.WithAPIKey(apiKey)),
+		model:  openai.EmbeddingModelTextEmbedding3Small,
+	}
+	for _, opt := range options {
+		opt(embedder)
+	}
+
+	return embedder
+}
+
+func (e *OpenAIEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	response, err := e.client.Embeddings.New(ctx, openai.EmbeddingNewParams{
+		Input: openai.EmbeddingNewParamsInputUnion{OfArrayOfStrings: texts},
+		Model: e.model,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("OpenAI embeddings API call failed: %w", err)
+	}
+
+	if len(response.Data) != len(texts) {
+		return nil, fmt.Errorf("%w: expected %d, got %d", ErrUnexpectedEmbeddingsCount, len(texts), len(response.Data))
+	}
+
+	embeddings := make([][]float32, len(texts))
+	for _, embedding := range response.Data {
+		if embedding.Index < 0 || int(embedding.Index) >= len(texts) {
+			return nil, fmt.Errorf("%w: index %d out of range", ErrUnexpectedEmbeddingsCount, embedding.Index)
+		}
+
+		vector := make([]float32, len(embedding.Embedding))
+		for i, value := range embedding.Embedding {
+			vector[i] = float32(value)
+		}
+		embeddings[embedding.Index] = vector
+	}
+
+	return embeddings, nil
+}
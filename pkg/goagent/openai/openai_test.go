@@ -2,13 +2,19 @@ package openai_test
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
 	"github.com/vitalii-honchar/go-agent/pkg/goagent/openai"
 )
 
@@ -280,3 +286,271 @@ type AddToolResult struct {
 	llm.BaseLLMToolResult
 	Sum float64 `json:"sum"`
 }
+
+func TestOpenAIEmbedder_Embed(t *testing.T) {
+	t.Parallel()
+
+	apiKey := os.Getenv("OPENAI_API_KEY")
+	require.NotEmpty(t, apiKey, "OPENAI_API_KEY environment variable must be set")
+
+	embedder := openai.NewOpenAIEmbedder(apiKey)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	embeddings, err := embedder.Embed(ctx, []string{"hello world", "goodbye world"})
+
+	require.NoError(t, err)
+	require.Len(t, embeddings, 2)
+	assert.NotEmpty(t, embeddings[0])
+	assert.Len(t, embeddings[1], len(embeddings[0]))
+}
+
+// redirectTransport sends every request to the target server and counts the requests
+type redirectTransport struct {
+	target   *url.URL
+	requests atomic.Int32
+}
+
+func (r *redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.requests.Add(1)
+
+	req = req.Clone(req.Context())
+	req.URL.Scheme = r.target.Scheme
+	req.URL.Host = r.target.Host
+
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+const mockChatCompletion = `{
+	"id": "chatcmpl-1",
+	"object": "chat.completion",
+	"created": 1700000000,
+	"model": "gpt-4o-mini",
+	"choices": [{
+		"index": 0,
+		"finish_reason": "stop",
+		"message": {"role": "assistant", "content": "Hello from mock"}
+	}],
+	"usage": {"prompt_tokens": 12, "completion_tokens": 3, "total_tokens": 15}
+}`
+
+func newRedirectTransport(t *testing.T, server *httptest.Server) *redirectTransport {
+	t.Helper()
+
+	target, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	return &redirectTransport{target: target}
+}
+
+func writeMockCompletion(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(mockChatCompletion))
+}
+
+func TestOpenAILLM_WithHTTPClient(t *testing.T) {
+	t.Parallel()
+
+	// given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
+
+		writeMockCompletion(w)
+	}))
+	defer server.Close()
+
+	transport := newRedirectTransport(t, server)
+
+	openaiLLM := openai.NewOpenAILLM(
+		openai.WithAPIKey("test-key"),
+		openai.WithModel("gpt-4o-mini"),
+		openai.WithHTTPClient(&http.Client{Transport: transport}),
+	)
+
+	// when
+	response, err := openaiLLM.Call(context.Background(), []llm.LLMMessage{
+		llm.NewLLMMessage(llm.LLMMessageTypeUser, "Say hello"),
+	})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "Hello from mock", response.Content)
+	assert.True(t, response.End)
+	assert.Equal(t, llm.TokenUsage{InputTokens: 12, OutputTokens: 3}, response.Usage)
+	assert.Equal(t, int32(1), transport.requests.Load())
+}
+
+func TestOpenAILLM_WithRequestHeaders(t *testing.T) {
+	t.Parallel()
+
+	// given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer helicone-key", r.Header.Get("Helicone-Auth"))
+		assert.Equal(t, "team-a", r.Header.Get("X-Route"))
+		assert.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
+
+		writeMockCompletion(w)
+	}))
+	defer server.Close()
+
+	openaiLLM := openai.NewOpenAILLM(
+		openai.WithAPIKey("test-key"),
+		openai.WithModel("gpt-4o-mini"),
+		openai.WithHTTPClient(&http.Client{Transport: newRedirectTransport(t, server)}),
+		openai.WithRequestHeaders(map[string]string{
+			"Helicone-Auth": "Bearer helicone-key",
+			"X-Route":       "team-a",
+		}),
+	)
+
+	// when
+	response, err := openaiLLM.Call(context.Background(), []llm.LLMMessage{
+		llm.NewLLMMessage(llm.LLMMessageTypeUser, "Say hello"),
+	})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "Hello from mock", response.Content)
+}
+
+func TestOpenAILLM_WithProject(t *testing.T) {
+	t.Parallel()
+
+	// given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "proj_abc", r.Header.Get("OpenAI-Project"))
+		assert.Equal(t, "Bearer sk-proj-key", r.Header.Get("Authorization"))
+
+		writeMockCompletion(w)
+	}))
+	defer server.Close()
+
+	openaiLLM := openai.NewOpenAILLM(
+		openai.WithAPIKey("sk-proj-key"),
+		openai.WithModel("gpt-4o-mini"),
+		openai.WithProject("proj_abc"),
+		openai.WithBaseURL(server.URL),
+	)
+
+	// when
+	response, err := openaiLLM.Call(context.Background(), []llm.LLMMessage{
+		llm.NewLLMMessage(llm.LLMMessageTypeUser, "Say hello"),
+	})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "Hello from mock", response.Content)
+}
+
+func TestOpenAILLM_WithBaseURL(t *testing.T) {
+	t.Parallel()
+
+	// given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer proxy-key", r.Header.Get("Authorization"))
+
+		writeMockCompletion(w)
+	}))
+	defer server.Close()
+
+	openaiLLM := openai.NewOpenAILLM(
+		openai.WithAPIKey("proxy-key"),
+		openai.WithModel("openai/gpt-4o-mini"),
+		openai.WithBaseURL(server.URL+"/api/v1"),
+	)
+
+	// when
+	response, err := openaiLLM.Call(context.Background(), []llm.LLMMessage{
+		llm.NewLLMMessage(llm.LLMMessageTypeUser, "Say hello"),
+	})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "Hello from mock", response.Content)
+}
+
+const mockToolCallCompletion = `{
+	"id": "chatcmpl-2",
+	"object": "chat.completion",
+	"created": 1700000000,
+	"model": "gpt-4o-mini",
+	"choices": [{
+		"index": 0,
+		"finish_reason": "tool_calls",
+		"message": {"role": "assistant", "content": null, "tool_calls": [{
+			"id": "call_3",
+			"type": "function",
+			"function": {"name": "add", "arguments": "{\"num1\": 10, \"num2\": 15}"}
+		}]}
+	}],
+	"usage": {"prompt_tokens": 40, "completion_tokens": 9, "total_tokens": 49}
+}`
+
+func TestOpenAILLM_MessageRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	// given
+	var requestMessages []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Messages []map[string]any `json:"messages"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		requestMessages = body.Messages
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(mockToolCallCompletion))
+	}))
+	defer server.Close()
+
+	openaiLLM := openai.NewOpenAILLM(
+		openai.WithAPIKey("test-key"),
+		openai.WithModel("gpt-4o-mini"),
+		openai.WithBaseURL(server.URL),
+		openai.WithTools([]llm.LLMTool{createTestAddTool()}),
+	)
+
+	// when
+	llmtest.AssertMessageRoundTrip(t, openaiLLM, llmtest.RoundTripMessages())
+
+	// then
+	roles := make([]any, 0, len(requestMessages))
+	for _, msg := range requestMessages {
+		roles = append(roles, msg["role"])
+	}
+	assert.Equal(t, []any{"system", "user", "user", "assistant", "tool", "tool", "assistant", "user"}, roles)
+	assert.Equal(t, "call_1", requestMessages[4]["tool_call_id"])
+	assert.JSONEq(t, `{"id": "call_2", "sum": 20}`, requestMessages[5]["content"].(string))
+}
+
+func TestOpenAILLM_WithMaxTokens(t *testing.T) {
+	t.Parallel()
+
+	// given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.InDelta(t, 256, body["max_completion_tokens"], 0)
+
+		writeMockCompletion(w)
+	}))
+	defer server.Close()
+
+	openaiLLM := openai.NewOpenAILLM(
+		openai.WithAPIKey("test-key"),
+		openai.WithModel("gpt-4o-mini"),
+		openai.WithMaxTokens(256),
+		openai.WithHTTPClient(&http.Client{Transport: newRedirectTransport(t, server)}),
+	)
+
+	// when
+	_, err := openaiLLM.Call(context.Background(), []llm.LLMMessage{
+		llm.NewLLMMessage(llm.LLMMessageTypeUser, "Say hello"),
+	})
+
+	// then
+	require.NoError(t, err)
+}
@@ -0,0 +1,129 @@
+package openai_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/openai"
+)
+
+type echoParams struct {
+	Text string `json:"text" jsonschema_description:"Text to echo"`
+}
+
+func createEchoTool(t *testing.T) llm.LLMTool {
+	t.Helper()
+
+	tool, err := llm.NewLLMTool(
+		llm.WithLLMToolName("echo"),
+		llm.WithLLMToolDescription("Echoes the text"),
+		llm.WithLLMToolParametersSchema[echoParams](),
+		llm.WithLLMToolCall(func(callID string, _ echoParams) (llm.BaseLLMToolResult, error) {
+			return llm.BaseLLMToolResult{ID: callID}, nil
+		}),
+	)
+	require.NoError(t, err)
+
+	return tool
+}
+
+func TestOpenAILLM_StrictFunctionCalling(t *testing.T) {
+	t.Parallel()
+
+	tools := []llm.LLMTool{createEchoTool(t)}
+
+	strictParams, err := openai.NewOpenAILLM(
+		openai.WithTools(tools),
+		openai.WithStrictFunctionCalling(true),
+	).CreateToolParams()
+	require.NoError(t, err)
+	require.Len(t, strictParams, 1)
+	assert.True(t, strictParams[0].Function.Strict.Value)
+
+	autoParams, err := openai.NewOpenAILLM(openai.WithTools(tools)).CreateToolParams()
+	require.NoError(t, err)
+	require.Len(t, autoParams, 1)
+	assert.False(t, autoParams[0].Function.Strict.Valid())
+}
+
+func TestOpenAILLM_StrictFunctionCalling_NoAdditionalProperties(t *testing.T) {
+	t.Parallel()
+
+	tool := createEchoTool(t)
+	tool.ParametersSchema = map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"options": map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"upper": map[string]any{"type": "boolean"}},
+			},
+		},
+	}
+
+	strictParams, err := openai.NewOpenAILLM(
+		openai.WithTools([]llm.LLMTool{tool}),
+		openai.WithStrictFunctionCalling(true),
+	).CreateToolParams()
+	require.NoError(t, err)
+	require.Len(t, strictParams, 1)
+
+	parameters := strictParams[0].Function.Parameters
+	assert.Equal(t, false, parameters["additionalProperties"])
+	options, ok := parameters["properties"].(map[string]any)["options"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, false, options["additionalProperties"])
+
+	autoParams, err := openai.NewOpenAILLM(openai.WithTools([]llm.LLMTool{tool})).CreateToolParams()
+	require.NoError(t, err)
+	require.Len(t, autoParams, 1)
+	assert.NotContains(t, autoParams[0].Function.Parameters, "additionalProperties")
+}
+
+func TestOpenAILLM_RestrictTools(t *testing.T) {
+	t.Parallel()
+
+	echo := createEchoTool(t)
+	reverse := createEchoTool(t)
+	reverse.Name = "reverse"
+
+	openaiLLM := openai.NewOpenAILLM(openai.WithTools([]llm.LLMTool{echo, reverse}))
+
+	restricted, ok := openaiLLM.RestrictTools([]string{"reverse"}).(*openai.OpenAILLM)
+	require.True(t, ok)
+
+	restrictedParams, err := restricted.CreateToolParams()
+	require.NoError(t, err)
+	require.Len(t, restrictedParams, 1)
+	assert.Equal(t, "reverse", restrictedParams[0].Function.Name)
+
+	originalParams, err := openaiLLM.CreateToolParams()
+	require.NoError(t, err)
+	assert.Len(t, originalParams, 2)
+}
+
+func TestOpenAILLM_ToolParametersExample(t *testing.T) {
+	t.Parallel()
+
+	// given
+	tool, err := llm.NewLLMTool(
+		llm.WithLLMToolName("echo"),
+		llm.WithLLMToolDescription("Echoes the text"),
+		llm.WithLLMToolParametersSchema[echoParams](),
+		llm.WithLLMToolParametersExample(echoParams{Text: "hello"}),
+		llm.WithLLMToolCall(func(callID string, _ echoParams) (llm.BaseLLMToolResult, error) {
+			return llm.BaseLLMToolResult{ID: callID}, nil
+		}),
+	)
+	require.NoError(t, err)
+
+	// when
+	params, err := openai.NewOpenAILLM(openai.WithTools([]llm.LLMTool{tool, createEchoTool(t)})).CreateToolParams()
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, params, 2)
+	assert.Equal(t, "Echoes the text\n\nExample arguments:\n{\"text\":\"hello\"}", params[0].Function.Description.Value)
+	assert.Equal(t, "Echoes the text", params[1].Function.Description.Value)
+}
@@ -0,0 +1,284 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/responses"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+// ErrPromptResponseFailed is returned when a response to a stored prompt ends without completing
+var ErrPromptResponseFailed = errors.New("prompt response failed")
+
+// OpenAIPromptLLM executes the conversation with a prompt stored in the OpenAI dashboard
+// through the Responses API. Every call sends the whole conversation and is not stored by
+// OpenAI, so nothing is left on the server when a run ends or is abandoned. The system
+// prompt is passed as instructions next to the stored prompt.
+type OpenAIPromptLLM struct {
+	base     *OpenAILLM
+	promptID string
+}
+
+// NewOpenAIPromptLLM creates an LLM backed by the stored prompt with the given ID.
+// Model and temperature are optional and override the prompt settings when set.
+func NewOpenAIPromptLLM(promptID string, options ...OpenAILLMOption) *OpenAIPromptLLM {
+	return &OpenAIPromptLLM{
+		base:     NewOpenAILLM(options...),
+		promptID: promptID,
+	}
+}
+
+// RestrictTools returns a copy of the LLM which offers only the tools with the given names
+func (p *OpenAIPromptLLM) RestrictTools(names []string) llm.LLM {
+	clone := *p
+	restricted, _ := p.base.RestrictTools(names).(*OpenAILLM)
+	clone.base = restricted
+
+	return &clone
+}
+
+func (p *OpenAIPromptLLM) Call(ctx context.Context, msgs []llm.LLMMessage) (llm.LLMMessage, error) {
+	response, err := p.callLLM(ctx, msgs, nil)
+	if err != nil {
+		return llm.LLMMessage{}, err
+	}
+
+	return newPromptLLMMessage(response)
+}
+
+func (p *OpenAIPromptLLM) CallWithStructuredOutput(
+	ctx context.Context, msgs []llm.LLMMessage, schemaT any,
+) (string, error) {
+	response, err := p.callLLM(ctx, msgs, schemaT)
+	if err != nil {
+		return "", err
+	}
+
+	return response.OutputText(), nil
+}
+
+// callLLM sends the response request. The returned response has completed.
+func (p *OpenAIPromptLLM) callLLM(
+	ctx context.Context, msgs []llm.LLMMessage, schemaT any,
+) (*responses.Response, error) {
+	params, err := p.createParameters(msgs, schemaT)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OpenAI parameters: %w", err)
+	}
+
+	response, err := p.base.client.Responses.New(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("OpenAI API call failed: %w", err)
+	}
+
+	switch response.Status {
+	case responses.ResponseStatusCompleted:
+		return response, nil
+	case responses.ResponseStatusIncomplete:
+		return nil, fmt.Errorf("%w: response %s is incomplete: %s",
+			ErrPromptResponseFailed, response.ID, response.IncompleteDetails.Reason)
+	default:
+		return nil, fmt.Errorf("%w: response %s is %s: %s",
+			ErrPromptResponseFailed, response.ID, response.Status, response.Error.Message)
+	}
+}
+
+func newPromptLLMMessage(response *responses.Response) (llm.LLMMessage, error) {
+	var toolCalls []llm.LLMToolCall
+	for _, item := range response.Output {
+		if item.Type != "function_call" {
+			continue
+		}
+
+		toolCall, err := llm.NewLLMToolCall(item.CallID, item.Name, item.Arguments)
+		if err != nil {
+			return llm.LLMMessage{}, fmt.Errorf("failed to create tool calls: %w", err)
+		}
+		toolCalls = append(toolCalls, toolCall)
+	}
+
+	return llm.LLMMessage{
+		Type:      llm.LLMMessageTypeAssistant,
+		Content:   response.OutputText(),
+		ToolCalls: toolCalls,
+		End:       len(toolCalls) == 0,
+		Timestamp: time.Now().UTC(),
+		Usage: llm.TokenUsage{
+			InputTokens:  int(response.Usage.InputTokens),
+			OutputTokens: int(response.Usage.OutputTokens),
+		},
+	}, nil
+}
+
+func (p *OpenAIPromptLLM) createParameters(
+	msgs []llm.LLMMessage, schemaT any,
+) (responses.ResponseNewParams, error) {
+	instructions, input, err := createPromptInput(msgs)
+	if err != nil {
+		return responses.ResponseNewParams{}, err
+	}
+
+	params := responses.ResponseNewParams{
+		Prompt: responses.ResponsePromptParam{ID: p.promptID},
+		Input:  responses.ResponseNewParamsInputUnion{OfInputItemList: input},
+		Model:  p.base.model,
+		Store:  openai.Bool(false),
+	}
+	if instructions != "" {
+		params.Instructions = openai.String(instructions)
+	}
+	if p.base.temperature != 0 {
+		params.Temperature = openai.Float(p.base.temperature)
+	}
+	if p.base.maxTokens > 0 {
+		params.MaxOutputTokens = openai.Int(int64(p.base.maxTokens))
+	}
+
+	if schemaT != nil {
+		schemaMap, err := createResponseSchema(schemaT)
+		if err != nil {
+			return responses.ResponseNewParams{}, err
+		}
+		params.Text.Format = responses.ResponseFormatTextConfigUnionParam{
+			OfJSONSchema: &responses.ResponseFormatTextJSONSchemaConfigParam{
+				Name:        responseSchemaName,
+				Description: openai.String(responseSchemaDescription),
+				Schema:      schemaMap,
+				Strict:      openai.Bool(true),
+			},
+		}
+
+		return params, nil
+	}
+
+	functions, err := p.base.createFunctionDefinitions()
+	if err != nil {
+		return responses.ResponseNewParams{}, fmt.Errorf("failed to create tool parameters: %w", err)
+	}
+	for _, function := range functions {
+		params.Tools = append(params.Tools, responses.ToolUnionParam{
+			OfFunction: &responses.FunctionToolParam{
+				Name:        function.Name,
+				Description: function.Description,
+				Parameters:  function.Parameters,
+				Strict:      openai.Bool(p.base.strict),
+			},
+		})
+	}
+
+	return params, nil
+}
+
+// createPromptInput returns the system prompt, which is passed as instructions,
+// and the input items of the other messages
+func createPromptInput(msgs []llm.LLMMessage) (string, responses.ResponseInputParam, error) {
+	var instructions []string
+	input := make(responses.ResponseInputParam, 0, len(msgs))
+
+	for _, msg := range msgs {
+		switch msg.Type {
+		case llm.LLMMessageTypeSystem:
+			instructions = append(instructions, msg.Content)
+		case llm.LLMMessageTypeUser:
+			content, err := createPromptMessageContent(msg)
+			if err != nil {
+				return "", nil, err
+			}
+			input = append(input, responses.ResponseInputItemUnionParam{
+				OfMessage: &responses.EasyInputMessageParam{Role: responses.EasyInputMessageRoleUser, Content: content},
+			})
+		case llm.LLMMessageTypeAssistant:
+			items, err := createPromptAssistantItems(msg)
+			if err != nil {
+				return "", nil, err
+			}
+			input = append(input, items...)
+		}
+	}
+
+	return strings.Join(instructions, "\n\n"), input, nil
+}
+
+func createPromptAssistantItems(msg llm.LLMMessage) ([]responses.ResponseInputItemUnionParam, error) {
+	items := make([]responses.ResponseInputItemUnionParam, 0, 1+len(msg.ToolCalls)+len(msg.ToolResults))
+	if msg.Content != "" {
+		items = append(items, responses.ResponseInputItemUnionParam{
+			OfMessage: &responses.EasyInputMessageParam{
+				Role:    responses.EasyInputMessageRoleAssistant,
+				Content: responses.EasyInputMessageContentUnionParam{OfString: openai.String(msg.Content)},
+			},
+		})
+	}
+
+	for _, toolCall := range msg.ToolCalls {
+		items = append(items, responses.ResponseInputItemUnionParam{
+			OfFunctionCall: &responses.ResponseFunctionToolCallParam{
+				CallID:    toolCall.ID,
+				Name:      toolCall.ToolName,
+				Arguments: toolCall.Args,
+			},
+		})
+	}
+
+	for _, toolRes := range msg.ToolResults {
+		toolResJSON, err := llm.MarshalToolResult(toolRes)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrFailedToMarshalToolResult, err)
+		}
+
+		items = append(items, responses.ResponseInputItemUnionParam{
+			OfFunctionCallOutput: &responses.ResponseInputItemFunctionCallOutputParam{
+				CallID: toolRes.GetID(),
+				Output: string(toolResJSON),
+			},
+		})
+	}
+
+	return items, nil
+}
+
+func createPromptMessageContent(msg llm.LLMMessage) (responses.EasyInputMessageContentUnionParam, error) {
+	if len(msg.Parts) == 0 {
+		return responses.EasyInputMessageContentUnionParam{OfString: openai.String(msg.Content)}, nil
+	}
+
+	parts := make(responses.ResponseInputMessageContentListParam, 0, len(msg.Parts)+1)
+	if msg.Content != "" {
+		parts = append(parts, responses.ResponseInputContentUnionParam{
+			OfInputText: &responses.ResponseInputTextParam{Text: msg.Content},
+		})
+	}
+
+	for _, part := range msg.Parts {
+		if err := part.Validate(); err != nil {
+			return responses.EasyInputMessageContentUnionParam{}, fmt.Errorf("failed to create user message: %w", err)
+		}
+
+		if part.Text != nil {
+			parts = append(parts, responses.ResponseInputContentUnionParam{
+				OfInputText: &responses.ResponseInputTextParam{Text: part.Text.Text},
+			})
+
+			continue
+		}
+
+		imageURL, err := part.Image.Image.ImageURL()
+		if err != nil {
+			return responses.EasyInputMessageContentUnionParam{}, fmt.Errorf("failed to create user message: %w", err)
+		}
+
+		parts = append(parts, responses.ResponseInputContentUnionParam{
+			OfInputImage: &responses.ResponseInputImageParam{
+				Detail:   responses.ResponseInputImageDetailAuto,
+				ImageURL: openai.String(imageURL),
+			},
+		})
+	}
+
+	return responses.EasyInputMessageContentUnionParam{OfInputItemContentList: parts}, nil
+}
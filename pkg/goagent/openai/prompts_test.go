@@ -0,0 +1,238 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/openai"
+)
+
+const (
+	mockResponseToolCall = `{
+		"id": "resp_1", "object": "response", "status": "completed",
+		"output": [{
+			"id": "fc_1", "type": "function_call", "status": "completed",
+			"call_id": "call_1", "name": "add", "arguments": "{\"num1\": 2, \"num2\": 3}"
+		}],
+		"usage": {"input_tokens": 10, "output_tokens": 5, "total_tokens": 15}
+	}`
+	mockResponseText = `{
+		"id": "resp_2", "object": "response", "status": "completed",
+		"output": [{
+			"id": "msg_1", "type": "message", "role": "assistant", "status": "completed",
+			"content": [{"type": "output_text", "text": "The sum is 5", "annotations": []}]
+		}],
+		"usage": {"input_tokens": 20, "output_tokens": 4, "total_tokens": 24}
+	}`
+	mockResponseFailed = `{
+		"id": "resp_3", "object": "response", "status": "failed", "output": [],
+		"error": {"code": "server_error", "message": "boom"}
+	}`
+)
+
+// fakeResponsesAPI serves the Responses API endpoint used by OpenAIPromptLLM
+type fakeResponsesAPI struct {
+	mu       sync.Mutex
+	response string
+	requests []map[string]any
+}
+
+func (f *fakeResponsesAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.Method != http.MethodPost || r.URL.Path != "/v1/responses" {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	var body map[string]any
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	f.requests = append(f.requests, body)
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(f.response))
+}
+
+func (f *fakeResponsesAPI) lastRequest() map[string]any {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.requests[len(f.requests)-1]
+}
+
+func (f *fakeResponsesAPI) respond(response string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.response = response
+}
+
+func newTestPromptLLM(t *testing.T, api *fakeResponsesAPI) *openai.OpenAIPromptLLM {
+	t.Helper()
+
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+
+	tool, err := llm.NewLLMTool(
+		llm.WithLLMToolName("add"),
+		llm.WithLLMToolDescription("Adds two numbers"),
+		llm.WithLLMToolParametersSchema[AddParams](),
+		llm.WithLLMToolCall(func(id string, params AddParams) (AddResult, error) {
+			return AddResult{BaseLLMToolResult: llm.BaseLLMToolResult{ID: id}, Sum: params.Num1 + params.Num2}, nil
+		}),
+	)
+	require.NoError(t, err)
+
+	return openai.NewOpenAIPromptLLM("pmpt_1",
+		openai.WithAPIKey("test-key"),
+		openai.WithHTTPClient(&http.Client{Transport: newRedirectTransport(t, server)}),
+		openai.WithTools([]llm.LLMTool{tool}),
+	)
+}
+
+type AddParams struct {
+	Num1 int `json:"num1" jsonschema_description:"First number"`
+	Num2 int `json:"num2" jsonschema_description:"Second number"`
+}
+
+type AddResult struct {
+	llm.BaseLLMToolResult
+	Sum int `json:"sum"`
+}
+
+func TestOpenAIPromptLLM_Call_ToolCallsAndCompletion(t *testing.T) {
+	t.Parallel()
+
+	// given
+	api := &fakeResponsesAPI{response: mockResponseToolCall}
+	promptLLM := newTestPromptLLM(t, api)
+	msgs := []llm.LLMMessage{
+		llm.NewLLMMessage(llm.LLMMessageTypeSystem, "You are a calculator."),
+		llm.NewLLMMessage(llm.LLMMessageTypeUser, "Add 2 and 3"),
+	}
+
+	// when
+	toolCallMsg, err := promptLLM.Call(context.Background(), msgs)
+
+	// then
+	require.NoError(t, err)
+	assert.False(t, toolCallMsg.End)
+	require.Len(t, toolCallMsg.ToolCalls, 1)
+	assert.Equal(t, "call_1", toolCallMsg.ToolCalls[0].ID)
+	assert.Equal(t, "add", toolCallMsg.ToolCalls[0].ToolName)
+	assert.Equal(t, llm.TokenUsage{InputTokens: 10, OutputTokens: 5}, toolCallMsg.Usage)
+
+	request := api.lastRequest()
+	assert.Equal(t, map[string]any{"id": "pmpt_1"}, request["prompt"])
+	assert.Equal(t, "You are a calculator.", request["instructions"])
+	assert.Equal(t, false, request["store"])
+	assert.Len(t, request["tools"], 1)
+	assert.Equal(t, []any{map[string]any{"role": "user", "content": "Add 2 and 3"}}, request["input"])
+
+	// when
+	api.respond(mockResponseText)
+	toolCallMsg.ToolResults = []llm.LLMToolResult{
+		AddResult{BaseLLMToolResult: llm.BaseLLMToolResult{ID: "call_1"}, Sum: 5},
+	}
+	finalMsg, err := promptLLM.Call(context.Background(), append(msgs, toolCallMsg))
+
+	// then
+	require.NoError(t, err)
+	assert.True(t, finalMsg.End)
+	assert.Equal(t, "The sum is 5", finalMsg.Content)
+
+	input, ok := api.lastRequest()["input"].([]any)
+	require.True(t, ok)
+	require.Len(t, input, 3)
+	assert.Equal(t, map[string]any{
+		"type": "function_call", "call_id": "call_1", "name": "add", "arguments": `{"num1": 2, "num2": 3}`,
+	}, input[1])
+	assert.Equal(t, map[string]any{
+		"type": "function_call_output", "call_id": "call_1", "output": `{"id":"call_1","sum":5}`,
+	}, input[2])
+}
+
+func TestOpenAIPromptLLM_Call_ResponseFailed(t *testing.T) {
+	t.Parallel()
+
+	// given
+	promptLLM := newTestPromptLLM(t, &fakeResponsesAPI{response: mockResponseFailed})
+	msgs := []llm.LLMMessage{llm.NewLLMMessage(llm.LLMMessageTypeUser, "Hello")}
+
+	// when
+	_, err := promptLLM.Call(context.Background(), msgs)
+
+	// then
+	require.ErrorIs(t, err, openai.ErrPromptResponseFailed)
+	assert.Contains(t, err.Error(), "boom")
+}
+
+func TestOpenAIPromptLLM_CallWithStructuredOutput(t *testing.T) {
+	t.Parallel()
+
+	// given
+	api := &fakeResponsesAPI{response: mockResponseText}
+	promptLLM := newTestPromptLLM(t, api)
+	msgs := []llm.LLMMessage{llm.NewLLMMessage(llm.LLMMessageTypeUser, "Add 2 and 3")}
+
+	// when
+	output, err := promptLLM.CallWithStructuredOutput(context.Background(), msgs, AddResult{})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "The sum is 5", output)
+
+	request := api.lastRequest()
+	assert.NotContains(t, request, "tools")
+	text, ok := request["text"].(map[string]any)
+	require.True(t, ok)
+	format, ok := text["format"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "json_schema", format["type"])
+	assert.Equal(t, true, format["strict"])
+}
+
+func TestOpenAIPromptLLM_MessageRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	// given
+	api := &fakeResponsesAPI{response: mockResponseToolCall}
+	promptLLM := newTestPromptLLM(t, api)
+
+	// when
+	llmtest.AssertMessageRoundTrip(t, promptLLM, llmtest.RoundTripMessages())
+
+	// then
+	request := api.lastRequest()
+	assert.Equal(t, "You are a calculator.", request["instructions"])
+
+	input, ok := request["input"].([]any)
+	require.True(t, ok)
+	items := make([]any, 0, len(input))
+	for _, item := range input {
+		fields, ok := item.(map[string]any)
+		require.True(t, ok)
+		if role, ok := fields["role"]; ok {
+			items = append(items, role)
+		} else {
+			items = append(items, fields["type"])
+		}
+	}
+	assert.Equal(t, []any{
+		"user", "user", "assistant", "function_call", "function_call",
+		"function_call_output", "function_call_output", "assistant", "user",
+	}, items)
+
+	assert.Equal(t, "call_1", input[5].(map[string]any)["call_id"])
+	assert.JSONEq(t, `{"id": "call_2", "sum": 20}`, input[6].(map[string]any)["output"].(string))
+}
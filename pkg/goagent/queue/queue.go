@@ -0,0 +1,284 @@
+// Package queue runs agents asynchronously through a work queue, for webhooks
+// and background jobs that must not wait for the agent to finish.
+//
+// Example:
+//
+//	q := queue.NewInMemoryQueue(myAgent, 4, queue.WithResultTTL(30*time.Minute))
+//	defer q.Close()
+//
+//	jobID, err := q.Submit(ctx, input)
+//	if err != nil {
+//		return err
+//	}
+//
+//	result, err := q.Result(ctx, jobID)
+//
+// NewRedisQueue provides the same API with jobs and results kept in Redis.
+package queue
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+)
+
+var (
+	// ErrJobNotFound is returned for unknown job IDs and for jobs whose result TTL expired
+	ErrJobNotFound = errors.New("job not found")
+	// ErrJobCanceled is returned as the result error of a canceled job
+	ErrJobCanceled = errors.New("job canceled")
+	// ErrQueueClosed is returned when submitting to a closed queue
+	ErrQueueClosed = errors.New("queue closed")
+)
+
+const (
+	defaultResultTTL = time.Hour
+	defaultCapacity  = 128
+	jobIDBytes       = 16
+)
+
+type config struct {
+	resultTTL time.Duration
+	capacity  int
+	keyPrefix string
+}
+
+// Option configures a Queue
+type Option func(*config)
+
+// WithResultTTL sets how long results of finished jobs are retained. Defaults to one hour.
+func WithResultTTL(ttl time.Duration) Option {
+	return func(c *config) {
+		c.resultTTL = ttl
+	}
+}
+
+// WithCapacity sets how many jobs can wait for a worker before Submit blocks. Defaults to 128.
+func WithCapacity(capacity int) Option {
+	return func(c *config) {
+		c.capacity = capacity
+	}
+}
+
+// WithKeyPrefix sets the prefix of the Redis keys of a RedisQueue, so several queues can share
+// one Redis. Defaults to DefaultRedisKeyPrefix.
+func WithKeyPrefix(prefix string) Option {
+	return func(c *config) {
+		c.keyPrefix = prefix
+	}
+}
+
+type job[T any] struct {
+	id         string
+	input      any
+	ctx        context.Context //nolint:containedctx // the job context is canceled by Cancel
+	cancel     context.CancelFunc
+	done       chan struct{}
+	result     *agent.AgentResult[T]
+	err        error
+	finishedAt time.Time
+}
+
+// Queue executes agent runs on a fixed pool of workers and keeps their results
+// for a limited time
+type Queue[T any] struct {
+	agent     *agent.Agent[T]
+	resultTTL time.Duration
+	pending   chan *job[T]
+	closed    chan struct{}
+	closeOnce sync.Once
+	workers   sync.WaitGroup
+
+	mu   sync.Mutex
+	jobs map[string]*job[T]
+}
+
+// NewInMemoryQueue creates a queue that runs a on the given number of workers.
+// Jobs and results are kept in memory and are lost when the process exits.
+func NewInMemoryQueue[T any](a *agent.Agent[T], workers int, options ...Option) *Queue[T] {
+	cfg := &config{
+		resultTTL: defaultResultTTL,
+		capacity:  defaultCapacity,
+	}
+	for _, opt := range options {
+		opt(cfg)
+	}
+
+	q := &Queue[T]{
+		agent:     a,
+		resultTTL: cfg.resultTTL,
+		pending:   make(chan *job[T], cfg.capacity),
+		closed:    make(chan struct{}),
+		jobs:      make(map[string]*job[T]),
+	}
+
+	for range max(workers, 1) {
+		q.workers.Add(1)
+		go q.work()
+	}
+
+	return q
+}
+
+// Submit enqueues an agent run with the given input and returns its job ID.
+// ctx only bounds waiting for a free slot in the queue, not the run itself.
+func (q *Queue[T]) Submit(ctx context.Context, input any) (string, error) {
+	id, err := newJobID()
+	if err != nil {
+		return "", err
+	}
+
+	jobCtx, cancel := context.WithCancel(context.Background())
+	newJob := &job[T]{
+		id:     id,
+		input:  input,
+		ctx:    jobCtx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	q.mu.Lock()
+	q.removeExpired()
+	q.jobs[id] = newJob
+	q.mu.Unlock()
+
+	select {
+	case <-q.closed:
+		q.forget(id)
+
+		return "", ErrQueueClosed
+	default:
+	}
+
+	select {
+	case q.pending <- newJob:
+		return id, nil
+	case <-q.closed:
+		q.forget(id)
+
+		return "", ErrQueueClosed
+	case <-ctx.Done():
+		q.forget(id)
+
+		return "", fmt.Errorf("failed to submit job: %w", ctx.Err())
+	}
+}
+
+// Result waits for the job to finish and returns the agent result and error.
+// Returns ErrJobNotFound when the job is unknown or its result has expired.
+func (q *Queue[T]) Result(ctx context.Context, jobID string) (*agent.AgentResult[T], error) {
+	q.mu.Lock()
+	q.removeExpired()
+	found, ok := q.jobs[jobID]
+	q.mu.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrJobNotFound, jobID)
+	}
+
+	select {
+	case <-found.done:
+		return found.result, found.err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("failed to wait for job %s: %w", jobID, ctx.Err())
+	}
+}
+
+// Cancel stops the job. A job that has not started yet is never run; a running job
+// has its context canceled. Its result error is ErrJobCanceled.
+func (q *Queue[T]) Cancel(jobID string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	found, ok := q.jobs[jobID]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrJobNotFound, jobID)
+	}
+
+	found.cancel()
+
+	return nil
+}
+
+// Close stops accepting jobs and waits for the workers to finish the jobs already queued
+func (q *Queue[T]) Close() {
+	q.closeOnce.Do(func() {
+		close(q.closed)
+	})
+	q.workers.Wait()
+}
+
+func (q *Queue[T]) work() {
+	defer q.workers.Done()
+
+	for {
+		select {
+		case next := <-q.pending:
+			q.run(next)
+		case <-q.closed:
+			for {
+				select {
+				case next := <-q.pending:
+					q.run(next)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (q *Queue[T]) run(j *job[T]) {
+	var result *agent.AgentResult[T]
+	var err error
+
+	if j.ctx.Err() == nil {
+		result, err = q.agent.Run(j.ctx, j.input)
+	}
+	if j.ctx.Err() != nil {
+		result, err = nil, ErrJobCanceled
+	}
+
+	q.mu.Lock()
+	j.result, j.err = result, err
+	j.finishedAt = time.Now()
+	q.mu.Unlock()
+
+	j.cancel()
+	close(j.done)
+}
+
+// removeExpired deletes finished jobs older than the result TTL. Callers must hold q.mu.
+func (q *Queue[T]) removeExpired() {
+	now := time.Now()
+	for id, j := range q.jobs {
+		if !j.finishedAt.IsZero() && now.Sub(j.finishedAt) > q.resultTTL {
+			delete(q.jobs, id)
+		}
+	}
+}
+
+func (q *Queue[T]) forget(id string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if j, ok := q.jobs[id]; ok {
+		j.cancel()
+		delete(q.jobs, id)
+	}
+}
+
+func newJobID() (string, error) {
+	id := make([]byte, jobIDBytes)
+	if _, err := rand.Read(id); err != nil {
+		return "", fmt.Errorf("failed to generate job id: %w", err)
+	}
+
+	return hex.EncodeToString(id), nil
+}
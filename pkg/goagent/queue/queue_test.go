@@ -0,0 +1,123 @@
+package queue_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/queue"
+)
+
+type Answer struct {
+	Text string `json:"text" jsonschema_description:"The answer"`
+}
+
+// blockingLLM blocks every call until its context is canceled
+type blockingLLM struct {
+	started chan struct{}
+}
+
+func (b *blockingLLM) Call(ctx context.Context, _ []llm.LLMMessage) (llm.LLMMessage, error) {
+	b.started <- struct{}{}
+	<-ctx.Done()
+
+	return llm.LLMMessage{}, ctx.Err()
+}
+
+func (b *blockingLLM) CallWithStructuredOutput(ctx context.Context, _ []llm.LLMMessage, _ any) (string, error) {
+	<-ctx.Done()
+
+	return "", ctx.Err()
+}
+
+func createAnswerAgent(t *testing.T, agentLLM llm.LLM) *agent.Agent[Answer] {
+	t.Helper()
+
+	answerAgent, err := agent.NewAgent(
+		agent.WithName[Answer]("answer_agent"),
+		agent.WithLLMConfig[Answer](llm.LLMConfig{
+			Type:   llm.LLMTypeOpenAI,
+			APIKey: "test-api-key",
+			Model:  "gpt-4",
+		}),
+		agent.WithBehavior[Answer]("You answer questions."),
+	)
+	require.NoError(t, err)
+
+	return answerAgent.UsingLLM(agentLLM)
+}
+
+func TestInMemoryQueue_Result(t *testing.T) {
+	t.Parallel()
+
+	mockLLM := llmtest.NewMockLLM(`{"text": "4"}`, llm.LLMMessage{Type: llm.LLMMessageTypeAssistant, End: true})
+	q := queue.NewInMemoryQueue(createAnswerAgent(t, mockLLM), 2)
+	defer q.Close()
+
+	jobID, err := q.Submit(context.Background(), "What is 2+2?")
+	require.NoError(t, err)
+
+	result, err := q.Result(context.Background(), jobID)
+
+	require.NoError(t, err)
+	assert.Equal(t, "4", result.Data.Text)
+}
+
+func TestInMemoryQueue_Cancel(t *testing.T) {
+	t.Parallel()
+
+	blocking := &blockingLLM{started: make(chan struct{}, 1)}
+	q := queue.NewInMemoryQueue(createAnswerAgent(t, blocking), 1)
+	defer q.Close()
+
+	running, err := q.Submit(context.Background(), "first")
+	require.NoError(t, err)
+	<-blocking.started
+
+	waiting, err := q.Submit(context.Background(), "second")
+	require.NoError(t, err)
+
+	require.NoError(t, q.Cancel(waiting))
+	require.NoError(t, q.Cancel(running))
+
+	_, err = q.Result(context.Background(), running)
+	require.ErrorIs(t, err, queue.ErrJobCanceled)
+
+	_, err = q.Result(context.Background(), waiting)
+	require.ErrorIs(t, err, queue.ErrJobCanceled)
+}
+
+func TestInMemoryQueue_ResultTTL(t *testing.T) {
+	t.Parallel()
+
+	mockLLM := llmtest.NewMockLLM(`{"text": "4"}`, llm.LLMMessage{Type: llm.LLMMessageTypeAssistant, End: true})
+	q := queue.NewInMemoryQueue(createAnswerAgent(t, mockLLM), 1, queue.WithResultTTL(time.Millisecond))
+	defer q.Close()
+
+	jobID, err := q.Submit(context.Background(), "What is 2+2?")
+	require.NoError(t, err)
+	_, err = q.Result(context.Background(), jobID)
+	require.NoError(t, err)
+
+	time.Sleep(5 * time.Millisecond)
+
+	_, err = q.Result(context.Background(), jobID)
+	require.ErrorIs(t, err, queue.ErrJobNotFound)
+	require.ErrorIs(t, q.Cancel(jobID), queue.ErrJobNotFound)
+}
+
+func TestInMemoryQueue_Closed(t *testing.T) {
+	t.Parallel()
+
+	q := queue.NewInMemoryQueue(createAnswerAgent(t, llmtest.NewMockLLM("")), 1)
+	q.Close()
+
+	_, err := q.Submit(context.Background(), "too late")
+
+	require.ErrorIs(t, err, queue.ErrQueueClosed)
+}
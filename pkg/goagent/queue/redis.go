@@ -0,0 +1,355 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+)
+
+// ErrJobFailed is returned as the result error of a RedisQueue job whose run failed,
+// wrapping the message of the original error
+var ErrJobFailed = errors.New("job failed")
+
+const (
+	// DefaultRedisKeyPrefix is prepended to the keys of RedisQueue
+	DefaultRedisKeyPrefix = "goagent:queue:"
+
+	redisTimeout      = time.Second
+	redisPopTimeout   = time.Second
+	redisPollInterval = 50 * time.Millisecond
+
+	statusPending  = "pending"
+	statusRunning  = "running"
+	statusDone     = "done"
+	statusCanceled = "canceled"
+)
+
+// startScript marks a pending job as running and returns its input, or nil when the job
+// was canceled or is unknown
+var startScript = redis.NewScript(`
+if redis.call('HGET', KEYS[1], 'status') ~= 'pending' then
+	return false
+end
+redis.call('HSET', KEYS[1], 'status', 'running')
+return redis.call('HGET', KEYS[1], 'input')
+`)
+
+// cancelScript cancels a pending job right away and flags a running one, returning the
+// status the job had, or nil when the job is unknown
+var cancelScript = redis.NewScript(`
+local status = redis.call('HGET', KEYS[1], 'status')
+if not status then
+	return false
+end
+if status == 'pending' then
+	redis.call('HSET', KEYS[1], 'status', 'canceled')
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+elseif status == 'running' then
+	redis.call('HSET', KEYS[1], 'canceled', '1')
+end
+return status
+`)
+
+// RedisQueue executes agent runs like Queue, but keeps jobs and results in Redis, so they
+// survive restarts and are shared by every process using the same Redis and key prefix.
+// Inputs are stored as JSON and the agent receives them as json.RawMessage. Results are
+// stored with AgentResult.ToJSON, so the tool result types of the agent must be registered
+// with agent.RegisterToolResultType. A job taken by a process that dies while running it
+// is not retried.
+type RedisQueue[T any] struct {
+	rdb       redis.UniversalClient
+	agent     *agent.Agent[T]
+	prefix    string
+	resultTTL time.Duration
+	cancelSub *redis.PubSub
+
+	ctx       context.Context //nolint:containedctx // canceled by Close to stop the workers
+	stop      context.CancelFunc
+	closeOnce sync.Once
+	workers   sync.WaitGroup
+
+	mu      sync.Mutex
+	running map[string]context.CancelFunc
+}
+
+// NewRedisQueue creates a queue that runs a on the given number of workers and keeps its
+// jobs in Redis under keys starting with DefaultRedisKeyPrefix, see WithKeyPrefix.
+// WithCapacity does not apply, the number of waiting jobs is only limited by Redis.
+func NewRedisQueue[T any](
+	rdb redis.UniversalClient,
+	a *agent.Agent[T],
+	workers int,
+	options ...Option,
+) *RedisQueue[T] {
+	cfg := &config{
+		resultTTL: defaultResultTTL,
+		keyPrefix: DefaultRedisKeyPrefix,
+	}
+	for _, opt := range options {
+		opt(cfg)
+	}
+
+	ctx, stop := context.WithCancel(context.Background())
+	q := &RedisQueue[T]{
+		rdb:       rdb,
+		agent:     a,
+		prefix:    cfg.keyPrefix,
+		resultTTL: cfg.resultTTL,
+		ctx:       ctx,
+		stop:      stop,
+		running:   make(map[string]context.CancelFunc),
+	}
+	q.cancelSub = rdb.Subscribe(ctx, q.cancelChannel())
+
+	q.workers.Add(1)
+	go q.listenCancels()
+
+	for range max(workers, 1) {
+		q.workers.Add(1)
+		go q.work()
+	}
+
+	return q
+}
+
+// Submit stores an agent run with the given input in Redis and returns its job ID
+func (q *RedisQueue[T]) Submit(ctx context.Context, input any) (string, error) {
+	if q.ctx.Err() != nil {
+		return "", ErrQueueClosed
+	}
+
+	inputJSON, err := json.Marshal(input)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal job input: %w", err)
+	}
+
+	id, err := newJobID()
+	if err != nil {
+		return "", err
+	}
+
+	_, err = q.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, q.jobKey(id), "status", statusPending, "input", inputJSON)
+		pipe.LPush(ctx, q.pendingKey(), id)
+
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to submit job: %w", err)
+	}
+
+	return id, nil
+}
+
+// Result waits for the job to finish and returns the agent result and error.
+// Returns ErrJobNotFound when the job is unknown or its result has expired.
+func (q *RedisQueue[T]) Result(ctx context.Context, jobID string) (*agent.AgentResult[T], error) {
+	ticker := time.NewTicker(redisPollInterval)
+	defer ticker.Stop()
+
+	for {
+		values, err := q.rdb.HMGet(ctx, q.jobKey(jobID), "status", "result", "error").Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read job %s: %w", jobID, err)
+		}
+
+		status, _ := values[0].(string)
+		switch status {
+		case "":
+			return nil, fmt.Errorf("%w: %s", ErrJobNotFound, jobID)
+		case statusCanceled:
+			return nil, ErrJobCanceled
+		case statusDone:
+			return finishedResult[T](values[1], values[2])
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to wait for job %s: %w", jobID, ctx.Err())
+		}
+	}
+}
+
+// Cancel stops the job. A job that has not started yet is never run; a running job
+// has its context canceled, in whichever process runs it. Its result error is ErrJobCanceled.
+func (q *RedisQueue[T]) Cancel(jobID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	status, err := cancelScript.Run(ctx, q.rdb, []string{q.jobKey(jobID)}, q.resultTTL.Milliseconds()).Text()
+	if errors.Is(err, redis.Nil) {
+		return fmt.Errorf("%w: %s", ErrJobNotFound, jobID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to cancel job %s: %w", jobID, err)
+	}
+
+	if status == statusRunning {
+		if err := q.rdb.Publish(ctx, q.cancelChannel(), jobID).Err(); err != nil {
+			return fmt.Errorf("failed to cancel job %s: %w", jobID, err)
+		}
+	}
+
+	return nil
+}
+
+// Close stops taking jobs from Redis and waits for the running jobs to finish.
+// Jobs which are still pending stay in Redis for other or later queues.
+func (q *RedisQueue[T]) Close() {
+	q.closeOnce.Do(func() {
+		q.stop()
+		if err := q.cancelSub.Close(); err != nil {
+			slog.Warn("failed to close job cancel subscription", "error", err)
+		}
+	})
+	q.workers.Wait()
+}
+
+func (q *RedisQueue[T]) work() {
+	defer q.workers.Done()
+
+	for q.ctx.Err() == nil {
+		popped, err := q.rdb.BRPop(q.ctx, redisPopTimeout, q.pendingKey()).Result()
+		if errors.Is(err, redis.Nil) || q.ctx.Err() != nil {
+			continue
+		}
+		if err != nil {
+			slog.Error("failed to take job from redis", "error", err)
+			q.backOff()
+
+			continue
+		}
+
+		q.run(popped[1])
+	}
+}
+
+func (q *RedisQueue[T]) run(jobID string) {
+	startCtx, cancelStart := context.WithTimeout(context.Background(), redisTimeout)
+	input, err := startScript.Run(startCtx, q.rdb, []string{q.jobKey(jobID)}).Text()
+	cancelStart()
+	if errors.Is(err, redis.Nil) {
+		return
+	}
+	if err != nil {
+		slog.Error("failed to start job", "job_id", jobID, "error", err)
+
+		return
+	}
+
+	jobCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	q.track(jobID, cancel)
+	defer q.untrack(jobID)
+
+	// Cancel may have flagged the job before it was tracked
+	if q.rdb.HExists(jobCtx, q.jobKey(jobID), "canceled").Val() {
+		cancel()
+	}
+
+	var result *agent.AgentResult[T]
+	if jobCtx.Err() == nil {
+		result, err = q.agent.Run(jobCtx, json.RawMessage(input))
+	}
+
+	q.finish(jobID, result, err, jobCtx.Err() != nil)
+}
+
+func (q *RedisQueue[T]) finish(jobID string, result *agent.AgentResult[T], runErr error, canceled bool) {
+	fields := []any{"status", statusDone}
+	switch {
+	case canceled:
+		fields = []any{"status", statusCanceled}
+	case runErr != nil:
+		fields = append(fields, "error", runErr.Error())
+	default:
+		resultJSON, err := result.ToJSON()
+		if err != nil {
+			fields = append(fields, "error", fmt.Sprintf("failed to marshal result: %v", err))
+		} else {
+			fields = append(fields, "result", resultJSON)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	_, err := q.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, q.jobKey(jobID), fields...)
+		pipe.HDel(ctx, q.jobKey(jobID), "input")
+		pipe.Expire(ctx, q.jobKey(jobID), q.resultTTL)
+
+		return nil
+	})
+	if err != nil {
+		slog.Error("failed to store job result", "job_id", jobID, "error", err)
+	}
+}
+
+func (q *RedisQueue[T]) listenCancels() {
+	defer q.workers.Done()
+
+	for msg := range q.cancelSub.Channel() {
+		q.mu.Lock()
+		if cancel, ok := q.running[msg.Payload]; ok {
+			cancel()
+		}
+		q.mu.Unlock()
+	}
+}
+
+func (q *RedisQueue[T]) track(jobID string, cancel context.CancelFunc) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.running[jobID] = cancel
+}
+
+func (q *RedisQueue[T]) untrack(jobID string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	delete(q.running, jobID)
+}
+
+// backOff waits before the next attempt to take a job after a Redis error
+func (q *RedisQueue[T]) backOff() {
+	select {
+	case <-time.After(redisPopTimeout):
+	case <-q.ctx.Done():
+	}
+}
+
+func (q *RedisQueue[T]) jobKey(jobID string) string {
+	return q.prefix + "job:" + jobID
+}
+
+func (q *RedisQueue[T]) pendingKey() string {
+	return q.prefix + "pending"
+}
+
+func (q *RedisQueue[T]) cancelChannel() string {
+	return q.prefix + "cancel"
+}
+
+func finishedResult[T any](result, errMessage any) (*agent.AgentResult[T], error) {
+	if message, ok := errMessage.(string); ok {
+		return nil, fmt.Errorf("%w: %s", ErrJobFailed, message)
+	}
+
+	resultJSON, _ := result.(string)
+	decoded, err := agent.AgentResultFromJSON[T]([]byte(resultJSON))
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal job result: %w", err)
+	}
+
+	return decoded, nil
+}
@@ -0,0 +1,173 @@
+package queue_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/queue"
+)
+
+func newRedisClient(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	return server, rdb
+}
+
+func TestRedisQueue_Result(t *testing.T) {
+	t.Parallel()
+
+	// given
+	_, rdb := newRedisClient(t)
+	mockLLM := llmtest.NewMockLLM(`{"text": "4"}`, llm.LLMMessage{Type: llm.LLMMessageTypeAssistant, End: true})
+	q := queue.NewRedisQueue(rdb, createAnswerAgent(t, mockLLM), 2)
+	defer q.Close()
+
+	jobID, err := q.Submit(context.Background(), "What is 2+2?")
+	require.NoError(t, err)
+
+	// when
+	result, err := q.Result(context.Background(), jobID)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "4", result.Data.Text)
+	assert.Equal(t, `"What is 2+2?"`, result.Messages[1].Content)
+}
+
+func TestRedisQueue_SharedBetweenQueues(t *testing.T) {
+	t.Parallel()
+
+	// given
+	_, rdb := newRedisClient(t)
+	mockLLM := llmtest.NewMockLLM(`{"text": "4"}`, llm.LLMMessage{Type: llm.LLMMessageTypeAssistant, End: true})
+	worker := queue.NewRedisQueue(rdb, createAnswerAgent(t, mockLLM), 1)
+	defer worker.Close()
+
+	reader := queue.NewRedisQueue(rdb, createAnswerAgent(t, llmtest.NewMockLLM("")), 1)
+	reader.Close()
+	otherPrefix := queue.NewRedisQueue(rdb, createAnswerAgent(t, llmtest.NewMockLLM("")), 1,
+		queue.WithKeyPrefix("other:"))
+	otherPrefix.Close()
+
+	jobID, err := worker.Submit(context.Background(), "What is 2+2?")
+	require.NoError(t, err)
+
+	// when
+	result, err := reader.Result(context.Background(), jobID)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "4", result.Data.Text)
+
+	_, err = otherPrefix.Result(context.Background(), jobID)
+	require.ErrorIs(t, err, queue.ErrJobNotFound)
+}
+
+func TestRedisQueue_Closed(t *testing.T) {
+	t.Parallel()
+
+	// given
+	_, rdb := newRedisClient(t)
+	q := queue.NewRedisQueue(rdb, createAnswerAgent(t, llmtest.NewMockLLM("")), 1)
+	q.Close()
+
+	// when
+	_, err := q.Submit(context.Background(), "too late")
+
+	// then
+	require.ErrorIs(t, err, queue.ErrQueueClosed)
+}
+
+func TestRedisQueue_Cancel(t *testing.T) {
+	t.Parallel()
+
+	// given
+	_, rdb := newRedisClient(t)
+	blocking := &blockingLLM{started: make(chan struct{}, 1)}
+	q := queue.NewRedisQueue(rdb, createAnswerAgent(t, blocking), 1)
+	defer q.Close()
+
+	running, err := q.Submit(context.Background(), "first")
+	require.NoError(t, err)
+	<-blocking.started
+
+	waiting, err := q.Submit(context.Background(), "second")
+	require.NoError(t, err)
+
+	// when
+	require.NoError(t, q.Cancel(waiting))
+	require.NoError(t, q.Cancel(running))
+
+	// then
+	_, err = q.Result(context.Background(), running)
+	require.ErrorIs(t, err, queue.ErrJobCanceled)
+
+	_, err = q.Result(context.Background(), waiting)
+	require.ErrorIs(t, err, queue.ErrJobCanceled)
+}
+
+func TestRedisQueue_Failed(t *testing.T) {
+	t.Parallel()
+
+	// given
+	_, rdb := newRedisClient(t)
+	q := queue.NewRedisQueue(rdb, createAnswerAgent(t, &failingLLM{}), 1)
+	defer q.Close()
+
+	jobID, err := q.Submit(context.Background(), "What is 2+2?")
+	require.NoError(t, err)
+
+	// when
+	_, err = q.Result(context.Background(), jobID)
+
+	// then
+	require.ErrorIs(t, err, queue.ErrJobFailed)
+	assert.Contains(t, err.Error(), errLLMUnavailable.Error())
+}
+
+func TestRedisQueue_ResultTTL(t *testing.T) {
+	t.Parallel()
+
+	// given
+	server, rdb := newRedisClient(t)
+	mockLLM := llmtest.NewMockLLM(`{"text": "4"}`, llm.LLMMessage{Type: llm.LLMMessageTypeAssistant, End: true})
+	q := queue.NewRedisQueue(rdb, createAnswerAgent(t, mockLLM), 1, queue.WithResultTTL(time.Minute))
+	defer q.Close()
+
+	jobID, err := q.Submit(context.Background(), "What is 2+2?")
+	require.NoError(t, err)
+	_, err = q.Result(context.Background(), jobID)
+	require.NoError(t, err)
+
+	// when
+	server.FastForward(2 * time.Minute)
+
+	// then
+	_, err = q.Result(context.Background(), jobID)
+	require.ErrorIs(t, err, queue.ErrJobNotFound)
+	require.ErrorIs(t, q.Cancel(jobID), queue.ErrJobNotFound)
+}
+
+var errLLMUnavailable = errors.New("llm unavailable")
+
+type failingLLM struct{}
+
+func (f *failingLLM) Call(context.Context, []llm.LLMMessage) (llm.LLMMessage, error) {
+	return llm.LLMMessage{}, errLLMUnavailable
+}
+
+func (f *failingLLM) CallWithStructuredOutput(context.Context, []llm.LLMMessage, any) (string, error) {
+	return "", errLLMUnavailable
+}
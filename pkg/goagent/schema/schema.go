@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
+	"sync"
 
 	"github.com/invopop/jsonschema"
 )
@@ -15,8 +17,26 @@ var reflector = jsonschema.Reflector{
 	DoNotReference:            true,
 }
 
-// Generate a JSON schema from the Go type T
+// cache stores generated schemas by reflect.Type. The type itself is the key rather than
+// its name, because types declared in different scopes can share the same name.
+var cache sync.Map
+
+// Generate a JSON schema from the Go type T.
+// A pre-generated schema passed as map[string]any is returned as is.
+// Generated schemas are cached per type and shared between callers, so the
+// returned map must not be modified.
 func GenerateSchema(schemaT any) (map[string]any, error) {
+	if schemaMap, ok := schemaT.(map[string]any); ok {
+		return schemaMap, nil
+	}
+
+	schemaType := reflect.TypeOf(schemaT)
+	if cached, ok := cache.Load(schemaType); ok {
+		if schemaMap, ok := cached.(map[string]any); ok {
+			return schemaMap, nil
+		}
+	}
+
 	schema, err := GenerateSchemaStr(schemaT)
 	if err != nil {
 		return nil, err
@@ -27,9 +47,16 @@ func GenerateSchema(schemaT any) (map[string]any, error) {
 		return nil, fmt.Errorf("%w: %v", ErrCannotCreateSchema, err)
 	}
 
+	cache.Store(schemaType, result)
+
 	return result, nil
 }
 
+// ClearCache removes all cached schemas. It is intended for tests.
+func ClearCache() {
+	cache.Clear()
+}
+
 func GenerateSchemaStr(schemaT any) (string, error) {
 	schema := reflector.Reflect(schemaT)
 
@@ -40,3 +67,139 @@ func GenerateSchemaStr(schemaT any) (string, error) {
 
 	return string(schemaMap), nil
 }
+
+// SchemaOption adjusts a schema generated by GenerateSchemaWithOptions
+type SchemaOption func(*schemaOptions)
+
+type schemaOptions struct {
+	noAdditionalProperties bool
+}
+
+// WithNoAdditionalProperties sets "additionalProperties": false on every object node of
+// the schema, as OpenAI strict mode requires. Nodes which describe their additional
+// properties with a schema, e.g. the values of a Go map, are left unchanged.
+func WithNoAdditionalProperties() SchemaOption {
+	return func(o *schemaOptions) {
+		o.noAdditionalProperties = true
+	}
+}
+
+// GenerateSchemaWithOptions generates a JSON schema like GenerateSchema and applies the
+// given options to a copy of it, so the cached schema is never modified.
+func GenerateSchemaWithOptions(schemaT any, opts ...SchemaOption) (map[string]any, error) {
+	result, err := GenerateSchema(schemaT)
+	if err != nil {
+		return nil, err
+	}
+	if len(opts) == 0 {
+		return result, nil
+	}
+
+	options := &schemaOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	result, ok := deepCopy(result).(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: unexpected schema copy type", ErrCannotCreateSchema)
+	}
+
+	if options.noAdditionalProperties {
+		disallowAdditionalProperties(result)
+	}
+
+	return result, nil
+}
+
+func deepCopy(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		copied := make(map[string]any, len(v))
+		for key, item := range v {
+			copied[key] = deepCopy(item)
+		}
+
+		return copied
+	case []any:
+		copied := make([]any, len(v))
+		for i, item := range v {
+			copied[i] = deepCopy(item)
+		}
+
+		return copied
+	default:
+		return value
+	}
+}
+
+func disallowAdditionalProperties(value any) {
+	switch v := value.(type) {
+	case map[string]any:
+		if isObjectNode(v) {
+			if _, isSchema := v["additionalProperties"].(map[string]any); !isSchema {
+				v["additionalProperties"] = false
+			}
+		}
+		for keyword, item := range v {
+			switch keyword {
+			case "properties", "patternProperties", "$defs", "definitions":
+				// keyword maps names to schemas, the map itself is not a schema
+				if schemas, ok := item.(map[string]any); ok {
+					for _, itemSchema := range schemas {
+						disallowAdditionalProperties(itemSchema)
+					}
+				}
+			case "enum", "const", "default", "examples":
+				// keyword holds JSON values, not schemas
+			default:
+				disallowAdditionalProperties(item)
+			}
+		}
+	case []any:
+		for _, item := range v {
+			disallowAdditionalProperties(item)
+		}
+	}
+}
+
+// isObjectNode reports whether the schema node describes a JSON object
+func isObjectNode(node map[string]any) bool {
+	switch nodeType := node["type"].(type) {
+	case string:
+		return nodeType == "object"
+	case []any:
+		for _, t := range nodeType {
+			if t == "object" {
+				return true
+			}
+		}
+	}
+
+	_, hasProperties := node["properties"].(map[string]any)
+
+	return hasProperties
+}
+
+// MustGenerateSchema is like GenerateSchema but panics when the schema cannot be generated.
+// It simplifies schema generation in init functions and package level variables and should
+// only be used with types known at compile time, never with values from user input.
+func MustGenerateSchema(schemaT any) map[string]any {
+	schema, err := GenerateSchema(schemaT)
+	if err != nil {
+		panic(fmt.Sprintf("schema: MustGenerateSchema(%T): %v", schemaT, err))
+	}
+
+	return schema
+}
+
+// MustGenerateSchemaStr is like GenerateSchemaStr but panics when the schema cannot be generated.
+// Like MustGenerateSchema, it should only be used with types known at compile time.
+func MustGenerateSchemaStr(schemaT any) string {
+	schema, err := GenerateSchemaStr(schemaT)
+	if err != nil {
+		panic(fmt.Sprintf("schema: MustGenerateSchemaStr(%T): %v", schemaT, err))
+	}
+
+	return schema
+}
@@ -2,8 +2,11 @@ package schema_test
 
 import (
 	"encoding/json"
+	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/invopop/jsonschema"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/vitalii-honchar/go-agent/pkg/goagent/schema"
@@ -175,3 +178,169 @@ func TestGenerateSchema_WithDescription(t *testing.T) {
 	assert.Contains(t, name, "description")
 	assert.Equal(t, "The user's full name", name["description"])
 }
+
+func TestGenerateSchema_PreGeneratedMap(t *testing.T) {
+	t.Parallel()
+	preGenerated := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"value": map[string]any{"type": "string"},
+		},
+	}
+
+	result, err := schema.GenerateSchema(preGenerated)
+
+	require.NoError(t, err)
+	assert.Equal(t, preGenerated, result)
+}
+
+func TestGenerateSchema_Cached(t *testing.T) {
+	t.Parallel()
+	type Cached struct {
+		Value string `json:"value"`
+	}
+
+	first, err := schema.GenerateSchema(Cached{})
+	require.NoError(t, err)
+
+	second, err := schema.GenerateSchema(Cached{})
+	require.NoError(t, err)
+
+	assert.Equal(t, reflect.ValueOf(first).Pointer(), reflect.ValueOf(second).Pointer())
+}
+
+type benchmarkSchema struct {
+	Name    string            `json:"name"    jsonschema_description:"Name"`
+	Age     int               `json:"age"     jsonschema_description:"Age"`
+	Tags    []string          `json:"tags"    jsonschema_description:"Tags"`
+	Details map[string]string `json:"details" jsonschema_description:"Details"`
+}
+
+func BenchmarkGenerateSchemaRepeated(b *testing.B) {
+	b.Run("cached", func(b *testing.B) {
+		for range b.N {
+			if _, err := schema.GenerateSchema(benchmarkSchema{}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("uncached", func(b *testing.B) {
+		for range b.N {
+			schema.ClearCache()
+			if _, err := schema.GenerateSchema(benchmarkSchema{}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestGenerateSchemaWithOptions_NoAdditionalProperties(t *testing.T) {
+	t.Parallel()
+	type Item struct {
+		Name  string `json:"name"`
+		Extra any    `json:"extra"`
+	}
+	type Order struct {
+		Items  []Item            `json:"items"`
+		Labels map[string]string `json:"labels"`
+	}
+
+	result, err := schema.GenerateSchemaWithOptions(Order{}, schema.WithNoAdditionalProperties())
+
+	require.NoError(t, err)
+	assert.Equal(t, false, result["additionalProperties"])
+
+	properties, isOK := result["properties"].(map[string]any)
+	require.True(t, isOK)
+	items, isOK := properties["items"].(map[string]any)["items"].(map[string]any)
+	require.True(t, isOK)
+	assert.Equal(t, false, items["additionalProperties"])
+
+	labels, isOK := properties["labels"].(map[string]any)
+	require.True(t, isOK)
+	assert.Equal(t, map[string]any{"type": "string"}, labels["additionalProperties"])
+}
+
+func TestGenerateSchemaWithOptions_PreGeneratedMap(t *testing.T) {
+	t.Parallel()
+	preGenerated := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"properties": map[string]any{"type": "string"},
+			"nested": map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"value": map[string]any{"type": "string"}},
+				"default":    map[string]any{"value": "x"},
+			},
+		},
+	}
+
+	result, err := schema.GenerateSchemaWithOptions(preGenerated, schema.WithNoAdditionalProperties())
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"type":                 "object",
+		"additionalProperties": false,
+		"properties": map[string]any{
+			"properties": map[string]any{"type": "string"},
+			"nested": map[string]any{
+				"type":                 "object",
+				"additionalProperties": false,
+				"properties":           map[string]any{"value": map[string]any{"type": "string"}},
+				"default":              map[string]any{"value": "x"},
+			},
+		},
+	}, result)
+	assert.NotContains(t, preGenerated, "additionalProperties", "the input schema must not be modified")
+}
+
+func TestGenerateSchemaWithOptions_NoOptions(t *testing.T) {
+	t.Parallel()
+	type Plain struct {
+		Value string `json:"value"`
+	}
+
+	withOptions, err := schema.GenerateSchemaWithOptions(Plain{})
+	require.NoError(t, err)
+
+	plain, err := schema.GenerateSchema(Plain{})
+	require.NoError(t, err)
+
+	assert.Equal(t, plain, withOptions)
+}
+
+// unmarshalableSchema has a schema which cannot be encoded to JSON
+type unmarshalableSchema struct{}
+
+func (unmarshalableSchema) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{Type: "object", Default: func() {}}
+}
+
+func TestMustGenerateSchema(t *testing.T) {
+	t.Parallel()
+
+	generated := schema.MustGenerateSchema(benchmarkSchema{})
+	expected, err := schema.GenerateSchema(benchmarkSchema{})
+	require.NoError(t, err)
+	assert.Equal(t, expected, generated)
+
+	defer func() {
+		message, ok := recover().(string)
+		require.True(t, ok)
+		assert.True(t, strings.HasPrefix(message,
+			"schema: MustGenerateSchema(schema_test.unmarshalableSchema): cannot create schema from output type"))
+	}()
+	schema.MustGenerateSchema(unmarshalableSchema{})
+}
+
+func TestMustGenerateSchemaStr(t *testing.T) {
+	t.Parallel()
+
+	generated := schema.MustGenerateSchemaStr(benchmarkSchema{})
+	expected, err := schema.GenerateSchemaStr(benchmarkSchema{})
+	require.NoError(t, err)
+	assert.JSONEq(t, expected, generated)
+
+	assert.Panics(t, func() { schema.MustGenerateSchemaStr(unmarshalableSchema{}) })
+}
@@ -0,0 +1,254 @@
+// Package telemetry wires logging, OpenTelemetry tracing and Prometheus metrics of agents
+// in one place.
+//
+// Example:
+//
+//	telemetryConfig, err := telemetry.NewTelemetryConfig(
+//		telemetry.WithServiceName("support-bot"),
+//		telemetry.WithOtelEndpoint("http://localhost:4318"),
+//		telemetry.WithPrometheusPort(9464),
+//		telemetry.WithLogLevel(slog.LevelDebug),
+//	)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer telemetryConfig.Shutdown(context.Background())
+//
+//	supportAgent, err := agent.NewAgent(append(
+//		telemetry.AgentOptions[Answer](telemetryConfig),
+//		agent.WithName[Answer]("support_agent"),
+//		agent.WithLLMConfig[Answer](llmConfig),
+//	)...)
+//
+// Spans are exported over OTLP/HTTP, metrics are served at /metrics on the Prometheus port
+// and logs are written as JSON to stderr.
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+)
+
+const (
+	// DefaultServiceName is the service name used when WithServiceName is not set
+	DefaultServiceName = "go-agent"
+
+	maxPort              = 65535
+	metricsPath          = "/metrics"
+	metricsServerTimeout = 10 * time.Second
+)
+
+// ErrTelemetrySetup is returned when an exporter or the metrics server cannot be started
+var ErrTelemetrySetup = errors.New("failed to set up telemetry")
+
+// TelemetryConfig holds the logger, tracer provider and metrics registry shared by all agents of a
+// service. Create it with NewTelemetryConfig and release it with Shutdown.
+type TelemetryConfig struct {
+	serviceName    string
+	otelEndpoint   string
+	prometheusPort int
+	logLevel       slog.Level
+
+	logger         *slog.Logger
+	tracerProvider *sdktrace.TracerProvider
+	registry       *prometheus.Registry
+	metricsServer  *http.Server
+}
+
+// TelemetryOption configures a TelemetryConfig
+type TelemetryOption func(*TelemetryConfig)
+
+// WithServiceName sets the service name attached to logs, spans and metrics
+func WithServiceName(name string) TelemetryOption {
+	return func(c *TelemetryConfig) {
+		c.serviceName = name
+	}
+}
+
+// WithOtelEndpoint enables tracing and exports spans to the OTLP/HTTP collector at the URL,
+// e.g. http://localhost:4318
+func WithOtelEndpoint(endpoint string) TelemetryOption {
+	return func(c *TelemetryConfig) {
+		c.otelEndpoint = endpoint
+	}
+}
+
+// WithPrometheusPort enables metrics and serves them at /metrics on the port
+func WithPrometheusPort(port int) TelemetryOption {
+	return func(c *TelemetryConfig) {
+		c.prometheusPort = port
+	}
+}
+
+// WithLogLevel sets the minimum level of the agent logs. Defaults to slog.LevelInfo.
+func WithLogLevel(level slog.Level) TelemetryOption {
+	return func(c *TelemetryConfig) {
+		c.logLevel = level
+	}
+}
+
+// NewTelemetryConfig creates the logger and, when configured, the OTLP trace exporter and
+// the Prometheus metrics server. Tracing and metrics are disabled unless WithOtelEndpoint
+// and WithPrometheusPort are set.
+func NewTelemetryConfig(opts ...TelemetryOption) (*TelemetryConfig, error) {
+	cfg := &TelemetryConfig{
+		serviceName: DefaultServiceName,
+		logLevel:    slog.LevelInfo,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("failed to create telemetry config: %w", err)
+	}
+
+	cfg.logger = slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: cfg.logLevel}))
+
+	if cfg.otelEndpoint != "" {
+		if err := cfg.setUpTracing(); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.prometheusPort != 0 {
+		if err := cfg.setUpMetrics(); err != nil {
+			_ = cfg.Shutdown(context.Background())
+
+			return nil, err
+		}
+	}
+
+	return cfg, nil
+}
+
+func (c *TelemetryConfig) validate() error {
+	return validation.ValidateAll(
+		validation.WithPrefix("service name", func() error {
+			return validation.StringIsNotEmpty(c.serviceName)
+		}),
+		func() error {
+			return validation.IntIsInRange(c.prometheusPort, 0, maxPort, "prometheus port")
+		},
+		validation.WithPrefix("otel endpoint", func() error {
+			if c.otelEndpoint == "" {
+				return nil
+			}
+
+			endpoint, err := url.Parse(c.otelEndpoint)
+			if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+				return fmt.Errorf("%w: %q must be an http or https URL", validation.ErrValidationFailed, c.otelEndpoint)
+			}
+
+			return nil
+		}),
+	)
+}
+
+func (c *TelemetryConfig) setUpTracing() error {
+	exporter, err := otlptracehttp.New(context.Background(), otlptracehttp.WithEndpointURL(c.otelEndpoint))
+	if err != nil {
+		return fmt.Errorf("%w: OTLP exporter: %w", ErrTelemetrySetup, err)
+	}
+
+	c.tracerProvider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(c.serviceName))),
+	)
+
+	return nil
+}
+
+func (c *TelemetryConfig) setUpMetrics() error {
+	c.registry = prometheus.NewRegistry()
+
+	listener, err := net.Listen("tcp", ":"+strconv.Itoa(c.prometheusPort))
+	if err != nil {
+		return fmt.Errorf("%w: prometheus port %d: %w", ErrTelemetrySetup, c.prometheusPort, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(metricsPath, c.MetricsHandler())
+	c.metricsServer = &http.Server{Handler: mux, ReadHeaderTimeout: metricsServerTimeout}
+
+	go func() {
+		if err := c.metricsServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			c.logger.Error("prometheus metrics server failed", "port", c.prometheusPort, "error", err)
+		}
+	}()
+
+	return nil
+}
+
+// ObservabilityConfig returns the agent observability config with the logger, tracer
+// provider and metrics registry of the telemetry config
+func (c *TelemetryConfig) ObservabilityConfig() agent.ObservabilityConfig {
+	cfg := agent.ObservabilityConfig{
+		Logger:      c.logger,
+		ServiceName: c.serviceName,
+	}
+	if c.tracerProvider != nil {
+		cfg.TracerProvider = c.tracerProvider
+	}
+	if c.registry != nil {
+		cfg.MetricsRegisterer = c.registry
+	}
+
+	return cfg
+}
+
+// MetricsHandler returns the handler serving the Prometheus metrics, e.g. to expose them on
+// an existing HTTP server. It returns http.NotFoundHandler when metrics are disabled.
+func (c *TelemetryConfig) MetricsHandler() http.Handler {
+	if c.registry == nil {
+		return http.NotFoundHandler()
+	}
+
+	return promhttp.HandlerFor(c.registry, promhttp.HandlerOpts{})
+}
+
+// Shutdown stops the metrics server and flushes the spans which were not exported yet
+func (c *TelemetryConfig) Shutdown(ctx context.Context) error {
+	var errs []error
+
+	if c.metricsServer != nil {
+		if err := c.metricsServer.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to stop prometheus metrics server: %w", err))
+		}
+	}
+
+	if c.tracerProvider != nil {
+		if err := c.tracerProvider.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to shut down tracer provider: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// AgentOptions returns the agent options which enable the configured logging, tracing and
+// metrics. It is a function rather than a method of TelemetryConfig because Go methods
+// cannot have type parameters.
+func AgentOptions[T any](c *TelemetryConfig) []agent.AgentOption[T] {
+	return []agent.AgentOption[T]{
+		agent.WithObservabilityMiddleware[T](c.ObservabilityConfig()),
+	}
+}
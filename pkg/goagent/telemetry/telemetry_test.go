@@ -0,0 +1,169 @@
+package telemetry_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/telemetry"
+)
+
+type Answer struct {
+	Text string `json:"text" jsonschema_description:"The answer"`
+}
+
+// collector is a fake OTLP/HTTP collector which records the paths of the export requests
+type collector struct {
+	mu    sync.Mutex
+	paths []string
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	c.paths = append(c.paths, r.URL.Path)
+	c.mu.Unlock()
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (c *collector) Paths() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]string(nil), c.paths...)
+}
+
+func freePort(t *testing.T) int {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	port := listener.Addr().(*net.TCPAddr).Port
+	require.NoError(t, listener.Close())
+
+	return port
+}
+
+func runAgent(t *testing.T, telemetryConfig *telemetry.TelemetryConfig) {
+	t.Helper()
+
+	answerAgent, err := agent.NewAgent(append(
+		telemetry.AgentOptions[Answer](telemetryConfig),
+		agent.WithName[Answer]("telemetry_agent"),
+		agent.WithLLMConfig[Answer](llm.LLMConfig{Type: llm.LLMTypeOpenAI, APIKey: "test-key", Model: "gpt-4"}),
+		agent.WithBehavior[Answer]("You answer questions."),
+	)...)
+	require.NoError(t, err)
+
+	mockLLM := llmtest.NewMockLLM(`{"text": "42"}`, llm.LLMMessage{
+		Type:    llm.LLMMessageTypeAssistant,
+		Content: "done",
+		End:     true,
+	})
+
+	_, err = answerAgent.UsingLLM(mockLLM).Run(context.Background(), "What is the answer?")
+	require.NoError(t, err)
+}
+
+func TestNewTelemetryConfig(t *testing.T) {
+	t.Parallel()
+
+	// given
+	otlpCollector := &collector{}
+	otlpServer := httptest.NewServer(otlpCollector)
+	t.Cleanup(otlpServer.Close)
+
+	port := freePort(t)
+	telemetryConfig, err := telemetry.NewTelemetryConfig(
+		telemetry.WithServiceName("support_bot"),
+		telemetry.WithOtelEndpoint(otlpServer.URL),
+		telemetry.WithPrometheusPort(port),
+	)
+	require.NoError(t, err)
+
+	// when
+	runAgent(t, telemetryConfig)
+
+	// then
+	request, err := http.NewRequestWithContext(context.Background(), http.MethodGet,
+		"http://127.0.0.1:"+strconv.Itoa(port)+"/metrics", nil)
+	require.NoError(t, err)
+	response, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body),
+		`goagent_runs_total{agent="telemetry_agent",service="support_bot",status="success"} 1`)
+	assert.Contains(t, string(body), `goagent_llm_messages_total{agent="telemetry_agent",service="support_bot"} 1`)
+
+	require.NoError(t, telemetryConfig.Shutdown(context.Background()))
+	assert.Contains(t, otlpCollector.Paths(), "/v1/traces")
+}
+
+func TestNewTelemetryConfig_Defaults(t *testing.T) {
+	t.Parallel()
+
+	// when
+	telemetryConfig, err := telemetry.NewTelemetryConfig()
+
+	// then
+	require.NoError(t, err)
+
+	observability := telemetryConfig.ObservabilityConfig()
+	assert.Equal(t, telemetry.DefaultServiceName, observability.ServiceName)
+	assert.NotNil(t, observability.Logger)
+	assert.Nil(t, observability.TracerProvider)
+	assert.Nil(t, observability.MetricsRegisterer)
+	assert.Len(t, telemetry.AgentOptions[Answer](telemetryConfig), 1)
+
+	recorder := httptest.NewRecorder()
+	telemetryConfig.MetricsHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+	runAgent(t, telemetryConfig)
+	require.NoError(t, telemetryConfig.Shutdown(context.Background()))
+}
+
+func TestNewTelemetryConfig_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := telemetry.NewTelemetryConfig(
+		telemetry.WithOtelEndpoint("localhost:4318"),
+		telemetry.WithPrometheusPort(-1),
+	)
+
+	require.ErrorIs(t, err, validation.ErrMultiValidation)
+	assert.Contains(t, err.Error(), "otel endpoint")
+	assert.Contains(t, err.Error(), "prometheus port")
+}
+
+func TestNewTelemetryConfig_PortInUse(t *testing.T) {
+	t.Parallel()
+
+	// given
+	listener, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	// when
+	_, err = telemetry.NewTelemetryConfig(
+		telemetry.WithPrometheusPort(listener.Addr().(*net.TCPAddr).Port),
+	)
+
+	// then
+	require.ErrorIs(t, err, telemetry.ErrTelemetrySetup)
+}
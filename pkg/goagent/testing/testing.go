@@ -0,0 +1,90 @@
+// Package testing provides a table-driven runner for agent tests.
+//
+// Example:
+//
+//	func TestCalculatorAgent(t *testing.T) {
+//		agenttesting.RunAgentTests(t, calculatorAgent, []agenttesting.AgentTestCase[Result]{
+//			{
+//				Name:    "adds numbers",
+//				Input:   AddNumbers{Num1: 3, Num2: 5},
+//				MockLLM: llmtest.NewMockLLM(`{"sum": 8}`, llm.LLMMessage{
+//					Type: llm.LLMMessageTypeAssistant,
+//					End:  true,
+//				}),
+//				AssertResult: func(t *testing.T, result *agent.AgentResult[Result]) {
+//					assert.Equal(t, 8, result.Data.Sum)
+//				},
+//			},
+//		})
+//	}
+package testing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+)
+
+// DefaultTimeout is applied to test cases without an explicit Timeout
+const DefaultTimeout = 2 * time.Minute
+
+// AgentTestCase describes a single agent run and the assertions on its outcome
+type AgentTestCase[T any] struct {
+	Name  string
+	Input any
+	// AssertResult is called with the result when the run succeeds
+	AssertResult func(*testing.T, *agent.AgentResult[T])
+	// AssertError is called with the run error, which is nil when the run succeeds.
+	// When it is not set, any run error fails the test.
+	AssertError func(*testing.T, error)
+	// Timeout bounds the run. Defaults to DefaultTimeout.
+	Timeout time.Duration
+	// MockLLM replaces the agent's LLM for this case. When nil, the agent's own LLM is used.
+	MockLLM *llmtest.MockLLM
+}
+
+// RunAgentTests runs every case as a parallel subtest of t
+func RunAgentTests[T any](t *testing.T, a *agent.Agent[T], cases []AgentTestCase[T]) {
+	t.Helper()
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			runAgentTest(t, a, tc)
+		})
+	}
+}
+
+func runAgentTest[T any](t *testing.T, a *agent.Agent[T], tc AgentTestCase[T]) {
+	t.Helper()
+
+	if tc.MockLLM != nil {
+		a = a.UsingLLM(tc.MockLLM)
+	}
+
+	timeout := tc.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	result, err := a.Run(ctx, tc.Input)
+
+	switch {
+	case tc.AssertError != nil:
+		tc.AssertError(t, err)
+	case err != nil && ctx.Err() != nil:
+		t.Fatalf("agent run timed out after %s for input %+v: %v", timeout, tc.Input, err)
+	case err != nil:
+		t.Fatalf("agent run failed for input %+v: %v", tc.Input, err)
+	}
+
+	if err == nil && tc.AssertResult != nil {
+		tc.AssertResult(t, result)
+	}
+}
@@ -0,0 +1,56 @@
+package testing_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/agent"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llmtest"
+	agenttesting "github.com/vitalii-honchar/go-agent/pkg/goagent/testing"
+)
+
+type Answer struct {
+	Text string `json:"text" jsonschema_description:"The answer"`
+}
+
+func endMessage() llm.LLMMessage {
+	return llm.LLMMessage{Type: llm.LLMMessageTypeAssistant, Content: "done", End: true}
+}
+
+func TestRunAgentTests(t *testing.T) {
+	t.Parallel()
+
+	answerAgent, err := agent.NewAgent(
+		agent.WithName[Answer]("answer_agent"),
+		agent.WithLLMConfig[Answer](llm.LLMConfig{
+			Type:   llm.LLMTypeOpenAI,
+			APIKey: "test-api-key",
+			Model:  "gpt-4",
+		}),
+		agent.WithBehavior[Answer]("You answer questions."),
+	)
+	require.NoError(t, err)
+
+	agenttesting.RunAgentTests(t, answerAgent, []agenttesting.AgentTestCase[Answer]{
+		{
+			Name:    "result",
+			Input:   "What is 2+2?",
+			MockLLM: llmtest.NewMockLLM(`{"text": "4"}`, endMessage()),
+			AssertResult: func(t *testing.T, result *agent.AgentResult[Answer]) {
+				t.Helper()
+				assert.Equal(t, "4", result.Data.Text)
+			},
+		},
+		{
+			Name:    "error",
+			Input:   "What is 2+2?",
+			MockLLM: llmtest.NewMockLLM(`{"text": "4"}`),
+			AssertError: func(t *testing.T, err error) {
+				t.Helper()
+				require.ErrorIs(t, err, agent.ErrLLMCall)
+			},
+		},
+	})
+}
@@ -0,0 +1,242 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+// Language is a programming language supported by the code execution tool
+type Language string
+
+const (
+	// LanguageGo runs Go programs with package main
+	LanguageGo Language = "go"
+	// LanguagePython runs Python 3 scripts
+	LanguagePython Language = "python"
+)
+
+const (
+	// CodeExecutionToolName is the name of the tool created by NewCodeExecutionTool
+	CodeExecutionToolName = "code_execution"
+
+	codeMemoryLimitBytes = 512 << 20
+	codeFilePermissions  = 0o600
+	// goRuntimeReservation is address space the Go runtime reserves at startup without using it
+	goRuntimeReservation = 1 << 30
+)
+
+var (
+	// ErrUnsupportedLanguage is returned for a language the code execution tool can't run
+	ErrUnsupportedLanguage = errors.New("unsupported language")
+	// ErrCodeExecutionTimeout is returned when the code does not finish within the timeout
+	ErrCodeExecutionTimeout = errors.New("code execution timed out")
+	// ErrSandboxUnavailable is returned on platforms where resource limits can't be applied
+	ErrSandboxUnavailable = errors.New("code execution sandbox unavailable")
+)
+
+// CodeExecutionParams are the parameters of the code execution tool
+type CodeExecutionParams struct {
+	Code string `json:"code" jsonschema_description:"Complete source code of the program to run"`
+}
+
+// CodeExecutionResult is the result of the code execution tool
+type CodeExecutionResult struct {
+	llm.BaseLLMToolResult
+	Stdout   string `json:"stdout"`
+	Stderr   string `json:"stderr"`
+	ExitCode int    `json:"exit_code"`
+}
+
+// NewCodeExecutionTool creates a code_execution tool which runs Go or Python programs written
+// by the LLM. Every run happens in a new temporary directory which is removed afterwards, with
+// an environment that contains only PATH and HOME, so secrets in environment variables of the
+// host process are not visible to the code. The program is killed after timeout, and its CPU
+// time (RLIMIT_CPU) and address space (RLIMIT_AS, 512 MiB) are limited. Go programs get
+// additional address space for the reservations of the Go runtime, their heap is limited
+// to 512 MiB with GOMEMLIMIT instead.
+//
+// Go programs are compiled with go build before they run, so a compilation error is returned
+// as stderr with a non-zero exit code, and the limits apply to the program, not the compiler.
+// The timeout covers both compilation and the run. The tool is available on Unix systems only.
+//
+// The limits protect the host from runaway programs, they are not a security boundary:
+// run the agent in a container or VM when it executes untrusted code.
+func NewCodeExecutionTool(lang Language, timeout time.Duration) (llm.LLMTool, error) {
+	if timeout <= 0 {
+		return llm.LLMTool{}, fmt.Errorf("timeout: %w: must be positive, got %s",
+			validation.ErrValidationFailed, timeout)
+	}
+
+	if err := checkSandbox(); err != nil {
+		return llm.LLMTool{}, err
+	}
+
+	runner, err := newCodeRunner(lang, timeout)
+	if err != nil {
+		return llm.LLMTool{}, err
+	}
+
+	return llm.NewLLMTool(
+		llm.WithLLMToolName(CodeExecutionToolName),
+		llm.WithLLMToolDescription(runner.description()),
+		llm.WithLLMToolTags(map[string]string{"category": "code"}),
+		llm.WithLLMToolParametersSchema[CodeExecutionParams](),
+		llm.WithLLMToolCall(func(id string, params CodeExecutionParams) (CodeExecutionResult, error) {
+			if err := validation.StringIsNotEmpty(params.Code); err != nil {
+				return CodeExecutionResult{}, fmt.Errorf("code: %w", err)
+			}
+
+			result, err := runner.run(params.Code)
+			if err != nil {
+				return CodeExecutionResult{}, err
+			}
+			result.ID = id
+
+			return result, nil
+		}),
+	)
+}
+
+type codeRunner struct {
+	lang    Language
+	binary  string
+	timeout time.Duration
+}
+
+func newCodeRunner(lang Language, timeout time.Duration) (*codeRunner, error) {
+	var name string
+	switch lang {
+	case LanguageGo:
+		name = "go"
+	case LanguagePython:
+		name = "python3"
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedLanguage, lang)
+	}
+
+	binary, err := exec.LookPath(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s is not installed: %w", ErrUnsupportedLanguage, lang, err)
+	}
+
+	return &codeRunner{lang: lang, binary: binary, timeout: timeout}, nil
+}
+
+func (r *codeRunner) description() string {
+	if r.lang == LanguageGo {
+		return fmt.Sprintf("Runs a Go program (package main with func main) and returns stdout, stderr "+
+			"and the exit code. Only the standard library is available. Time limit: %s", r.timeout)
+	}
+
+	return fmt.Sprintf("Runs a Python 3 script and returns stdout, stderr and the exit code. "+
+		"Only the standard library is available. Time limit: %s", r.timeout)
+}
+
+func (r *codeRunner) run(code string) (CodeExecutionResult, error) {
+	dir, err := os.MkdirTemp("", "goagent-code-")
+	if err != nil {
+		return CodeExecutionResult{}, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+
+	program, failure, err := r.prepare(ctx, dir, code)
+	if err != nil || failure != nil {
+		return derefResult(failure), err
+	}
+
+	cmd, err := sandboxedCommand(ctx, program, r.timeout, r.memoryLimit())
+	if err != nil {
+		return CodeExecutionResult{}, err
+	}
+	cmd.Dir = dir
+	cmd.Env = []string{"PATH=" + os.Getenv("PATH"), "HOME=" + dir}
+	if r.lang == LanguageGo {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("GOMEMLIMIT=%d", codeMemoryLimitBytes))
+	}
+
+	return execute(ctx, cmd, r.timeout)
+}
+
+func (r *codeRunner) memoryLimit() int {
+	if r.lang == LanguageGo {
+		return codeMemoryLimitBytes + goRuntimeReservation
+	}
+
+	return codeMemoryLimitBytes
+}
+
+// prepare writes the code to dir and returns the command line which runs it.
+// When a Go program fails to compile, the compiler output is returned as the result.
+func (r *codeRunner) prepare(ctx context.Context, dir, code string) ([]string, *CodeExecutionResult, error) {
+	if r.lang == LanguagePython {
+		script := filepath.Join(dir, "main.py")
+		if err := os.WriteFile(script, []byte(code), codeFilePermissions); err != nil {
+			return nil, nil, fmt.Errorf("failed to write code: %w", err)
+		}
+
+		return []string{r.binary, "-I", script}, nil, nil
+	}
+
+	source := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(source, []byte(code), codeFilePermissions); err != nil {
+		return nil, nil, fmt.Errorf("failed to write code: %w", err)
+	}
+
+	program := filepath.Join(dir, "main")
+	build := exec.CommandContext(ctx, r.binary, "build", "-o", program, source) //nolint:gosec // fixed arguments
+	build.Dir = dir
+	build.Env = append(os.Environ(), "GO111MODULE=off", "CGO_ENABLED=0")
+
+	result, err := execute(ctx, build, r.timeout)
+	if err != nil {
+		return nil, nil, err
+	}
+	if result.ExitCode != 0 {
+		return nil, &result, nil
+	}
+
+	return []string{program}, nil, nil
+}
+
+func execute(ctx context.Context, cmd *exec.Cmd, timeout time.Duration) (CodeExecutionResult, error) {
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if ctx.Err() != nil {
+		return CodeExecutionResult{}, fmt.Errorf("%w after %s", ErrCodeExecutionTimeout, timeout)
+	}
+
+	result := CodeExecutionResult{Stdout: stdout.String(), Stderr: stderr.String()}
+
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+	case err != nil:
+		return CodeExecutionResult{}, fmt.Errorf("failed to run code: %w", err)
+	}
+
+	return result, nil
+}
+
+func derefResult(result *CodeExecutionResult) CodeExecutionResult {
+	if result == nil {
+		return CodeExecutionResult{}
+	}
+
+	return *result
+}
@@ -0,0 +1,19 @@
+//go:build !unix
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"time"
+)
+
+func checkSandbox() error {
+	return fmt.Errorf("%w: resource limits are not supported on %s", ErrSandboxUnavailable, runtime.GOOS)
+}
+
+func sandboxedCommand(_ context.Context, _ []string, _ time.Duration, _ int) (*exec.Cmd, error) {
+	return nil, checkSandbox()
+}
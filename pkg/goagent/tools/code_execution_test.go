@@ -0,0 +1,144 @@
+//go:build unix
+
+package tools_test
+
+import (
+	"encoding/json"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/tools"
+)
+
+const codeExecutionTimeout = 30 * time.Second
+
+func runCode(t *testing.T, lang tools.Language, timeout time.Duration, code string) (tools.CodeExecutionResult, error) {
+	t.Helper()
+
+	tool, err := tools.NewCodeExecutionTool(lang, timeout)
+	if err != nil {
+		t.Skipf("%s is not available: %v", lang, err)
+	}
+
+	args, err := json.Marshal(tools.CodeExecutionParams{Code: code})
+	require.NoError(t, err)
+
+	result, err := tool.Call("call-1", string(args))
+	if err != nil {
+		return tools.CodeExecutionResult{}, err
+	}
+
+	codeResult, ok := result.(tools.CodeExecutionResult)
+	require.True(t, ok)
+
+	return codeResult, nil
+}
+
+func TestCodeExecutionTool_Python(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		code             string
+		expectedStdout   string
+		expectedStderr   string
+		expectedExitCode int
+	}{
+		{"stdout", "print(sum(range(10)))", "45\n", "", 0},
+		{"stderr and exit code", "import sys\nprint('failed', file=sys.stderr)\nsys.exit(3)", "", "failed\n", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := runCode(t, tools.LanguagePython, codeExecutionTimeout, tt.code)
+
+			require.NoError(t, err)
+			assert.Equal(t, "call-1", result.GetID())
+			assert.Equal(t, tt.expectedStdout, result.Stdout)
+			assert.Equal(t, tt.expectedStderr, result.Stderr)
+			assert.Equal(t, tt.expectedExitCode, result.ExitCode)
+		})
+	}
+}
+
+//nolint:paralleltest // t.Setenv cannot be used in parallel tests
+func TestCodeExecutionTool_IsolatedEnvironment(t *testing.T) {
+	t.Setenv("GOAGENT_TEST_SECRET", "secret")
+
+	result, err := runCode(t, tools.LanguagePython, codeExecutionTimeout,
+		"import os\nprint(os.environ.get('GOAGENT_TEST_SECRET', 'none'))")
+
+	require.NoError(t, err)
+	assert.Equal(t, "none\n", result.Stdout)
+}
+
+func TestCodeExecutionTool_Go(t *testing.T) {
+	t.Parallel()
+
+	code := `package main
+
+import "fmt"
+
+func main() {
+	fmt.Println(6 * 7)
+}
+`
+
+	result, err := runCode(t, tools.LanguageGo, codeExecutionTimeout, code)
+
+	require.NoError(t, err)
+	assert.Equal(t, "42\n", result.Stdout)
+	assert.Equal(t, 0, result.ExitCode)
+}
+
+func TestCodeExecutionTool_GoCompileError(t *testing.T) {
+	t.Parallel()
+
+	result, err := runCode(t, tools.LanguageGo, codeExecutionTimeout, "package main\n\nfunc main() { undefined() }\n")
+
+	require.NoError(t, err)
+	assert.NotEqual(t, 0, result.ExitCode)
+	assert.Contains(t, result.Stderr, "undefined")
+}
+
+func TestCodeExecutionTool_Timeout(t *testing.T) {
+	t.Parallel()
+
+	_, err := runCode(t, tools.LanguagePython, time.Second, "while True:\n    pass")
+
+	require.ErrorIs(t, err, tools.ErrCodeExecutionTimeout)
+}
+
+func TestCodeExecutionTool_MemoryLimit(t *testing.T) {
+	t.Parallel()
+
+	result, err := runCode(t, tools.LanguagePython, codeExecutionTimeout, "data = bytearray(1024 * 1024 * 1024)")
+
+	require.NoError(t, err)
+	assert.NotEqual(t, 0, result.ExitCode)
+	assert.Contains(t, result.Stderr, "MemoryError")
+}
+
+func TestNewCodeExecutionTool_Validation(t *testing.T) {
+	t.Parallel()
+
+	_, err := tools.NewCodeExecutionTool(tools.LanguageGo, 0)
+	require.ErrorIs(t, err, validation.ErrValidationFailed)
+
+	_, err = tools.NewCodeExecutionTool("ruby", time.Second)
+	require.ErrorIs(t, err, tools.ErrUnsupportedLanguage)
+
+	if _, lookErr := exec.LookPath("python3"); lookErr == nil {
+		tool, err := tools.NewCodeExecutionTool(tools.LanguagePython, time.Second)
+		require.NoError(t, err)
+
+		_, err = tool.Call("call-1", `{"code":""}`)
+		require.ErrorIs(t, err, validation.ErrValidationFailed)
+	}
+}
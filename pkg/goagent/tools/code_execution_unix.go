@@ -0,0 +1,41 @@
+//go:build unix
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// limitsScript sets RLIMIT_CPU (ulimit -t, seconds) and RLIMIT_AS (ulimit -v, KiB) in the
+// shell, which then replaces itself with the program, so the limits apply only to the child
+const limitsScript = `ulimit -t %d && ulimit -v %d && exec "$@"`
+
+func checkSandbox() error {
+	return nil
+}
+
+// sandboxedCommand returns a command which runs program with CPU time and memory limits.
+// The program runs in its own process group, which is killed as a whole on timeout.
+func sandboxedCommand(
+	ctx context.Context,
+	program []string,
+	cpuTime time.Duration,
+	memoryBytes int,
+) (*exec.Cmd, error) {
+	cpuSeconds := int(math.Ceil(cpuTime.Seconds()))
+	script := fmt.Sprintf(limitsScript, cpuSeconds, memoryBytes>>10)
+
+	args := append([]string{"-c", script, "sh"}, program...)
+	cmd := exec.CommandContext(ctx, "/bin/sh", args...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+
+	return cmd, nil
+}
@@ -0,0 +1,291 @@
+package tools
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pingcap/tidb/pkg/parser"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	// Registers the value expression implementation required by the standalone parser
+	_ "github.com/pingcap/tidb/pkg/parser/test_driver"
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+const (
+	// DatabaseQueryToolName is the name of the tool created by NewDatabaseQueryTool
+	DatabaseQueryToolName = "database_query"
+
+	databaseQueryTimeout = 30 * time.Second
+	// databaseMaxRows caps the rows returned to the LLM, so one query can't flood its context
+	databaseMaxRows = 100
+)
+
+var (
+	// ErrQueryNotAllowed is returned when a query is not a single read-only SELECT statement
+	ErrQueryNotAllowed = errors.New("query not allowed: only read-only SELECT statements are permitted")
+	// ErrTableNotAllowed is returned when a query references a table which is not allowed
+	ErrTableNotAllowed = errors.New("table not allowed")
+	// ErrQueryTimeout is returned when a query does not finish in time
+	ErrQueryTimeout = errors.New("query timed out")
+)
+
+// DatabaseQueryParams are the parameters of the database query tool
+type DatabaseQueryParams struct {
+	Query  string `json:"query" jsonschema_description:"SQL SELECT query, use ? placeholders for parameters"`
+	Params []any  `json:"params" jsonschema_description:"Values for the ? placeholders in the query, in order"`
+}
+
+// DatabaseQueryResult is the result of the database query tool
+type DatabaseQueryResult struct {
+	llm.BaseLLMToolResult
+	Rows []map[string]any `json:"rows"`
+	// Truncated is true when the query returned more than 100 rows and only the first 100
+	// are included
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// NewDatabaseQueryTool creates a database_query tool which runs read-only SQL queries.
+// Every query is parsed before execution: only a single SELECT statement (including
+// UNION and WITH) is accepted, locking reads and SELECT ... INTO are rejected, and every
+// referenced table must be in allowedTables. A table can be allowed by name or as
+// schema.table. Queries are cancelled after 30 seconds and return at most 100 rows.
+//
+// The parser understands the MySQL dialect. Still run the tool with a read-only database
+// user: the check protects against mistakes of the LLM, not against a hostile database.
+func NewDatabaseQueryTool(db *sql.DB, allowedTables []string) (llm.LLMTool, error) {
+	if db == nil {
+		return llm.LLMTool{}, fmt.Errorf("db: %w: value cannot be nil", validation.ErrValidationFailed)
+	}
+	if len(allowedTables) == 0 {
+		return llm.LLMTool{}, fmt.Errorf("allowed tables: %w: at least one table is required",
+			validation.ErrValidationFailed)
+	}
+
+	allowed := make(map[string]bool, len(allowedTables))
+	for _, table := range allowedTables {
+		if err := validation.StringIsNotEmpty(table); err != nil {
+			return llm.LLMTool{}, fmt.Errorf("allowed tables: %w", err)
+		}
+		allowed[strings.ToLower(table)] = true
+	}
+
+	description := "Runs a read-only SQL SELECT query and returns the rows. Allowed tables: " +
+		strings.Join(allowedTables, ", ")
+
+	return llm.NewLLMTool(
+		llm.WithLLMToolName(DatabaseQueryToolName),
+		llm.WithLLMToolDescription(description),
+		llm.WithLLMToolTags(map[string]string{"category": "database"}),
+		llm.WithLLMToolParametersSchema[DatabaseQueryParams](),
+		llm.WithLLMToolCall(func(id string, params DatabaseQueryParams) (DatabaseQueryResult, error) {
+			if err := checkReadOnlyQuery(params.Query, allowed); err != nil {
+				return DatabaseQueryResult{}, err
+			}
+
+			rows, truncated, err := queryRows(db, params.Query, params.Params)
+			if err != nil {
+				return DatabaseQueryResult{}, err
+			}
+
+			return DatabaseQueryResult{
+				BaseLLMToolResult: llm.BaseLLMToolResult{ID: id},
+				Rows:              rows,
+				Truncated:         truncated,
+			}, nil
+		}),
+	)
+}
+
+func checkReadOnlyQuery(query string, allowed map[string]bool) error {
+	if err := validation.StringIsNotEmpty(query); err != nil {
+		return fmt.Errorf("query: %w", err)
+	}
+
+	stmts, _, err := parser.New().Parse(query, "", "")
+	if err != nil {
+		return fmt.Errorf("%w: failed to parse query: %w", ErrQueryNotAllowed, err)
+	}
+	if len(stmts) != 1 {
+		return fmt.Errorf("%w: expected one statement, got %d", ErrQueryNotAllowed, len(stmts))
+	}
+
+	switch stmts[0].(type) {
+	case *ast.SelectStmt, *ast.SetOprStmt:
+	default:
+		return ErrQueryNotAllowed
+	}
+
+	checker := &queryChecker{allowed: allowed}
+	stmts[0].Accept(checker)
+
+	return checker.err
+}
+
+// queryChecker walks the query AST and records the first disallowed construct
+type queryChecker struct {
+	allowed map[string]bool
+	// cteScopes holds the CTE names visible at the current node, one scope per statement
+	// with a WITH clause, so a CTE declared in a subquery does not hide a table outside of it
+	cteScopes []map[string]bool
+	err       error
+}
+
+func (c *queryChecker) Enter(node ast.Node) (ast.Node, bool) {
+	switch n := node.(type) {
+	case *ast.SelectStmt:
+		if n.With != nil {
+			c.pushCTEScope()
+		}
+		if n.LockInfo != nil && n.LockInfo.LockType != ast.SelectLockNone {
+			c.fail(fmt.Errorf("%w: locking reads are not allowed", ErrQueryNotAllowed))
+		}
+		if n.SelectIntoOpt != nil {
+			c.fail(fmt.Errorf("%w: SELECT ... INTO is not allowed", ErrQueryNotAllowed))
+		}
+	case *ast.SetOprStmt:
+		if n.With != nil {
+			c.pushCTEScope()
+		}
+	case *ast.CommonTableExpression:
+		// a recursive CTE refers to itself, other CTEs only see the ones declared before them
+		if n.IsRecursive {
+			c.declareCTE(n.Name.L)
+		}
+	case *ast.TableName:
+		c.checkTable(n)
+	}
+
+	return node, c.err != nil
+}
+
+func (c *queryChecker) Leave(node ast.Node) (ast.Node, bool) {
+	switch n := node.(type) {
+	case *ast.SelectStmt:
+		if n.With != nil {
+			c.popCTEScope()
+		}
+	case *ast.SetOprStmt:
+		if n.With != nil {
+			c.popCTEScope()
+		}
+	case *ast.CommonTableExpression:
+		c.declareCTE(n.Name.L)
+	}
+
+	return node, c.err == nil
+}
+
+func (c *queryChecker) pushCTEScope() {
+	c.cteScopes = append(c.cteScopes, make(map[string]bool))
+}
+
+func (c *queryChecker) popCTEScope() {
+	c.cteScopes = c.cteScopes[:len(c.cteScopes)-1]
+}
+
+// declareCTE adds the CTE to the scope of the statement whose WITH clause declares it
+func (c *queryChecker) declareCTE(name string) {
+	c.cteScopes[len(c.cteScopes)-1][name] = true
+}
+
+func (c *queryChecker) isCTE(name string) bool {
+	for _, scope := range c.cteScopes {
+		if scope[name] {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *queryChecker) checkTable(table *ast.TableName) {
+	name := table.Name.L
+	if table.Schema.L == "" && c.isCTE(name) {
+		return
+	}
+
+	if c.allowed[name] || (table.Schema.L != "" && c.allowed[table.Schema.L+"."+name]) {
+		return
+	}
+
+	qualified := table.Name.O
+	if table.Schema.O != "" {
+		qualified = table.Schema.O + "." + qualified
+	}
+	c.fail(fmt.Errorf("%w: %s", ErrTableNotAllowed, qualified))
+}
+
+func (c *queryChecker) fail(err error) {
+	if c.err == nil {
+		c.err = err
+	}
+}
+
+// queryRows returns the first databaseMaxRows rows of the query and whether there were more
+func queryRows(db *sql.DB, query string, params []any) ([]map[string]any, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), databaseQueryTimeout)
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx, query, params...)
+	if err != nil {
+		return nil, false, queryError(err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read columns: %w", err)
+	}
+
+	result := make([]map[string]any, 0)
+	for rows.Next() {
+		if len(result) == databaseMaxRows {
+			return result, true, nil
+		}
+
+		values := make([]any, len(columns))
+		pointers := make([]any, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, false, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		row := make(map[string]any, len(columns))
+		for i, column := range columns {
+			row[column] = jsonValue(values[i])
+		}
+		result = append(result, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, false, queryError(err)
+	}
+
+	return result, false, nil
+}
+
+func queryError(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w after %s", ErrQueryTimeout, databaseQueryTimeout)
+	}
+
+	return fmt.Errorf("failed to execute query: %w", err)
+}
+
+// jsonValue converts values returned by database drivers to JSON friendly ones:
+// text columns are often returned as []byte, which would be encoded as base64
+func jsonValue(value any) any {
+	if bytes, ok := value.([]byte); ok {
+		return string(bytes)
+	}
+
+	return value
+}
@@ -0,0 +1,168 @@
+package tools_test
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/tools"
+)
+
+func newDatabaseQueryTool(t *testing.T) (llm.LLMTool, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	tool, err := tools.NewDatabaseQueryTool(db, []string{"users", "sales.orders"})
+	require.NoError(t, err)
+
+	return tool, mock
+}
+
+func TestDatabaseQueryTool_Select(t *testing.T) {
+	t.Parallel()
+
+	// given
+	tool, mock := newDatabaseQueryTool(t)
+	query := "SELECT id, name FROM users WHERE age > ?"
+	mock.ExpectQuery(query).
+		WithArgs(float64(30)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+			AddRow(int64(1), []byte("Alice")).
+			AddRow(int64(2), "Bob"))
+
+	// when
+	result, err := tool.Call("call-1", `{"query":"SELECT id, name FROM users WHERE age > ?","params":[30]}`)
+
+	// then
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	queryResult, ok := result.(tools.DatabaseQueryResult)
+	require.True(t, ok)
+	assert.Equal(t, "call-1", queryResult.GetID())
+	assert.Equal(t, []map[string]any{
+		{"id": int64(1), "name": "Alice"},
+		{"id": int64(2), "name": "Bob"},
+	}, queryResult.Rows)
+}
+
+func TestDatabaseQueryTool_TruncatesRows(t *testing.T) {
+	t.Parallel()
+
+	// given
+	tool, mock := newDatabaseQueryTool(t)
+	rows := sqlmock.NewRows([]string{"id"})
+	for i := range 150 {
+		rows.AddRow(int64(i))
+	}
+	mock.ExpectQuery("SELECT id FROM users").WillReturnRows(rows)
+
+	// when
+	result, err := tool.Call("call-1", `{"query":"SELECT id FROM users"}`)
+
+	// then
+	require.NoError(t, err)
+	queryResult, ok := result.(tools.DatabaseQueryResult)
+	require.True(t, ok)
+	assert.Len(t, queryResult.Rows, 100)
+	assert.True(t, queryResult.Truncated)
+}
+
+func TestDatabaseQueryTool_AllowedQueries(t *testing.T) {
+	t.Parallel()
+
+	tests := []string{
+		"SELECT * FROM users",
+		"SELECT * FROM USERS u JOIN sales.orders o ON o.user_id = u.id",
+		"SELECT id FROM users UNION SELECT user_id FROM sales.orders",
+		"WITH active AS (SELECT * FROM users WHERE active = 1) SELECT * FROM active",
+		"WITH active AS (SELECT * FROM users), recent AS (SELECT * FROM active) SELECT * FROM recent",
+		"WITH RECURSIVE seq AS (SELECT 1 AS n UNION ALL SELECT n + 1 FROM seq WHERE n < 5) SELECT * FROM seq",
+		"SELECT * FROM users WHERE id IN (WITH paid AS (SELECT * FROM sales.orders) SELECT user_id FROM paid)",
+		"SELECT * FROM users WHERE id IN (SELECT user_id FROM sales.orders)",
+	}
+
+	for _, query := range tests {
+		t.Run(query, func(t *testing.T) {
+			t.Parallel()
+
+			tool, mock := newDatabaseQueryTool(t)
+			mock.ExpectQuery(query).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+			_, err := tool.Call("call-1", `{"query":"`+query+`"}`)
+
+			require.NoError(t, err)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDatabaseQueryTool_RejectedQueries(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		query       string
+		expectedErr error
+	}{
+		{"insert", "INSERT INTO users (name) VALUES ('Eve')", tools.ErrQueryNotAllowed},
+		{"update", "UPDATE users SET name = 'Eve'", tools.ErrQueryNotAllowed},
+		{"delete", "DELETE FROM users", tools.ErrQueryNotAllowed},
+		{"drop", "DROP TABLE users", tools.ErrQueryNotAllowed},
+		{"alter", "ALTER TABLE users ADD COLUMN age INT", tools.ErrQueryNotAllowed},
+		{"multiple statements", "SELECT * FROM users; DELETE FROM users", tools.ErrQueryNotAllowed},
+		{"locking read", "SELECT * FROM users FOR UPDATE", tools.ErrQueryNotAllowed},
+		{"select into", "SELECT * FROM users INTO OUTFILE '/tmp/users'", tools.ErrQueryNotAllowed},
+		{"invalid sql", "SELEC * FROM users", tools.ErrQueryNotAllowed},
+		{"not allowed table", "SELECT * FROM secrets", tools.ErrTableNotAllowed},
+		{"not allowed schema", "SELECT * FROM billing.orders", tools.ErrTableNotAllowed},
+		{"not allowed subquery", "SELECT * FROM users WHERE id IN (SELECT id FROM secrets)", tools.ErrTableNotAllowed},
+		{
+			"cte declared in a subquery",
+			"SELECT (WITH secrets AS (SELECT 1) SELECT * FROM secrets) AS x, s.* FROM secrets s",
+			tools.ErrTableNotAllowed,
+		},
+		{
+			"cte in its own body",
+			"WITH secrets AS (SELECT * FROM secrets) SELECT * FROM secrets",
+			tools.ErrTableNotAllowed,
+		},
+		{"empty query", "", validation.ErrValidationFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tool, mock := newDatabaseQueryTool(t)
+
+			_, err := tool.Call("call-1", `{"query":"`+tt.query+`"}`)
+
+			require.ErrorIs(t, err, tt.expectedErr)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestNewDatabaseQueryTool_Validation(t *testing.T) {
+	t.Parallel()
+
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = tools.NewDatabaseQueryTool(nil, []string{"users"})
+	require.ErrorIs(t, err, validation.ErrValidationFailed)
+
+	_, err = tools.NewDatabaseQueryTool(db, nil)
+	require.ErrorIs(t, err, validation.ErrValidationFailed)
+
+	_, err = tools.NewDatabaseQueryTool(db, []string{""})
+	require.ErrorIs(t, err, validation.ErrValidationFailed)
+}
@@ -0,0 +1,253 @@
+package tools
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+const (
+	// FilesystemToolName is the name of the tool created by NewFilesystemTool
+	FilesystemToolName = "filesystem"
+
+	// FilesystemOperationReadFile reads a file
+	FilesystemOperationReadFile = "read_file"
+	// FilesystemOperationListDir lists a directory
+	FilesystemOperationListDir = "list_dir"
+	// FilesystemOperationWriteFile writes a file, only available when writing is enabled
+	FilesystemOperationWriteFile = "write_file"
+
+	writtenFilePermissions = 0o600
+)
+
+var (
+	// ErrPathNotAllowed is returned when a path is outside of the allowed paths
+	ErrPathNotAllowed = errors.New("path not allowed")
+	// ErrUnsupportedOperation is returned for an unknown or disabled filesystem operation
+	ErrUnsupportedOperation = errors.New("unsupported operation")
+)
+
+// FilesystemParams are the parameters of the filesystem tool
+type FilesystemParams struct {
+	Operation string `json:"operation" jsonschema:"enum=read_file,enum=list_dir,enum=write_file"`
+	Path      string `json:"path" jsonschema_description:"Path of the file or directory"`
+	Content   string `json:"content,omitempty" jsonschema_description:"Content to write, only for write_file"`
+}
+
+// FileEntry describes a directory entry returned by list_dir
+type FileEntry struct {
+	Name  string `json:"name"`
+	IsDir bool   `json:"is_dir"`
+	Size  int64  `json:"size"`
+}
+
+// FilesystemResult is the result of the filesystem tool
+type FilesystemResult struct {
+	llm.BaseLLMToolResult
+	Content      string      `json:"content,omitempty"`
+	Entries      []FileEntry `json:"entries,omitempty"`
+	BytesWritten int         `json:"bytes_written,omitempty"`
+}
+
+// NewFilesystemTool creates a filesystem tool which reads files and lists directories inside
+// allowedPaths and, when writeEnabled is true, writes files there. Relative paths are resolved
+// against the first allowed path. The directories of a path are resolved with symlinks evaluated
+// before the path is checked, and a symlink as the last element is rejected, so neither ../
+// segments nor symlinks can escape the allowed directories. Rejected paths return ErrPathNotAllowed.
+func NewFilesystemTool(allowedPaths []string, writeEnabled bool) (llm.LLMTool, error) {
+	if len(allowedPaths) == 0 {
+		return llm.LLMTool{}, fmt.Errorf("allowed paths: %w: at least one path is required",
+			validation.ErrValidationFailed)
+	}
+
+	roots := make([]string, 0, len(allowedPaths))
+	for _, path := range allowedPaths {
+		if err := validation.StringIsNotEmpty(path); err != nil {
+			return llm.LLMTool{}, fmt.Errorf("allowed paths: %w", err)
+		}
+
+		root, err := resolvePath(path)
+		if err != nil {
+			return llm.LLMTool{}, fmt.Errorf("allowed paths: %w", err)
+		}
+		roots = append(roots, root)
+	}
+
+	fs := &filesystem{roots: roots, writeEnabled: writeEnabled}
+
+	return llm.NewLLMTool(
+		llm.WithLLMToolName(FilesystemToolName),
+		llm.WithLLMToolDescription(fs.description(allowedPaths)),
+		llm.WithLLMToolTags(map[string]string{"category": "filesystem"}),
+		llm.WithLLMToolParametersSchema[FilesystemParams](),
+		llm.WithLLMToolCall(func(id string, params FilesystemParams) (FilesystemResult, error) {
+			result, err := fs.run(params)
+			if err != nil {
+				return FilesystemResult{}, err
+			}
+			result.ID = id
+
+			return result, nil
+		}),
+	)
+}
+
+type filesystem struct {
+	roots        []string
+	writeEnabled bool
+}
+
+func (f *filesystem) description(allowedPaths []string) string {
+	operations := FilesystemOperationReadFile + ", " + FilesystemOperationListDir
+	if f.writeEnabled {
+		operations += ", " + FilesystemOperationWriteFile
+	}
+
+	return fmt.Sprintf("Works with local files. Operations: %s. Allowed paths: %s",
+		operations, strings.Join(allowedPaths, ", "))
+}
+
+func (f *filesystem) run(params FilesystemParams) (FilesystemResult, error) {
+	if err := validation.StringIsNotEmpty(params.Path); err != nil {
+		return FilesystemResult{}, fmt.Errorf("path: %w", err)
+	}
+
+	switch params.Operation {
+	case FilesystemOperationReadFile:
+		return f.readFile(params.Path)
+	case FilesystemOperationListDir:
+		return f.listDir(params.Path)
+	case FilesystemOperationWriteFile:
+		if !f.writeEnabled {
+			return FilesystemResult{}, fmt.Errorf("%w: writing is disabled", ErrUnsupportedOperation)
+		}
+
+		return f.writeFile(params.Path, params.Content)
+	default:
+		return FilesystemResult{}, fmt.Errorf("%w: %s", ErrUnsupportedOperation, params.Operation)
+	}
+}
+
+func (f *filesystem) readFile(path string) (FilesystemResult, error) {
+	resolved, err := f.allowedPath(path)
+	if err != nil {
+		return FilesystemResult{}, err
+	}
+
+	content, err := os.ReadFile(resolved) //nolint:gosec // resolved is checked against the allowed paths
+	if err != nil {
+		return FilesystemResult{}, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	return FilesystemResult{Content: string(content)}, nil
+}
+
+func (f *filesystem) listDir(path string) (FilesystemResult, error) {
+	resolved, err := f.allowedPath(path)
+	if err != nil {
+		return FilesystemResult{}, err
+	}
+
+	dirEntries, err := os.ReadDir(resolved)
+	if err != nil {
+		return FilesystemResult{}, fmt.Errorf("failed to list directory: %w", err)
+	}
+
+	entries := make([]FileEntry, 0, len(dirEntries))
+	for _, dirEntry := range dirEntries {
+		info, err := dirEntry.Info()
+		if err != nil {
+			return FilesystemResult{}, fmt.Errorf("failed to read %s: %w", dirEntry.Name(), err)
+		}
+		entries = append(entries, FileEntry{Name: dirEntry.Name(), IsDir: dirEntry.IsDir(), Size: info.Size()})
+	}
+
+	return FilesystemResult{Entries: entries}, nil
+}
+
+func (f *filesystem) writeFile(path, content string) (FilesystemResult, error) {
+	resolved, err := f.allowedPath(path)
+	if err != nil {
+		return FilesystemResult{}, err
+	}
+
+	if err := os.WriteFile(resolved, []byte(content), writtenFilePermissions); err != nil {
+		return FilesystemResult{}, fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return FilesystemResult{BytesWritten: len(content)}, nil
+}
+
+// allowedPath resolves path and checks that it is inside one of the allowed roots
+func (f *filesystem) allowedPath(path string) (string, error) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(f.roots[0], path)
+	}
+
+	resolved, err := resolveParent(path)
+	if err != nil {
+		return "", err
+	}
+
+	info, err := os.Lstat(resolved)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("failed to resolve path %s: %w", path, err)
+	}
+	if err == nil && info.Mode()&os.ModeSymlink != 0 {
+		return "", fmt.Errorf("%w: %s is a symlink", ErrPathNotAllowed, path)
+	}
+
+	for _, root := range f.roots {
+		rel, err := filepath.Rel(root, resolved)
+		if err != nil {
+			continue
+		}
+		if rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return resolved, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrPathNotAllowed, path)
+}
+
+// resolveParent returns the absolute path with symlinks in its directory evaluated,
+// leaving the last element as it is
+func resolveParent(path string) (string, error) {
+	abs := filepath.Clean(path)
+
+	parent, err := filepath.EvalSymlinks(filepath.Dir(abs))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve path %s: %w", path, err)
+	}
+
+	return filepath.Join(parent, filepath.Base(abs)), nil
+}
+
+// resolvePath returns the absolute path with symlinks evaluated. A path which doesn't
+// exist yet is resolved through its parent directory.
+func resolvePath(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve path %s: %w", path, err)
+	}
+
+	resolved, err := filepath.EvalSymlinks(abs)
+	if err == nil {
+		return resolved, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("failed to resolve path %s: %w", path, err)
+	}
+
+	parent, err := filepath.EvalSymlinks(filepath.Dir(abs))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve path %s: %w", path, err)
+	}
+
+	return filepath.Join(parent, filepath.Base(abs)), nil
+}
@@ -0,0 +1,199 @@
+package tools_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/tools"
+)
+
+func callFilesystemTool(t *testing.T, writeEnabled bool, root string, params tools.FilesystemParams) (
+	tools.FilesystemResult, error,
+) {
+	t.Helper()
+
+	tool, err := tools.NewFilesystemTool([]string{root}, writeEnabled)
+	require.NoError(t, err)
+
+	args, err := json.Marshal(params)
+	require.NoError(t, err)
+
+	result, err := tool.Call("call-1", string(args))
+	if err != nil {
+		return tools.FilesystemResult{}, err
+	}
+
+	fsResult, ok := result.(tools.FilesystemResult)
+	require.True(t, ok)
+
+	return fsResult, nil
+}
+
+func newFilesystemRoot(t *testing.T) string {
+	t.Helper()
+
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "config.yaml"), []byte("debug: true"), 0o600))
+	require.NoError(t, os.Mkdir(filepath.Join(root, "reports"), 0o700))
+
+	return root
+}
+
+func TestFilesystemTool_ReadFile(t *testing.T) {
+	t.Parallel()
+
+	root := newFilesystemRoot(t)
+
+	result, err := callFilesystemTool(t, false, root, tools.FilesystemParams{
+		Operation: tools.FilesystemOperationReadFile,
+		Path:      filepath.Join(root, "config.yaml"),
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "call-1", result.GetID())
+	assert.Equal(t, "debug: true", result.Content)
+}
+
+func TestFilesystemTool_RelativePath(t *testing.T) {
+	t.Parallel()
+
+	root := newFilesystemRoot(t)
+
+	result, err := callFilesystemTool(t, false, root, tools.FilesystemParams{
+		Operation: tools.FilesystemOperationReadFile,
+		Path:      "config.yaml",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "debug: true", result.Content)
+}
+
+func TestFilesystemTool_ListDir(t *testing.T) {
+	t.Parallel()
+
+	root := newFilesystemRoot(t)
+
+	result, err := callFilesystemTool(t, false, root, tools.FilesystemParams{
+		Operation: tools.FilesystemOperationListDir,
+		Path:      root,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, result.Entries, 2)
+	assert.Equal(t, tools.FileEntry{Name: "config.yaml", IsDir: false, Size: int64(len("debug: true"))},
+		result.Entries[0])
+	assert.Equal(t, "reports", result.Entries[1].Name)
+	assert.True(t, result.Entries[1].IsDir)
+}
+
+func TestFilesystemTool_WriteFile(t *testing.T) {
+	t.Parallel()
+
+	root := newFilesystemRoot(t)
+	path := filepath.Join(root, "reports", "summary.md")
+
+	result, err := callFilesystemTool(t, true, root, tools.FilesystemParams{
+		Operation: tools.FilesystemOperationWriteFile,
+		Path:      path,
+		Content:   "# Summary",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, len("# Summary"), result.BytesWritten)
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "# Summary", string(content))
+}
+
+func TestFilesystemTool_WriteDisabled(t *testing.T) {
+	t.Parallel()
+
+	root := newFilesystemRoot(t)
+	path := filepath.Join(root, "summary.md")
+
+	_, err := callFilesystemTool(t, false, root, tools.FilesystemParams{
+		Operation: tools.FilesystemOperationWriteFile,
+		Path:      path,
+		Content:   "# Summary",
+	})
+
+	require.ErrorIs(t, err, tools.ErrUnsupportedOperation)
+	assert.NoFileExists(t, path)
+}
+
+func TestFilesystemTool_PathNotAllowed(t *testing.T) {
+	t.Parallel()
+
+	root := newFilesystemRoot(t)
+	outside := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(outside, "secret.txt"), []byte("secret"), 0o600))
+	require.NoError(t, os.Symlink(outside, filepath.Join(root, "link")))
+	require.NoError(t, os.Symlink(filepath.Join(outside, "secret.txt"), filepath.Join(root, "file-link")))
+	require.NoError(t, os.Symlink(filepath.Join(outside, "new.txt"), filepath.Join(root, "dangling-link")))
+
+	tests := []struct {
+		name   string
+		params tools.FilesystemParams
+	}{
+		{"absolute path outside", tools.FilesystemParams{
+			Operation: tools.FilesystemOperationReadFile,
+			Path:      filepath.Join(outside, "secret.txt"),
+		}},
+		{"directory traversal", tools.FilesystemParams{
+			Operation: tools.FilesystemOperationReadFile,
+			Path:      filepath.Join(root, "..", filepath.Base(outside), "secret.txt"),
+		}},
+		{"symlink escape", tools.FilesystemParams{
+			Operation: tools.FilesystemOperationReadFile,
+			Path:      filepath.Join(root, "link", "secret.txt"),
+		}},
+		{"symlink to file", tools.FilesystemParams{
+			Operation: tools.FilesystemOperationReadFile,
+			Path:      filepath.Join(root, "file-link"),
+		}},
+		{"write through dangling symlink", tools.FilesystemParams{
+			Operation: tools.FilesystemOperationWriteFile,
+			Path:      filepath.Join(root, "dangling-link"),
+			Content:   "data",
+		}},
+		{"relative traversal", tools.FilesystemParams{
+			Operation: tools.FilesystemOperationReadFile,
+			Path:      filepath.Join("..", filepath.Base(outside), "secret.txt"),
+		}},
+		{"list parent", tools.FilesystemParams{
+			Operation: tools.FilesystemOperationListDir,
+			Path:      filepath.Join(root, ".."),
+		}},
+		{"write outside", tools.FilesystemParams{
+			Operation: tools.FilesystemOperationWriteFile,
+			Path:      filepath.Join(outside, "new.txt"),
+			Content:   "data",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := callFilesystemTool(t, true, root, tt.params)
+
+			require.ErrorIs(t, err, tools.ErrPathNotAllowed)
+		})
+	}
+}
+
+func TestNewFilesystemTool_Validation(t *testing.T) {
+	t.Parallel()
+
+	_, err := tools.NewFilesystemTool(nil, false)
+	require.ErrorIs(t, err, validation.ErrValidationFailed)
+
+	_, err = tools.NewFilesystemTool([]string{""}, false)
+	require.ErrorIs(t, err, validation.ErrValidationFailed)
+}
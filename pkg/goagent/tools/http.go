@@ -0,0 +1,247 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+const (
+	// HTTPToolName is the name of the tool created by NewHTTPTool
+	HTTPToolName = "http"
+	// DefaultMaxResponseBodySize is the number of bytes of a response body the HTTP tool
+	// returns by default, see WithMaxResponseBodySize
+	DefaultMaxResponseBodySize = 64 << 10
+
+	defaultHTTPTimeout = 30 * time.Second
+	maxHTTPTimeout     = 2 * time.Minute
+	maxHTTPRedirects   = 10
+)
+
+var (
+	// ErrInvalidURL is returned when a URL can't be parsed, doesn't use http or https or
+	// contains credentials
+	ErrInvalidURL = errors.New("invalid URL")
+	// ErrHostNotAllowed is returned when a request or redirect targets a host which is not allowed
+	ErrHostNotAllowed = errors.New("host not allowed")
+	// ErrTooManyRedirects is returned when a request is redirected more than 10 times
+	ErrTooManyRedirects = errors.New("too many redirects")
+	// ErrUnsupportedMethod is returned for HTTP methods other than GET, POST, PUT, PATCH and DELETE
+	ErrUnsupportedMethod = errors.New("unsupported HTTP method")
+)
+
+var httpToolMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// HTTPToolParams are the parameters of the HTTP tool
+//
+//nolint:lll // struct tags can't be wrapped
+type HTTPToolParams struct {
+	URL            string            `json:"url"             jsonschema_description:"URL to request"`
+	Method         string            `json:"method"          jsonschema:"enum=GET,enum=POST,enum=PUT,enum=PATCH,enum=DELETE"`
+	Headers        map[string]string `json:"headers"         jsonschema_description:"Request headers"`
+	Body           string            `json:"body"            jsonschema_description:"Request body"`
+	TimeoutSeconds int               `json:"timeout_seconds" jsonschema_description:"Timeout, 30 by default"`
+}
+
+// HTTPToolResult is the result of the HTTP tool
+type HTTPToolResult struct {
+	llm.BaseLLMToolResult
+	StatusCode int               `json:"status_code" jsonschema_description:"HTTP status code"`
+	Body       string            `json:"body"        jsonschema_description:"Response body"`
+	Headers    map[string]string `json:"headers"     jsonschema_description:"Response headers"`
+	// Truncated reports that the body was cut at the maximum response body size
+	Truncated bool `json:"truncated,omitempty" jsonschema_description:"Whether the body was truncated"`
+}
+
+// HTTPToolOption configures the HTTP tool
+type HTTPToolOption func(t *httpTool)
+
+// WithAllowedHosts restricts requests, including redirects, to the given host names.
+// Without it the tool can reach any host, including internal services, so set it
+// whenever the agent runs inside a private network.
+func WithAllowedHosts(hosts []string) HTTPToolOption {
+	return func(t *httpTool) {
+		t.allowedHosts = slices.Clone(hosts)
+	}
+}
+
+// WithHTTPToolClient sets the HTTP client used for requests
+func WithHTTPToolClient(client *http.Client) HTTPToolOption {
+	return func(t *httpTool) {
+		t.client = client
+	}
+}
+
+// WithMaxResponseBodySize sets the number of bytes of a response body returned to the LLM.
+// Longer bodies are cut and the result is marked as truncated. Defaults to
+// DefaultMaxResponseBodySize.
+func WithMaxResponseBodySize(size int) HTTPToolOption {
+	return func(t *httpTool) {
+		t.maxBodySize = size
+	}
+}
+
+type httpTool struct {
+	client       *http.Client
+	allowedHosts []string
+	maxBodySize  int
+}
+
+// NewHTTPTool creates an http tool which sends GET, POST, PUT, PATCH and DELETE requests
+// to http and https URLs and returns the status code, body and headers of the response.
+// Response bodies are cut at DefaultMaxResponseBodySize bytes, see WithMaxResponseBodySize.
+//
+// Example:
+//
+//	httpTool, err := tools.NewHTTPTool(tools.WithAllowedHosts([]string{"api.github.com"}))
+func NewHTTPTool(opts ...HTTPToolOption) (llm.LLMTool, error) {
+	tool := &httpTool{client: &http.Client{}, maxBodySize: DefaultMaxResponseBodySize}
+	for _, opt := range opts {
+		opt(tool)
+	}
+
+	if err := validation.NotNil(tool.client); err != nil {
+		return llm.LLMTool{}, fmt.Errorf("http client: %w", err)
+	}
+	if err := validation.IntIsPositive(tool.maxBodySize, "max response body size"); err != nil {
+		return llm.LLMTool{}, err
+	}
+
+	// Copy the client, so redirects can be checked without changing the caller's client
+	client := *tool.client
+	client.CheckRedirect = tool.checkRedirect
+	tool.client = &client
+
+	return llm.NewLLMTool(
+		llm.WithLLMToolName(HTTPToolName),
+		llm.WithLLMToolDescription(tool.description()),
+		llm.WithLLMToolTags(map[string]string{"category": "web"}),
+		llm.WithLLMToolParametersSchema[HTTPToolParams](),
+		llm.WithLLMToolCall(tool.call),
+	)
+}
+
+func (t *httpTool) description() string {
+	description := "Sends an HTTP request (GET, POST, PUT, PATCH or DELETE) and returns the response " +
+		"status code, body and headers"
+	if len(t.allowedHosts) > 0 {
+		hosts := slices.Clone(t.allowedHosts)
+		slices.Sort(hosts)
+		description += ". Allowed hosts: " + strings.Join(hosts, ", ")
+	}
+
+	return description
+}
+
+func (t *httpTool) call(id string, params HTTPToolParams) (HTTPToolResult, error) {
+	method := strings.ToUpper(params.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+	if !slices.Contains(httpToolMethods, method) {
+		return HTTPToolResult{}, fmt.Errorf("%w: %s", ErrUnsupportedMethod, params.Method)
+	}
+
+	requestURL, err := t.checkURL(params.URL)
+	if err != nil {
+		return HTTPToolResult{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), httpTimeout(params.TimeoutSeconds))
+	defer cancel()
+
+	var body io.Reader
+	if params.Body != "" {
+		body = strings.NewReader(params.Body)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, requestURL.String(), body)
+	if err != nil {
+		return HTTPToolResult{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	for name, value := range params.Headers {
+		request.Header.Set(name, value)
+	}
+
+	response, err := t.client.Do(request)
+	if err != nil {
+		return HTTPToolResult{}, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer response.Body.Close()
+
+	// read one more byte than the limit to tell whether the body was cut
+	responseBody, err := io.ReadAll(io.LimitReader(response.Body, int64(t.maxBodySize)+1))
+	if err != nil {
+		return HTTPToolResult{}, fmt.Errorf("failed to read response body: %w", err)
+	}
+	truncated := len(responseBody) > t.maxBodySize
+	if truncated {
+		responseBody = responseBody[:t.maxBodySize]
+	}
+
+	return HTTPToolResult{
+		BaseLLMToolResult: llm.BaseLLMToolResult{ID: id},
+		StatusCode:        response.StatusCode,
+		Body:              string(responseBody),
+		Headers:           firstHeaderValues(response.Header),
+		Truncated:         truncated,
+	}, nil
+}
+
+func (t *httpTool) checkURL(rawURL string) (*url.URL, error) {
+	if err := validation.URLIsValid(rawURL); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidURL, err)
+	}
+	if len(t.allowedHosts) > 0 {
+		if err := validation.URLMatchesAllowList(rawURL, t.allowedHosts); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrHostNotAllowed, err)
+		}
+	}
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidURL, err)
+	}
+
+	return parsed, nil
+}
+
+// checkRedirect applies the URL checks to every redirect, so an allowed host can't
+// redirect the request to a host which is not allowed
+func (t *httpTool) checkRedirect(request *http.Request, via []*http.Request) error {
+	if len(via) >= maxHTTPRedirects {
+		return fmt.Errorf("%w: stopped after %d redirects", ErrTooManyRedirects, maxHTTPRedirects)
+	}
+
+	_, err := t.checkURL(request.URL.String())
+
+	return err
+}
+
+func httpTimeout(timeoutSeconds int) time.Duration {
+	if timeoutSeconds <= 0 {
+		return defaultHTTPTimeout
+	}
+
+	return min(time.Duration(timeoutSeconds)*time.Second, maxHTTPTimeout)
+}
+
+func firstHeaderValues(header http.Header) map[string]string {
+	headers := make(map[string]string, len(header))
+	for name, values := range header {
+		if len(values) > 0 {
+			headers[name] = values[0]
+		}
+	}
+
+	return headers
+}
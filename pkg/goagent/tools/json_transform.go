@@ -0,0 +1,223 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/itchyny/gojq"
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+const (
+	// JSONTransformToolName is the name of the tool created by NewJSONTransformTool
+	JSONTransformToolName = "json_transform"
+
+	// ResultTypeArray is the result type of a JSON array
+	ResultTypeArray = "array"
+	// ResultTypeObject is the result type of a JSON object
+	ResultTypeObject = "object"
+	// ResultTypeScalar is the result type of a string, number, boolean or null
+	ResultTypeScalar = "scalar"
+
+	jqTimeout = 5 * time.Second
+)
+
+var (
+	// ErrInvalidInputJSON is returned when the input of the JSON transform tool is not valid JSON
+	ErrInvalidInputJSON = errors.New("invalid input JSON")
+	// ErrInvalidJQExpression is returned when a jq expression can't be parsed or evaluated
+	ErrInvalidJQExpression = errors.New("invalid jq expression")
+	// ErrFilterNotAllowed is returned when a jq expression uses a banned filter
+	ErrFilterNotAllowed = errors.New("jq filter not allowed")
+)
+
+// bannedJQFilters are filters which read the process environment or input streams,
+// write to stderr, stop the process or decode arbitrary binary data
+var bannedJQFilters = map[string]bool{
+	"@base64d":       true,
+	"env":            true,
+	"$ENV":           true,
+	"input":          true,
+	"inputs":         true,
+	"input_filename": true,
+	"debug":          true,
+	"stderr":         true,
+	"halt":           true,
+	"halt_error":     true,
+}
+
+// JSONTransformParams are the parameters of the JSON transform tool
+type JSONTransformParams struct {
+	InputJSON    string `json:"input_json"    jsonschema_description:"JSON document to transform"`
+	JQExpression string `json:"jq_expression" jsonschema_description:"jq expression, e.g. .items[] | {id, name}"`
+}
+
+// JSONTransformResult is the result of the JSON transform tool
+type JSONTransformResult struct {
+	llm.BaseLLMToolResult
+	ResultJSON string `json:"result_json"`
+	ResultType string `json:"result_type"`
+}
+
+// NewJSONTransformTool creates a json_transform tool which filters and reshapes JSON with
+// a jq expression. An expression which produces several values returns them as an array.
+// Filters which access the environment, input streams or stderr, and @base64d, are
+// rejected with ErrFilterNotAllowed, and the evaluation is stopped after 5 seconds.
+func NewJSONTransformTool() (llm.LLMTool, error) {
+	return llm.NewLLMTool(
+		llm.WithLLMToolName(JSONTransformToolName),
+		llm.WithLLMToolDescription("Filters and reshapes a JSON document with a jq expression"),
+		llm.WithLLMToolTags(map[string]string{"category": "data"}),
+		llm.WithLLMToolParametersSchema[JSONTransformParams](),
+		llm.WithLLMToolCall(func(id string, params JSONTransformParams) (JSONTransformResult, error) {
+			result, err := transformJSON(params.InputJSON, params.JQExpression)
+			if err != nil {
+				return JSONTransformResult{}, err
+			}
+			result.ID = id
+
+			return result, nil
+		}),
+	)
+}
+
+func transformJSON(inputJSON, expression string) (JSONTransformResult, error) {
+	if err := validation.StringIsNotEmpty(expression); err != nil {
+		return JSONTransformResult{}, fmt.Errorf("jq expression: %w", err)
+	}
+
+	var input any
+	if err := json.Unmarshal([]byte(inputJSON), &input); err != nil {
+		return JSONTransformResult{}, fmt.Errorf("%w: %w", ErrInvalidInputJSON, err)
+	}
+
+	code, err := compileJQ(expression)
+	if err != nil {
+		return JSONTransformResult{}, err
+	}
+
+	outputs, err := runJQ(code, input)
+	if err != nil {
+		return JSONTransformResult{}, err
+	}
+
+	var output any = outputs
+	if len(outputs) == 1 {
+		output = outputs[0]
+	}
+
+	resultJSON, err := json.Marshal(output)
+	if err != nil {
+		return JSONTransformResult{}, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return JSONTransformResult{ResultJSON: string(resultJSON), ResultType: jsonResultType(output)}, nil
+}
+
+func compileJQ(expression string) (*gojq.Code, error) {
+	query, err := gojq.Parse(expression)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidJQExpression, err)
+	}
+
+	if err := checkJQFilters(reflect.ValueOf(query)); err != nil {
+		return nil, err
+	}
+
+	code, err := gojq.Compile(query, gojq.WithEnvironLoader(func() []string { return nil }))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidJQExpression, err)
+	}
+
+	return code, nil
+}
+
+func runJQ(code *gojq.Code, input any) ([]any, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), jqTimeout)
+	defer cancel()
+
+	outputs := make([]any, 0)
+	iter := code.RunWithContext(ctx, input)
+	for {
+		value, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if err, isErr := value.(error); isErr {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidJQExpression, err)
+		}
+		outputs = append(outputs, value)
+	}
+
+	return outputs, nil
+}
+
+// checkJQFilters walks the parsed query and rejects banned filters. Function calls,
+// variables and formats such as @base64d are all stored in string fields of the AST:
+// Func.Name and Term.Format.
+func checkJQFilters(value reflect.Value) error {
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if value.IsNil() {
+			return nil
+		}
+
+		return checkJQFilters(value.Elem())
+	case reflect.Slice:
+		for i := range value.Len() {
+			if err := checkJQFilters(value.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		if err := checkJQNode(value); err != nil {
+			return err
+		}
+		for i := range value.NumField() {
+			if err := checkJQFilters(value.Field(i)); err != nil {
+				return err
+			}
+		}
+	default:
+	}
+
+	return nil
+}
+
+func checkJQNode(value reflect.Value) error {
+	if !value.CanInterface() {
+		return nil
+	}
+
+	var name string
+	switch node := value.Interface().(type) {
+	case gojq.Func:
+		name = node.Name
+	case gojq.Term:
+		name = node.Format
+	default:
+		return nil
+	}
+
+	if bannedJQFilters[name] {
+		return fmt.Errorf("%w: %s", ErrFilterNotAllowed, name)
+	}
+
+	return nil
+}
+
+func jsonResultType(value any) string {
+	switch value.(type) {
+	case []any:
+		return ResultTypeArray
+	case map[string]any:
+		return ResultTypeObject
+	default:
+		return ResultTypeScalar
+	}
+}
@@ -0,0 +1,106 @@
+package tools_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/tools"
+)
+
+const ordersJSON = `{"orders": [
+	{"id": 1, "customer": "alice", "total": 30},
+	{"id": 2, "customer": "bob", "total": 120},
+	{"id": 3, "customer": "alice", "total": 75}
+]}`
+
+func callJSONTransformTool(t *testing.T, input, expression string) (tools.JSONTransformResult, error) {
+	t.Helper()
+
+	tool, err := tools.NewJSONTransformTool()
+	require.NoError(t, err)
+
+	args, err := json.Marshal(tools.JSONTransformParams{InputJSON: input, JQExpression: expression})
+	require.NoError(t, err)
+
+	result, err := tool.Call("call-1", string(args))
+	if err != nil {
+		return tools.JSONTransformResult{}, err
+	}
+
+	transformResult, ok := result.(tools.JSONTransformResult)
+	require.True(t, ok)
+
+	return transformResult, nil
+}
+
+func TestJSONTransformTool(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		expression   string
+		expectedJSON string
+		expectedType string
+	}{
+		{"filter array", `[.orders[] | select(.total > 50) | .id]`, `[2, 3]`, tools.ResultTypeArray},
+		{"reshape object", `{count: (.orders | length), customers: ([.orders[].customer] | unique)}`,
+			`{"count": 3, "customers": ["alice", "bob"]}`, tools.ResultTypeObject},
+		{"scalar", `[.orders[].total] | add`, `225`, tools.ResultTypeScalar},
+		{"multiple outputs", `.orders[] | .customer`, `["alice", "bob", "alice"]`, tools.ResultTypeArray},
+		{"no outputs", `.orders[] | select(.total > 1000)`, `[]`, tools.ResultTypeArray},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := callJSONTransformTool(t, ordersJSON, tt.expression)
+
+			require.NoError(t, err)
+			assert.Equal(t, "call-1", result.GetID())
+			assert.JSONEq(t, tt.expectedJSON, result.ResultJSON)
+			assert.Equal(t, tt.expectedType, result.ResultType)
+		})
+	}
+}
+
+func TestJSONTransformTool_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		input       string
+		expression  string
+		expectedErr error
+	}{
+		{"invalid input", `{"orders":`, `.orders`, tools.ErrInvalidInputJSON},
+		{"syntax error", ordersJSON, `.orders[`, tools.ErrInvalidJQExpression},
+		{"runtime error", ordersJSON, `.orders[0].customer + 1`, tools.ErrInvalidJQExpression},
+		{"base64d", `"aGVsbG8="`, `@base64d`, tools.ErrFilterNotAllowed},
+		{"env", ordersJSON, `env.OPENAI_API_KEY`, tools.ErrFilterNotAllowed},
+		{"ENV variable", ordersJSON, `$ENV.HOME`, tools.ErrFilterNotAllowed},
+		{"nested in interpolation", ordersJSON, `"key: \(env.HOME)"`, tools.ErrFilterNotAllowed},
+		{"nested in function", ordersJSON, `def f: input; f`, tools.ErrFilterNotAllowed},
+		{"halt", ordersJSON, `halt_error`, tools.ErrFilterNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := callJSONTransformTool(t, tt.input, tt.expression)
+
+			require.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestJSONTransformTool_Timeout(t *testing.T) {
+	t.Parallel()
+
+	_, err := callJSONTransformTool(t, `0`, `until(false; .)`)
+
+	require.ErrorIs(t, err, tools.ErrInvalidJQExpression)
+}
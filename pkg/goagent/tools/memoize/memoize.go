@@ -0,0 +1,155 @@
+// Package memoize caches the results of deterministic tools, e.g. hashing or format
+// conversion, which always return the same result for the same arguments.
+//
+// Example:
+//
+//	cache := memoize.NewInMemoryCacheBackend()
+//	hashTool = memoize.Memoize(hashTool, cache, memoize.WithTTL(time.Hour))
+//
+// Results are cached by the SHA-256 fingerprint of the tool name and the arguments, so
+// tools which share a backend never see each other's results. Errors are not cached.
+package memoize
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+// CacheBackend stores cached tool results. Implementations must be safe for concurrent use.
+type CacheBackend interface {
+	// Get returns the value stored under key and whether it was found
+	Get(key string) (string, bool)
+	// Set stores the value under key. A ttl of zero or less keeps the value until it is evicted.
+	Set(key, value string, ttl time.Duration)
+}
+
+// Option configures Memoize
+type Option func(*memoizer)
+
+// WithTTL sets how long results stay cached. By default they are kept until the backend evicts them.
+func WithTTL(ttl time.Duration) Option {
+	return func(m *memoizer) {
+		m.ttl = ttl
+	}
+}
+
+// CachedResult is the result of a memoized tool call which was served from the cache.
+// It is sent to the LLM exactly like the original result, with the ID of the new call.
+type CachedResult struct {
+	llm.BaseLLMToolResult
+	Content json.RawMessage `json:"content"`
+}
+
+// MarshalForLLM returns the content of the original result
+func (r CachedResult) MarshalForLLM() ([]byte, error) {
+	return r.Content, nil
+}
+
+type memoizer struct {
+	tool  llm.LLMTool
+	cache CacheBackend
+	ttl   time.Duration
+}
+
+// Memoize returns a copy of the tool which caches its results in the backend. Only use it
+// for deterministic tools without side effects: a cached call does not run the tool.
+// Cached results are returned as CachedResult rather than the result type of the tool.
+func Memoize(tool llm.LLMTool, cache CacheBackend, opts ...Option) llm.LLMTool {
+	m := &memoizer{tool: tool, cache: cache}
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	memoized := tool
+	memoized.Call = m.call
+
+	return memoized
+}
+
+func (m *memoizer) call(id string, args string) (llm.LLMToolResult, error) {
+	key := Fingerprint(m.tool.Name, args)
+	if content, ok := m.cache.Get(key); ok {
+		return CachedResult{
+			BaseLLMToolResult: llm.BaseLLMToolResult{ID: id},
+			Content:           replaceResultID(json.RawMessage(content), id),
+		}, nil
+	}
+
+	result, err := m.tool.Call(id, args)
+	if err != nil {
+		return nil, err
+	}
+	if _, isError := result.(llm.ErrorLLMToolResult); isError {
+		return result, nil
+	}
+
+	content, err := llm.MarshalToolResult(result)
+	if err != nil {
+		slog.Warn("failed to cache tool result", "tool", m.tool.Name, "error", err)
+
+		return result, nil
+	}
+	m.cache.Set(key, string(content), m.ttl)
+
+	return result, nil
+}
+
+// Fingerprint returns the cache key of a tool call: the hex encoded SHA-256 of the tool name
+// and the arguments. Arguments which are valid JSON are normalized first, so the order of
+// the fields and whitespace do not matter.
+func Fingerprint(toolName, args string) string {
+	normalized := []byte(args)
+
+	// UseNumber keeps large integers exact, so different arguments never share a fingerprint
+	decoder := json.NewDecoder(bytes.NewReader(normalized))
+	decoder.UseNumber()
+
+	var decoded any
+	if err := decoder.Decode(&decoded); err == nil && !decoder.More() {
+		if encoded, err := json.Marshal(decoded); err == nil {
+			normalized = encoded
+		}
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(toolName))
+	hash.Write([]byte{0})
+	hash.Write(normalized)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// replaceResultID replaces the "id" field of the cached content, which holds the ID of the
+// call that was cached, with the ID of the current call
+func replaceResultID(content json.RawMessage, id string) json.RawMessage {
+	if !bytes.HasPrefix(bytes.TrimSpace(content), []byte("{")) {
+		return content
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(content, &fields); err != nil {
+		return content
+	}
+	if _, ok := fields["id"]; !ok {
+		return content
+	}
+
+	encodedID, err := json.Marshal(id)
+	if err != nil {
+		return content
+	}
+	fields["id"] = encodedID
+
+	replaced, err := json.Marshal(fields)
+	if err != nil {
+		return content
+	}
+
+	return replaced
+}
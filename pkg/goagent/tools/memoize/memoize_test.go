@@ -0,0 +1,189 @@
+package memoize_test
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/tools/memoize"
+)
+
+var errHashFailed = errors.New("hash failed")
+
+type HashParams struct {
+	Text      string `json:"text"`
+	Algorithm string `json:"algorithm"`
+}
+
+type HashResult struct {
+	llm.BaseLLMToolResult
+	Hash string `json:"hash"`
+}
+
+func createHashTool(t *testing.T, calls *atomic.Int32) llm.LLMTool {
+	t.Helper()
+
+	tool, err := llm.NewLLMToolFromFunc("hash", "Hashes the text",
+		func(callID string, params HashParams) (HashResult, error) {
+			calls.Add(1)
+			if params.Text == "" {
+				return HashResult{}, errHashFailed
+			}
+
+			return HashResult{BaseLLMToolResult: llm.BaseLLMToolResult{ID: callID}, Hash: "hash-of-" + params.Text}, nil
+		},
+	)
+	require.NoError(t, err)
+
+	return tool
+}
+
+func TestMemoize(t *testing.T) {
+	t.Parallel()
+
+	// given
+	var calls atomic.Int32
+	tool := memoize.Memoize(createHashTool(t, &calls), memoize.NewInMemoryCacheBackend())
+
+	// when
+	first, firstErr := tool.Call("call_1", `{"text": "abc", "algorithm": "sha256"}`)
+	second, secondErr := tool.Call("call_2", `{"algorithm":"sha256","text":"abc"}`)
+
+	// then
+	require.NoError(t, firstErr)
+	require.NoError(t, secondErr)
+	assert.Equal(t, int32(1), calls.Load())
+	assert.IsType(t, HashResult{}, first)
+
+	cached, ok := second.(memoize.CachedResult)
+	require.True(t, ok)
+	assert.Equal(t, "call_2", cached.GetID())
+
+	content, err := llm.MarshalToolResult(cached)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id": "call_2", "hash": "hash-of-abc"}`, string(content))
+}
+
+func TestMemoize_DoesNotCacheErrors(t *testing.T) {
+	t.Parallel()
+
+	// given
+	var calls atomic.Int32
+	tool := memoize.Memoize(createHashTool(t, &calls), memoize.NewInMemoryCacheBackend())
+
+	// when
+	_, firstErr := tool.Call("call_1", `{"text": ""}`)
+	_, secondErr := tool.Call("call_2", `{"text": ""}`)
+
+	// then
+	require.ErrorIs(t, firstErr, errHashFailed)
+	require.ErrorIs(t, secondErr, errHashFailed)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestMemoize_SharedBackend(t *testing.T) {
+	t.Parallel()
+
+	// given
+	var hashCalls, otherCalls atomic.Int32
+	cache := memoize.NewInMemoryCacheBackend()
+	hashTool := memoize.Memoize(createHashTool(t, &hashCalls), cache)
+
+	otherTool := createHashTool(t, &otherCalls)
+	otherTool.Name = "other_hash"
+	otherTool = memoize.Memoize(otherTool, cache)
+
+	// when
+	_, err := hashTool.Call("call_1", `{"text": "abc"}`)
+	require.NoError(t, err)
+	_, err = otherTool.Call("call_2", `{"text": "abc"}`)
+	require.NoError(t, err)
+
+	// then
+	assert.Equal(t, int32(1), hashCalls.Load())
+	assert.Equal(t, int32(1), otherCalls.Load())
+}
+
+func TestMemoize_TTL(t *testing.T) {
+	t.Parallel()
+
+	// given
+	var calls atomic.Int32
+	tool := memoize.Memoize(createHashTool(t, &calls), memoize.NewInMemoryCacheBackend(),
+		memoize.WithTTL(10*time.Millisecond))
+
+	// when
+	_, err := tool.Call("call_1", `{"text": "abc"}`)
+	require.NoError(t, err)
+	time.Sleep(20 * time.Millisecond)
+	_, err = tool.Call("call_2", `{"text": "abc"}`)
+	require.NoError(t, err)
+
+	// then
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestFingerprint(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, memoize.Fingerprint("hash", `{"a": 1, "b": 2}`), memoize.Fingerprint("hash", `{"b":2,"a":1}`))
+	assert.NotEqual(t, memoize.Fingerprint("hash", `{"a": 1}`), memoize.Fingerprint("other", `{"a": 1}`))
+	assert.NotEqual(t,
+		memoize.Fingerprint("hash", `{"id": 12345678901234567890}`),
+		memoize.Fingerprint("hash", `{"id": 12345678901234567891}`),
+	)
+	assert.Len(t, memoize.Fingerprint("hash", "not json"), 64)
+}
+
+func TestRedisCacheBackend(t *testing.T) {
+	t.Parallel()
+
+	// given
+	server := miniredis.RunT(t)
+	cache := memoize.NewRedisCacheBackend(redis.NewClient(&redis.Options{Addr: server.Addr()}))
+
+	// when
+	cache.Set("key", "value", time.Minute)
+	cache.Set("forever", "value", 0)
+
+	// then
+	value, ok := cache.Get("key")
+	require.True(t, ok)
+	assert.Equal(t, "value", value)
+	assert.True(t, server.Exists(memoize.DefaultRedisKeyPrefix+"key"))
+
+	server.FastForward(2 * time.Minute)
+	_, ok = cache.Get("key")
+	assert.False(t, ok)
+	_, ok = cache.Get("forever")
+	assert.True(t, ok)
+}
+
+func TestRedisCacheBackend_Unavailable(t *testing.T) {
+	t.Parallel()
+
+	// given
+	server := miniredis.RunT(t)
+	var calls atomic.Int32
+	client := redis.NewClient(&redis.Options{
+		Addr:        server.Addr(),
+		MaxRetries:  -1,
+		DialTimeout: 100 * time.Millisecond,
+	})
+	tool := memoize.Memoize(createHashTool(t, &calls), memoize.NewRedisCacheBackend(client))
+	server.Close()
+
+	// when
+	result, err := tool.Call("call_1", `{"text": "abc"}`)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "hash-of-abc", result.(HashResult).Hash)
+	assert.Equal(t, int32(1), calls.Load())
+}
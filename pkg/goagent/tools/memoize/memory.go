@@ -0,0 +1,53 @@
+package memoize
+
+import (
+	"sync"
+	"time"
+)
+
+type memoryEntry struct {
+	value     string
+	expiresAt time.Time
+}
+
+// InMemoryCacheBackend keeps cached results in a map of the process. Expired results are
+// removed when they are read.
+type InMemoryCacheBackend struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+}
+
+// NewInMemoryCacheBackend creates an empty in-memory cache backend
+func NewInMemoryCacheBackend() *InMemoryCacheBackend {
+	return &InMemoryCacheBackend{entries: make(map[string]memoryEntry)}
+}
+
+// Get returns the value stored under key unless it has expired
+func (c *InMemoryCacheBackend) Get(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		delete(c.entries, key)
+
+		return "", false
+	}
+
+	return entry.value, true
+}
+
+// Set stores the value under key for ttl, or without expiration when ttl is zero or less
+func (c *InMemoryCacheBackend) Set(key, value string, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := memoryEntry{value: value}
+	if ttl > 0 {
+		entry.expiresAt = time.Now().Add(ttl)
+	}
+	c.entries[key] = entry
+}
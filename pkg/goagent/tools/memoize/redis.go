@@ -0,0 +1,62 @@
+package memoize
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// DefaultRedisKeyPrefix is prepended to the keys of RedisCacheBackend
+	DefaultRedisKeyPrefix = "goagent:memoize:"
+
+	redisTimeout = time.Second
+)
+
+// RedisCacheBackend keeps cached results in Redis, so they are shared between processes.
+// Redis errors are logged and treated as cache misses, so an unavailable Redis only
+// disables the cache.
+type RedisCacheBackend struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedisCacheBackend creates a backend which stores results in Redis under keys
+// starting with DefaultRedisKeyPrefix
+func NewRedisCacheBackend(client redis.UniversalClient) *RedisCacheBackend {
+	return &RedisCacheBackend{client: client, prefix: DefaultRedisKeyPrefix}
+}
+
+// Get returns the value stored under key
+func (c *RedisCacheBackend) Get(key string) (string, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	value, err := c.client.Get(ctx, c.prefix+key).Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			slog.WarnContext(ctx, "failed to read cached tool result from redis", "error", err)
+		}
+
+		return "", false
+	}
+
+	return value, true
+}
+
+// Set stores the value under key for ttl, or without expiration when ttl is zero or less
+func (c *RedisCacheBackend) Set(key, value string, ttl time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	if ttl < 0 {
+		ttl = 0
+	}
+
+	if err := c.client.Set(ctx, c.prefix+key, value, ttl).Err(); err != nil {
+		slog.WarnContext(ctx, "failed to cache tool result in redis", "error", err)
+	}
+}
@@ -0,0 +1,153 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const (
+	ddgSearchURL   = "https://api.duckduckgo.com/"
+	braveSearchURL = "https://api.search.brave.com/res/v1/web/search"
+
+	braveTokenHeader = "X-Subscription-Token"
+)
+
+// DDGSearchProvider searches with the DuckDuckGo Instant Answer API, which requires no API key.
+// The API returns instant answers and related topics rather than full web results, so it works
+// best for entity and definition lookups.
+type DDGSearchProvider struct {
+	client searchClient
+}
+
+// NewDDGSearchProvider creates a DuckDuckGo Instant Answer search provider
+func NewDDGSearchProvider(options ...SearchProviderOption) *DDGSearchProvider {
+	return &DDGSearchProvider{client: newSearchClient(ddgSearchURL, options)}
+}
+
+type ddgResponse struct {
+	Heading       string     `json:"Heading"`
+	AbstractText  string     `json:"AbstractText"`
+	AbstractURL   string     `json:"AbstractURL"`
+	RelatedTopics []ddgTopic `json:"RelatedTopics"`
+}
+
+type ddgTopic struct {
+	Text     string     `json:"Text"`
+	FirstURL string     `json:"FirstURL"`
+	Topics   []ddgTopic `json:"Topics"`
+}
+
+// Search implements WebSearchProvider
+func (p *DDGSearchProvider) Search(ctx context.Context, query string, maxResults int) ([]SearchResult, error) {
+	requestURL := p.client.baseURL + "?" + url.Values{
+		"q":             {query},
+		"format":        {"json"},
+		"no_html":       {"1"},
+		"skip_disambig": {"1"},
+	}.Encode()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create search request: %w", err)
+	}
+
+	response, err := p.client.do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	var body ddgResponse
+	if err := json.NewDecoder(response.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("%w: failed to decode response: %w", ErrSearchFailed, err)
+	}
+
+	results := make([]SearchResult, 0, maxResults)
+	if body.AbstractText != "" {
+		results = append(results, SearchResult{Title: body.Heading, URL: body.AbstractURL, Snippet: body.AbstractText})
+	}
+
+	return appendDDGTopics(results, body.RelatedTopics, maxResults), nil
+}
+
+// appendDDGTopics flattens related topics, which can be grouped into nested categories
+func appendDDGTopics(results []SearchResult, topics []ddgTopic, maxResults int) []SearchResult {
+	for _, topic := range topics {
+		if len(results) >= maxResults {
+			break
+		}
+
+		if len(topic.Topics) > 0 {
+			results = appendDDGTopics(results, topic.Topics, maxResults)
+
+			continue
+		}
+
+		if topic.FirstURL != "" {
+			results = append(results, SearchResult{Title: topic.Text, URL: topic.FirstURL, Snippet: topic.Text})
+		}
+	}
+
+	return results[:min(len(results), maxResults)]
+}
+
+// BraveSearchProvider searches with the Brave Search API
+type BraveSearchProvider struct {
+	apiKey string
+	client searchClient
+}
+
+// NewBraveSearchProvider creates a Brave Search provider authenticated with the given API key
+func NewBraveSearchProvider(apiKey string, options ...SearchProviderOption) *BraveSearchProvider {
+	return &BraveSearchProvider{
+		apiKey: apiKey,
+		client: newSearchClient(braveSearchURL, options),
+	}
+}
+
+type braveResponse struct {
+	Web struct {
+		Results []struct {
+			Title       string `json:"title"`
+			URL         string `json:"url"`
+			Description string `json:"description"`
+		} `json:"results"`
+	} `json:"web"`
+}
+
+// Search implements WebSearchProvider
+func (p *BraveSearchProvider) Search(ctx context.Context, query string, maxResults int) ([]SearchResult, error) {
+	requestURL := p.client.baseURL + "?" + url.Values{
+		"q":     {query},
+		"count": {strconv.Itoa(maxResults)},
+	}.Encode()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create search request: %w", err)
+	}
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set(braveTokenHeader, p.apiKey)
+
+	response, err := p.client.do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	var body braveResponse
+	if err := json.NewDecoder(response.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("%w: failed to decode response: %w", ErrSearchFailed, err)
+	}
+
+	results := make([]SearchResult, 0, len(body.Web.Results))
+	for _, result := range body.Web.Results {
+		results = append(results, SearchResult{Title: result.Title, URL: result.URL, Snippet: result.Description})
+	}
+
+	return results[:min(len(results), maxResults)], nil
+}
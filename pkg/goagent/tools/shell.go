@@ -0,0 +1,187 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+const (
+	// ShellToolName is the name of the tool created by NewShellTool
+	ShellToolName = "shell"
+
+	defaultShellTimeout = 30 * time.Second
+	maxShellTimeout     = 5 * time.Minute
+	// shellWaitDelay bounds the wait for the output of processes started by a killed command
+	shellWaitDelay = time.Second
+)
+
+var (
+	// ErrCommandNotAllowed is returned when a command is not in the allowed commands of the
+	// shell tool
+	ErrCommandNotAllowed = errors.New("command not allowed")
+	// ErrCommandTimeout is returned when a command does not finish within its timeout
+	ErrCommandTimeout = errors.New("command timed out")
+)
+
+// ShellParams are the parameters of the shell tool
+type ShellParams struct {
+	Command        string   `json:"command"         jsonschema_description:"Name of the command to run"`
+	Args           []string `json:"args"            jsonschema_description:"Arguments of the command"`
+	TimeoutSeconds int      `json:"timeout_seconds" jsonschema_description:"Timeout, 30 by default"`
+}
+
+// ShellResult is the result of the shell tool
+type ShellResult struct {
+	llm.BaseLLMToolResult
+	Stdout   string `json:"stdout"`
+	Stderr   string `json:"stderr"`
+	ExitCode int    `json:"exit_code"`
+}
+
+// NewShellTool creates a shell tool which runs the commands listed in allowedCommands, e.g.
+// kubectl or terraform, in workDir and returns their stdout, stderr and exit code. Commands
+// are bare binary names looked up in PATH; a command which is not listed exactly, including
+// one given as a path, returns ErrCommandNotAllowed. The arguments are passed to the command
+// as they are, without a shell, so pipes, globs and variables are not expanded and can't
+// start other commands. The command inherits the environment of the host process and is
+// killed after its timeout, 30 seconds by default and at most 5 minutes.
+//
+// Example:
+//
+//	shellTool, err := tools.NewShellTool([]string{"kubectl", "helm"}, "/srv/deploy")
+func NewShellTool(allowedCommands []string, workDir string) (llm.LLMTool, error) {
+	if len(allowedCommands) == 0 {
+		return llm.LLMTool{}, fmt.Errorf("allowed commands: %w: at least one command is required",
+			validation.ErrValidationFailed)
+	}
+	for _, command := range allowedCommands {
+		if err := checkCommandName(command); err != nil {
+			return llm.LLMTool{}, fmt.Errorf("allowed commands: %w", err)
+		}
+	}
+
+	dir, err := shellWorkDir(workDir)
+	if err != nil {
+		return llm.LLMTool{}, fmt.Errorf("work dir: %w", err)
+	}
+
+	shell := &shellRunner{allowedCommands: slices.Clone(allowedCommands), workDir: dir}
+
+	return llm.NewLLMTool(
+		llm.WithLLMToolName(ShellToolName),
+		llm.WithLLMToolDescription(shell.description()),
+		llm.WithLLMToolTags(map[string]string{"category": "system"}),
+		llm.WithLLMToolParametersSchema[ShellParams](),
+		llm.WithLLMToolCall(func(id string, params ShellParams) (ShellResult, error) {
+			result, err := shell.run(params)
+			if err != nil {
+				return ShellResult{}, err
+			}
+			result.ID = id
+
+			return result, nil
+		}),
+	)
+}
+
+type shellRunner struct {
+	allowedCommands []string
+	workDir         string
+}
+
+func (s *shellRunner) description() string {
+	return "Runs a command with arguments, without a shell, and returns its stdout, stderr and exit code. " +
+		"Allowed commands: " + strings.Join(s.allowedCommands, ", ")
+}
+
+func (s *shellRunner) run(params ShellParams) (ShellResult, error) {
+	if !slices.Contains(s.allowedCommands, params.Command) {
+		return ShellResult{}, fmt.Errorf("%w: %q", ErrCommandNotAllowed, params.Command)
+	}
+
+	binary, err := exec.LookPath(params.Command)
+	if err != nil {
+		return ShellResult{}, fmt.Errorf("failed to find command %s: %w", params.Command, err)
+	}
+
+	timeout := shellTimeout(params.TimeoutSeconds)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, binary, params.Args...) //nolint:gosec // the command is allow-listed
+	cmd.Dir = s.workDir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = shellWaitDelay
+
+	err = cmd.Run()
+	if ctx.Err() != nil {
+		return ShellResult{}, fmt.Errorf("%w: %s after %s", ErrCommandTimeout, params.Command, timeout)
+	}
+
+	result := ShellResult{Stdout: stdout.String(), Stderr: stderr.String()}
+
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+	case err != nil:
+		return ShellResult{}, fmt.Errorf("failed to run command %s: %w", params.Command, err)
+	}
+
+	return result, nil
+}
+
+// checkCommandName accepts bare binary names only, so an allowed command can't refer to a
+// binary outside of PATH
+func checkCommandName(command string) error {
+	if err := validation.StringIsNotEmpty(command); err != nil {
+		return err
+	}
+	if strings.ContainsAny(command, `/\`) || command == "." || command == ".." {
+		return fmt.Errorf("%w: %q must be a binary name, not a path", validation.ErrValidationFailed, command)
+	}
+
+	return nil
+}
+
+func shellWorkDir(workDir string) (string, error) {
+	if err := validation.StringIsNotEmpty(workDir); err != nil {
+		return "", err
+	}
+
+	dir, err := filepath.Abs(workDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", workDir, err)
+	}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", validation.ErrValidationFailed, err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("%w: %s is not a directory", validation.ErrValidationFailed, workDir)
+	}
+
+	return dir, nil
+}
+
+func shellTimeout(timeoutSeconds int) time.Duration {
+	if timeoutSeconds <= 0 {
+		return defaultShellTimeout
+	}
+
+	return min(time.Duration(timeoutSeconds)*time.Second, maxShellTimeout)
+}
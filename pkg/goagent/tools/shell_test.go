@@ -0,0 +1,119 @@
+//go:build unix
+
+package tools_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/tools"
+)
+
+func runShell(t *testing.T, workDir string, params tools.ShellParams) (tools.ShellResult, error) {
+	t.Helper()
+
+	tool, err := tools.NewShellTool([]string{"pwd", "sh", "sleep"}, workDir)
+	require.NoError(t, err)
+
+	args, err := json.Marshal(params)
+	require.NoError(t, err)
+
+	result, err := tool.Call("call-1", string(args))
+	if err != nil {
+		return tools.ShellResult{}, err
+	}
+
+	shellResult, ok := result.(tools.ShellResult)
+	require.True(t, ok)
+
+	return shellResult, nil
+}
+
+func TestShellTool(t *testing.T) {
+	t.Parallel()
+
+	workDir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name             string
+		params           tools.ShellParams
+		expectedStdout   string
+		expectedStderr   string
+		expectedExitCode int
+	}{
+		{"work dir", tools.ShellParams{Command: "pwd"}, workDir + "\n", "", 0},
+		{"args are not expanded", tools.ShellParams{Command: "sh", Args: []string{"-c", `echo "$0"`, "$HOME"}},
+			"$HOME\n", "", 0},
+		{"stderr and exit code", tools.ShellParams{Command: "sh", Args: []string{"-c", "echo failed >&2; exit 3"}},
+			"", "failed\n", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := runShell(t, workDir, tt.params)
+
+			require.NoError(t, err)
+			assert.Equal(t, "call-1", result.GetID())
+			assert.Equal(t, tt.expectedStdout, result.Stdout)
+			assert.Equal(t, tt.expectedStderr, result.Stderr)
+			assert.Equal(t, tt.expectedExitCode, result.ExitCode)
+		})
+	}
+}
+
+func TestShellTool_CommandNotAllowed(t *testing.T) {
+	t.Parallel()
+
+	for _, command := range []string{"rm", "/bin/sh", "./sh", "../sh", "SH", ""} {
+		_, err := runShell(t, t.TempDir(), tools.ShellParams{Command: command})
+
+		require.ErrorIs(t, err, tools.ErrCommandNotAllowed, "command %q", command)
+	}
+}
+
+func TestShellTool_Timeout(t *testing.T) {
+	t.Parallel()
+
+	_, err := runShell(t, t.TempDir(), tools.ShellParams{Command: "sleep", Args: []string{"10"}, TimeoutSeconds: 1})
+
+	require.ErrorIs(t, err, tools.ErrCommandTimeout)
+}
+
+func TestNewShellTool_Errors(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "file.txt")
+	require.NoError(t, os.WriteFile(file, []byte("content"), 0o600))
+
+	tests := []struct {
+		name            string
+		allowedCommands []string
+		workDir         string
+	}{
+		{"no commands", nil, t.TempDir()},
+		{"empty command", []string{""}, t.TempDir()},
+		{"command path", []string{"/bin/sh"}, t.TempDir()},
+		{"relative command path", []string{"../sh"}, t.TempDir()},
+		{"missing work dir", []string{"sh"}, filepath.Join(t.TempDir(), "missing")},
+		{"work dir is a file", []string{"sh"}, file},
+		{"empty work dir", []string{"sh"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := tools.NewShellTool(tt.allowedCommands, tt.workDir)
+
+			require.ErrorIs(t, err, validation.ErrValidationFailed)
+		})
+	}
+}
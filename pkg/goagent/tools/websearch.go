@@ -0,0 +1,165 @@
+// Package tools provides built-in tools which can be registered in an agent with agent.WithTool
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/vitalii-honchar/go-agent/internal/validation"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/llm"
+)
+
+const (
+	// WebSearchToolName is the name of the tool created by NewWebSearchTool
+	WebSearchToolName = "web_search"
+
+	defaultSearchResults = 5
+	maxSearchResults     = 20
+	searchTimeout        = 30 * time.Second
+)
+
+// ErrSearchFailed is returned when a search provider responds with an error
+var ErrSearchFailed = errors.New("web search failed")
+
+// SearchResult is a single web search hit
+type SearchResult struct {
+	Title   string `json:"title"`
+	URL     string `json:"url"`
+	Snippet string `json:"snippet"`
+}
+
+// WebSearchProvider searches the web using a specific search API
+type WebSearchProvider interface {
+	Search(ctx context.Context, query string, maxResults int) ([]SearchResult, error)
+}
+
+// WebSearchParams are the parameters of the web search tool
+type WebSearchParams struct {
+	Query      string `json:"query" jsonschema_description:"Search query"`
+	MaxResults int    `json:"max_results" jsonschema_description:"Maximum number of results to return, 5 by default"`
+}
+
+// WebSearchResult is the result of the web search tool
+type WebSearchResult struct {
+	llm.BaseLLMToolResult
+	Results []SearchResult `json:"results"`
+}
+
+// NewWebSearchTool creates a web_search tool which searches the web with the given provider.
+// Results whose URL is not a valid http or https URL, or contains credentials, are dropped
+// before they reach the LLM.
+//
+// Example:
+//
+//	searchTool, err := tools.NewWebSearchTool(tools.NewBraveSearchProvider(os.Getenv("BRAVE_API_KEY")))
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	researcher, err := agent.NewAgent(
+//		agent.WithName[Report]("researcher"),
+//		agent.WithLLMConfig[Report](cfg),
+//		agent.WithBehavior[Report]("Research the topic using web search"),
+//		agent.WithTool[Report](tools.WebSearchToolName, searchTool),
+//	)
+func NewWebSearchTool(provider WebSearchProvider) (llm.LLMTool, error) {
+	if err := validation.NotNil(provider); err != nil {
+		return llm.LLMTool{}, fmt.Errorf("search provider: %w", err)
+	}
+
+	return llm.NewLLMTool(
+		llm.WithLLMToolName(WebSearchToolName),
+		llm.WithLLMToolDescription("Searches the web and returns result titles, URLs and snippets"),
+		llm.WithLLMToolTags(map[string]string{"category": "web"}),
+		llm.WithLLMToolParametersSchema[WebSearchParams](),
+		llm.WithLLMToolCall(func(id string, params WebSearchParams) (WebSearchResult, error) {
+			if err := validation.StringIsNotEmpty(params.Query); err != nil {
+				return WebSearchResult{}, fmt.Errorf("query: %w", err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), searchTimeout)
+			defer cancel()
+
+			results, err := provider.Search(ctx, params.Query, searchResultsLimit(params.MaxResults))
+			if err != nil {
+				return WebSearchResult{}, err
+			}
+
+			return WebSearchResult{
+				BaseLLMToolResult: llm.BaseLLMToolResult{ID: id},
+				Results:           validSearchResults(results),
+			}, nil
+		}),
+	)
+}
+
+func validSearchResults(results []SearchResult) []SearchResult {
+	valid := make([]SearchResult, 0, len(results))
+	for _, result := range results {
+		if validation.URLIsValid(result.URL) == nil {
+			valid = append(valid, result)
+		}
+	}
+
+	return valid
+}
+
+func searchResultsLimit(maxResults int) int {
+	if maxResults <= 0 {
+		return defaultSearchResults
+	}
+
+	return min(maxResults, maxSearchResults)
+}
+
+// SearchProviderOption configures a search provider
+type SearchProviderOption func(c *searchClient)
+
+// WithSearchBaseURL overrides the search API endpoint, e.g. for a proxy or tests
+func WithSearchBaseURL(baseURL string) SearchProviderOption {
+	return func(c *searchClient) {
+		c.baseURL = baseURL
+	}
+}
+
+// WithSearchHTTPClient sets the HTTP client used for search requests
+func WithSearchHTTPClient(client *http.Client) SearchProviderOption {
+	return func(c *searchClient) {
+		c.httpClient = client
+	}
+}
+
+type searchClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+func newSearchClient(baseURL string, options []SearchProviderOption) searchClient {
+	client := searchClient{
+		baseURL:    baseURL,
+		httpClient: &http.Client{Timeout: searchTimeout},
+	}
+	for _, opt := range options {
+		opt(&client)
+	}
+
+	return client
+}
+
+func (c searchClient) do(request *http.Request) (*http.Response, error) {
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSearchFailed, err)
+	}
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		_ = response.Body.Close()
+
+		return nil, fmt.Errorf("%w: unexpected status %d", ErrSearchFailed, response.StatusCode)
+	}
+
+	return response, nil
+}
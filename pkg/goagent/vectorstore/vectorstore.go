@@ -0,0 +1,129 @@
+// Package vectorstore provides vector storage and similarity search for
+// retrieval-augmented generation (RAG) tools.
+package vectorstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+)
+
+var (
+	// ErrDimensionMismatch is returned when vectors have different dimensions
+	ErrDimensionMismatch = errors.New("vector dimension mismatch")
+	// ErrEmptyEmbedding is returned when a document has no embedding
+	ErrEmptyEmbedding = errors.New("embedding cannot be empty")
+)
+
+// Document is a piece of content with its embedding vector
+type Document struct {
+	ID        string            `json:"id"`
+	Content   string            `json:"content"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+	Embedding []float32         `json:"-"`
+}
+
+// SearchResult is a document returned by a similarity search together with its score
+type SearchResult struct {
+	Document Document `json:"document"`
+	Score    float64  `json:"score"`
+}
+
+// VectorStore stores documents and finds the most similar ones to a query vector
+type VectorStore interface {
+	Add(ctx context.Context, docs ...Document) error
+	Search(ctx context.Context, query []float32, topK int) ([]SearchResult, error)
+}
+
+// InMemoryVectorStore is a VectorStore that keeps documents in memory and
+// ranks them by cosine similarity. It is safe for concurrent use.
+type InMemoryVectorStore struct {
+	mu        sync.RWMutex
+	documents []Document
+}
+
+// NewInMemoryVectorStore creates an empty in-memory vector store
+func NewInMemoryVectorStore() *InMemoryVectorStore {
+	return &InMemoryVectorStore{}
+}
+
+// Add stores the given documents. All embeddings must have the same dimension.
+// When any document is invalid, none of them are stored.
+func (s *InMemoryVectorStore) Add(_ context.Context, docs ...Document) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dimension := 0
+	if len(s.documents) > 0 {
+		dimension = len(s.documents[0].Embedding)
+	}
+
+	for _, doc := range docs {
+		if len(doc.Embedding) == 0 {
+			return fmt.Errorf("document %s: %w", doc.ID, ErrEmptyEmbedding)
+		}
+		if dimension == 0 {
+			dimension = len(doc.Embedding)
+		}
+		if len(doc.Embedding) != dimension {
+			return fmt.Errorf("document %s: %w: expected %d, got %d",
+				doc.ID, ErrDimensionMismatch, dimension, len(doc.Embedding))
+		}
+	}
+
+	s.documents = append(s.documents, docs...)
+
+	return nil
+}
+
+// Search returns up to topK documents ordered by descending cosine similarity to query
+func (s *InMemoryVectorStore) Search(_ context.Context, query []float32, topK int) ([]SearchResult, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if topK <= 0 || len(s.documents) == 0 {
+		return nil, nil
+	}
+
+	results := make([]SearchResult, 0, len(s.documents))
+	for _, doc := range s.documents {
+		if len(doc.Embedding) != len(query) {
+			return nil, fmt.Errorf("%w: expected %d, got %d", ErrDimensionMismatch, len(doc.Embedding), len(query))
+		}
+
+		results = append(results, SearchResult{
+			Document: doc,
+			Score:    CosineSimilarity(query, doc.Embedding),
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+
+	if len(results) > topK {
+		results = results[:topK]
+	}
+
+	return results, nil
+}
+
+// CosineSimilarity returns the cosine similarity of two vectors of equal length.
+// It returns 0 if either vector has zero magnitude.
+func CosineSimilarity(vecA, vecB []float32) float64 {
+	var dot, normA, normB float64
+	for i := range vecA {
+		dot += float64(vecA[i]) * float64(vecB[i])
+		normA += float64(vecA[i]) * float64(vecA[i])
+		normB += float64(vecB[i]) * float64(vecB[i])
+	}
+
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
@@ -0,0 +1,85 @@
+package vectorstore_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitalii-honchar/go-agent/pkg/goagent/vectorstore"
+)
+
+func TestCosineSimilarity(t *testing.T) {
+	t.Parallel()
+
+	assert.InDelta(t, 1.0, vectorstore.CosineSimilarity([]float32{1, 2}, []float32{2, 4}), 1e-9)
+	assert.InDelta(t, 0.0, vectorstore.CosineSimilarity([]float32{1, 0}, []float32{0, 1}), 1e-9)
+	assert.InDelta(t, -1.0, vectorstore.CosineSimilarity([]float32{1, 0}, []float32{-1, 0}), 1e-9)
+	assert.InDelta(t, 0.0, vectorstore.CosineSimilarity([]float32{0, 0}, []float32{1, 1}), 1e-9)
+}
+
+func TestInMemoryVectorStore_Search(t *testing.T) {
+	t.Parallel()
+
+	store := vectorstore.NewInMemoryVectorStore()
+	err := store.Add(context.Background(),
+		vectorstore.Document{ID: "north", Content: "north", Embedding: []float32{0, 1}},
+		vectorstore.Document{ID: "east", Content: "east", Embedding: []float32{1, 0}},
+		vectorstore.Document{ID: "north_east", Content: "north east", Embedding: []float32{1, 1}},
+	)
+	require.NoError(t, err)
+
+	results, err := store.Search(context.Background(), []float32{0.1, 1}, 2)
+
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "north", results[0].Document.ID)
+	assert.Equal(t, "north_east", results[1].Document.ID)
+	assert.Greater(t, results[0].Score, results[1].Score)
+}
+
+func TestInMemoryVectorStore_EmptyStore(t *testing.T) {
+	t.Parallel()
+
+	results, err := vectorstore.NewInMemoryVectorStore().Search(context.Background(), []float32{1}, 3)
+
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func TestInMemoryVectorStore_DimensionMismatch(t *testing.T) {
+	t.Parallel()
+
+	store := vectorstore.NewInMemoryVectorStore()
+	require.NoError(t, store.Add(context.Background(), vectorstore.Document{ID: "a", Embedding: []float32{1, 0}}))
+
+	err := store.Add(context.Background(), vectorstore.Document{ID: "b", Embedding: []float32{1, 0, 0}})
+	require.ErrorIs(t, err, vectorstore.ErrDimensionMismatch)
+
+	_, err = store.Search(context.Background(), []float32{1, 0, 0}, 1)
+	require.ErrorIs(t, err, vectorstore.ErrDimensionMismatch)
+}
+
+func TestInMemoryVectorStore_EmptyEmbedding(t *testing.T) {
+	t.Parallel()
+
+	err := vectorstore.NewInMemoryVectorStore().Add(context.Background(), vectorstore.Document{ID: "a"})
+
+	require.ErrorIs(t, err, vectorstore.ErrEmptyEmbedding)
+}
+
+func TestInMemoryVectorStore_InvalidBatch(t *testing.T) {
+	t.Parallel()
+
+	store := vectorstore.NewInMemoryVectorStore()
+
+	err := store.Add(context.Background(),
+		vectorstore.Document{ID: "a", Embedding: []float32{1, 0}},
+		vectorstore.Document{ID: "b", Embedding: []float32{1, 0, 0}},
+	)
+	require.ErrorIs(t, err, vectorstore.ErrDimensionMismatch)
+
+	results, err := store.Search(context.Background(), []float32{1, 0, 0}, 1)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}